@@ -0,0 +1,1063 @@
+// Definición de la API gRPC de gobcv. El código Go de este directorio se genera con
+// protoc-gen-go y protoc-gen-go-grpc (ver la sección "API gRPC" del README).
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.8
+// 	protoc        v5.29.3
+// source: api/gobcv/v1/currency.proto
+
+package gobcvv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Currency es una moneda con su valor en bolívares.
+type Currency struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Value         float64                `protobuf:"fixed64,3,opt,name=value,proto3" json:"value,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Source        string                 `protobuf:"bytes,5,opt,name=source,proto3" json:"source,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Currency) Reset() {
+	*x = Currency{}
+	mi := &file_api_gobcv_v1_currency_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Currency) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Currency) ProtoMessage() {}
+
+func (x *Currency) ProtoReflect() protoreflect.Message {
+	mi := &file_api_gobcv_v1_currency_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Currency.ProtoReflect.Descriptor instead.
+func (*Currency) Descriptor() ([]byte, []int) {
+	return file_api_gobcv_v1_currency_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Currency) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Currency) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Currency) GetValue() float64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+func (x *Currency) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *Currency) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+// RateChange es un cambio en el valor de una moneda. sequence crece de forma estricta
+// y sirve para reanudar WatchRates.
+type RateChange struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sequence      uint64                 `protobuf:"varint,1,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	CurrencyId    string                 `protobuf:"bytes,2,opt,name=currency_id,json=currencyId,proto3" json:"currency_id,omitempty"`
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Value         float64                `protobuf:"fixed64,4,opt,name=value,proto3" json:"value,omitempty"`
+	PreviousValue float64                `protobuf:"fixed64,5,opt,name=previous_value,json=previousValue,proto3" json:"previous_value,omitempty"`
+	Source        string                 `protobuf:"bytes,6,opt,name=source,proto3" json:"source,omitempty"`
+	ChangedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=changed_at,json=changedAt,proto3" json:"changed_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RateChange) Reset() {
+	*x = RateChange{}
+	mi := &file_api_gobcv_v1_currency_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RateChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RateChange) ProtoMessage() {}
+
+func (x *RateChange) ProtoReflect() protoreflect.Message {
+	mi := &file_api_gobcv_v1_currency_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RateChange.ProtoReflect.Descriptor instead.
+func (*RateChange) Descriptor() ([]byte, []int) {
+	return file_api_gobcv_v1_currency_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *RateChange) GetSequence() uint64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *RateChange) GetCurrencyId() string {
+	if x != nil {
+		return x.CurrencyId
+	}
+	return ""
+}
+
+func (x *RateChange) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *RateChange) GetValue() float64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+func (x *RateChange) GetPreviousValue() float64 {
+	if x != nil {
+		return x.PreviousValue
+	}
+	return 0
+}
+
+func (x *RateChange) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *RateChange) GetChangedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ChangedAt
+	}
+	return nil
+}
+
+// GetCurrencyRequest solicita una moneda. skip_cache omite el caché.
+type GetCurrencyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CurrencyId    string                 `protobuf:"bytes,1,opt,name=currency_id,json=currencyId,proto3" json:"currency_id,omitempty"`
+	SkipCache     bool                   `protobuf:"varint,2,opt,name=skip_cache,json=skipCache,proto3" json:"skip_cache,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCurrencyRequest) Reset() {
+	*x = GetCurrencyRequest{}
+	mi := &file_api_gobcv_v1_currency_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCurrencyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCurrencyRequest) ProtoMessage() {}
+
+func (x *GetCurrencyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_gobcv_v1_currency_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCurrencyRequest.ProtoReflect.Descriptor instead.
+func (*GetCurrencyRequest) Descriptor() ([]byte, []int) {
+	return file_api_gobcv_v1_currency_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetCurrencyRequest) GetCurrencyId() string {
+	if x != nil {
+		return x.CurrencyId
+	}
+	return ""
+}
+
+func (x *GetCurrencyRequest) GetSkipCache() bool {
+	if x != nil {
+		return x.SkipCache
+	}
+	return false
+}
+
+// GetCurrencyResponse contiene la moneda solicitada.
+type GetCurrencyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Currency      *Currency              `protobuf:"bytes,1,opt,name=currency,proto3" json:"currency,omitempty"`
+	FromCache     bool                   `protobuf:"varint,2,opt,name=from_cache,json=fromCache,proto3" json:"from_cache,omitempty"`
+	Stale         bool                   `protobuf:"varint,3,opt,name=stale,proto3" json:"stale,omitempty"`
+	AgeSeconds    int64                  `protobuf:"varint,4,opt,name=age_seconds,json=ageSeconds,proto3" json:"age_seconds,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCurrencyResponse) Reset() {
+	*x = GetCurrencyResponse{}
+	mi := &file_api_gobcv_v1_currency_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCurrencyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCurrencyResponse) ProtoMessage() {}
+
+func (x *GetCurrencyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_gobcv_v1_currency_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCurrencyResponse.ProtoReflect.Descriptor instead.
+func (*GetCurrencyResponse) Descriptor() ([]byte, []int) {
+	return file_api_gobcv_v1_currency_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetCurrencyResponse) GetCurrency() *Currency {
+	if x != nil {
+		return x.Currency
+	}
+	return nil
+}
+
+func (x *GetCurrencyResponse) GetFromCache() bool {
+	if x != nil {
+		return x.FromCache
+	}
+	return false
+}
+
+func (x *GetCurrencyResponse) GetStale() bool {
+	if x != nil {
+		return x.Stale
+	}
+	return false
+}
+
+func (x *GetCurrencyResponse) GetAgeSeconds() int64 {
+	if x != nil {
+		return x.AgeSeconds
+	}
+	return 0
+}
+
+// ListCurrenciesRequest solicita todas las monedas.
+type ListCurrenciesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SkipCache     bool                   `protobuf:"varint,1,opt,name=skip_cache,json=skipCache,proto3" json:"skip_cache,omitempty"`
+	IncludeStale  bool                   `protobuf:"varint,2,opt,name=include_stale,json=includeStale,proto3" json:"include_stale,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCurrenciesRequest) Reset() {
+	*x = ListCurrenciesRequest{}
+	mi := &file_api_gobcv_v1_currency_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCurrenciesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCurrenciesRequest) ProtoMessage() {}
+
+func (x *ListCurrenciesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_gobcv_v1_currency_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCurrenciesRequest.ProtoReflect.Descriptor instead.
+func (*ListCurrenciesRequest) Descriptor() ([]byte, []int) {
+	return file_api_gobcv_v1_currency_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListCurrenciesRequest) GetSkipCache() bool {
+	if x != nil {
+		return x.SkipCache
+	}
+	return false
+}
+
+func (x *ListCurrenciesRequest) GetIncludeStale() bool {
+	if x != nil {
+		return x.IncludeStale
+	}
+	return false
+}
+
+// ListCurrenciesResponse contiene el listado de monedas.
+type ListCurrenciesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Currencies    []*Currency            `protobuf:"bytes,1,rep,name=currencies,proto3" json:"currencies,omitempty"`
+	Count         int32                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	FromCache     bool                   `protobuf:"varint,3,opt,name=from_cache,json=fromCache,proto3" json:"from_cache,omitempty"`
+	Stale         bool                   `protobuf:"varint,4,opt,name=stale,proto3" json:"stale,omitempty"`
+	AgeSeconds    int64                  `protobuf:"varint,5,opt,name=age_seconds,json=ageSeconds,proto3" json:"age_seconds,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCurrenciesResponse) Reset() {
+	*x = ListCurrenciesResponse{}
+	mi := &file_api_gobcv_v1_currency_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCurrenciesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCurrenciesResponse) ProtoMessage() {}
+
+func (x *ListCurrenciesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_gobcv_v1_currency_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCurrenciesResponse.ProtoReflect.Descriptor instead.
+func (*ListCurrenciesResponse) Descriptor() ([]byte, []int) {
+	return file_api_gobcv_v1_currency_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListCurrenciesResponse) GetCurrencies() []*Currency {
+	if x != nil {
+		return x.Currencies
+	}
+	return nil
+}
+
+func (x *ListCurrenciesResponse) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *ListCurrenciesResponse) GetFromCache() bool {
+	if x != nil {
+		return x.FromCache
+	}
+	return false
+}
+
+func (x *ListCurrenciesResponse) GetStale() bool {
+	if x != nil {
+		return x.Stale
+	}
+	return false
+}
+
+func (x *ListCurrenciesResponse) GetAgeSeconds() int64 {
+	if x != nil {
+		return x.AgeSeconds
+	}
+	return 0
+}
+
+// ConvertRequest solicita convertir amount de from a to. to vacío equivale a VES.
+type ConvertRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	From          string                 `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	To            string                 `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	Amount        float64                `protobuf:"fixed64,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	SkipCache     bool                   `protobuf:"varint,4,opt,name=skip_cache,json=skipCache,proto3" json:"skip_cache,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConvertRequest) Reset() {
+	*x = ConvertRequest{}
+	mi := &file_api_gobcv_v1_currency_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConvertRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConvertRequest) ProtoMessage() {}
+
+func (x *ConvertRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_gobcv_v1_currency_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConvertRequest.ProtoReflect.Descriptor instead.
+func (*ConvertRequest) Descriptor() ([]byte, []int) {
+	return file_api_gobcv_v1_currency_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ConvertRequest) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *ConvertRequest) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+func (x *ConvertRequest) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *ConvertRequest) GetSkipCache() bool {
+	if x != nil {
+		return x.SkipCache
+	}
+	return false
+}
+
+// ConvertResponse contiene el resultado de la conversión.
+type ConvertResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	From          string                 `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	To            string                 `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	Amount        float64                `protobuf:"fixed64,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	Result        float64                `protobuf:"fixed64,4,opt,name=result,proto3" json:"result,omitempty"`
+	Rate          float64                `protobuf:"fixed64,5,opt,name=rate,proto3" json:"rate,omitempty"`
+	RatesAt       *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=rates_at,json=ratesAt,proto3" json:"rates_at,omitempty"`
+	FromCache     bool                   `protobuf:"varint,7,opt,name=from_cache,json=fromCache,proto3" json:"from_cache,omitempty"`
+	Stale         bool                   `protobuf:"varint,8,opt,name=stale,proto3" json:"stale,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConvertResponse) Reset() {
+	*x = ConvertResponse{}
+	mi := &file_api_gobcv_v1_currency_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConvertResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConvertResponse) ProtoMessage() {}
+
+func (x *ConvertResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_gobcv_v1_currency_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConvertResponse.ProtoReflect.Descriptor instead.
+func (*ConvertResponse) Descriptor() ([]byte, []int) {
+	return file_api_gobcv_v1_currency_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ConvertResponse) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *ConvertResponse) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+func (x *ConvertResponse) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *ConvertResponse) GetResult() float64 {
+	if x != nil {
+		return x.Result
+	}
+	return 0
+}
+
+func (x *ConvertResponse) GetRate() float64 {
+	if x != nil {
+		return x.Rate
+	}
+	return 0
+}
+
+func (x *ConvertResponse) GetRatesAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RatesAt
+	}
+	return nil
+}
+
+func (x *ConvertResponse) GetFromCache() bool {
+	if x != nil {
+		return x.FromCache
+	}
+	return false
+}
+
+func (x *ConvertResponse) GetStale() bool {
+	if x != nil {
+		return x.Stale
+	}
+	return false
+}
+
+// GetHistoryRequest solicita los cambios de una moneda en el rango [from, to].
+// from vacío no limita el inicio y to vacío equivale al momento actual.
+type GetHistoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CurrencyId    string                 `protobuf:"bytes,1,opt,name=currency_id,json=currencyId,proto3" json:"currency_id,omitempty"`
+	From          *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
+	To            *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetHistoryRequest) Reset() {
+	*x = GetHistoryRequest{}
+	mi := &file_api_gobcv_v1_currency_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetHistoryRequest) ProtoMessage() {}
+
+func (x *GetHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_gobcv_v1_currency_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetHistoryRequest.ProtoReflect.Descriptor instead.
+func (*GetHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_api_gobcv_v1_currency_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetHistoryRequest) GetCurrencyId() string {
+	if x != nil {
+		return x.CurrencyId
+	}
+	return ""
+}
+
+func (x *GetHistoryRequest) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *GetHistoryRequest) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+// GetHistoryResponse contiene los cambios registrados en el rango.
+type GetHistoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CurrencyId    string                 `protobuf:"bytes,1,opt,name=currency_id,json=currencyId,proto3" json:"currency_id,omitempty"`
+	From          *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
+	To            *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`
+	Changes       []*RateChange          `protobuf:"bytes,4,rep,name=changes,proto3" json:"changes,omitempty"`
+	Count         int32                  `protobuf:"varint,5,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetHistoryResponse) Reset() {
+	*x = GetHistoryResponse{}
+	mi := &file_api_gobcv_v1_currency_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetHistoryResponse) ProtoMessage() {}
+
+func (x *GetHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_gobcv_v1_currency_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetHistoryResponse.ProtoReflect.Descriptor instead.
+func (*GetHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_api_gobcv_v1_currency_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetHistoryResponse) GetCurrencyId() string {
+	if x != nil {
+		return x.CurrencyId
+	}
+	return ""
+}
+
+func (x *GetHistoryResponse) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *GetHistoryResponse) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *GetHistoryResponse) GetChanges() []*RateChange {
+	if x != nil {
+		return x.Changes
+	}
+	return nil
+}
+
+func (x *GetHistoryResponse) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+// WatchRatesRequest abre una suscripción a los cambios de las monedas indicadas
+// (todas si está vacía). since_sequence reanuda desde el historial y snapshot envía
+// primero el valor actual de las monedas cuando no se reanuda.
+type WatchRatesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Currencies    []string               `protobuf:"bytes,1,rep,name=currencies,proto3" json:"currencies,omitempty"`
+	SinceSequence uint64                 `protobuf:"varint,2,opt,name=since_sequence,json=sinceSequence,proto3" json:"since_sequence,omitempty"`
+	Snapshot      bool                   `protobuf:"varint,3,opt,name=snapshot,proto3" json:"snapshot,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchRatesRequest) Reset() {
+	*x = WatchRatesRequest{}
+	mi := &file_api_gobcv_v1_currency_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchRatesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchRatesRequest) ProtoMessage() {}
+
+func (x *WatchRatesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_gobcv_v1_currency_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchRatesRequest.ProtoReflect.Descriptor instead.
+func (*WatchRatesRequest) Descriptor() ([]byte, []int) {
+	return file_api_gobcv_v1_currency_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *WatchRatesRequest) GetCurrencies() []string {
+	if x != nil {
+		return x.Currencies
+	}
+	return nil
+}
+
+func (x *WatchRatesRequest) GetSinceSequence() uint64 {
+	if x != nil {
+		return x.SinceSequence
+	}
+	return 0
+}
+
+func (x *WatchRatesRequest) GetSnapshot() bool {
+	if x != nil {
+		return x.Snapshot
+	}
+	return false
+}
+
+// RateEvent es un mensaje de WatchRates: un cambio de tasa (type "rate") o el
+// snapshot inicial (type "snapshot").
+type RateEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Change        *RateChange            `protobuf:"bytes,2,opt,name=change,proto3" json:"change,omitempty"`
+	Currencies    []*Currency            `protobuf:"bytes,3,rep,name=currencies,proto3" json:"currencies,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RateEvent) Reset() {
+	*x = RateEvent{}
+	mi := &file_api_gobcv_v1_currency_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RateEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RateEvent) ProtoMessage() {}
+
+func (x *RateEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_api_gobcv_v1_currency_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RateEvent.ProtoReflect.Descriptor instead.
+func (*RateEvent) Descriptor() ([]byte, []int) {
+	return file_api_gobcv_v1_currency_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *RateEvent) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *RateEvent) GetChange() *RateChange {
+	if x != nil {
+		return x.Change
+	}
+	return nil
+}
+
+func (x *RateEvent) GetCurrencies() []*Currency {
+	if x != nil {
+		return x.Currencies
+	}
+	return nil
+}
+
+var File_api_gobcv_v1_currency_proto protoreflect.FileDescriptor
+
+const file_api_gobcv_v1_currency_proto_rawDesc = "" +
+	"\n" +
+	"\x1bapi/gobcv/v1/currency.proto\x12\bgobcv.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\x97\x01\n" +
+	"\bCurrency\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
+	"\x05value\x18\x03 \x01(\x01R\x05value\x129\n" +
+	"\n" +
+	"updated_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\x16\n" +
+	"\x06source\x18\x05 \x01(\tR\x06source\"\xed\x01\n" +
+	"\n" +
+	"RateChange\x12\x1a\n" +
+	"\bsequence\x18\x01 \x01(\x04R\bsequence\x12\x1f\n" +
+	"\vcurrency_id\x18\x02 \x01(\tR\n" +
+	"currencyId\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x14\n" +
+	"\x05value\x18\x04 \x01(\x01R\x05value\x12%\n" +
+	"\x0eprevious_value\x18\x05 \x01(\x01R\rpreviousValue\x12\x16\n" +
+	"\x06source\x18\x06 \x01(\tR\x06source\x129\n" +
+	"\n" +
+	"changed_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tchangedAt\"T\n" +
+	"\x12GetCurrencyRequest\x12\x1f\n" +
+	"\vcurrency_id\x18\x01 \x01(\tR\n" +
+	"currencyId\x12\x1d\n" +
+	"\n" +
+	"skip_cache\x18\x02 \x01(\bR\tskipCache\"\x9b\x01\n" +
+	"\x13GetCurrencyResponse\x12.\n" +
+	"\bcurrency\x18\x01 \x01(\v2\x12.gobcv.v1.CurrencyR\bcurrency\x12\x1d\n" +
+	"\n" +
+	"from_cache\x18\x02 \x01(\bR\tfromCache\x12\x14\n" +
+	"\x05stale\x18\x03 \x01(\bR\x05stale\x12\x1f\n" +
+	"\vage_seconds\x18\x04 \x01(\x03R\n" +
+	"ageSeconds\"[\n" +
+	"\x15ListCurrenciesRequest\x12\x1d\n" +
+	"\n" +
+	"skip_cache\x18\x01 \x01(\bR\tskipCache\x12#\n" +
+	"\rinclude_stale\x18\x02 \x01(\bR\fincludeStale\"\xb8\x01\n" +
+	"\x16ListCurrenciesResponse\x122\n" +
+	"\n" +
+	"currencies\x18\x01 \x03(\v2\x12.gobcv.v1.CurrencyR\n" +
+	"currencies\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x05R\x05count\x12\x1d\n" +
+	"\n" +
+	"from_cache\x18\x03 \x01(\bR\tfromCache\x12\x14\n" +
+	"\x05stale\x18\x04 \x01(\bR\x05stale\x12\x1f\n" +
+	"\vage_seconds\x18\x05 \x01(\x03R\n" +
+	"ageSeconds\"k\n" +
+	"\x0eConvertRequest\x12\x12\n" +
+	"\x04from\x18\x01 \x01(\tR\x04from\x12\x0e\n" +
+	"\x02to\x18\x02 \x01(\tR\x02to\x12\x16\n" +
+	"\x06amount\x18\x03 \x01(\x01R\x06amount\x12\x1d\n" +
+	"\n" +
+	"skip_cache\x18\x04 \x01(\bR\tskipCache\"\xe5\x01\n" +
+	"\x0fConvertResponse\x12\x12\n" +
+	"\x04from\x18\x01 \x01(\tR\x04from\x12\x0e\n" +
+	"\x02to\x18\x02 \x01(\tR\x02to\x12\x16\n" +
+	"\x06amount\x18\x03 \x01(\x01R\x06amount\x12\x16\n" +
+	"\x06result\x18\x04 \x01(\x01R\x06result\x12\x12\n" +
+	"\x04rate\x18\x05 \x01(\x01R\x04rate\x125\n" +
+	"\brates_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\aratesAt\x12\x1d\n" +
+	"\n" +
+	"from_cache\x18\a \x01(\bR\tfromCache\x12\x14\n" +
+	"\x05stale\x18\b \x01(\bR\x05stale\"\x90\x01\n" +
+	"\x11GetHistoryRequest\x12\x1f\n" +
+	"\vcurrency_id\x18\x01 \x01(\tR\n" +
+	"currencyId\x12.\n" +
+	"\x04from\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x04from\x12*\n" +
+	"\x02to\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x02to\"\xd7\x01\n" +
+	"\x12GetHistoryResponse\x12\x1f\n" +
+	"\vcurrency_id\x18\x01 \x01(\tR\n" +
+	"currencyId\x12.\n" +
+	"\x04from\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x04from\x12*\n" +
+	"\x02to\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x02to\x12.\n" +
+	"\achanges\x18\x04 \x03(\v2\x14.gobcv.v1.RateChangeR\achanges\x12\x14\n" +
+	"\x05count\x18\x05 \x01(\x05R\x05count\"v\n" +
+	"\x11WatchRatesRequest\x12\x1e\n" +
+	"\n" +
+	"currencies\x18\x01 \x03(\tR\n" +
+	"currencies\x12%\n" +
+	"\x0esince_sequence\x18\x02 \x01(\x04R\rsinceSequence\x12\x1a\n" +
+	"\bsnapshot\x18\x03 \x01(\bR\bsnapshot\"\x81\x01\n" +
+	"\tRateEvent\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12,\n" +
+	"\x06change\x18\x02 \x01(\v2\x14.gobcv.v1.RateChangeR\x06change\x122\n" +
+	"\n" +
+	"currencies\x18\x03 \x03(\v2\x12.gobcv.v1.CurrencyR\n" +
+	"currencies2\xfd\x02\n" +
+	"\x0fCurrencyService\x12J\n" +
+	"\vGetCurrency\x12\x1c.gobcv.v1.GetCurrencyRequest\x1a\x1d.gobcv.v1.GetCurrencyResponse\x12S\n" +
+	"\x0eListCurrencies\x12\x1f.gobcv.v1.ListCurrenciesRequest\x1a .gobcv.v1.ListCurrenciesResponse\x12>\n" +
+	"\aConvert\x12\x18.gobcv.v1.ConvertRequest\x1a\x19.gobcv.v1.ConvertResponse\x12G\n" +
+	"\n" +
+	"GetHistory\x12\x1b.gobcv.v1.GetHistoryRequest\x1a\x1c.gobcv.v1.GetHistoryResponse\x12@\n" +
+	"\n" +
+	"WatchRates\x12\x1b.gobcv.v1.WatchRatesRequest\x1a\x13.gobcv.v1.RateEvent0\x01B\x1cZ\x1agobcv/api/gobcv/v1;gobcvv1b\x06proto3"
+
+var (
+	file_api_gobcv_v1_currency_proto_rawDescOnce sync.Once
+	file_api_gobcv_v1_currency_proto_rawDescData []byte
+)
+
+func file_api_gobcv_v1_currency_proto_rawDescGZIP() []byte {
+	file_api_gobcv_v1_currency_proto_rawDescOnce.Do(func() {
+		file_api_gobcv_v1_currency_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_api_gobcv_v1_currency_proto_rawDesc), len(file_api_gobcv_v1_currency_proto_rawDesc)))
+	})
+	return file_api_gobcv_v1_currency_proto_rawDescData
+}
+
+var file_api_gobcv_v1_currency_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_api_gobcv_v1_currency_proto_goTypes = []any{
+	(*Currency)(nil),               // 0: gobcv.v1.Currency
+	(*RateChange)(nil),             // 1: gobcv.v1.RateChange
+	(*GetCurrencyRequest)(nil),     // 2: gobcv.v1.GetCurrencyRequest
+	(*GetCurrencyResponse)(nil),    // 3: gobcv.v1.GetCurrencyResponse
+	(*ListCurrenciesRequest)(nil),  // 4: gobcv.v1.ListCurrenciesRequest
+	(*ListCurrenciesResponse)(nil), // 5: gobcv.v1.ListCurrenciesResponse
+	(*ConvertRequest)(nil),         // 6: gobcv.v1.ConvertRequest
+	(*ConvertResponse)(nil),        // 7: gobcv.v1.ConvertResponse
+	(*GetHistoryRequest)(nil),      // 8: gobcv.v1.GetHistoryRequest
+	(*GetHistoryResponse)(nil),     // 9: gobcv.v1.GetHistoryResponse
+	(*WatchRatesRequest)(nil),      // 10: gobcv.v1.WatchRatesRequest
+	(*RateEvent)(nil),              // 11: gobcv.v1.RateEvent
+	(*timestamppb.Timestamp)(nil),  // 12: google.protobuf.Timestamp
+}
+var file_api_gobcv_v1_currency_proto_depIdxs = []int32{
+	12, // 0: gobcv.v1.Currency.updated_at:type_name -> google.protobuf.Timestamp
+	12, // 1: gobcv.v1.RateChange.changed_at:type_name -> google.protobuf.Timestamp
+	0,  // 2: gobcv.v1.GetCurrencyResponse.currency:type_name -> gobcv.v1.Currency
+	0,  // 3: gobcv.v1.ListCurrenciesResponse.currencies:type_name -> gobcv.v1.Currency
+	12, // 4: gobcv.v1.ConvertResponse.rates_at:type_name -> google.protobuf.Timestamp
+	12, // 5: gobcv.v1.GetHistoryRequest.from:type_name -> google.protobuf.Timestamp
+	12, // 6: gobcv.v1.GetHistoryRequest.to:type_name -> google.protobuf.Timestamp
+	12, // 7: gobcv.v1.GetHistoryResponse.from:type_name -> google.protobuf.Timestamp
+	12, // 8: gobcv.v1.GetHistoryResponse.to:type_name -> google.protobuf.Timestamp
+	1,  // 9: gobcv.v1.GetHistoryResponse.changes:type_name -> gobcv.v1.RateChange
+	1,  // 10: gobcv.v1.RateEvent.change:type_name -> gobcv.v1.RateChange
+	0,  // 11: gobcv.v1.RateEvent.currencies:type_name -> gobcv.v1.Currency
+	2,  // 12: gobcv.v1.CurrencyService.GetCurrency:input_type -> gobcv.v1.GetCurrencyRequest
+	4,  // 13: gobcv.v1.CurrencyService.ListCurrencies:input_type -> gobcv.v1.ListCurrenciesRequest
+	6,  // 14: gobcv.v1.CurrencyService.Convert:input_type -> gobcv.v1.ConvertRequest
+	8,  // 15: gobcv.v1.CurrencyService.GetHistory:input_type -> gobcv.v1.GetHistoryRequest
+	10, // 16: gobcv.v1.CurrencyService.WatchRates:input_type -> gobcv.v1.WatchRatesRequest
+	3,  // 17: gobcv.v1.CurrencyService.GetCurrency:output_type -> gobcv.v1.GetCurrencyResponse
+	5,  // 18: gobcv.v1.CurrencyService.ListCurrencies:output_type -> gobcv.v1.ListCurrenciesResponse
+	7,  // 19: gobcv.v1.CurrencyService.Convert:output_type -> gobcv.v1.ConvertResponse
+	9,  // 20: gobcv.v1.CurrencyService.GetHistory:output_type -> gobcv.v1.GetHistoryResponse
+	11, // 21: gobcv.v1.CurrencyService.WatchRates:output_type -> gobcv.v1.RateEvent
+	17, // [17:22] is the sub-list for method output_type
+	12, // [12:17] is the sub-list for method input_type
+	12, // [12:12] is the sub-list for extension type_name
+	12, // [12:12] is the sub-list for extension extendee
+	0,  // [0:12] is the sub-list for field type_name
+}
+
+func init() { file_api_gobcv_v1_currency_proto_init() }
+func file_api_gobcv_v1_currency_proto_init() {
+	if File_api_gobcv_v1_currency_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_gobcv_v1_currency_proto_rawDesc), len(file_api_gobcv_v1_currency_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   12,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_api_gobcv_v1_currency_proto_goTypes,
+		DependencyIndexes: file_api_gobcv_v1_currency_proto_depIdxs,
+		MessageInfos:      file_api_gobcv_v1_currency_proto_msgTypes,
+	}.Build()
+	File_api_gobcv_v1_currency_proto = out.File
+	file_api_gobcv_v1_currency_proto_goTypes = nil
+	file_api_gobcv_v1_currency_proto_depIdxs = nil
+}
@@ -0,0 +1,402 @@
+// Package main implementa la línea de comandos gobcv, que consulta las tasas del BCV
+// desde una API en ejecución o desde una base SQLite local, y también inicia el servidor.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"gobcv/internal/infrastructure/logging"
+	"gobcv/internal/server"
+	"gobcv/pkg/bcv"
+	"gobcv/pkg/config"
+)
+
+const usage = `Uso: gobcv <comando> [opciones] [argumentos]
+
+Comandos:
+  scrape              obtiene las tasas del BCV una vez y las muestra
+  get [MONEDA...]     muestra las tasas vigentes (todas si no se indica ninguna)
+  convert MONTO DE [A]
+                      convierte un monto entre monedas (A es VES por defecto)
+  history MONEDA      muestra los cambios de una moneda
+  serve               inicia el servidor de la API
+  config print        muestra la configuración efectiva del servidor
+  config validate     verifica la configuración del servidor
+
+Sin -api los comandos usan la base SQLite local (-db, por defecto DB_PATH o gobcv.db)
+y la llenan desde el BCV la primera vez. Ejecute "gobcv <comando> -h" para ver sus opciones.
+`
+
+// errUsage indica argumentos inválidos; el mensaje de uso ya se mostró.
+var errUsage = errors.New("invalid usage")
+
+// commandTimeout limita la duración de los comandos de consulta.
+const commandTimeout = 60 * time.Second
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	// Los registros de la aplicación solo interesan al depurar
+	logger, err := logging.New(os.Stderr, logging.FormatText, getEnvOrDefault("GOBCV_LOG_LEVEL", "warn"))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(2)
+	}
+	slog.SetDefault(logger)
+
+	command, args := os.Args[1], os.Args[2:]
+
+	var run func(ctx context.Context, args []string, stdout io.Writer) error
+	switch command {
+	case "scrape":
+		run = runScrape
+	case "get":
+		run = runGet
+	case "convert":
+		run = runConvert
+	case "history":
+		run = runHistory
+	case "config":
+		run = runConfig
+	case "serve":
+		os.Exit(runServe(args))
+	case "help", "-h", "-help", "--help":
+		fmt.Fprint(os.Stdout, usage)
+		return
+	default:
+		fmt.Fprintf(os.Stderr, "error: unknown command %q\n\n%s", command, usage)
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	ctx, cancel := context.WithTimeout(ctx, commandTimeout)
+	err = run(ctx, args, os.Stdout)
+	cancel()
+	stop()
+
+	switch {
+	case errors.Is(err, flag.ErrHelp):
+		return
+	case errors.Is(err, errUsage):
+		os.Exit(2)
+	case err != nil:
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// runScrape obtiene las tasas del BCV, o de la página guardada con -file, sin pasar por
+// el caché ni la base. Con -save las guarda además en la base local.
+func runScrape(ctx context.Context, args []string, stdout io.Writer) error {
+	fs := newFlagSet("scrape", "")
+	file := fs.String("file", "", "página del BCV guardada en disco, para obtener las tasas sin conexión")
+	save := fs.Bool("save", false, "guardar las tasas en la base SQLite local")
+	db := fs.String("db", defaultDatabasePath(), "archivo de la base SQLite local (con -save)")
+	format := fs.String("format", formatTable, "formato de salida: table, json o csv")
+
+	if _, err := parseArgs(fs, args, 0, 0); err != nil {
+		return err
+	}
+	if err := checkFormat(*format); err != nil {
+		return err
+	}
+
+	if *save {
+		local, err := openLocalSource(ctx, *db, *file)
+		if err != nil {
+			return err
+		}
+		defer local.Close()
+
+		if _, err := local.Service.Refresh(ctx, true); err != nil {
+			return err
+		}
+		currencies, err := local.Latest(ctx)
+		if err != nil {
+			return err
+		}
+		return printCurrencies(stdout, *format, currencies)
+	}
+
+	scraper := bcv.NewBCVScraper()
+	if *file != "" {
+		var err error
+		if scraper, err = bcv.NewFileScraper(*file); err != nil {
+			return err
+		}
+	}
+
+	currencies, err := scraper.ScrapeCurrencies(ctx)
+	if err != nil {
+		return err
+	}
+	return printCurrencies(stdout, *format, currencies)
+}
+
+// runGet muestra las monedas indicadas o todas las vigentes.
+func runGet(ctx context.Context, args []string, stdout io.Writer) error {
+	fs := newFlagSet("get", "[MONEDA...]")
+	var sf sourceFlags
+	sf.register(fs)
+	format := fs.String("format", formatTable, "formato de salida: table, json o csv")
+
+	ids, err := parseArgs(fs, args, 0, -1)
+	if err != nil {
+		return err
+	}
+	if err := checkFormat(*format); err != nil {
+		return err
+	}
+
+	src, err := sf.open(ctx)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	var currencies []*bcv.Currency
+	if len(ids) == 0 {
+		if currencies, err = src.Currencies(ctx); err != nil {
+			return err
+		}
+	}
+	for _, id := range ids {
+		currency, err := src.Currency(ctx, strings.ToUpper(id))
+		if err != nil {
+			return err
+		}
+		currencies = append(currencies, currency)
+	}
+
+	return printCurrencies(stdout, *format, currencies)
+}
+
+// runConvert convierte un monto entre dos monedas.
+func runConvert(ctx context.Context, args []string, stdout io.Writer) error {
+	fs := newFlagSet("convert", "MONTO DE [A]")
+	var sf sourceFlags
+	sf.register(fs)
+	format := fs.String("format", formatTable, "formato de salida: table, json o csv")
+
+	positional, err := parseArgs(fs, args, 2, 3)
+	if err != nil {
+		return err
+	}
+	if err := checkFormat(*format); err != nil {
+		return err
+	}
+
+	amount, err := strconv.ParseFloat(positional[0], 64)
+	if err != nil {
+		return fmt.Errorf("invalid amount %q", positional[0])
+	}
+	from, to := strings.ToUpper(positional[1]), "VES"
+	if len(positional) == 3 {
+		to = strings.ToUpper(positional[2])
+	}
+
+	src, err := sf.open(ctx)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	conversion, err := src.Convert(ctx, from, to, amount)
+	if err != nil {
+		return err
+	}
+	return printConversion(stdout, *format, conversion)
+}
+
+// runHistory muestra los cambios de una moneda en un rango de fechas.
+func runHistory(ctx context.Context, args []string, stdout io.Writer) error {
+	fs := newFlagSet("history", "MONEDA")
+	var sf sourceFlags
+	sf.register(fs)
+	from := fs.String("from", "", "inicio del rango: fecha (2006-01-02) o RFC 3339")
+	to := fs.String("to", "", "fin del rango: fecha (incluye el día completo) o RFC 3339; por defecto ahora")
+	format := fs.String("format", formatTable, "formato de salida: table, json o csv")
+
+	positional, err := parseArgs(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	if err := checkFormat(*format); err != nil {
+		return err
+	}
+
+	fromTime, err := parseTime(*from, false)
+	if err != nil {
+		return fmt.Errorf("invalid -from: %w", err)
+	}
+	toTime, err := parseTime(*to, true)
+	if err != nil {
+		return fmt.Errorf("invalid -to: %w", err)
+	}
+
+	src, err := sf.open(ctx)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	changes, err := src.History(ctx, strings.ToUpper(positional[0]), fromTime, toTime)
+	if err != nil {
+		return err
+	}
+	return printHistory(stdout, *format, changes)
+}
+
+// runServe inicia el servidor con la configuración del archivo y del entorno; las
+// opciones tienen prioridad sobre ambos. Retorna el código de salida.
+func runServe(args []string) int {
+	fs := newFlagSet("serve", "")
+	var opts config.Options
+	opts.RegisterFlags(fs)
+	host := fs.String("host", "", "dirección en la que escucha el servidor (server.host)")
+	port := fs.String("port", "", "puerto HTTP (server.port)")
+	db := fs.String("db", "", "guardar monedas e historial en esta base SQLite (database.type=sqlite)")
+
+	if _, err := parseArgs(fs, args, 0, 0); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+
+	if *host != "" {
+		opts.Overrides = append(opts.Overrides, "server.host="+*host)
+	}
+	if *port != "" {
+		opts.Overrides = append(opts.Overrides, "server.port="+*port)
+	}
+	if *db != "" {
+		opts.Overrides = append(opts.Overrides, "database.type=sqlite", "database.path="+*db)
+	}
+
+	cfg, err := config.Load(opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		return 1
+	}
+
+	server.Run(cfg)
+	return 0
+}
+
+// runConfig muestra o verifica la configuración que usaría el servidor con las mismas
+// opciones -config y -set.
+func runConfig(ctx context.Context, args []string, stdout io.Writer) error {
+	if len(args) == 0 || args[0] != "print" && args[0] != "validate" {
+		fmt.Fprint(os.Stderr, "Uso: gobcv config print|validate [opciones]\n")
+		return errUsage
+	}
+	action := args[0]
+
+	fs := newFlagSet("config "+action, "")
+	var opts config.Options
+	opts.RegisterFlags(fs)
+	format := fs.String("format", config.FormatYAML, "formato de salida de print: yaml, json, toml o env")
+
+	if _, err := parseArgs(fs, args[1:], 0, 0); err != nil {
+		return err
+	}
+
+	cfg, err := config.Load(opts)
+	if err != nil {
+		return err
+	}
+
+	if action == "validate" {
+		_, err := fmt.Fprintln(stdout, "Configuración válida")
+		return err
+	}
+
+	output, err := cfg.Marshal(*format)
+	if err != nil {
+		return err
+	}
+	_, err = stdout.Write(output)
+	return err
+}
+
+// newFlagSet crea el conjunto de flags de un comando con su mensaje de uso.
+func newFlagSet(command, arguments string) *flag.FlagSet {
+	fs := flag.NewFlagSet(command, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Uso: gobcv %s [opciones] %s\n\nOpciones:\n", command, arguments)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parseArgs procesa las opciones, que pueden aparecer antes o después de los argumentos,
+// y verifica que la cantidad de argumentos esté entre minArgs y maxArgs (maxArgs < 0
+// no limita). Los errores ya se muestran junto con el uso del comando.
+func parseArgs(fs *flag.FlagSet, args []string, minArgs, maxArgs int) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return nil, err
+			}
+			return nil, errUsage
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			break
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+
+	if len(positional) < minArgs || maxArgs >= 0 && len(positional) > maxArgs {
+		fmt.Fprintf(fs.Output(), "gobcv %s: unexpected number of arguments\n", fs.Name())
+		fs.Usage()
+		return nil, errUsage
+	}
+	return positional, nil
+}
+
+// parseTime interpreta una fecha (2006-01-02, en hora local) o una fecha y hora RFC 3339.
+// Con endOfDay, una fecha sin hora corresponde al último instante de ese día.
+func parseTime(value string, endOfDay bool) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	t, err := time.ParseInLocation(time.DateOnly, value, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is neither a date (2006-01-02) nor an RFC 3339 time", value)
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	}
+	return t, nil
+}
+
+// getEnvOrDefault obtiene una variable de entorno o retorna un valor por defecto.
+func getEnvOrDefault(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
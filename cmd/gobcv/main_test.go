@@ -0,0 +1,87 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseArgs(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		min, max   int
+		positional []string
+		format     string
+		err        error
+	}{
+		{"flags before arguments", []string{"-format", "csv", "USD", "EUR"}, 0, -1, []string{"USD", "EUR"}, "csv", nil},
+		{"flags after arguments", []string{"USD", "-format", "json", "EUR"}, 0, -1, []string{"USD", "EUR"}, "json", nil},
+		{"flags only", []string{"-format=csv"}, 0, 0, nil, "csv", nil},
+		{"after --", []string{"--", "-1"}, 1, 1, []string{"-1"}, "table", nil},
+		{"too few arguments", []string{"100"}, 2, 3, nil, "", errUsage},
+		{"too many arguments", []string{"USD", "EUR"}, 1, 1, nil, "", errUsage},
+		{"unknown flag", []string{"-nope"}, 0, -1, nil, "", errUsage},
+		{"help", []string{"-h"}, 0, -1, nil, "", flag.ErrHelp},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := newFlagSet("test", "")
+			fs.SetOutput(io.Discard)
+			format := fs.String("format", formatTable, "")
+
+			positional, err := parseArgs(fs, tt.args, tt.min, tt.max)
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Errorf("err = %v, want %v", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseArgs: %v", err)
+			}
+			if !reflect.DeepEqual(positional, tt.positional) || *format != tt.format {
+				t.Errorf("got %q with -format %s, want %q with -format %s", positional, *format, tt.positional, tt.format)
+			}
+		})
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		endOfDay bool
+		want     time.Time
+		err      bool
+	}{
+		{"empty", "", false, time.Time{}, false},
+		{"empty end", "", true, time.Time{}, false},
+		{"date", "2026-10-12", false, time.Date(2026, 10, 12, 0, 0, 0, 0, time.Local), false},
+		{"date end of day", "2026-10-12", true, time.Date(2026, 10, 12, 23, 59, 59, 999999999, time.Local), false},
+		{"rfc 3339", "2026-10-12T08:30:00-04:00", true, time.Date(2026, 10, 12, 12, 30, 0, 0, time.UTC), false},
+		{"invalid", "12/10/2026", false, time.Time{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseTime(tt.value, tt.endOfDay)
+			if tt.err {
+				if err == nil {
+					t.Errorf("parseTime(%q) = %v, want an error", tt.value, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseTime(%q): %v", tt.value, err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("parseTime(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
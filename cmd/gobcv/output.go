@@ -0,0 +1,130 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"gobcv/pkg/bcv"
+)
+
+// Formatos de salida de los comandos.
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+	formatCSV   = "csv"
+)
+
+// tableTimeLayout es el formato de las fechas en la salida de tabla, en hora local.
+const tableTimeLayout = "2006-01-02 15:04:05"
+
+// checkFormat verifica que el formato de salida sea uno de los soportados.
+func checkFormat(format string) error {
+	switch format {
+	case formatTable, formatJSON, formatCSV:
+		return nil
+	default:
+		return fmt.Errorf("unknown format %q: must be %s, %s or %s", format, formatTable, formatJSON, formatCSV)
+	}
+}
+
+// printCurrencies escribe las monedas ordenadas por código.
+func printCurrencies(w io.Writer, format string, currencies []*bcv.Currency) error {
+	sort.Slice(currencies, func(i, j int) bool { return currencies[i].ID < currencies[j].ID })
+
+	switch format {
+	case formatJSON:
+		return writeJSON(w, currencies)
+	case formatCSV:
+		rows := [][]string{{"id", "name", "value", "updated_at", "source"}}
+		for _, c := range currencies {
+			rows = append(rows, []string{c.ID, c.Name, formatFloat(c.Value), c.UpdatedAt.Format(time.RFC3339), c.Source})
+		}
+		return writeCSV(w, rows)
+	default:
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "MONEDA\tNOMBRE\tVALOR (VES)\tACTUALIZADA")
+		for _, c := range currencies {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", c.ID, c.Name, formatFloat(c.Value), c.UpdatedAt.Local().Format(tableTimeLayout))
+		}
+		return tw.Flush()
+	}
+}
+
+// printConversion escribe el resultado de una conversión.
+func printConversion(w io.Writer, format string, conversion *bcv.Conversion) error {
+	switch format {
+	case formatJSON:
+		return writeJSON(w, conversion)
+	case formatCSV:
+		return writeCSV(w, [][]string{
+			{"from", "to", "amount", "result", "rate", "rates_at", "stale"},
+			{conversion.From, conversion.To, formatFloat(conversion.Amount), formatFloat(conversion.Result),
+				formatFloat(conversion.Rate), conversion.RatesAt.Format(time.RFC3339), strconv.FormatBool(conversion.Stale)},
+		})
+	default:
+		stale := ""
+		if conversion.Stale {
+			stale = ", desactualizada"
+		}
+		_, err := fmt.Fprintf(w, "%s %s = %s %s (tasa %s del %s%s)\n",
+			formatFloat(conversion.Amount), conversion.From, formatFloat(conversion.Result), conversion.To,
+			formatFloat(conversion.Rate), conversion.RatesAt.Local().Format(tableTimeLayout), stale)
+		return err
+	}
+}
+
+// printHistory escribe los cambios de una moneda en orden cronológico.
+func printHistory(w io.Writer, format string, changes []*bcv.RateChange) error {
+	switch format {
+	case formatJSON:
+		if changes == nil {
+			changes = []*bcv.RateChange{}
+		}
+		return writeJSON(w, changes)
+	case formatCSV:
+		rows := [][]string{{"sequence", "currency_id", "value", "previous_value", "changed_at", "source"}}
+		for _, c := range changes {
+			rows = append(rows, []string{strconv.FormatUint(c.Sequence, 10), c.CurrencyID, formatFloat(c.Value),
+				formatFloat(c.PreviousValue), c.ChangedAt.Format(time.RFC3339), c.Source})
+		}
+		return writeCSV(w, rows)
+	default:
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "FECHA\tMONEDA\tVALOR (VES)\tANTERIOR")
+		for _, c := range changes {
+			previous := "-"
+			if c.PreviousValue != 0 {
+				previous = formatFloat(c.PreviousValue)
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", c.ChangedAt.Local().Format(tableTimeLayout), c.CurrencyID, formatFloat(c.Value), previous)
+		}
+		return tw.Flush()
+	}
+}
+
+// formatFloat escribe un número con todos sus decimales significativos.
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// writeJSON escribe v como JSON indentado.
+func writeJSON(w io.Writer, v interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// writeCSV escribe las filas como CSV.
+func writeCSV(w io.Writer, rows [][]string) error {
+	writer := csv.NewWriter(w)
+	if err := writer.WriteAll(rows); err != nil {
+		return fmt.Errorf("error writing csv: %w", err)
+	}
+	return nil
+}
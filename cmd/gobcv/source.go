@@ -0,0 +1,248 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"gobcv/internal/infrastructure/storage"
+	"gobcv/pkg/bcv"
+	"gobcv/pkg/client"
+)
+
+// source obtiene las monedas desde una API en ejecución o desde la base SQLite local.
+type source interface {
+	Currencies(ctx context.Context) ([]*bcv.Currency, error)
+	Currency(ctx context.Context, id string) (*bcv.Currency, error)
+	Convert(ctx context.Context, from, to string, amount float64) (*bcv.Conversion, error)
+	History(ctx context.Context, id string, from, to time.Time) ([]*bcv.RateChange, error)
+	Refresh(ctx context.Context) error
+	Close() error
+}
+
+// sourceFlags son las opciones comunes a los comandos que consultan monedas.
+type sourceFlags struct {
+	api     string
+	apiKey  string
+	db      string
+	file    string
+	refresh bool
+}
+
+// register agrega las opciones al conjunto de flags del comando.
+func (f *sourceFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.api, "api", os.Getenv("GOBCV_API_URL"), "URL de una API en ejecución; sin ella se usa la base SQLite local")
+	fs.StringVar(&f.apiKey, "api-key", os.Getenv("GOBCV_API_KEY"), "clave de API enviada en X-API-Key")
+	fs.StringVar(&f.db, "db", defaultDatabasePath(), "archivo de la base SQLite local")
+	fs.StringVar(&f.file, "file", "", "página del BCV guardada en disco para actualizar sin conexión (modo local)")
+	fs.BoolVar(&f.refresh, "refresh", false, "actualizar las monedas desde el BCV antes de consultar")
+}
+
+// open abre la fuente indicada por las opciones y la actualiza si se pidió.
+func (f *sourceFlags) open(ctx context.Context) (source, error) {
+	var src source
+	if f.api != "" {
+		if f.file != "" {
+			return nil, errors.New("-file only applies to the local database, not to -api")
+		}
+
+		apiClient, err := client.New(f.api, client.WithAPIKey(f.apiKey), client.WithUserAgent("gobcv-cli/1.0"))
+		if err != nil {
+			return nil, err
+		}
+		src = &apiSource{client: apiClient}
+	} else {
+		local, err := openLocalSource(ctx, f.db, f.file)
+		if err != nil {
+			return nil, err
+		}
+		src = local
+	}
+
+	if f.refresh {
+		if err := src.Refresh(ctx); err != nil {
+			src.Close()
+			return nil, fmt.Errorf("error refreshing currencies: %w", err)
+		}
+	}
+
+	return src, nil
+}
+
+// defaultDatabasePath retorna la base SQLite por defecto, la misma que usa el servidor
+// con DB_TYPE=sqlite.
+func defaultDatabasePath() string {
+	if path := os.Getenv("DB_PATH"); path != "" {
+		return path
+	}
+	return "gobcv.db"
+}
+
+// apiSource consulta una API en ejecución con el cliente Go.
+type apiSource struct {
+	client *client.Client
+}
+
+func (s *apiSource) Currencies(ctx context.Context) ([]*bcv.Currency, error) {
+	result, err := s.client.Currencies(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	currencies := make([]*bcv.Currency, 0, len(result.Currencies))
+	for _, currency := range result.Currencies {
+		currencies = append(currencies, fromClientCurrency(currency))
+	}
+	return currencies, nil
+}
+
+func (s *apiSource) Currency(ctx context.Context, id string) (*bcv.Currency, error) {
+	result, err := s.client.Currency(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return fromClientCurrency(result.Currency), nil
+}
+
+func (s *apiSource) Convert(ctx context.Context, from, to string, amount float64) (*bcv.Conversion, error) {
+	result, err := s.client.Convert(ctx, from, to, amount)
+	if err != nil {
+		return nil, err
+	}
+
+	return &bcv.Conversion{
+		From:    result.From,
+		To:      result.To,
+		Amount:  result.Amount,
+		Result:  result.Result,
+		Rate:    result.Rate,
+		RatesAt: result.RatesAt,
+		Stale:   result.Stale,
+	}, nil
+}
+
+func (s *apiSource) History(ctx context.Context, id string, from, to time.Time) ([]*bcv.RateChange, error) {
+	result, err := s.client.History(ctx, id, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make([]*bcv.RateChange, 0, len(result.Changes))
+	for _, change := range result.Changes {
+		changes = append(changes, &bcv.RateChange{
+			Sequence:      change.Sequence,
+			CurrencyID:    change.CurrencyID,
+			Name:          change.Name,
+			Value:         change.Value,
+			PreviousValue: change.PreviousValue,
+			Source:        change.Source,
+			ChangedAt:     change.ChangedAt,
+		})
+	}
+	return changes, nil
+}
+
+func (s *apiSource) Refresh(ctx context.Context) error {
+	result, err := s.client.Refresh(ctx, false)
+	if err != nil {
+		return err
+	}
+	if !result.Success {
+		return errors.New(result.Message)
+	}
+	return nil
+}
+
+func (s *apiSource) Close() error {
+	return nil
+}
+
+// fromClientCurrency convierte una moneda de la API al tipo del paquete bcv.
+func fromClientCurrency(currency *client.Currency) *bcv.Currency {
+	return &bcv.Currency{
+		ID:        currency.ID,
+		Name:      currency.Name,
+		Value:     currency.Value,
+		UpdatedAt: currency.UpdatedAt,
+		Source:    currency.Source,
+	}
+}
+
+// localSource consulta la base SQLite local con el servicio del paquete bcv. Si la base
+// está vacía o sus monedas son más antiguas que bcv.Freshness, las monedas se obtienen
+// del scraper en la primera consulta.
+type localSource struct {
+	*bcv.Service
+	db *sql.DB
+}
+
+// openLocalSource abre la base SQLite y crea el servicio sobre ella. Si file no está
+// vacío, las actualizaciones leen esa página en lugar del sitio web del BCV.
+func openLocalSource(ctx context.Context, path, file string) (*localSource, error) {
+	db, err := storage.OpenSQLite(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []bcv.Option{
+		bcv.WithRepository(storage.NewSQLiteRepository(db)),
+		bcv.WithHistory(storage.NewSQLiteRateHistory(db, 0)),
+	}
+	if file != "" {
+		fileScraper, err := bcv.NewFileScraper(file)
+		if err != nil {
+			db.Close()
+			return nil, err
+		}
+		opts = append(opts, bcv.WithScraper(fileScraper))
+	}
+
+	service, err := bcv.New(opts...)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &localSource{Service: service, db: db}, nil
+}
+
+func (s *localSource) Currencies(ctx context.Context) ([]*bcv.Currency, error) {
+	currencies, err := s.Latest(ctx)
+	return currencies, staleHint(err)
+}
+
+func (s *localSource) Currency(ctx context.Context, id string) (*bcv.Currency, error) {
+	currency, err := s.Get(ctx, id)
+	return currency, staleHint(err)
+}
+
+func (s *localSource) Convert(ctx context.Context, from, to string, amount float64) (*bcv.Conversion, error) {
+	conversion, err := s.Service.Convert(ctx, from, to, amount)
+	return conversion, staleHint(err)
+}
+
+func (s *localSource) Refresh(ctx context.Context) error {
+	_, err := s.Service.Refresh(ctx, false)
+	return err
+}
+
+// staleHint indica cómo actualizar la base local cuando sus monedas están obsoletas y
+// no se pudieron obtener del BCV.
+func staleHint(err error) error {
+	if errors.Is(err, bcv.ErrStale) {
+		return fmt.Errorf("%w; run again with -refresh once the BCV site is reachable, or with -file and a saved page", err)
+	}
+	return err
+}
+
+func (s *localSource) Close() error {
+	err := s.Service.Close()
+	if dbErr := s.db.Close(); err == nil {
+		err = dbErr
+	}
+	return err
+}
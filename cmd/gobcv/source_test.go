@@ -0,0 +1,84 @@
+//go:build cgo
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writePage guarda una página del BCV con las tasas indicadas.
+func writePage(t *testing.T, path string, euro, dolar string) {
+	t.Helper()
+
+	page := fmt.Sprintf(`<html><body>
+<div id="euro"><div class="centrado"><strong> %s </strong></div></div>
+<div id="dolar"><div class="centrado"><strong> %s </strong></div></div>
+</body></html>`, euro, dolar)
+	if err := os.WriteFile(path, []byte(page), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestHistoryCSVFromLocalDatabase(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	db, page := filepath.Join(dir, "gobcv.db"), filepath.Join(dir, "bcv.html")
+	t.Setenv("GOBCV_API_URL", "")
+
+	// Dos actualizaciones desde la página guardada: solo cambia el dólar
+	writePage(t, page, "40,00", "36,50")
+	if err := runScrape(ctx, []string{"-save", "-file", page, "-db", db}, &bytes.Buffer{}); err != nil {
+		t.Fatalf("scrape -save: %v", err)
+	}
+	writePage(t, page, "40,00", "36,75")
+	if err := runScrape(ctx, []string{"-save", "-file", page, "-db", db}, &bytes.Buffer{}); err != nil {
+		t.Fatalf("scrape -save: %v", err)
+	}
+
+	var output bytes.Buffer
+	if err := runHistory(ctx, []string{"usd", "-db", db, "-format", "csv"}, &output); err != nil {
+		t.Fatalf("history: %v", err)
+	}
+
+	rows, err := csv.NewReader(&output).ReadAll()
+	if err != nil {
+		t.Fatalf("reading csv: %v\n%s", err, output.String())
+	}
+	if len(rows) != 3 {
+		t.Fatalf("got %d rows, want header and two changes:\n%v", len(rows), rows)
+	}
+	if strings.Join(rows[0], ",") != "sequence,currency_id,value,previous_value,changed_at,source" {
+		t.Errorf("header = %v", rows[0])
+	}
+
+	// EUR y USD se registraron en la primera actualización; solo USD en la segunda
+	want := [][]string{{"2", "USD", "36.5", "0"}, {"3", "USD", "36.75", "36.5"}}
+	for i, row := range rows[1:] {
+		if strings.Join(row[:4], ",") != strings.Join(want[i], ",") {
+			t.Errorf("row %d = %v, want %v", i+1, row, want[i])
+		}
+		if !strings.HasPrefix(row[5], "file://") {
+			t.Errorf("row %d source = %q, want the saved page", i+1, row[5])
+		}
+	}
+
+	// Un rango anterior a los cambios solo tiene la cabecera
+	output.Reset()
+	if err := runHistory(ctx, []string{"USD", "-db", db, "-format", "csv", "-to", "2000-01-01"}, &output); err != nil {
+		t.Fatalf("history -to: %v", err)
+	}
+	if strings.TrimSpace(output.String()) != "sequence,currency_id,value,previous_value,changed_at,source" {
+		t.Errorf("history before the changes:\n%s", output.String())
+	}
+
+	if err := runHistory(ctx, []string{"USD", "-db", db, "-from", "ayer"}, &output); err == nil || !strings.Contains(err.Error(), "invalid -from") {
+		t.Errorf("history -from ayer = %v, want invalid -from", err)
+	}
+}
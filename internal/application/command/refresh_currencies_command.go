@@ -4,8 +4,11 @@ package command
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
+	"sync"
+	"time"
 
+	"gobcv/internal/domain/entity"
 	"gobcv/internal/domain/repository"
 	"gobcv/internal/domain/service"
 )
@@ -20,32 +23,62 @@ type RefreshCurrenciesHandler struct {
 	currencyRepo repository.CurrencyRepository
 	scraper      service.CurrencyScraper
 	cache        service.CacheService
+	bus          service.InvalidationBus
+	history      repository.RateHistoryRepository
+	events       service.RateEventBroker
+	observers    []service.RefreshObserver
+	replicaID    string
+
+	// mutex serializa las actualizaciones: dos ejecuciones simultáneas leerían el mismo
+	// valor anterior y registrarían y difundirían el mismo cambio dos veces
+	mutex sync.Mutex
 }
 
 // NewRefreshCurrenciesHandler crea un nuevo handler para el comando.
+// Las claves invalidadas se publican en bus identificadas con replicaID, y los
+// valores que cambiaron se registran en history y se difunden por events.
 func NewRefreshCurrenciesHandler(
 	currencyRepo repository.CurrencyRepository,
 	scraper service.CurrencyScraper,
 	cache service.CacheService,
+	bus service.InvalidationBus,
+	history repository.RateHistoryRepository,
+	events service.RateEventBroker,
+	replicaID string,
 ) *RefreshCurrenciesHandler {
 	return &RefreshCurrenciesHandler{
 		currencyRepo: currencyRepo,
 		scraper:      scraper,
 		cache:        cache,
+		bus:          bus,
+		history:      history,
+		events:       events,
+		replicaID:    replicaID,
 	}
 }
 
+// RegisterObserver agrega un observador que se ejecuta al final de cada actualización.
+// Debe llamarse antes de iniciar las actualizaciones.
+func (h *RefreshCurrenciesHandler) RegisterObserver(observer service.RefreshObserver) {
+	h.observers = append(h.observers, observer)
+}
+
 // RefreshCurrenciesResult representa el resultado del comando.
 type RefreshCurrenciesResult struct {
 	UpdatedCount int      `json:"updated_count"`
 	Currencies   []string `json:"currencies"`
+	ChangedCount int      `json:"changed_count"`
 	Success      bool     `json:"success"`
 	Message      string   `json:"message"`
 }
 
-// Handle ejecuta el comando de actualización de monedas.
+// Handle ejecuta el comando de actualización de monedas. Las ejecuciones simultáneas
+// se serializan para que cada cambio se registre y difunda una sola vez.
 func (h *RefreshCurrenciesHandler) Handle(ctx context.Context, cmd RefreshCurrenciesCommand) (*RefreshCurrenciesResult, error) {
-	log.Printf("Ejecutando comando RefreshCurrencies (force_refresh: %v)", cmd.ForceRefresh)
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	slog.InfoContext(ctx, "Ejecutando comando RefreshCurrencies", "force_refresh", cmd.ForceRefresh)
 
 	// Verificar si el scraper está disponible
 	if err := h.scraper.IsHealthy(ctx); err != nil {
@@ -65,28 +98,75 @@ func (h *RefreshCurrenciesHandler) Handle(ctx context.Context, cmd RefreshCurren
 	}
 
 	var updatedCurrencies []string
+	var changes []*entity.RateChange
+	invalidatedTags := []string{service.CurrenciesTag}
 
 	// Guardar cada moneda en el repositorio
 	for _, currency := range currencies {
+		previous, err := h.currencyRepo.FindByID(ctx, currency.ID)
+		if err != nil {
+			slog.WarnContext(ctx, "Error obteniendo valor anterior de moneda", "currency", currency.ID, "error", err)
+		}
+
 		if err := h.currencyRepo.Save(ctx, currency); err != nil {
-			log.Printf("Error al guardar moneda %s: %v", currency.ID, err)
+			slog.ErrorContext(ctx, "Error al guardar moneda", "currency", currency.ID, "error", err)
 			continue
 		}
 
-		// Invalidar caché para esta moneda
-		cacheKey := fmt.Sprintf("currency:%s", currency.ID)
-		h.cache.Delete(ctx, cacheKey)
-
 		updatedCurrencies = append(updatedCurrencies, currency.ID)
+		invalidatedTags = append(invalidatedTags, service.CurrencyTag(currency.ID))
+
+		if previous == nil || previous.Value != currency.Value {
+			changes = append(changes, h.recordChange(ctx, currency, previous))
+		}
+	}
+
+	// Difundir los cambios ya registrados en el historial
+	if len(changes) > 0 {
+		h.events.Publish(ctx, changes)
+	}
+
+	// Invalidar todo lo derivado de las monedas actualizadas y del listado general
+	for _, tag := range invalidatedTags {
+		if err := h.cache.InvalidateTag(ctx, tag); err != nil {
+			slog.ErrorContext(ctx, "Error invalidando etiqueta de caché", "tag", tag, "error", err)
+		}
+	}
+
+	// Notificar al resto de réplicas para que invaliden sus cachés locales
+	event := service.InvalidationEvent{
+		Tags:      invalidatedTags,
+		Source:    h.replicaID,
+		CreatedAt: time.Now(),
+	}
+	if err := h.bus.Publish(ctx, event); err != nil {
+		slog.ErrorContext(ctx, "Error publicando invalidación de caché", "error", err)
+	}
+
+	for _, observer := range h.observers {
+		observer.OnRefresh(ctx, changes)
 	}
 
-	// Invalidar caché de listado general
-	h.cache.Delete(ctx, "currencies:all")
+	slog.InfoContext(ctx, "Monedas actualizadas",
+		"updated_count", len(updatedCurrencies), "changed_count", len(changes), "currencies", updatedCurrencies)
 
 	return &RefreshCurrenciesResult{
 		UpdatedCount: len(updatedCurrencies),
 		Currencies:   updatedCurrencies,
+		ChangedCount: len(changes),
 		Success:      true,
 		Message:      fmt.Sprintf("Se actualizaron %d monedas exitosamente", len(updatedCurrencies)),
 	}, nil
 }
+
+// recordChange registra en el historial el cambio de una moneda. Si el historial
+// falla el cambio se difunde igualmente, sin secuencia.
+func (h *RefreshCurrenciesHandler) recordChange(ctx context.Context, current, previous *entity.Currency) *entity.RateChange {
+	change := entity.NewRateChange(current, previous)
+
+	if err := h.history.Append(ctx, change); err != nil {
+		slog.ErrorContext(ctx, "Error registrando cambio de tasa en el historial", "currency", current.ID, "error", err)
+	}
+
+	return change
+}
@@ -0,0 +1,107 @@
+package command
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"gobcv/internal/domain/entity"
+	"gobcv/internal/domain/repository"
+	"gobcv/internal/infrastructure/cache"
+	"gobcv/internal/infrastructure/invalidation"
+)
+
+// slowRepository demora la lectura del valor anterior para que las actualizaciones
+// simultáneas se solapen entre la lectura y el guardado.
+type slowRepository struct {
+	repository.CurrencyRepository
+}
+
+func (r slowRepository) FindByID(ctx context.Context, id string) (*entity.Currency, error) {
+	currency, err := r.CurrencyRepository.FindByID(ctx, id)
+	time.Sleep(10 * time.Millisecond)
+	return currency, err
+}
+
+// fixedScraper retorna siempre USD y EUR.
+type fixedScraper struct{}
+
+func (fixedScraper) ScrapeCurrencies(ctx context.Context) ([]*entity.Currency, error) {
+	return []*entity.Currency{
+		entity.NewCurrency("USD", "Dólar", 36.5, "test"),
+		entity.NewCurrency("EUR", "Euro", 40, "test"),
+	}, nil
+}
+
+func (fixedScraper) ScrapeCurrency(ctx context.Context, currencyID string) (*entity.Currency, error) {
+	return nil, nil
+}
+
+func (fixedScraper) IsHealthy(ctx context.Context) error {
+	return nil
+}
+
+// recordingBroker guarda los cambios publicados.
+type recordingBroker struct {
+	mutex     sync.Mutex
+	published []*entity.RateChange
+}
+
+func (b *recordingBroker) Publish(ctx context.Context, changes []*entity.RateChange) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.published = append(b.published, changes...)
+}
+
+func (b *recordingBroker) Subscribe(ctx context.Context, currencyIDs []string, buffer int) <-chan *entity.RateChange {
+	return nil
+}
+
+func (b *recordingBroker) Close() error {
+	return nil
+}
+
+func TestRefreshRecordsEachChangeOnceUnderConcurrency(t *testing.T) {
+	ctx := context.Background()
+	memoryCache := cache.NewMemoryCache()
+	defer memoryCache.Close()
+	history := cache.NewMemoryRateHistory(100)
+	broker := &recordingBroker{}
+
+	handler := NewRefreshCurrenciesHandler(slowRepository{cache.NewMemoryRepository()}, fixedScraper{}, memoryCache,
+		invalidation.NewMemoryBus(), history, broker, "test")
+
+	// Como la actualización inicial y un POST /refresh simultáneos sobre un repositorio vacío
+	var wg sync.WaitGroup
+	changed := make([]int, 4)
+	for i := range changed {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			result, err := handler.Handle(ctx, RefreshCurrenciesCommand{ForceRefresh: i%2 == 0})
+			if err != nil || !result.Success {
+				t.Errorf("Handle = %+v, %v", result, err)
+				return
+			}
+			changed[i] = result.ChangedCount
+		}(i)
+	}
+	wg.Wait()
+
+	total := 0
+	for _, count := range changed {
+		total += count
+	}
+	if total != 2 {
+		t.Errorf("changed counts = %v, want 2 changes across all refreshes", changed)
+	}
+
+	changes, err := history.FindSince(ctx, 0, nil, 0)
+	if err != nil {
+		t.Fatalf("FindSince: %v", err)
+	}
+	if len(changes) != 2 || len(broker.published) != 2 {
+		t.Errorf("history = %d changes, published = %d, want one per currency", len(changes), len(broker.published))
+	}
+}
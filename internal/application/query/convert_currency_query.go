@@ -0,0 +1,134 @@
+// Package query contiene la consulta de conversión entre monedas.
+package query
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"gobcv/pkg/telemetry"
+)
+
+// BaseCurrency es la moneda en la que el BCV expresa todas las tasas.
+const BaseCurrency = "VES"
+
+// ErrInvalidQuery indica que los parámetros de la consulta no son válidos.
+var ErrInvalidQuery = errors.New("invalid query")
+
+// ConvertCurrencyQuery representa la consulta para convertir un monto entre dos monedas.
+// Cualquiera de las dos puede ser la moneda base (VES).
+type ConvertCurrencyQuery struct {
+	From     string  `json:"from"`
+	To       string  `json:"to"`
+	Amount   float64 `json:"amount"`
+	UseCache bool    `json:"use_cache"`
+}
+
+// ConvertCurrencyHandler maneja las conversiones a partir de las tasas de cada moneda.
+type ConvertCurrencyHandler struct {
+	getCurrency *GetCurrencyHandler
+}
+
+// NewConvertCurrencyHandler crea un nuevo handler de conversiones. Las tasas se obtienen
+// con la consulta de monedas individuales, compartiendo su caché.
+func NewConvertCurrencyHandler(getCurrency *GetCurrencyHandler) *ConvertCurrencyHandler {
+	return &ConvertCurrencyHandler{getCurrency: getCurrency}
+}
+
+// ConvertCurrencyResult representa el resultado de la conversión.
+type ConvertCurrencyResult struct {
+	From      string    `json:"from" xml:"from"`
+	To        string    `json:"to" xml:"to"`
+	Amount    float64   `json:"amount" xml:"amount"`
+	Result    float64   `json:"result" xml:"result"`
+	Rate      float64   `json:"rate" xml:"rate"` // Unidades de To por unidad de From
+	RatesAt   time.Time `json:"rates_at" xml:"rates_at"`
+	FromCache bool      `json:"from_cache" xml:"from_cache"`
+	Stale     bool      `json:"stale" xml:"stale"`
+	Success   bool      `json:"success" xml:"success"`
+	Message   string    `json:"message" xml:"message"`
+}
+
+// Handle ejecuta la conversión.
+func (h *ConvertCurrencyHandler) Handle(ctx context.Context, query ConvertCurrencyQuery) (*ConvertCurrencyResult, error) {
+	query.From = strings.ToUpper(strings.TrimSpace(query.From))
+	query.To = strings.ToUpper(strings.TrimSpace(query.To))
+
+	ctx, span := telemetry.Tracer().Start(ctx, "ConvertCurrencyHandler.Handle",
+		trace.WithAttributes(attribute.String("currency.from", query.From), attribute.String("currency.to", query.To)))
+	defer span.End()
+
+	result, err := h.handle(ctx, query)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	return result, err
+}
+
+// handle obtiene la tasa de cada moneda respecto a VES y calcula el cruce.
+func (h *ConvertCurrencyHandler) handle(ctx context.Context, query ConvertCurrencyQuery) (*ConvertCurrencyResult, error) {
+	if len(query.From) != 3 || len(query.To) != 3 {
+		return nil, fmt.Errorf("%w: from and to must be three-letter currency codes", ErrInvalidQuery)
+	}
+	if query.Amount < 0 || math.IsNaN(query.Amount) || math.IsInf(query.Amount, 0) {
+		return nil, fmt.Errorf("%w: amount must be a non-negative number", ErrInvalidQuery)
+	}
+
+	result := &ConvertCurrencyResult{
+		From:      query.From,
+		To:        query.To,
+		Amount:    query.Amount,
+		FromCache: true,
+	}
+
+	fromRate, ok, err := h.rate(ctx, query.From, query.UseCache, result)
+	if err != nil || !ok {
+		return result, err
+	}
+	toRate, ok, err := h.rate(ctx, query.To, query.UseCache, result)
+	if err != nil || !ok {
+		return result, err
+	}
+
+	result.Rate = fromRate / toRate
+	result.Result = query.Amount * result.Rate
+	result.Success = true
+	result.Message = "Conversión realizada"
+	return result, nil
+}
+
+// rate retorna el valor en VES de la moneda y acumula en el resultado la fecha, el origen
+// y la obsolescencia de las tasas usadas. Si la moneda no existe marca el resultado y retorna false.
+func (h *ConvertCurrencyHandler) rate(ctx context.Context, currencyID string, useCache bool, result *ConvertCurrencyResult) (float64, bool, error) {
+	if currencyID == BaseCurrency {
+		return 1, true, nil
+	}
+
+	currency, err := h.getCurrency.Handle(ctx, GetCurrencyQuery{CurrencyID: currencyID, UseCache: useCache})
+	if err != nil {
+		result.Message = fmt.Sprintf("Error al obtener moneda %s: %v", currencyID, err)
+		return 0, false, err
+	}
+	if !currency.Success {
+		result.Message = fmt.Sprintf("Moneda %s no encontrada", currencyID)
+		return 0, false, nil
+	}
+
+	// La conversión es tan reciente como la más antigua de sus tasas
+	if result.RatesAt.IsZero() || currency.Currency.UpdatedAt.Before(result.RatesAt) {
+		result.RatesAt = currency.Currency.UpdatedAt
+	}
+	result.FromCache = result.FromCache && currency.FromCache
+	result.Stale = result.Stale || currency.Stale
+
+	return currency.Currency.Value, true, nil
+}
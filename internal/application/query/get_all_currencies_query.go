@@ -4,13 +4,35 @@ package query
 import (
 	"context"
 	"encoding/json"
+	"log/slog"
+	"sync"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
 	"gobcv/internal/domain/entity"
 	"gobcv/internal/domain/repository"
 	"gobcv/internal/domain/service"
+	"gobcv/pkg/telemetry"
+)
+
+const (
+	// allCurrenciesCacheKey es la clave del listado general en caché.
+	allCurrenciesCacheKey = "currencies:all"
+
+	// allCurrenciesCacheTTL es el tiempo durante el cual el listado en caché se considera fresco.
+	allCurrenciesCacheTTL = 2 * time.Minute
+
+	// revalidateTimeout limita la duración de una revalidación en background.
+	revalidateTimeout = 10 * time.Second
 )
 
+// CurrencyFreshness es la antigüedad a partir de la cual una moneda se considera obsoleta.
+// Sin IncludeStale, el listado descarta las monedas más antiguas.
+const CurrencyFreshness = 30 * time.Minute
+
 // GetAllCurrenciesQuery representa la consulta para obtener todas las monedas.
 type GetAllCurrenciesQuery struct {
 	UseCache     bool `json:"use_cache"`
@@ -21,16 +43,21 @@ type GetAllCurrenciesQuery struct {
 type GetAllCurrenciesHandler struct {
 	currencyRepo repository.CurrencyRepository
 	cache        service.CacheService
+	maxStale     time.Duration
+	revalidating sync.Map
 }
 
 // NewGetAllCurrenciesHandler crea un nuevo handler para consultas de múltiples monedas.
+// maxStale define durante cuánto tiempo, pasado su vencimiento, se siguen sirviendo datos obsoletos.
 func NewGetAllCurrenciesHandler(
 	currencyRepo repository.CurrencyRepository,
 	cache service.CacheService,
+	maxStale time.Duration,
 ) *GetAllCurrenciesHandler {
 	return &GetAllCurrenciesHandler{
 		currencyRepo: currencyRepo,
 		cache:        cache,
+		maxStale:     maxStale,
 	}
 }
 
@@ -39,27 +66,51 @@ type GetAllCurrenciesResult struct {
 	Currencies []*entity.Currency `json:"currencies"`
 	Count      int                `json:"count"`
 	FromCache  bool               `json:"from_cache"`
+	Stale      bool               `json:"stale"`
+	Age        time.Duration      `json:"-"`
 	Success    bool               `json:"success"`
 	Message    string             `json:"message"`
 }
 
 // Handle ejecuta la consulta para obtener todas las monedas.
 func (h *GetAllCurrenciesHandler) Handle(ctx context.Context, query GetAllCurrenciesQuery) (*GetAllCurrenciesResult, error) {
-	cacheKey := "currencies:all"
+	ctx, span := telemetry.Tracer().Start(ctx, "GetAllCurrenciesHandler.Handle",
+		trace.WithAttributes(attribute.Bool("query.use_cache", query.UseCache)))
+	defer span.End()
+
+	result, err := h.handle(ctx, query)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	if result != nil {
+		span.SetAttributes(
+			attribute.Bool("currencies.from_cache", result.FromCache),
+			attribute.Bool("currencies.stale", result.Stale),
+			attribute.Int("currencies.count", result.Count),
+		)
+	}
+
+	return result, err
+}
 
+// handle resuelve la consulta desde el caché o el repositorio.
+func (h *GetAllCurrenciesHandler) handle(ctx context.Context, query GetAllCurrenciesQuery) (*GetAllCurrenciesResult, error) {
 	// Intentar obtener desde caché si está habilitado
 	if query.UseCache {
-		if cached, err := h.cache.Get(ctx, cacheKey); err == nil && cached != nil {
-			if currenciesData, ok := cached.([]byte); ok {
+		if entry, err := h.cache.GetEntry(ctx, allCurrenciesCacheKey); err == nil && entry != nil {
+			if currenciesData, ok := entry.Value.([]byte); ok {
 				var currencies []*entity.Currency
 				if err := json.Unmarshal(currenciesData, &currencies); err == nil {
-					return &GetAllCurrenciesResult{
-						Currencies: currencies,
-						Count:      len(currencies),
-						FromCache:  true,
-						Success:    true,
-						Message:    "Monedas obtenidas desde caché",
-					}, nil
+					// Servir el valor obsoleto mientras se repuebla en background
+					if entry.Stale {
+						h.revalidate(ctx)
+					}
+
+					result := h.buildResult(currencies, query.IncludeStale, "Monedas obtenidas desde caché")
+					result.FromCache = true
+					result.Stale = result.Stale || entry.Stale
+					return result, nil
 				}
 			}
 		}
@@ -74,30 +125,82 @@ func (h *GetAllCurrenciesHandler) Handle(ctx context.Context, query GetAllCurren
 		}, err
 	}
 
-	// Filtrar monedas obsoletas si no se incluyen
-	if !query.IncludeStale {
-		var freshCurrencies []*entity.Currency
-		for _, currency := range currencies {
-			if !currency.IsStale(30 * time.Minute) { // Considera obsoleto después de 30 minutos
-				freshCurrencies = append(freshCurrencies, currency)
-			}
-		}
-		currencies = freshCurrencies
-	}
-
 	// Guardar en caché si está habilitado
 	if query.UseCache {
-		if currenciesData, err := json.Marshal(currencies); err == nil {
-			// Cache por 2 minutos
-			h.cache.Set(ctx, cacheKey, currenciesData, 2*time.Minute)
-		}
+		h.store(ctx, currencies)
 	}
 
-	return &GetAllCurrenciesResult{
-		Currencies: currencies,
-		Count:      len(currencies),
-		FromCache:  false,
+	return h.buildResult(currencies, query.IncludeStale, "Monedas obtenidas desde repositorio"), nil
+}
+
+// buildResult filtra las monedas según su antigüedad y calcula los metadatos de frescura.
+// Sin includeStale se descartan las monedas obsoletas, como antes de servir datos vencidos;
+// la ventana de maxStale solo aplica al listado guardado en caché.
+func (h *GetAllCurrenciesHandler) buildResult(currencies []*entity.Currency, includeStale bool, message string) *GetAllCurrenciesResult {
+	result := &GetAllCurrenciesResult{
+		Currencies: make([]*entity.Currency, 0, len(currencies)),
 		Success:    true,
-		Message:    "Monedas obtenidas desde repositorio",
-	}, nil
+		Message:    message,
+	}
+
+	for _, currency := range currencies {
+		stale := currency.IsStale(CurrencyFreshness)
+		if stale && !includeStale {
+			continue
+		}
+
+		if stale {
+			result.Stale = true
+		}
+
+		if age := time.Since(currency.UpdatedAt); age > result.Age {
+			result.Age = age
+		}
+
+		result.Currencies = append(result.Currencies, currency)
+	}
+
+	result.Count = len(result.Currencies)
+	return result
+}
+
+// store guarda el listado completo en caché con su ventana de obsolescencia.
+func (h *GetAllCurrenciesHandler) store(ctx context.Context, currencies []*entity.Currency) {
+	currenciesData, err := json.Marshal(currencies)
+	if err != nil {
+		return
+	}
+
+	// Etiquetar el listado con cada moneda para invalidarlo junto con ellas
+	tags := []string{service.CurrenciesTag}
+	for _, currency := range currencies {
+		tags = append(tags, service.CurrencyTag(currency.ID))
+	}
+
+	h.cache.SetWithStale(ctx, allCurrenciesCacheKey, currenciesData,
+		allCurrenciesCacheTTL, allCurrenciesCacheTTL+h.maxStale, tags...)
+}
+
+// revalidate repuebla el listado en caché desde el repositorio en background.
+// Solo se ejecuta una revalidación a la vez.
+func (h *GetAllCurrenciesHandler) revalidate(parent context.Context) {
+	if _, running := h.revalidating.LoadOrStore(allCurrenciesCacheKey, struct{}{}); running {
+		return
+	}
+
+	go func() {
+		defer h.revalidating.Delete(allCurrenciesCacheKey)
+
+		// Conservar los valores del contexto (ID de petición) sin heredar su cancelación
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(parent), revalidateTimeout)
+		defer cancel()
+
+		currencies, err := h.currencyRepo.FindAll(ctx)
+		if err != nil {
+			slog.ErrorContext(ctx, "Error revalidando caché", "key", allCurrenciesCacheKey, "error", err)
+			return
+		}
+
+		h.store(ctx, currencies)
+	}()
 }
@@ -0,0 +1,109 @@
+package query
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestGetAllCurrenciesDropsOldCurrencies(t *testing.T) {
+	ctx := context.Background()
+	repo := newStubRepository(
+		testCurrency("USD", 36.5, time.Minute),
+		testCurrency("EUR", 40, CurrencyFreshness+time.Hour),
+	)
+	handler := NewGetAllCurrenciesHandler(repo, newClockCache(), time.Hour)
+
+	tests := []struct {
+		name      string
+		query     GetAllCurrenciesQuery
+		fromCache bool
+		count     int
+		stale     bool
+	}{
+		{name: "repository", query: GetAllCurrenciesQuery{UseCache: true}, count: 1},
+		{name: "cache", query: GetAllCurrenciesQuery{UseCache: true}, fromCache: true, count: 1},
+		{name: "include stale", query: GetAllCurrenciesQuery{UseCache: true, IncludeStale: true}, fromCache: true, count: 2, stale: true},
+		{name: "include stale without cache", query: GetAllCurrenciesQuery{IncludeStale: true}, count: 2, stale: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := handler.Handle(ctx, tt.query)
+			if err != nil {
+				t.Fatalf("Handle: %v", err)
+			}
+			if result.FromCache != tt.fromCache || result.Count != tt.count || result.Stale != tt.stale {
+				t.Errorf("Handle = %+v, want from cache %t, %d currencies, stale %t", result, tt.fromCache, tt.count, tt.stale)
+			}
+			if tt.stale && result.Age < CurrencyFreshness+time.Hour {
+				t.Errorf("Age = %s, want the age of the oldest currency", result.Age)
+			}
+			if !tt.stale && result.Currencies[0].ID != "USD" {
+				t.Errorf("Currencies = %v, want only USD", result.Currencies)
+			}
+		})
+	}
+}
+
+func TestGetAllCurrenciesServesStaleListAndRevalidatesOnce(t *testing.T) {
+	ctx := context.Background()
+	repo := newStubRepository(testCurrency("USD", 36.5, 0))
+	cache := newClockCache()
+	handler := NewGetAllCurrenciesHandler(repo, cache, time.Hour)
+	query := GetAllCurrenciesQuery{UseCache: true}
+
+	if _, err := handler.Handle(ctx, query); err != nil {
+		t.Fatalf("Handle: %v", err)
+	}
+
+	cache.advance(allCurrenciesCacheTTL + time.Minute)
+	release := make(chan struct{})
+	repo.set(testCurrency("EUR", 40, 0), release)
+
+	for i := 0; i < 5; i++ {
+		result, err := handler.Handle(ctx, query)
+		if err != nil {
+			t.Fatalf("Handle: %v", err)
+		}
+		if !result.FromCache || !result.Stale || result.Count != 1 {
+			t.Errorf("stale Handle = %+v, want the cached list marked stale", result)
+		}
+	}
+
+	close(release)
+	waitFresh(t, cache, allCurrenciesCacheKey)
+	if reads := repo.readCount(); reads != 2 {
+		t.Errorf("repository reads = %d, want the first read and one revalidation", reads)
+	}
+
+	result, err := handler.Handle(ctx, query)
+	if err != nil || !result.FromCache || result.Stale || result.Count != 2 {
+		t.Errorf("Handle after revalidation = %+v, %v, want both currencies fresh from cache", result, err)
+	}
+}
+
+func TestGetAllCurrenciesExpiresAfterMaxStale(t *testing.T) {
+	ctx := context.Background()
+	maxStale := 10 * time.Minute
+	repo := newStubRepository(testCurrency("USD", 36.5, 0))
+	cache := newClockCache()
+	handler := NewGetAllCurrenciesHandler(repo, cache, maxStale)
+	query := GetAllCurrenciesQuery{UseCache: true}
+
+	if _, err := handler.Handle(ctx, query); err != nil {
+		t.Fatalf("Handle: %v", err)
+	}
+	if ttl := cache.hardTTL[allCurrenciesCacheKey]; ttl != allCurrenciesCacheTTL+maxStale {
+		t.Errorf("hard TTL = %s, want %s", ttl, allCurrenciesCacheTTL+maxStale)
+	}
+
+	cache.advance(allCurrenciesCacheTTL + maxStale)
+	result, err := handler.Handle(ctx, query)
+	if err != nil || result.FromCache || result.Stale {
+		t.Errorf("Handle after max stale = %+v, %v, want a fresh read from the repository", result, err)
+	}
+	if reads := repo.readCount(); reads != 2 {
+		t.Errorf("repository reads = %d, want 2", reads)
+	}
+}
@@ -5,13 +5,23 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
+	"sync"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
 	"gobcv/internal/domain/entity"
 	"gobcv/internal/domain/repository"
 	"gobcv/internal/domain/service"
+	"gobcv/pkg/telemetry"
 )
 
+// currencyCacheTTL es el tiempo durante el cual una moneda en caché se considera fresca.
+const currencyCacheTTL = 5 * time.Minute
+
 // GetCurrencyQuery representa la consulta para obtener una moneda específica.
 type GetCurrencyQuery struct {
 	CurrencyID string `json:"currency_id"`
@@ -22,40 +32,76 @@ type GetCurrencyQuery struct {
 type GetCurrencyHandler struct {
 	currencyRepo repository.CurrencyRepository
 	cache        service.CacheService
+	maxStale     time.Duration
+	revalidating sync.Map
 }
 
 // NewGetCurrencyHandler crea un nuevo handler para consultas de monedas.
+// maxStale define durante cuánto tiempo, pasado su vencimiento, se sigue sirviendo una moneda obsoleta.
 func NewGetCurrencyHandler(
 	currencyRepo repository.CurrencyRepository,
 	cache service.CacheService,
+	maxStale time.Duration,
 ) *GetCurrencyHandler {
 	return &GetCurrencyHandler{
 		currencyRepo: currencyRepo,
 		cache:        cache,
+		maxStale:     maxStale,
 	}
 }
 
 // GetCurrencyResult representa el resultado de la consulta.
 type GetCurrencyResult struct {
-	Currency   *entity.Currency `json:"currency"`
-	FromCache  bool             `json:"from_cache"`
-	Success    bool             `json:"success"`
-	Message    string           `json:"message"`
+	Currency  *entity.Currency `json:"currency"`
+	FromCache bool             `json:"from_cache"`
+	Stale     bool             `json:"stale"`
+	Age       time.Duration    `json:"-"`
+	Success   bool             `json:"success"`
+	Message   string           `json:"message"`
 }
 
 // Handle ejecuta la consulta para obtener una moneda.
 func (h *GetCurrencyHandler) Handle(ctx context.Context, query GetCurrencyQuery) (*GetCurrencyResult, error) {
+	ctx, span := telemetry.Tracer().Start(ctx, "GetCurrencyHandler.Handle",
+		trace.WithAttributes(attribute.String("currency.id", query.CurrencyID), attribute.Bool("query.use_cache", query.UseCache)))
+	defer span.End()
+
+	result, err := h.handle(ctx, query)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	if result != nil {
+		span.SetAttributes(
+			attribute.Bool("currency.from_cache", result.FromCache),
+			attribute.Bool("currency.stale", result.Stale),
+			attribute.Bool("currency.found", result.Success),
+		)
+	}
+
+	return result, err
+}
+
+// handle resuelve la consulta desde el caché o el repositorio.
+func (h *GetCurrencyHandler) handle(ctx context.Context, query GetCurrencyQuery) (*GetCurrencyResult, error) {
 	cacheKey := fmt.Sprintf("currency:%s", query.CurrencyID)
-	
+
 	// Intentar obtener desde caché si está habilitado
 	if query.UseCache {
-		if cached, err := h.cache.Get(ctx, cacheKey); err == nil && cached != nil {
-			if currencyData, ok := cached.([]byte); ok {
+		if entry, err := h.cache.GetEntry(ctx, cacheKey); err == nil && entry != nil {
+			if currencyData, ok := entry.Value.([]byte); ok {
 				var currency entity.Currency
 				if err := json.Unmarshal(currencyData, &currency); err == nil {
+					// Servir el valor obsoleto mientras se repuebla en background
+					if entry.Stale {
+						h.revalidate(ctx, query.CurrencyID, cacheKey)
+					}
+
 					return &GetCurrencyResult{
 						Currency:  &currency,
 						FromCache: true,
+						Stale:     entry.Stale || currency.IsStale(CurrencyFreshness),
+						Age:       time.Since(currency.UpdatedAt),
 						Success:   true,
 						Message:   "Moneda obtenida desde caché",
 					}, nil
@@ -82,16 +128,52 @@ func (h *GetCurrencyHandler) Handle(ctx context.Context, query GetCurrencyQuery)
 
 	// Guardar en caché si está habilitado
 	if query.UseCache {
-		if currencyData, err := json.Marshal(currency); err == nil {
-			// Cache por 5 minutos
-			h.cache.Set(ctx, cacheKey, currencyData, 5*time.Minute)
-		}
+		h.store(ctx, cacheKey, currency)
 	}
 
 	return &GetCurrencyResult{
 		Currency:  currency,
 		FromCache: false,
+		Stale:     currency.IsStale(CurrencyFreshness),
+		Age:       time.Since(currency.UpdatedAt),
 		Success:   true,
 		Message:   "Moneda obtenida desde repositorio",
 	}, nil
 }
+
+// store guarda la moneda en caché con su ventana de obsolescencia.
+func (h *GetCurrencyHandler) store(ctx context.Context, cacheKey string, currency *entity.Currency) {
+	if currencyData, err := json.Marshal(currency); err == nil {
+		h.cache.SetWithStale(ctx, cacheKey, currencyData,
+			currencyCacheTTL, currencyCacheTTL+h.maxStale, service.CurrencyTag(currency.ID))
+	}
+}
+
+// revalidate repuebla la moneda en caché desde el repositorio en background.
+// Solo se ejecuta una revalidación a la vez por moneda.
+func (h *GetCurrencyHandler) revalidate(parent context.Context, currencyID, cacheKey string) {
+	if _, running := h.revalidating.LoadOrStore(cacheKey, struct{}{}); running {
+		return
+	}
+
+	go func() {
+		defer h.revalidating.Delete(cacheKey)
+
+		// Conservar los valores del contexto (ID de petición) sin heredar su cancelación
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(parent), revalidateTimeout)
+		defer cancel()
+
+		currency, err := h.currencyRepo.FindByID(ctx, currencyID)
+		if err != nil {
+			slog.ErrorContext(ctx, "Error revalidando caché", "key", cacheKey, "error", err)
+			return
+		}
+
+		if currency == nil {
+			h.cache.Delete(ctx, cacheKey)
+			return
+		}
+
+		h.store(ctx, cacheKey, currency)
+	}()
+}
@@ -0,0 +1,308 @@
+package query
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"gobcv/internal/domain/entity"
+	"gobcv/internal/domain/service"
+)
+
+// stubRepository guarda monedas con su UpdatedAt tal cual y cuenta las lecturas. Mientras
+// block no esté cerrado, las lecturas esperan, como un repositorio lento.
+type stubRepository struct {
+	mutex      sync.Mutex
+	currencies map[string]*entity.Currency
+	reads      int
+	block      chan struct{}
+}
+
+func newStubRepository(currencies ...*entity.Currency) *stubRepository {
+	r := &stubRepository{currencies: make(map[string]*entity.Currency)}
+	for _, currency := range currencies {
+		r.currencies[currency.ID] = currency
+	}
+	return r
+}
+
+// read cuenta una lectura y espera a que se libere el bloqueo, si hay uno.
+func (r *stubRepository) read() {
+	r.mutex.Lock()
+	r.reads++
+	block := r.block
+	r.mutex.Unlock()
+
+	if block != nil {
+		<-block
+	}
+}
+
+// readCount retorna la cantidad de lecturas.
+func (r *stubRepository) readCount() int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.reads
+}
+
+// set reemplaza una moneda y, con block, hace esperar a las lecturas siguientes.
+func (r *stubRepository) set(currency *entity.Currency, block chan struct{}) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.currencies[currency.ID] = currency
+	r.block = block
+}
+
+func (r *stubRepository) Save(ctx context.Context, currency *entity.Currency) error {
+	r.set(currency, nil)
+	return nil
+}
+
+func (r *stubRepository) FindByID(ctx context.Context, id string) (*entity.Currency, error) {
+	r.read()
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if currency, ok := r.currencies[id]; ok {
+		copied := *currency
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+func (r *stubRepository) FindAll(ctx context.Context) ([]*entity.Currency, error) {
+	r.read()
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	currencies := make([]*entity.Currency, 0, len(r.currencies))
+	for _, id := range []string{"EUR", "USD"} {
+		if currency, ok := r.currencies[id]; ok {
+			copied := *currency
+			currencies = append(currencies, &copied)
+		}
+	}
+	return currencies, nil
+}
+
+func (r *stubRepository) Delete(ctx context.Context, id string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	delete(r.currencies, id)
+	return nil
+}
+
+func (r *stubRepository) FindByLastUpdate(ctx context.Context, since time.Time) ([]*entity.Currency, error) {
+	return r.FindAll(ctx)
+}
+
+// clockEntry es un valor de clockCache con sus vencimientos.
+type clockEntry struct {
+	value     interface{}
+	storedAt  time.Time
+	staleAt   time.Time
+	expiresAt time.Time
+}
+
+// clockCache es un caché en memoria con un reloj manual, para recorrer los vencimientos
+// de las entradas sin esperar.
+type clockCache struct {
+	mutex   sync.Mutex
+	now     time.Time
+	entries map[string]*clockEntry
+	hardTTL map[string]time.Duration
+}
+
+func newClockCache() *clockCache {
+	return &clockCache{
+		now:     time.Now(),
+		entries: make(map[string]*clockEntry),
+		hardTTL: make(map[string]time.Duration),
+	}
+}
+
+// advance adelanta el reloj del caché.
+func (c *clockCache) advance(d time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// fresh indica si la clave tiene una entrada todavía fresca.
+func (c *clockCache) fresh(key string) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	entry, ok := c.entries[key]
+	return ok && c.now.Before(entry.staleAt)
+}
+
+func (c *clockCache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration, tags ...string) error {
+	return c.SetWithStale(ctx, key, value, ttl, ttl, tags...)
+}
+
+func (c *clockCache) SetWithStale(ctx context.Context, key string, value interface{}, softTTL, hardTTL time.Duration, tags ...string) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.entries[key] = &clockEntry{value: value, storedAt: c.now, staleAt: c.now.Add(softTTL), expiresAt: c.now.Add(hardTTL)}
+	c.hardTTL[key] = hardTTL
+	return nil
+}
+
+func (c *clockCache) Get(ctx context.Context, key string) (interface{}, error) {
+	entry, err := c.GetEntry(ctx, key)
+	if entry == nil || entry.Stale {
+		return nil, err
+	}
+	return entry.Value, nil
+}
+
+func (c *clockCache) GetEntry(ctx context.Context, key string) (*service.CacheEntry, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	entry, ok := c.entries[key]
+	if !ok || !c.now.Before(entry.expiresAt) {
+		delete(c.entries, key)
+		return nil, nil
+	}
+	return &service.CacheEntry{
+		Value:    entry.value,
+		StoredAt: entry.storedAt,
+		StaleAt:  entry.staleAt,
+		Stale:    !c.now.Before(entry.staleAt),
+	}, nil
+}
+
+func (c *clockCache) Delete(ctx context.Context, key string) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	delete(c.entries, key)
+	return nil
+}
+
+func (c *clockCache) DeletePrefix(ctx context.Context, prefix string) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for key := range c.entries {
+		if strings.HasPrefix(key, prefix) {
+			delete(c.entries, key)
+		}
+	}
+	return nil
+}
+
+func (c *clockCache) InvalidateTag(ctx context.Context, tag string) error {
+	return nil
+}
+
+func (c *clockCache) Exists(ctx context.Context, key string) (bool, error) {
+	entry, err := c.GetEntry(ctx, key)
+	return entry != nil, err
+}
+
+func (c *clockCache) Clear(ctx context.Context) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.entries = make(map[string]*clockEntry)
+	return nil
+}
+
+func (c *clockCache) GetStats(ctx context.Context) (service.CacheStats, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return service.CacheStats{Keys: int64(len(c.entries))}, nil
+}
+
+// testCurrency crea una moneda actualizada hace age.
+func testCurrency(id string, value float64, age time.Duration) *entity.Currency {
+	return &entity.Currency{ID: id, Name: id, Value: value, UpdatedAt: time.Now().Add(-age), Source: "test"}
+}
+
+// waitFresh espera a que la revalidación en background vuelva a dejar fresca la clave.
+func waitFresh(t *testing.T, cache *clockCache, key string) {
+	t.Helper()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !cache.fresh(key) {
+		if time.Now().After(deadline) {
+			t.Fatalf("%s was not revalidated", key)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestGetCurrencyServesStaleEntryAndRevalidatesOnce(t *testing.T) {
+	ctx := context.Background()
+	repo := newStubRepository(testCurrency("USD", 36.5, 10*time.Minute))
+	cache := newClockCache()
+	handler := NewGetCurrencyHandler(repo, cache, time.Hour)
+	query := GetCurrencyQuery{CurrencyID: "USD", UseCache: true}
+
+	if result, err := handler.Handle(ctx, query); err != nil || result.FromCache || result.Stale {
+		t.Fatalf("first Handle = %+v, %v, want a fresh read from the repository", result, err)
+	}
+
+	// Vencida la entrada, se sirve la anterior mientras una sola revalidación lee el
+	// repositorio, que quedó bloqueado con el valor nuevo
+	cache.advance(currencyCacheTTL + time.Minute)
+	release := make(chan struct{})
+	repo.set(testCurrency("USD", 37, 0), release)
+
+	for i := 0; i < 5; i++ {
+		result, err := handler.Handle(ctx, query)
+		if err != nil {
+			t.Fatalf("Handle: %v", err)
+		}
+		if !result.FromCache || !result.Stale || result.Currency.Value != 36.5 {
+			t.Errorf("stale Handle = %+v, want the cached 36.5 marked stale", result)
+		}
+		if result.Age < 10*time.Minute {
+			t.Errorf("Age = %s, want the age of the cached currency", result.Age)
+		}
+	}
+
+	close(release)
+	waitFresh(t, cache, "currency:USD")
+	if reads := repo.readCount(); reads != 2 {
+		t.Errorf("repository reads = %d, want the first read and one revalidation", reads)
+	}
+
+	result, err := handler.Handle(ctx, query)
+	if err != nil || !result.FromCache || result.Stale || result.Currency.Value != 37 {
+		t.Errorf("Handle after revalidation = %+v, %v, want the fresh 37 from cache", result, err)
+	}
+}
+
+func TestGetCurrencyExpiresAfterMaxStale(t *testing.T) {
+	ctx := context.Background()
+	maxStale := 10 * time.Minute
+	repo := newStubRepository(testCurrency("USD", 36.5, 0))
+	cache := newClockCache()
+	handler := NewGetCurrencyHandler(repo, cache, maxStale)
+	query := GetCurrencyQuery{CurrencyID: "USD", UseCache: true}
+
+	if _, err := handler.Handle(ctx, query); err != nil {
+		t.Fatalf("Handle: %v", err)
+	}
+	if ttl := cache.hardTTL["currency:USD"]; ttl != currencyCacheTTL+maxStale {
+		t.Errorf("hard TTL = %s, want %s", ttl, currencyCacheTTL+maxStale)
+	}
+
+	cache.advance(currencyCacheTTL + maxStale)
+	result, err := handler.Handle(ctx, query)
+	if err != nil || result.FromCache || result.Stale {
+		t.Errorf("Handle after max stale = %+v, %v, want a fresh read from the repository", result, err)
+	}
+	if reads := repo.readCount(); reads != 2 {
+		t.Errorf("repository reads = %d, want 2", reads)
+	}
+}
+
+func TestGetCurrencyMarksOldCurrencyStale(t *testing.T) {
+	repo := newStubRepository(testCurrency("USD", 36.5, CurrencyFreshness+time.Minute))
+	handler := NewGetCurrencyHandler(repo, newClockCache(), time.Hour)
+
+	result, err := handler.Handle(context.Background(), GetCurrencyQuery{CurrencyID: "USD"})
+	if err != nil || !result.Success || !result.Stale || result.Age < CurrencyFreshness {
+		t.Errorf("Handle = %+v, %v, want the old currency marked stale", result, err)
+	}
+}
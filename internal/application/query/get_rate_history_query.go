@@ -0,0 +1,101 @@
+// Package query contiene la consulta del historial de tasas.
+package query
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"gobcv/internal/domain/entity"
+	"gobcv/internal/domain/repository"
+	"gobcv/pkg/telemetry"
+)
+
+// GetRateHistoryQuery representa la consulta de los cambios de una moneda en un rango.
+// From en cero no limita el inicio y To en cero equivale al momento actual.
+type GetRateHistoryQuery struct {
+	CurrencyID string    `json:"currency_id"`
+	From       time.Time `json:"from"`
+	To         time.Time `json:"to"`
+}
+
+// GetRateHistoryHandler maneja las consultas del historial de tasas.
+type GetRateHistoryHandler struct {
+	history repository.RateHistoryRepository
+}
+
+// NewGetRateHistoryHandler crea un nuevo handler para consultas del historial.
+func NewGetRateHistoryHandler(history repository.RateHistoryRepository) *GetRateHistoryHandler {
+	return &GetRateHistoryHandler{history: history}
+}
+
+// GetRateHistoryResult representa el resultado de la consulta.
+type GetRateHistoryResult struct {
+	CurrencyID string               `json:"currency_id" xml:"currency_id"`
+	From       time.Time            `json:"from" xml:"from"`
+	To         time.Time            `json:"to" xml:"to"`
+	Changes    []*entity.RateChange `json:"changes" xml:"changes>change"`
+	Count      int                  `json:"count" xml:"count"`
+	Success    bool                 `json:"success" xml:"success"`
+	Message    string               `json:"message" xml:"message"`
+}
+
+// Handle ejecuta la consulta del historial.
+func (h *GetRateHistoryHandler) Handle(ctx context.Context, query GetRateHistoryQuery) (*GetRateHistoryResult, error) {
+	query.CurrencyID = strings.ToUpper(strings.TrimSpace(query.CurrencyID))
+
+	ctx, span := telemetry.Tracer().Start(ctx, "GetRateHistoryHandler.Handle",
+		trace.WithAttributes(attribute.String("currency.id", query.CurrencyID)))
+	defer span.End()
+
+	result, err := h.handle(ctx, query)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	if result != nil {
+		span.SetAttributes(attribute.Int("history.count", result.Count))
+	}
+
+	return result, err
+}
+
+// handle obtiene los cambios desde el repositorio del historial.
+func (h *GetRateHistoryHandler) handle(ctx context.Context, query GetRateHistoryQuery) (*GetRateHistoryResult, error) {
+	if len(query.CurrencyID) != 3 {
+		return nil, fmt.Errorf("%w: currency must be a three-letter code", ErrInvalidQuery)
+	}
+	if query.To.IsZero() {
+		query.To = time.Now()
+	}
+	if query.From.After(query.To) {
+		return nil, fmt.Errorf("%w: from must not be after to", ErrInvalidQuery)
+	}
+
+	changes, err := h.history.FindByCurrency(ctx, query.CurrencyID, query.From, query.To)
+	if err != nil {
+		return &GetRateHistoryResult{
+			CurrencyID: query.CurrencyID,
+			Success:    false,
+			Message:    fmt.Sprintf("Error al obtener historial: %v", err),
+		}, err
+	}
+	if changes == nil {
+		changes = []*entity.RateChange{}
+	}
+
+	return &GetRateHistoryResult{
+		CurrencyID: query.CurrencyID,
+		From:       query.From,
+		To:         query.To,
+		Changes:    changes,
+		Count:      len(changes),
+		Success:    true,
+		Message:    "Historial obtenido",
+	}, nil
+}
@@ -0,0 +1,357 @@
+// Package service contiene los servicios de aplicación que coordinan la lógica de negocio.
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"gobcv/internal/domain/entity"
+	"gobcv/internal/domain/repository"
+	"gobcv/internal/domain/service"
+)
+
+// Errores retornados por AlertService.
+var (
+	ErrAlertRuleNotFound = errors.New("alert rule not found")
+	ErrInvalidAlertRule  = errors.New("invalid alert rule")
+)
+
+// alertNotifyTimeout limita el envío de una alerta por todos sus canales.
+const alertNotifyTimeout = 30 * time.Second
+
+// alertReferenceWindow es la ventana de las reglas de variación porcentual.
+const alertReferenceWindow = 24 * time.Hour
+
+// AlertRuleInput contiene los campos que el cliente puede definir en una regla.
+// En las actualizaciones, los campos nil no se modifican.
+type AlertRuleInput struct {
+	CurrencyID  *string   `json:"currency_id"`
+	Type        *string   `json:"type"`
+	Threshold   *float64  `json:"threshold"`
+	Cooldown    *string   `json:"cooldown"` // Duración de Go: 30m, 1h, 24h
+	Notifiers   *[]string `json:"notifiers"`
+	Description *string   `json:"description"`
+	Disabled    *bool     `json:"disabled"`
+}
+
+// AlertService administra las reglas de alerta y las evalúa al final de cada
+// actualización de monedas.
+type AlertService struct {
+	alerts          repository.AlertRepository
+	history         repository.RateHistoryRepository
+	notifiers       map[string]service.AlertNotifier
+	defaultCooldown time.Duration
+	// mutex serializa la evaluación y las modificaciones para que dos actualizaciones
+	// simultáneas no disparen la misma regla dentro de su enfriamiento
+	mutex    sync.Mutex
+	inflight sync.WaitGroup
+}
+
+// NewAlertService crea un nuevo servicio de alertas. defaultCooldown se aplica a las
+// reglas creadas sin enfriamiento.
+func NewAlertService(
+	alerts repository.AlertRepository,
+	history repository.RateHistoryRepository,
+	notifiers []service.AlertNotifier,
+	defaultCooldown time.Duration,
+) *AlertService {
+	byName := make(map[string]service.AlertNotifier, len(notifiers))
+	for _, notifier := range notifiers {
+		byName[notifier.Name()] = notifier
+	}
+
+	return &AlertService{
+		alerts:          alerts,
+		history:         history,
+		notifiers:       byName,
+		defaultCooldown: defaultCooldown,
+	}
+}
+
+var _ service.RefreshObserver = (*AlertService)(nil)
+
+// CreateRule registra una regla de alerta.
+func (s *AlertService) CreateRule(ctx context.Context, input AlertRuleInput) (*entity.AlertRule, error) {
+	now := time.Now()
+	rule := &entity.AlertRule{
+		ID:              "rule_" + randomHex(8),
+		CooldownSeconds: int64(s.defaultCooldown.Seconds()),
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+
+	if input.CurrencyID == nil || input.Type == nil || input.Threshold == nil {
+		return nil, fmt.Errorf("%w: currency_id, type and threshold are required", ErrInvalidAlertRule)
+	}
+	if err := s.applyInput(rule, input); err != nil {
+		return nil, err
+	}
+
+	if err := s.alerts.SaveRule(ctx, rule); err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "Regla de alerta registrada", "rule", rule.ID, "currency", rule.CurrencyID,
+		"type", rule.Type, "threshold", rule.Threshold)
+	return rule, nil
+}
+
+// UpdateRule modifica los campos indicados de una regla.
+func (s *AlertService) UpdateRule(ctx context.Context, id string, input AlertRuleInput) (*entity.AlertRule, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	rule, err := s.findRule(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.applyInput(rule, input); err != nil {
+		return nil, err
+	}
+	rule.UpdatedAt = time.Now()
+
+	if err := s.alerts.SaveRule(ctx, rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// GetRule obtiene una regla.
+func (s *AlertService) GetRule(ctx context.Context, id string) (*entity.AlertRule, error) {
+	return s.findRule(ctx, id)
+}
+
+// ListRules obtiene todas las reglas.
+func (s *AlertService) ListRules(ctx context.Context) ([]*entity.AlertRule, error) {
+	return s.alerts.FindRules(ctx)
+}
+
+// DeleteRule elimina una regla.
+func (s *AlertService) DeleteRule(ctx context.Context, id string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, err := s.findRule(ctx, id); err != nil {
+		return err
+	}
+
+	if err := s.alerts.DeleteRule(ctx, id); err != nil {
+		return err
+	}
+
+	slog.InfoContext(ctx, "Regla de alerta eliminada", "rule", id)
+	return nil
+}
+
+// Alerts obtiene las alertas disparadas más recientes.
+func (s *AlertService) Alerts(ctx context.Context, limit int) ([]*entity.Alert, error) {
+	return s.alerts.FindAlerts(ctx, limit)
+}
+
+// OnRefresh evalúa las reglas con los cambios de la actualización. Cada regla se
+// dispara como máximo una vez por actualización y nunca dentro de su enfriamiento;
+// las notificaciones se envían en segundo plano para no demorar la actualización.
+func (s *AlertService) OnRefresh(ctx context.Context, changes []*entity.RateChange) {
+	if len(changes) == 0 {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	rules, err := s.alerts.FindRules(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error obteniendo reglas de alerta", "error", err)
+		return
+	}
+
+	references := make(map[string]float64)
+	now := time.Now()
+
+	for _, rule := range rules {
+		if rule.Disabled || rule.InCooldown(now) {
+			continue
+		}
+
+		for _, change := range changes {
+			if change.CurrencyID != rule.CurrencyID {
+				continue
+			}
+
+			reference := 0.0
+			if rule.Type == entity.AlertChangePercent {
+				var known bool
+				if reference, known = references[change.CurrencyID]; !known {
+					reference = s.referenceValue(ctx, change)
+					references[change.CurrencyID] = reference
+				}
+			}
+
+			alert, triggered := rule.Evaluate(change, reference)
+			if !triggered {
+				continue
+			}
+
+			alert.ID = "alr_" + randomHex(8)
+			rule.LastTriggeredAt = now
+			if err := s.alerts.SaveRule(ctx, rule); err != nil {
+				slog.ErrorContext(ctx, "Error registrando disparo de regla", "rule", rule.ID, "error", err)
+			}
+
+			s.inflight.Add(1)
+			go func(rule *entity.AlertRule, alert *entity.Alert) {
+				defer s.inflight.Done()
+				s.notify(context.WithoutCancel(ctx), rule, alert)
+			}(rule, alert)
+			break
+		}
+	}
+}
+
+// Wait espera a que terminen las notificaciones en curso.
+func (s *AlertService) Wait() {
+	s.inflight.Wait()
+}
+
+// notify envía la alerta por los canales de la regla y la registra con el resultado.
+func (s *AlertService) notify(ctx context.Context, rule *entity.AlertRule, alert *entity.Alert) {
+	ctx, cancel := context.WithTimeout(ctx, alertNotifyTimeout)
+	defer cancel()
+
+	slog.InfoContext(ctx, "Regla de alerta disparada", "rule", rule.ID, "alert", alert.ID, "message", alert.Message)
+
+	// El resultado se asigna al final para que los canales reciban la alerta sin él
+	var notified, failures []string
+	for _, name := range s.ruleNotifiers(rule) {
+		notifier, ok := s.notifiers[name]
+		if !ok {
+			failures = append(failures, name+": notifier not configured")
+			continue
+		}
+
+		if err := notifier.Notify(ctx, alert); err != nil {
+			slog.ErrorContext(ctx, "Error notificando alerta", "alert", alert.ID, "notifier", name, "error", err)
+			failures = append(failures, name+": "+err.Error())
+			continue
+		}
+		notified = append(notified, name)
+	}
+	alert.Notified = notified
+	alert.Errors = failures
+
+	if err := s.alerts.AppendAlert(ctx, alert); err != nil {
+		slog.ErrorContext(ctx, "Error registrando alerta", "alert", alert.ID, "error", err)
+	}
+}
+
+// ruleNotifiers retorna los canales de la regla, o todos los configurados si no indica ninguno.
+func (s *AlertService) ruleNotifiers(rule *entity.AlertRule) []string {
+	if len(rule.Notifiers) > 0 {
+		return rule.Notifiers
+	}
+
+	names := make([]string, 0, len(s.notifiers))
+	for name := range s.notifiers {
+		names = append(names, name)
+	}
+	return names
+}
+
+// referenceValue retorna el valor vigente 24 horas antes del cambio: el del último
+// cambio anterior a la ventana o, si el historial no llega tan atrás, el valor previo
+// al primer cambio dentro de ella (su propio valor si fue la carga inicial). Retorna 0
+// si no se conoce.
+func (s *AlertService) referenceValue(ctx context.Context, change *entity.RateChange) float64 {
+	since := change.ChangedAt.Add(-alertReferenceWindow)
+
+	changes, err := s.history.FindByCurrency(ctx, change.CurrencyID, time.Time{}, change.ChangedAt)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error leyendo historial para alertas", "currency", change.CurrencyID, "error", err)
+		return 0
+	}
+
+	for i := len(changes) - 1; i >= 0; i-- {
+		if !changes[i].ChangedAt.After(since) {
+			return changes[i].Value
+		}
+	}
+	if len(changes) > 0 {
+		if changes[0].PreviousValue == 0 {
+			return changes[0].Value
+		}
+		return changes[0].PreviousValue
+	}
+	return change.PreviousValue
+}
+
+// findRule obtiene una regla o ErrAlertRuleNotFound.
+func (s *AlertService) findRule(ctx context.Context, id string) (*entity.AlertRule, error) {
+	rule, err := s.alerts.FindRuleByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if rule == nil {
+		return nil, ErrAlertRuleNotFound
+	}
+	return rule, nil
+}
+
+// applyInput aplica y valida los campos indicados por el cliente.
+func (s *AlertService) applyInput(rule *entity.AlertRule, input AlertRuleInput) error {
+	if input.CurrencyID != nil {
+		rule.CurrencyID = strings.ToUpper(strings.TrimSpace(*input.CurrencyID))
+		if len(rule.CurrencyID) != 3 {
+			return fmt.Errorf("%w: invalid currency %q", ErrInvalidAlertRule, *input.CurrencyID)
+		}
+	}
+
+	if input.Type != nil {
+		switch *input.Type {
+		case entity.AlertChangePercent, entity.AlertAbove, entity.AlertBelow:
+			rule.Type = *input.Type
+		default:
+			return fmt.Errorf("%w: type must be one of %s, %s, %s", ErrInvalidAlertRule,
+				entity.AlertChangePercent, entity.AlertAbove, entity.AlertBelow)
+		}
+	}
+
+	if input.Threshold != nil {
+		if *input.Threshold <= 0 {
+			return fmt.Errorf("%w: threshold must be positive", ErrInvalidAlertRule)
+		}
+		rule.Threshold = *input.Threshold
+	}
+
+	if input.Cooldown != nil {
+		cooldown, err := time.ParseDuration(*input.Cooldown)
+		if err != nil || cooldown < 0 {
+			return fmt.Errorf("%w: cooldown must be a non-negative duration such as 1h", ErrInvalidAlertRule)
+		}
+		rule.CooldownSeconds = int64(cooldown.Seconds())
+	}
+
+	if input.Notifiers != nil {
+		for _, name := range *input.Notifiers {
+			if _, ok := s.notifiers[name]; !ok {
+				return fmt.Errorf("%w: notifier %q is not configured", ErrInvalidAlertRule, name)
+			}
+		}
+		rule.Notifiers = append([]string(nil), *input.Notifiers...)
+	}
+
+	if input.Description != nil {
+		rule.Description = *input.Description
+	}
+
+	if input.Disabled != nil {
+		rule.Disabled = *input.Disabled
+	}
+
+	return nil
+}
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"gobcv/internal/domain/entity"
+	"gobcv/internal/domain/service"
+	"gobcv/internal/infrastructure/cache"
+)
+
+// recordingNotifier guarda las alertas recibidas.
+type recordingNotifier struct {
+	mutex  sync.Mutex
+	alerts []*entity.Alert
+}
+
+func (n *recordingNotifier) Name() string {
+	return "test"
+}
+
+func (n *recordingNotifier) Notify(ctx context.Context, alert *entity.Alert) error {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	n.alerts = append(n.alerts, alert)
+	return nil
+}
+
+// count retorna la cantidad de alertas recibidas.
+func (n *recordingNotifier) count() int {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	return len(n.alerts)
+}
+
+// newTestAlertService crea un AlertService en memoria con un notificador que registra las alertas.
+func newTestAlertService(t *testing.T) (*AlertService, *recordingNotifier, *cache.MemoryRateHistory) {
+	t.Helper()
+
+	notifier := &recordingNotifier{}
+	history := cache.NewMemoryRateHistory(100).(*cache.MemoryRateHistory)
+	alertService := NewAlertService(cache.NewMemoryAlertRepository(100), history, []service.AlertNotifier{notifier}, time.Hour)
+
+	return alertService, notifier, history
+}
+
+// createTestRule registra una regla sobre USD.
+func createTestRule(t *testing.T, service *AlertService, ruleType string, threshold float64, cooldown string) *entity.AlertRule {
+	t.Helper()
+
+	currency := "USD"
+	rule, err := service.CreateRule(context.Background(), AlertRuleInput{
+		CurrencyID: &currency,
+		Type:       &ruleType,
+		Threshold:  &threshold,
+		Cooldown:   &cooldown,
+	})
+	if err != nil {
+		t.Fatalf("CreateRule: %v", err)
+	}
+	return rule
+}
+
+// usdChange crea un cambio de USD de previous a value.
+func usdChange(previous, value float64) *entity.RateChange {
+	return &entity.RateChange{CurrencyID: "USD", PreviousValue: previous, Value: value, ChangedAt: time.Now()}
+}
+
+func TestAlertServiceEvaluatesRules(t *testing.T) {
+	tests := []struct {
+		name      string
+		ruleType  string
+		threshold float64
+		change    *entity.RateChange
+		fires     bool
+	}{
+		{"above crossing up", entity.AlertAbove, 40, usdChange(39.5, 40.2), true},
+		{"above reaching threshold", entity.AlertAbove, 40, usdChange(39.5, 40), true},
+		{"above already above", entity.AlertAbove, 40, usdChange(40.5, 41), false},
+		{"above still below", entity.AlertAbove, 40, usdChange(38, 39), false},
+		{"above initial load", entity.AlertAbove, 40, usdChange(0, 41), false},
+		{"below crossing down", entity.AlertBelow, 35, usdChange(35.2, 34.9), true},
+		{"below already below", entity.AlertBelow, 35, usdChange(34, 33), false},
+		{"below rising", entity.AlertBelow, 35, usdChange(34, 36), false},
+		{"other currency", entity.AlertAbove, 40, &entity.RateChange{CurrencyID: "EUR", PreviousValue: 39, Value: 41}, false},
+		// La referencia es el valor de hace 24 horas del historial: 100
+		{"percent rise", entity.AlertChangePercent, 2, usdChange(101, 102.5), true},
+		{"percent drop", entity.AlertChangePercent, 2, usdChange(99, 97.5), true},
+		{"percent within threshold", entity.AlertChangePercent, 2, usdChange(100.5, 101.5), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			service, notifier, history := newTestAlertService(t)
+			history.Append(ctx, &entity.RateChange{CurrencyID: "USD", Value: 100, ChangedAt: time.Now().Add(-25 * time.Hour)})
+
+			rule := createTestRule(t, service, tt.ruleType, tt.threshold, "1h")
+			service.OnRefresh(ctx, []*entity.RateChange{tt.change})
+			service.Wait()
+
+			if fired := notifier.count() == 1; fired != tt.fires {
+				t.Fatalf("fired = %v, want %v", fired, tt.fires)
+			}
+			if !tt.fires {
+				return
+			}
+
+			alerts, _ := service.Alerts(ctx, 0)
+			if len(alerts) != 1 || alerts[0].RuleID != rule.ID || len(alerts[0].Notified) != 1 || alerts[0].Notified[0] != "test" {
+				t.Fatalf("alerts = %+v, want one alert notified by test", alerts)
+			}
+			if tt.ruleType == entity.AlertChangePercent && alerts[0].ReferenceValue != 100 {
+				t.Errorf("ReferenceValue = %v, want 100", alerts[0].ReferenceValue)
+			}
+		})
+	}
+}
+
+func TestAlertServiceCooldown(t *testing.T) {
+	ctx := context.Background()
+	service, notifier, _ := newTestAlertService(t)
+	rule := createTestRule(t, service, entity.AlertAbove, 40, "1h")
+
+	// Dos cruces en la misma actualización disparan una sola vez
+	service.OnRefresh(ctx, []*entity.RateChange{usdChange(39, 41), usdChange(39.5, 40.5)})
+	service.Wait()
+	if got := notifier.count(); got != 1 {
+		t.Fatalf("alerts after first refresh = %d, want 1", got)
+	}
+
+	// Dentro del enfriamiento no se vuelve a disparar
+	service.OnRefresh(ctx, []*entity.RateChange{usdChange(39, 42)})
+	service.Wait()
+	if got := notifier.count(); got != 1 {
+		t.Fatalf("alerts within cooldown = %d, want 1", got)
+	}
+
+	stored, err := service.GetRule(ctx, rule.ID)
+	if err != nil || stored.LastTriggeredAt.IsZero() {
+		t.Fatalf("GetRule = %+v, %v, want LastTriggeredAt set", stored, err)
+	}
+
+	// Vencido el enfriamiento vuelve a dispararse
+	stored.LastTriggeredAt = time.Now().Add(-2 * time.Hour)
+	service.alerts.SaveRule(ctx, stored)
+	service.OnRefresh(ctx, []*entity.RateChange{usdChange(39, 42)})
+	service.Wait()
+	if got := notifier.count(); got != 2 {
+		t.Fatalf("alerts after cooldown = %d, want 2", got)
+	}
+
+	// Sin enfriamiento se dispara en cada actualización
+	zero := "0s"
+	if _, err := service.UpdateRule(ctx, rule.ID, AlertRuleInput{Cooldown: &zero}); err != nil {
+		t.Fatalf("UpdateRule: %v", err)
+	}
+	service.OnRefresh(ctx, []*entity.RateChange{usdChange(39, 42)})
+	service.OnRefresh(ctx, []*entity.RateChange{usdChange(39, 42)})
+	service.Wait()
+	if got := notifier.count(); got != 4 {
+		t.Errorf("alerts without cooldown = %d, want 4", got)
+	}
+
+	// Las reglas desactivadas no se evalúan
+	disabled := true
+	service.UpdateRule(ctx, rule.ID, AlertRuleInput{Disabled: &disabled})
+	service.OnRefresh(ctx, []*entity.RateChange{usdChange(39, 42)})
+	service.Wait()
+	if got := notifier.count(); got != 4 {
+		t.Errorf("alerts from a disabled rule = %d, want 4", got)
+	}
+}
@@ -3,13 +3,15 @@ package service
 
 import (
 	"context"
-	"log"
+	"log/slog"
+	"sync/atomic"
 	"time"
 
 	"gobcv/internal/application/command"
 	"gobcv/internal/application/query"
 	"gobcv/internal/domain/repository"
 	"gobcv/internal/domain/service"
+	"gobcv/pkg/requestid"
 )
 
 // CurrencyService coordina las operaciones relacionadas con monedas.
@@ -17,20 +19,38 @@ type CurrencyService struct {
 	refreshHandler     *command.RefreshCurrenciesHandler
 	getCurrencyHandler *query.GetCurrencyHandler
 	getAllHandler      *query.GetAllCurrenciesHandler
+	convertHandler     *query.ConvertCurrencyHandler
+	historyHandler     *query.GetRateHistoryHandler
 	cacheService       service.CacheService
+	invalidationBus    service.InvalidationBus
+	replicaID          string
+	nextRefreshAt      atomic.Int64
 }
 
 // NewCurrencyService crea una nueva instancia del servicio de monedas.
+// maxStale define la ventana durante la cual las consultas sirven datos obsoletos y
+// replicaID identifica a esta instancia en los eventos de invalidación.
 func NewCurrencyService(
 	currencyRepo repository.CurrencyRepository,
 	scraper service.CurrencyScraper,
 	cache service.CacheService,
+	invalidationBus service.InvalidationBus,
+	history repository.RateHistoryRepository,
+	events service.RateEventBroker,
+	replicaID string,
+	maxStale time.Duration,
 ) *CurrencyService {
+	getCurrencyHandler := query.NewGetCurrencyHandler(currencyRepo, cache, maxStale)
+
 	return &CurrencyService{
-		refreshHandler:     command.NewRefreshCurrenciesHandler(currencyRepo, scraper, cache),
-		getCurrencyHandler: query.NewGetCurrencyHandler(currencyRepo, cache),
-		getAllHandler:      query.NewGetAllCurrenciesHandler(currencyRepo, cache),
+		refreshHandler:     command.NewRefreshCurrenciesHandler(currencyRepo, scraper, cache, invalidationBus, history, events, replicaID),
+		getCurrencyHandler: getCurrencyHandler,
+		getAllHandler:      query.NewGetAllCurrenciesHandler(currencyRepo, cache, maxStale),
+		convertHandler:     query.NewConvertCurrencyHandler(getCurrencyHandler),
+		historyHandler:     query.NewGetRateHistoryHandler(history),
 		cacheService:       cache,
+		invalidationBus:    invalidationBus,
+		replicaID:          replicaID,
 	}
 }
 
@@ -49,12 +69,24 @@ func (s *CurrencyService) GetAllCurrenciesHandler() *query.GetAllCurrenciesHandl
 	return s.getAllHandler
 }
 
+// GetConvertHandler retorna el handler de conversión entre monedas.
+func (s *CurrencyService) GetConvertHandler() *query.ConvertCurrencyHandler {
+	return s.convertHandler
+}
+
+// GetRateHistoryHandler retorna el handler de consulta del historial de tasas.
+func (s *CurrencyService) GetRateHistoryHandler() *query.GetRateHistoryHandler {
+	return s.historyHandler
+}
+
 // StartPeriodicRefresh inicia la actualización periódica de monedas.
 func (s *CurrencyService) StartPeriodicRefresh(ctx context.Context, interval time.Duration) {
 	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
-	log.Printf("Iniciando actualización periódica de monedas cada %v", interval)
+	slog.InfoContext(ctx, "Iniciando actualización periódica de monedas", "interval", interval)
+
+	s.scheduleNextRefresh(interval)
 
 	// Actualización inicial
 	s.refreshCurrencies(ctx)
@@ -62,30 +94,72 @@ func (s *CurrencyService) StartPeriodicRefresh(ctx context.Context, interval tim
 	for {
 		select {
 		case <-ctx.Done():
-			log.Println("Deteniendo actualización periódica de monedas")
+			slog.InfoContext(ctx, "Deteniendo actualización periódica de monedas")
 			return
 		case <-ticker.C:
+			s.scheduleNextRefresh(interval)
 			s.refreshCurrencies(ctx)
 		}
 	}
 }
 
+// scheduleNextRefresh registra el momento de la próxima actualización periódica.
+func (s *CurrencyService) scheduleNextRefresh(interval time.Duration) {
+	s.nextRefreshAt.Store(time.Now().Add(interval).UnixNano())
+}
+
+// NextRefreshAt retorna el momento de la próxima actualización periódica programada,
+// o el instante cero si la actualización periódica no está activa.
+func (s *CurrencyService) NextRefreshAt() time.Time {
+	nanos := s.nextRefreshAt.Load()
+	if nanos == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, nanos)
+}
+
+// StartInvalidationListener suscribe el caché local a las invalidaciones publicadas por otras réplicas.
+func (s *CurrencyService) StartInvalidationListener(ctx context.Context) error {
+	return s.invalidationBus.Subscribe(ctx, func(ctx context.Context, event service.InvalidationEvent) {
+		// Las invalidaciones propias ya se aplicaron al publicarlas
+		if event.Source == s.replicaID {
+			return
+		}
+
+		slog.InfoContext(ctx, "Invalidando caché por actualización en otra réplica",
+			"keys", len(event.Keys), "tags", len(event.Tags), "source", event.Source)
+		for _, key := range event.Keys {
+			if err := s.cacheService.Delete(ctx, key); err != nil {
+				slog.ErrorContext(ctx, "Error invalidando clave", "key", key, "error", err)
+			}
+		}
+		for _, tag := range event.Tags {
+			if err := s.cacheService.InvalidateTag(ctx, tag); err != nil {
+				slog.ErrorContext(ctx, "Error invalidando etiqueta", "tag", tag, "error", err)
+			}
+		}
+	})
+}
+
 // refreshCurrencies ejecuta la actualización de monedas y maneja errores.
+// Cada ejecución recibe su propio ID para correlacionar sus registros.
 func (s *CurrencyService) refreshCurrencies(ctx context.Context) {
+	ctx = requestid.WithID(ctx, "refresh-"+requestid.New())
+
 	cmd := command.RefreshCurrenciesCommand{
 		ForceRefresh: false,
 	}
 
 	result, err := s.refreshHandler.Handle(ctx, cmd)
 	if err != nil {
-		log.Printf("Error en actualización automática de monedas: %v", err)
+		slog.ErrorContext(ctx, "Error en actualización automática de monedas", "error", err)
 		return
 	}
 
 	if result.Success {
-		log.Printf("Actualización automática exitosa: %s", result.Message)
+		slog.InfoContext(ctx, "Actualización automática exitosa", "message", result.Message)
 	} else {
-		log.Printf("Fallo en actualización automática: %s", result.Message)
+		slog.WarnContext(ctx, "Fallo en actualización automática", "message", result.Message)
 	}
 }
 
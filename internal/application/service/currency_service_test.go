@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gobcv/internal/domain/service"
+	"gobcv/internal/infrastructure/cache"
+	"gobcv/internal/infrastructure/events"
+	"gobcv/internal/infrastructure/invalidation"
+)
+
+func TestInvalidationListenerAppliesForeignEvents(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	memoryCache := cache.NewMemoryCache()
+	defer memoryCache.Close()
+	bus := invalidation.NewMemoryBus()
+	defer bus.Close()
+
+	currencies := NewCurrencyService(cache.NewMemoryRepository(), nil, memoryCache, bus,
+		cache.NewMemoryRateHistory(10), events.NewBroker(), "replica-a", time.Hour)
+	if err := currencies.StartInvalidationListener(ctx); err != nil {
+		t.Fatalf("StartInvalidationListener: %v", err)
+	}
+
+	memoryCache.Set(ctx, "currency:USD", []byte("36.5"), time.Minute, service.CurrencyTag("USD"))
+	memoryCache.Set(ctx, "currencies:all", []byte("[]"), time.Minute, service.CurrenciesTag)
+	memoryCache.Set(ctx, "currency:EUR", []byte("40"), time.Minute, service.CurrencyTag("EUR"))
+
+	exists := func(key string) bool {
+		t.Helper()
+		found, err := memoryCache.Exists(ctx, key)
+		if err != nil {
+			t.Fatalf("Exists(%s): %v", key, err)
+		}
+		return found
+	}
+
+	// Los eventos propios ya se aplicaron al publicarlos y se ignoran
+	bus.Publish(ctx, service.InvalidationEvent{Keys: []string{"currency:USD"}, Tags: []string{service.CurrenciesTag}, Source: "replica-a"})
+	if !exists("currency:USD") || !exists("currencies:all") {
+		t.Fatal("the listener applied an event from its own replica")
+	}
+
+	bus.Publish(ctx, service.InvalidationEvent{Keys: []string{"currency:USD"}, Tags: []string{service.CurrenciesTag}, Source: "replica-b"})
+	if exists("currency:USD") || exists("currencies:all") {
+		t.Error("the listener kept keys invalidated by another replica")
+	}
+	if !exists("currency:EUR") {
+		t.Error("the listener dropped a key the event did not name")
+	}
+}
@@ -0,0 +1,247 @@
+// Package service contiene el servicio de verificación de salud de la aplicación.
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"gobcv/internal/domain/repository"
+	"gobcv/internal/domain/service"
+)
+
+// Estados de salud de la aplicación y de sus componentes.
+const (
+	HealthStatusUp       = "up"
+	HealthStatusDegraded = "degraded"
+	HealthStatusDown     = "down"
+)
+
+const (
+	// healthCheckTimeout limita la duración de cada verificación de componente.
+	healthCheckTimeout = 3 * time.Second
+
+	// scraperCheckTTL evita consultar BCV en cada sonda de readiness.
+	scraperCheckTTL = 30 * time.Second
+
+	// healthCheckKey es la clave consultada para verificar el caché. No se almacena: basta
+	// con que el caché responda, sin recorrer sus claves como GetStats.
+	healthCheckKey = "health:probe"
+)
+
+// ComponentHealth representa el estado de un componente.
+type ComponentHealth struct {
+	Status   string                 `json:"status"`
+	Critical bool                   `json:"critical"`
+	Error    string                 `json:"error,omitempty"`
+	Details  map[string]interface{} `json:"details,omitempty"`
+}
+
+// HealthReport representa el resultado de una verificación de readiness.
+// Ready es false si algún componente crítico está caído.
+type HealthReport struct {
+	Status     string                      `json:"status"`
+	Ready      bool                        `json:"ready"`
+	Components map[string]*ComponentHealth `json:"components"`
+	CheckedAt  time.Time                   `json:"checked_at"`
+}
+
+// scraperCheck guarda el último resultado de IsHealthy.
+type scraperCheck struct {
+	err       error
+	checkedAt time.Time
+}
+
+// HealthService verifica el estado de las dependencias de la aplicación.
+type HealthService struct {
+	currencyRepo    repository.CurrencyRepository
+	cache           service.CacheService
+	scraper         service.CurrencyScraper
+	refreshInterval time.Duration
+	startedAt       time.Time
+
+	scraperMutex sync.Mutex
+	lastScraper  scraperCheck
+}
+
+// NewHealthService crea el servicio de salud. refreshInterval es el intervalo de
+// actualización periódica con el que se evalúa la antigüedad de los datos.
+func NewHealthService(
+	currencyRepo repository.CurrencyRepository,
+	cache service.CacheService,
+	scraper service.CurrencyScraper,
+	refreshInterval time.Duration,
+) *HealthService {
+	return &HealthService{
+		currencyRepo:    currencyRepo,
+		cache:           cache,
+		scraper:         scraper,
+		refreshInterval: refreshInterval,
+		startedAt:       time.Now(),
+	}
+}
+
+// Uptime retorna el tiempo transcurrido desde el inicio del servicio.
+func (s *HealthService) Uptime() time.Duration {
+	return time.Since(s.startedAt)
+}
+
+// Readiness verifica los componentes. El repositorio, el caché y la existencia de datos
+// son críticos; la disponibilidad de BCV y la antigüedad de la última actualización solo
+// degradan el estado, ya que la API sigue sirviendo los datos obsoletos.
+func (s *HealthService) Readiness(ctx context.Context) HealthReport {
+	report := HealthReport{
+		Status:     HealthStatusUp,
+		Ready:      true,
+		Components: make(map[string]*ComponentHealth),
+		CheckedAt:  time.Now(),
+	}
+
+	var (
+		wg            sync.WaitGroup
+		currencies    int
+		lastUpdate    time.Time
+		repoHealth    *ComponentHealth
+		cacheHealth   *ComponentHealth
+		scraperHealth *ComponentHealth
+	)
+
+	// Las verificaciones son independientes; ejecutarlas en paralelo acota la latencia de la sonda
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		repoHealth, currencies, lastUpdate = s.checkRepository(ctx)
+	}()
+	go func() {
+		defer wg.Done()
+		cacheHealth = s.checkCache(ctx)
+	}()
+	go func() {
+		defer wg.Done()
+		scraperHealth = s.checkScraper(ctx)
+	}()
+	wg.Wait()
+
+	report.Components["repository"] = repoHealth
+	report.Components["cache"] = cacheHealth
+	report.Components["scraper"] = scraperHealth
+	report.Components["data"] = s.checkData(repoHealth, currencies)
+	report.Components["refresh"] = s.checkRefresh(repoHealth, lastUpdate)
+
+	for _, component := range report.Components {
+		switch {
+		case component.Status == HealthStatusUp:
+		case component.Critical:
+			report.Status = HealthStatusDown
+			report.Ready = false
+		case report.Status == HealthStatusUp:
+			report.Status = HealthStatusDegraded
+		}
+	}
+
+	return report
+}
+
+// checkRepository verifica que el repositorio responda y obtiene la cantidad de monedas
+// y la fecha de la actualización más reciente.
+func (s *HealthService) checkRepository(ctx context.Context) (*ComponentHealth, int, time.Time) {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	currencies, err := s.currencyRepo.FindAll(ctx)
+	if err != nil {
+		return &ComponentHealth{Status: HealthStatusDown, Critical: true, Error: err.Error()}, 0, time.Time{}
+	}
+
+	var lastUpdate time.Time
+	for _, currency := range currencies {
+		if currency.UpdatedAt.After(lastUpdate) {
+			lastUpdate = currency.UpdatedAt
+		}
+	}
+
+	return &ComponentHealth{Status: HealthStatusUp, Critical: true}, len(currencies), lastUpdate
+}
+
+// checkCache verifica que el caché responda con una consulta de costo constante.
+func (s *HealthService) checkCache(ctx context.Context) *ComponentHealth {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	if _, err := s.cache.Exists(ctx, healthCheckKey); err != nil {
+		return &ComponentHealth{Status: HealthStatusDown, Critical: true, Error: err.Error()}
+	}
+
+	return &ComponentHealth{Status: HealthStatusUp, Critical: true}
+}
+
+// checkScraper verifica la disponibilidad de BCV, reutilizando el resultado reciente.
+func (s *HealthService) checkScraper(ctx context.Context) *ComponentHealth {
+	s.scraperMutex.Lock()
+	defer s.scraperMutex.Unlock()
+
+	if time.Since(s.lastScraper.checkedAt) >= scraperCheckTTL {
+		checkCtx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+		err := s.scraper.IsHealthy(checkCtx)
+		cancel()
+
+		s.lastScraper = scraperCheck{err: err, checkedAt: time.Now()}
+	}
+
+	health := &ComponentHealth{
+		Status:  HealthStatusUp,
+		Details: map[string]interface{}{"checked_at": s.lastScraper.checkedAt},
+	}
+	if s.lastScraper.err != nil {
+		health.Status = HealthStatusDown
+		health.Error = s.lastScraper.err.Error()
+	}
+
+	return health
+}
+
+// checkData verifica que se haya cargado al menos una moneda.
+func (s *HealthService) checkData(repoHealth *ComponentHealth, currencies int) *ComponentHealth {
+	health := &ComponentHealth{
+		Status:   HealthStatusUp,
+		Critical: true,
+		Details:  map[string]interface{}{"currencies": currencies},
+	}
+
+	if repoHealth.Status != HealthStatusUp {
+		health.Status = HealthStatusDown
+		health.Error = "repository unavailable"
+	} else if currencies == 0 {
+		health.Status = HealthStatusDown
+		health.Error = "no currencies loaded yet"
+	}
+
+	return health
+}
+
+// checkRefresh compara la antigüedad de la última actualización exitosa con el
+// intervalo de actualización; se tolera perder una actualización programada.
+func (s *HealthService) checkRefresh(repoHealth *ComponentHealth, lastUpdate time.Time) *ComponentHealth {
+	maxAge := 2 * s.refreshInterval
+	health := &ComponentHealth{
+		Status:  HealthStatusUp,
+		Details: map[string]interface{}{"refresh_interval_seconds": s.refreshInterval.Seconds()},
+	}
+
+	if repoHealth.Status != HealthStatusUp || lastUpdate.IsZero() {
+		health.Status = HealthStatusDown
+		health.Error = "no successful refresh yet"
+		return health
+	}
+
+	age := time.Since(lastUpdate)
+	health.Details["last_refresh_at"] = lastUpdate
+	health.Details["age_seconds"] = int64(age.Seconds())
+
+	if age > maxAge {
+		health.Status = HealthStatusDown
+		health.Error = "last successful refresh is older than twice the refresh interval"
+	}
+
+	return health
+}
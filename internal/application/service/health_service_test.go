@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"gobcv/internal/domain/entity"
+	"gobcv/internal/domain/repository"
+	"gobcv/internal/domain/service"
+)
+
+// probeCache responde solo a Exists y falla el test si se consultan sus estadísticas.
+type probeCache struct {
+	service.CacheService
+	t      *testing.T
+	err    error
+	probed []string
+}
+
+func (c *probeCache) Exists(ctx context.Context, key string) (bool, error) {
+	c.probed = append(c.probed, key)
+	return false, c.err
+}
+
+func (c *probeCache) GetStats(ctx context.Context) (service.CacheStats, error) {
+	c.t.Error("readiness read the cache statistics")
+	return service.CacheStats{}, nil
+}
+
+// fixedRepository retorna siempre las mismas monedas o el mismo error.
+type fixedRepository struct {
+	repository.CurrencyRepository
+	currencies []*entity.Currency
+	err        error
+}
+
+func (r fixedRepository) FindAll(ctx context.Context) ([]*entity.Currency, error) {
+	return r.currencies, r.err
+}
+
+// healthScraper solo responde a IsHealthy.
+type healthScraper struct {
+	service.CurrencyScraper
+	err error
+}
+
+func (s healthScraper) IsHealthy(ctx context.Context) error {
+	return s.err
+}
+
+func TestHealthServiceReadiness(t *testing.T) {
+	updated := func(age time.Duration) []*entity.Currency {
+		return []*entity.Currency{{ID: "USD", Name: "Dólar", Value: 36.5, UpdatedAt: time.Now().Add(-age)}}
+	}
+
+	tests := []struct {
+		name       string
+		repo       fixedRepository
+		cacheErr   error
+		scraperErr error
+		status     string
+		ready      bool
+		down       map[string]string
+	}{
+		{
+			name:   "all up",
+			repo:   fixedRepository{currencies: updated(time.Minute)},
+			status: HealthStatusUp,
+			ready:  true,
+		},
+		{
+			name:   "no data",
+			repo:   fixedRepository{},
+			status: HealthStatusDown,
+			down:   map[string]string{"data": "no currencies loaded yet", "refresh": "no successful refresh yet"},
+		},
+		{
+			name:   "refresh overdue",
+			repo:   fixedRepository{currencies: updated(11 * time.Minute)},
+			status: HealthStatusDegraded,
+			ready:  true,
+			down:   map[string]string{"refresh": "last successful refresh is older than twice the refresh interval"},
+		},
+		{
+			name:       "scraper unhealthy",
+			repo:       fixedRepository{currencies: updated(time.Minute)},
+			scraperErr: errors.New("BCV website returned status 502"),
+			status:     HealthStatusDegraded,
+			ready:      true,
+			down:       map[string]string{"scraper": "BCV website returned status 502"},
+		},
+		{
+			name:     "cache down",
+			repo:     fixedRepository{currencies: updated(time.Minute)},
+			cacheErr: errors.New("connection refused"),
+			status:   HealthStatusDown,
+			down:     map[string]string{"cache": "connection refused"},
+		},
+		{
+			name:   "repository down",
+			repo:   fixedRepository{err: errors.New("database is locked")},
+			status: HealthStatusDown,
+			down: map[string]string{
+				"repository": "database is locked",
+				"data":       "repository unavailable",
+				"refresh":    "no successful refresh yet",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := &probeCache{t: t, err: tt.cacheErr}
+			health := NewHealthService(tt.repo, cache, healthScraper{err: tt.scraperErr}, 5*time.Minute)
+
+			report := health.Readiness(context.Background())
+			if report.Status != tt.status || report.Ready != tt.ready {
+				t.Errorf("report = %s ready=%t, want %s ready=%t", report.Status, report.Ready, tt.status, tt.ready)
+			}
+
+			for name, component := range report.Components {
+				wantErr, wantDown := tt.down[name]
+				if wantDown && (component.Status != HealthStatusDown || component.Error != wantErr) {
+					t.Errorf("%s = %+v, want down with %q", name, component, wantErr)
+				}
+				if !wantDown && component.Status != HealthStatusUp {
+					t.Errorf("%s = %+v, want up", name, component)
+				}
+			}
+
+			if len(cache.probed) != 1 || cache.probed[0] != healthCheckKey {
+				t.Errorf("cache probes = %v, want one Exists on %s", cache.probed, healthCheckKey)
+			}
+		})
+	}
+}
+
+func TestHealthServiceReportsRefreshAge(t *testing.T) {
+	lastRefresh := time.Now().Add(-11 * time.Minute)
+	repo := fixedRepository{currencies: []*entity.Currency{{ID: "USD", Name: "Dólar", Value: 36.5, UpdatedAt: lastRefresh}}}
+	health := NewHealthService(repo, &probeCache{t: t}, healthScraper{}, 5*time.Minute)
+
+	refresh := health.Readiness(context.Background()).Components["refresh"]
+	if age, _ := refresh.Details["age_seconds"].(int64); age < 660 {
+		t.Errorf("age_seconds = %v, want at least 660", refresh.Details["age_seconds"])
+	}
+	if at, _ := refresh.Details["last_refresh_at"].(time.Time); !at.Equal(lastRefresh) {
+		t.Errorf("last_refresh_at = %v, want %v", refresh.Details["last_refresh_at"], lastRefresh)
+	}
+	if interval := refresh.Details["refresh_interval_seconds"]; interval != 300.0 {
+		t.Errorf("refresh_interval_seconds = %v, want 300", interval)
+	}
+}
+
+func TestHealthServiceReusesScraperCheck(t *testing.T) {
+	calls := 0
+	scraper := &countingHealthScraper{calls: &calls}
+	repo := fixedRepository{currencies: []*entity.Currency{{ID: "USD", Name: "Dólar", Value: 36.5, UpdatedAt: time.Now()}}}
+	health := NewHealthService(repo, &probeCache{t: t}, scraper, 5*time.Minute)
+
+	for i := 0; i < 3; i++ {
+		health.Readiness(context.Background())
+	}
+	if calls != 1 {
+		t.Errorf("IsHealthy calls = %d, want 1 within %s", calls, scraperCheckTTL)
+	}
+}
+
+// countingHealthScraper cuenta las llamadas a IsHealthy.
+type countingHealthScraper struct {
+	service.CurrencyScraper
+	calls *int
+}
+
+func (s *countingHealthScraper) IsHealthy(ctx context.Context) error {
+	*s.calls++
+	return nil
+}
@@ -0,0 +1,458 @@
+// Package service contiene los servicios de aplicación que coordinan la lógica de negocio.
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"gobcv/internal/domain/entity"
+	"gobcv/internal/domain/repository"
+	"gobcv/internal/domain/service"
+	"gobcv/pkg/requestid"
+)
+
+// Errores retornados por WebhookService.
+var (
+	ErrWebhookNotFound = errors.New("webhook not found")
+	ErrInvalidWebhook  = errors.New("invalid webhook")
+)
+
+// WebhookPolicy define los reintentos de las entregas, cuándo se desactiva un webhook
+// y qué destinos se aceptan.
+type WebhookPolicy struct {
+	MaxAttempts  int           // Intentos por entrega, incluido el primero
+	Backoff      time.Duration // Espera antes del primer reintento; se duplica en cada uno
+	MaxBackoff   time.Duration // Espera máxima entre reintentos
+	DisableAfter int           // Entregas fallidas consecutivas tras las que se desactiva el webhook
+
+	// AllowPrivateNetworks acepta URLs que resuelven a loopback o redes privadas
+	AllowPrivateNetworks bool
+}
+
+// WebhookInput contiene los campos que el cliente puede definir en un webhook.
+// En las actualizaciones, los campos nil no se modifican.
+type WebhookInput struct {
+	URL         *string   `json:"url"`
+	Secret      *string   `json:"secret"`
+	Currencies  *[]string `json:"currencies"`
+	Description *string   `json:"description"`
+	Disabled    *bool     `json:"disabled"`
+}
+
+// webhookPayload es el cuerpo enviado en cada entrega.
+type webhookPayload struct {
+	ID        string             `json:"id"`
+	Event     string             `json:"event"`
+	CreatedAt time.Time          `json:"created_at"`
+	Data      *entity.RateChange `json:"data"`
+}
+
+// WebhookService administra los webhooks y les entrega los cambios de tasas.
+type WebhookService struct {
+	webhooks   repository.WebhookRepository
+	deliveries repository.WebhookDeliveryRepository
+	sender     service.WebhookSender
+	events     service.RateEventBroker
+	history    repository.RateHistoryRepository
+	policy     WebhookPolicy
+	// mutex serializa las modificaciones de webhooks para que las entregas
+	// concurrentes no pisen el contador de fallos
+	mutex    sync.Mutex
+	inflight sync.WaitGroup
+}
+
+// NewWebhookService crea un nuevo servicio de webhooks.
+func NewWebhookService(
+	webhooks repository.WebhookRepository,
+	deliveries repository.WebhookDeliveryRepository,
+	sender service.WebhookSender,
+	events service.RateEventBroker,
+	history repository.RateHistoryRepository,
+	policy WebhookPolicy,
+) *WebhookService {
+	return &WebhookService{
+		webhooks:   webhooks,
+		deliveries: deliveries,
+		sender:     sender,
+		events:     events,
+		history:    history,
+		policy:     policy,
+	}
+}
+
+// Create registra un webhook. Si no se indica un secreto se genera uno aleatorio;
+// el webhook retornado es la única respuesta que lo incluye.
+func (s *WebhookService) Create(ctx context.Context, input WebhookInput) (*entity.Webhook, error) {
+	now := time.Now()
+	webhook := &entity.Webhook{
+		ID:        "wh_" + randomHex(8),
+		Secret:    "whsec_" + randomHex(24),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if input.URL == nil {
+		return nil, fmt.Errorf("%w: url is required", ErrInvalidWebhook)
+	}
+	if err := applyWebhookInput(webhook, input); err != nil {
+		return nil, err
+	}
+	if err := s.checkDestination(ctx, webhook.URL); err != nil {
+		return nil, err
+	}
+
+	if err := s.webhooks.Save(ctx, webhook); err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "Webhook registrado", "webhook", webhook.ID, "url", webhook.URL)
+	return webhook, nil
+}
+
+// Update modifica los campos indicados de un webhook. Reactivarlo reinicia el contador de fallos.
+func (s *WebhookService) Update(ctx context.Context, id string, input WebhookInput) (*entity.Webhook, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	webhook, err := s.find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := applyWebhookInput(webhook, input); err != nil {
+		return nil, err
+	}
+	if input.URL != nil {
+		if err := s.checkDestination(ctx, webhook.URL); err != nil {
+			return nil, err
+		}
+	}
+	webhook.UpdatedAt = time.Now()
+
+	if err := s.webhooks.Save(ctx, webhook); err != nil {
+		return nil, err
+	}
+
+	return redactWebhook(webhook), nil
+}
+
+// Get obtiene un webhook sin su secreto.
+func (s *WebhookService) Get(ctx context.Context, id string) (*entity.Webhook, error) {
+	webhook, err := s.find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return redactWebhook(webhook), nil
+}
+
+// List obtiene todos los webhooks sin sus secretos.
+func (s *WebhookService) List(ctx context.Context) ([]*entity.Webhook, error) {
+	webhooks, err := s.webhooks.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, webhook := range webhooks {
+		webhooks[i] = redactWebhook(webhook)
+	}
+	return webhooks, nil
+}
+
+// Delete elimina un webhook y su registro de entregas.
+func (s *WebhookService) Delete(ctx context.Context, id string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, err := s.find(ctx, id); err != nil {
+		return err
+	}
+
+	if err := s.webhooks.Delete(ctx, id); err != nil {
+		return err
+	}
+	if err := s.deliveries.DeleteByWebhook(ctx, id); err != nil {
+		slog.WarnContext(ctx, "Error eliminando entregas del webhook", "webhook", id, "error", err)
+	}
+
+	slog.InfoContext(ctx, "Webhook eliminado", "webhook", id)
+	return nil
+}
+
+// Deliveries obtiene los intentos de entrega más recientes de un webhook.
+func (s *WebhookService) Deliveries(ctx context.Context, id string, limit int) ([]*entity.WebhookDelivery, error) {
+	if _, err := s.find(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.deliveries.FindByWebhook(ctx, id, limit)
+}
+
+// Start entrega a los webhooks los cambios publicados hasta que se cancele el contexto.
+// Si el broker descarta la suscripción, reanuda desde el historial sin perder cambios.
+func (s *WebhookService) Start(ctx context.Context) {
+	slog.InfoContext(ctx, "Iniciando entrega de webhooks")
+
+	var lastSequence uint64
+	for {
+		events := s.events.Subscribe(ctx, nil, 64)
+
+		missed, reset, err := repository.FindResumeBacklog(ctx, s.history, lastSequence, nil)
+		if err != nil {
+			slog.ErrorContext(ctx, "Error leyendo historial para reanudar webhooks", "error", err)
+		}
+		if reset {
+			// Las secuencias se reiniciaron: sin descartar el cursor se omitirían los cambios nuevos
+			slog.WarnContext(ctx, "Historial de tasas reiniciado, reanudando webhooks desde la secuencia actual", "last_sequence", lastSequence)
+			lastSequence = 0
+		}
+		for _, change := range missed {
+			lastSequence = change.Sequence
+			s.dispatch(ctx, change)
+		}
+
+		for change := range events {
+			if change.Sequence != 0 && change.Sequence <= lastSequence {
+				continue
+			}
+			if change.Sequence != 0 {
+				lastSequence = change.Sequence
+			}
+			s.dispatch(ctx, change)
+		}
+
+		select {
+		case <-ctx.Done():
+			slog.InfoContext(ctx, "Deteniendo entrega de webhooks")
+			s.inflight.Wait()
+			return
+		case <-time.After(time.Second):
+			// El broker cerró la suscripción: se reintenta sin saturar si se está cerrando
+		}
+	}
+}
+
+// dispatch inicia la entrega de un cambio a cada webhook activo suscrito a la moneda.
+func (s *WebhookService) dispatch(ctx context.Context, change *entity.RateChange) {
+	webhooks, err := s.webhooks.FindAll(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error obteniendo webhooks", "error", err)
+		return
+	}
+
+	for _, webhook := range webhooks {
+		if !webhook.Accepts(change) {
+			continue
+		}
+
+		s.inflight.Add(1)
+		go func(webhook *entity.Webhook) {
+			defer s.inflight.Done()
+			s.deliver(ctx, webhook, change)
+		}(webhook)
+	}
+}
+
+// deliver entrega un cambio a un webhook reintentando con espera exponencial,
+// y actualiza el contador de fallos consecutivos con el resultado.
+func (s *WebhookService) deliver(ctx context.Context, webhook *entity.Webhook, change *entity.RateChange) {
+	deliveryID := "dlv_" + randomHex(12)
+	ctx = requestid.WithID(ctx, "webhook-"+deliveryID)
+
+	payload, err := json.Marshal(webhookPayload{
+		ID:        deliveryID,
+		Event:     entity.WebhookEventRateChanged,
+		CreatedAt: time.Now(),
+		Data:      change,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Error codificando evento de webhook", "error", err)
+		return
+	}
+
+	backoff := s.policy.Backoff
+	for attempt := 1; attempt <= s.policy.MaxAttempts; attempt++ {
+		start := time.Now()
+		status, err := s.sender.Send(ctx, webhook, service.WebhookRequest{
+			DeliveryID: deliveryID,
+			Event:      entity.WebhookEventRateChanged,
+			Attempt:    attempt,
+			Payload:    payload,
+		})
+
+		delivery := &entity.WebhookDelivery{
+			DeliveryID:  deliveryID,
+			WebhookID:   webhook.ID,
+			Event:       entity.WebhookEventRateChanged,
+			Sequence:    change.Sequence,
+			Attempt:     attempt,
+			StatusCode:  status,
+			Success:     err == nil,
+			DurationMs:  time.Since(start).Milliseconds(),
+			AttemptedAt: start,
+		}
+		if err != nil {
+			delivery.Error = err.Error()
+		}
+		if appendErr := s.deliveries.Append(ctx, delivery); appendErr != nil {
+			slog.WarnContext(ctx, "Error registrando entrega de webhook", "webhook", webhook.ID, "error", appendErr)
+		}
+
+		if err == nil {
+			slog.DebugContext(ctx, "Webhook entregado", "webhook", webhook.ID, "attempt", attempt, "status", status)
+			s.recordResult(ctx, webhook.ID, nil)
+			return
+		}
+
+		slog.WarnContext(ctx, "Error entregando webhook", "webhook", webhook.ID, "attempt", attempt, "error", err)
+		if attempt == s.policy.MaxAttempts {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, s.policy.MaxBackoff)
+	}
+
+	s.recordResult(ctx, webhook.ID, fmt.Errorf("delivery %s failed after %d attempts", deliveryID, s.policy.MaxAttempts))
+}
+
+// recordResult actualiza el contador de entregas fallidas consecutivas de un webhook
+// y lo desactiva al alcanzar el límite de la política.
+func (s *WebhookService) recordResult(ctx context.Context, id string, failure error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	webhook, err := s.webhooks.FindByID(ctx, id)
+	if err != nil || webhook == nil {
+		// Eliminado mientras se entregaba
+		return
+	}
+
+	if failure == nil {
+		if webhook.ConsecutiveFailures == 0 {
+			return
+		}
+		webhook.ConsecutiveFailures = 0
+	} else {
+		webhook.ConsecutiveFailures++
+		if s.policy.DisableAfter > 0 && webhook.ConsecutiveFailures >= s.policy.DisableAfter && !webhook.Disabled {
+			webhook.Disabled = true
+			webhook.DisabledReason = fmt.Sprintf("disabled after %d consecutive failed deliveries: %v",
+				webhook.ConsecutiveFailures, failure)
+			slog.WarnContext(ctx, "Webhook desactivado por fallos consecutivos",
+				"webhook", webhook.ID, "failures", webhook.ConsecutiveFailures)
+		}
+	}
+	webhook.UpdatedAt = time.Now()
+
+	if err := s.webhooks.Save(ctx, webhook); err != nil {
+		slog.ErrorContext(ctx, "Error actualizando estado del webhook", "webhook", id, "error", err)
+	}
+}
+
+// checkDestination resuelve el host de la URL y rechaza los que apuntan a direcciones
+// que no son públicas. El emisor vuelve a validar la IP al conectar.
+func (s *WebhookService) checkDestination(ctx context.Context, rawURL string) error {
+	if s.policy.AllowPrivateNetworks {
+		return nil
+	}
+
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidWebhook, err)
+	}
+
+	host := parsed.Hostname()
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return fmt.Errorf("%w: cannot resolve url host %q", ErrInvalidWebhook, host)
+	}
+	for _, addr := range addrs {
+		if !entity.IsPublicWebhookAddress(addr) {
+			return fmt.Errorf("%w: url host %q resolves to non-public address %s", ErrInvalidWebhook, host, addr)
+		}
+	}
+
+	return nil
+}
+
+// find obtiene un webhook o ErrWebhookNotFound.
+func (s *WebhookService) find(ctx context.Context, id string) (*entity.Webhook, error) {
+	webhook, err := s.webhooks.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if webhook == nil {
+		return nil, ErrWebhookNotFound
+	}
+	return webhook, nil
+}
+
+// applyWebhookInput aplica y valida los campos indicados por el cliente.
+func applyWebhookInput(webhook *entity.Webhook, input WebhookInput) error {
+	if input.URL != nil {
+		if !entity.IsValidWebhookURL(*input.URL) {
+			return fmt.Errorf("%w: url must be an absolute http or https URL", ErrInvalidWebhook)
+		}
+		webhook.URL = *input.URL
+	}
+
+	if input.Secret != nil {
+		if len(*input.Secret) < 16 {
+			return fmt.Errorf("%w: secret must have at least 16 characters", ErrInvalidWebhook)
+		}
+		webhook.Secret = *input.Secret
+	}
+
+	if input.Currencies != nil {
+		currencies := make([]string, 0, len(*input.Currencies))
+		for _, id := range *input.Currencies {
+			id = strings.ToUpper(strings.TrimSpace(id))
+			if len(id) != 3 {
+				return fmt.Errorf("%w: invalid currency %q", ErrInvalidWebhook, id)
+			}
+			currencies = append(currencies, id)
+		}
+		webhook.Currencies = currencies
+	}
+
+	if input.Description != nil {
+		webhook.Description = *input.Description
+	}
+
+	if input.Disabled != nil {
+		webhook.Disabled = *input.Disabled
+		if !webhook.Disabled {
+			webhook.DisabledReason = ""
+			webhook.ConsecutiveFailures = 0
+		}
+	}
+
+	return nil
+}
+
+// redactWebhook retorna una copia del webhook sin su secreto.
+func redactWebhook(webhook *entity.Webhook) *entity.Webhook {
+	redacted := *webhook
+	redacted.Secret = ""
+	return &redacted
+}
+
+// randomHex genera n bytes aleatorios en hexadecimal.
+func randomHex(n int) string {
+	buf := make([]byte, n)
+	rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
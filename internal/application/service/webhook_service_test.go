@@ -0,0 +1,281 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"gobcv/internal/domain/entity"
+	"gobcv/internal/infrastructure/cache"
+	"gobcv/internal/infrastructure/events"
+	"gobcv/internal/infrastructure/webhook"
+)
+
+// receivedDelivery es una entrega recibida por el receptor de prueba.
+type receivedDelivery struct {
+	header http.Header
+	body   []byte
+}
+
+// webhookReceiver es un receptor de webhooks en proceso que responde con status.
+type webhookReceiver struct {
+	*httptest.Server
+
+	mutex      sync.Mutex
+	status     int
+	deliveries []receivedDelivery
+}
+
+// newWebhookReceiver inicia un receptor que responde 204 hasta que se cambie su status.
+func newWebhookReceiver(t *testing.T) *webhookReceiver {
+	t.Helper()
+
+	receiver := &webhookReceiver{status: http.StatusNoContent}
+	receiver.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		receiver.mutex.Lock()
+		receiver.deliveries = append(receiver.deliveries, receivedDelivery{header: r.Header.Clone(), body: body})
+		status := receiver.status
+		receiver.mutex.Unlock()
+
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(receiver.Close)
+
+	return receiver
+}
+
+// setStatus cambia el status con el que responde el receptor.
+func (r *webhookReceiver) setStatus(status int) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.status = status
+}
+
+// received retorna las entregas recibidas hasta el momento.
+func (r *webhookReceiver) received() []receivedDelivery {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return append([]receivedDelivery(nil), r.deliveries...)
+}
+
+// testWebhookPolicy reintenta sin esperas largas y admite el receptor en loopback.
+func testWebhookPolicy() WebhookPolicy {
+	return WebhookPolicy{
+		MaxAttempts:          3,
+		Backoff:              time.Millisecond,
+		MaxBackoff:           2 * time.Millisecond,
+		DisableAfter:         2,
+		AllowPrivateNetworks: true,
+	}
+}
+
+// newTestWebhookService crea un WebhookService con repositorios en memoria y el emisor HTTP.
+func newTestWebhookService(policy WebhookPolicy) (*WebhookService, *events.Broker) {
+	broker := events.NewBroker()
+	service := NewWebhookService(
+		cache.NewMemoryWebhookRepository(),
+		cache.NewMemoryWebhookDeliveryRepository(100),
+		webhook.NewHTTPSender(time.Second, policy.AllowPrivateNetworks),
+		broker,
+		cache.NewMemoryRateHistory(10),
+		policy,
+	)
+	return service, broker
+}
+
+// testRateChange crea un cambio de USD con la secuencia indicada.
+func testRateChange(sequence uint64) *entity.RateChange {
+	return &entity.RateChange{
+		Sequence:   sequence,
+		CurrencyID: "USD",
+		Value:      36.5,
+		ChangedAt:  time.Now(),
+	}
+}
+
+// deliverAndWait entrega un cambio a los webhooks y espera que terminen los reintentos.
+func deliverAndWait(service *WebhookService, change *entity.RateChange) {
+	service.dispatch(context.Background(), change)
+	service.inflight.Wait()
+}
+
+func TestWebhookServiceDeliversSignedEvents(t *testing.T) {
+	ctx := context.Background()
+	receiver := newWebhookReceiver(t)
+	service, broker := newTestWebhookService(testWebhookPolicy())
+
+	url := receiver.URL + "/hook"
+	created, err := service.Create(ctx, WebhookInput{URL: &url})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	// Entrega de extremo a extremo: el broker publica y Start despacha
+	startCtx, stop := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		service.Start(startCtx)
+		close(done)
+	}()
+	defer func() {
+		stop()
+		<-done
+	}()
+
+	for broker.Subscribers() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	broker.Publish(ctx, []*entity.RateChange{testRateChange(1)})
+
+	deadline := time.Now().Add(2 * time.Second)
+	for len(receiver.received()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	received := receiver.received()
+	if len(received) != 1 {
+		t.Fatalf("received %d deliveries, want 1", len(received))
+	}
+	delivery := received[0]
+
+	if err := webhook.Verify(created.Secret, delivery.header.Get(webhook.SignatureHeader), delivery.body, time.Minute); err != nil {
+		t.Errorf("Verify: %v", err)
+	}
+	if err := webhook.Verify("whsec_other", delivery.header.Get(webhook.SignatureHeader), delivery.body, time.Minute); err == nil {
+		t.Error("Verify accepted a signature with another secret")
+	}
+	if got := delivery.header.Get(webhook.EventHeader); got != entity.WebhookEventRateChanged {
+		t.Errorf("%s = %q", webhook.EventHeader, got)
+	}
+	if got := delivery.header.Get(webhook.AttemptHeader); got != "1" {
+		t.Errorf("%s = %q, want 1", webhook.AttemptHeader, got)
+	}
+
+	// El registro se escribe después de recibir la respuesta
+	var log []*entity.WebhookDelivery
+	for time.Now().Before(deadline) {
+		if log, _ = service.Deliveries(ctx, created.ID, 0); len(log) > 0 {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if len(log) != 1 || !log[0].Success || log[0].StatusCode != http.StatusNoContent || log[0].Sequence != 1 {
+		t.Fatalf("delivery log = %+v, want one successful 204 delivery of sequence 1", log)
+	}
+	if log[0].DeliveryID != delivery.header.Get(webhook.DeliveryHeader) {
+		t.Errorf("logged delivery %q, sent %q", log[0].DeliveryID, delivery.header.Get(webhook.DeliveryHeader))
+	}
+}
+
+func TestWebhookServiceRetriesAndDisables(t *testing.T) {
+	ctx := context.Background()
+	receiver := newWebhookReceiver(t)
+	receiver.setStatus(http.StatusInternalServerError)
+	policy := testWebhookPolicy()
+	service, _ := newTestWebhookService(policy)
+
+	url := receiver.URL
+	created, err := service.Create(ctx, WebhookInput{URL: &url})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	deliverAndWait(service, testRateChange(1))
+
+	received := receiver.received()
+	if len(received) != policy.MaxAttempts {
+		t.Fatalf("received %d attempts, want %d", len(received), policy.MaxAttempts)
+	}
+	deliveryID := received[0].header.Get(webhook.DeliveryHeader)
+	for i, delivery := range received {
+		if got := delivery.header.Get(webhook.AttemptHeader); got != strconv.Itoa(i+1) {
+			t.Errorf("attempt %d: %s = %q", i+1, webhook.AttemptHeader, got)
+		}
+		if got := delivery.header.Get(webhook.DeliveryHeader); got != deliveryID {
+			t.Errorf("attempt %d: delivery id %q, want %q shared by retries", i+1, got, deliveryID)
+		}
+	}
+
+	log, err := service.Deliveries(ctx, created.ID, 0)
+	if err != nil || len(log) != policy.MaxAttempts {
+		t.Fatalf("delivery log = %d entries, %v, want %d", len(log), err, policy.MaxAttempts)
+	}
+	for _, entry := range log {
+		if entry.Success || entry.StatusCode != http.StatusInternalServerError || entry.Error == "" {
+			t.Errorf("log entry = %+v, want a failed 500 attempt", entry)
+		}
+	}
+
+	current, _ := service.Get(ctx, created.ID)
+	if current.ConsecutiveFailures != 1 || current.Disabled {
+		t.Fatalf("after one failed delivery: %+v", current)
+	}
+
+	// La segunda entrega fallida alcanza DisableAfter
+	deliverAndWait(service, testRateChange(2))
+	current, _ = service.Get(ctx, created.ID)
+	if !current.Disabled || current.ConsecutiveFailures != policy.DisableAfter || current.DisabledReason == "" {
+		t.Fatalf("after %d failed deliveries: %+v, want disabled", policy.DisableAfter, current)
+	}
+
+	// Desactivado, no recibe más entregas
+	before := len(receiver.received())
+	deliverAndWait(service, testRateChange(3))
+	if after := len(receiver.received()); after != before {
+		t.Errorf("disabled webhook received %d more requests", after-before)
+	}
+
+	// Reactivarlo reinicia el contador y una entrega exitosa lo mantiene en cero
+	receiver.setStatus(http.StatusOK)
+	enabled := false
+	updated, err := service.Update(ctx, created.ID, WebhookInput{Disabled: &enabled})
+	if err != nil || updated.Disabled || updated.ConsecutiveFailures != 0 {
+		t.Fatalf("Update = %+v, %v, want enabled", updated, err)
+	}
+	deliverAndWait(service, testRateChange(4))
+	current, _ = service.Get(ctx, created.ID)
+	if current.Disabled || current.ConsecutiveFailures != 0 {
+		t.Errorf("after a successful delivery: %+v", current)
+	}
+}
+
+func TestWebhookServiceRejectsPrivateDestinations(t *testing.T) {
+	ctx := context.Background()
+	policy := testWebhookPolicy()
+	policy.AllowPrivateNetworks = false
+	service, _ := newTestWebhookService(policy)
+
+	for _, url := range []string{
+		"http://127.0.0.1:8080/hook",
+		"http://localhost/hook",
+		"http://[::1]/hook",
+		"http://169.254.169.254/latest/meta-data",
+		"http://10.0.0.1/hook",
+		"http://192.168.1.10/hook",
+		"http://0.0.0.0/hook",
+		"http://[::ffff:127.0.0.1]/hook",
+	} {
+		if _, err := service.Create(ctx, WebhookInput{URL: &url}); !errors.Is(err, ErrInvalidWebhook) {
+			t.Errorf("Create(%s) error = %v, want ErrInvalidWebhook", url, err)
+		}
+	}
+
+	public := "https://93.184.215.14/hook"
+	created, err := service.Create(ctx, WebhookInput{URL: &public})
+	if err != nil {
+		t.Fatalf("Create(public IP): %v", err)
+	}
+
+	private := "http://127.0.0.1/hook"
+	if _, err := service.Update(ctx, created.ID, WebhookInput{URL: &private}); !errors.Is(err, ErrInvalidWebhook) {
+		t.Errorf("Update to a private URL error = %v, want ErrInvalidWebhook", err)
+	}
+}
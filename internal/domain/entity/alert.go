@@ -0,0 +1,120 @@
+// Package entity contiene las entidades de dominio del sistema.
+package entity
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Tipos de reglas de alerta.
+const (
+	// AlertChangePercent se dispara cuando la variación respecto del valor de 24 horas
+	// antes alcanza el umbral, en porcentaje y en cualquier dirección.
+	AlertChangePercent = "change_percent"
+	// AlertAbove se dispara cuando el valor cruza el umbral hacia arriba.
+	AlertAbove = "above"
+	// AlertBelow se dispara cuando el valor cruza el umbral hacia abajo.
+	AlertBelow = "below"
+)
+
+// AlertRule representa una regla de alerta sobre el valor de una moneda.
+type AlertRule struct {
+	ID              string    `json:"id"`
+	CurrencyID      string    `json:"currency_id"`
+	Type            string    `json:"type"`
+	Threshold       float64   `json:"threshold"`
+	CooldownSeconds int64     `json:"cooldown_seconds"`
+	Notifiers       []string  `json:"notifiers,omitempty"` // Vacío envía a todos los configurados
+	Description     string    `json:"description,omitempty"`
+	Disabled        bool      `json:"disabled"`
+	LastTriggeredAt time.Time `json:"last_triggered_at,omitempty"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// IsValid verifica si la regla tiene datos válidos.
+func (r *AlertRule) IsValid() bool {
+	if r.ID == "" || len(r.CurrencyID) != 3 || r.Threshold <= 0 || r.CooldownSeconds < 0 {
+		return false
+	}
+	return r.Type == AlertChangePercent || r.Type == AlertAbove || r.Type == AlertBelow
+}
+
+// InCooldown verifica si la regla se disparó hace menos de su tiempo de enfriamiento.
+func (r *AlertRule) InCooldown(now time.Time) bool {
+	return !r.LastTriggeredAt.IsZero() && now.Sub(r.LastTriggeredAt) < r.Cooldown()
+}
+
+// Cooldown retorna el tiempo mínimo entre dos disparos de la regla.
+func (r *AlertRule) Cooldown() time.Duration {
+	return time.Duration(r.CooldownSeconds) * time.Second
+}
+
+// Evaluate verifica si el cambio dispara la regla. reference es el valor de 24 horas
+// antes y solo se usa en las reglas de variación porcentual; 0 indica que no se conoce.
+func (r *AlertRule) Evaluate(change *RateChange, reference float64) (*Alert, bool) {
+	if r.Disabled || change.CurrencyID != r.CurrencyID {
+		return nil, false
+	}
+
+	alert := &Alert{
+		RuleID:        r.ID,
+		CurrencyID:    change.CurrencyID,
+		Type:          r.Type,
+		Threshold:     r.Threshold,
+		Value:         change.Value,
+		PreviousValue: change.PreviousValue,
+		Sequence:      change.Sequence,
+		TriggeredAt:   time.Now(),
+	}
+
+	switch r.Type {
+	case AlertChangePercent:
+		if reference == 0 {
+			return nil, false
+		}
+		percent := (change.Value - reference) / reference * 100
+		if math.Abs(percent) < r.Threshold {
+			return nil, false
+		}
+		alert.ReferenceValue = reference
+		alert.ChangePercent = percent
+		alert.Message = fmt.Sprintf("%s varió %.2f%% en 24 horas (%.4f -> %.4f)",
+			change.CurrencyID, percent, reference, change.Value)
+	case AlertAbove:
+		if change.PreviousValue == 0 || change.PreviousValue >= r.Threshold || change.Value < r.Threshold {
+			return nil, false
+		}
+		alert.Message = fmt.Sprintf("%s subió por encima de %.4f (%.4f -> %.4f)",
+			change.CurrencyID, r.Threshold, change.PreviousValue, change.Value)
+	case AlertBelow:
+		if change.PreviousValue == 0 || change.PreviousValue <= r.Threshold || change.Value > r.Threshold {
+			return nil, false
+		}
+		alert.Message = fmt.Sprintf("%s bajó por debajo de %.4f (%.4f -> %.4f)",
+			change.CurrencyID, r.Threshold, change.PreviousValue, change.Value)
+	default:
+		return nil, false
+	}
+
+	return alert, true
+}
+
+// Alert representa el disparo de una regla de alerta.
+type Alert struct {
+	ID             string    `json:"id"`
+	RuleID         string    `json:"rule_id"`
+	CurrencyID     string    `json:"currency_id"`
+	Type           string    `json:"type"`
+	Threshold      float64   `json:"threshold"`
+	Value          float64   `json:"value"`
+	PreviousValue  float64   `json:"previous_value,omitempty"`
+	ReferenceValue float64   `json:"reference_value,omitempty"`
+	ChangePercent  float64   `json:"change_percent,omitempty"`
+	Sequence       uint64    `json:"sequence,omitempty"`
+	Message        string    `json:"message"`
+	Notified       []string  `json:"notified,omitempty"`
+	Errors         []string  `json:"errors,omitempty"`
+	TriggeredAt    time.Time `json:"triggered_at"`
+}
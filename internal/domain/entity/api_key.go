@@ -0,0 +1,48 @@
+// Package entity contiene las entidades de dominio del sistema.
+package entity
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+// Scopes disponibles para las claves de API.
+const (
+	ScopeRead    = "read"
+	ScopeRefresh = "refresh"
+	ScopeAdmin   = "admin"
+)
+
+// APIKey representa una clave de API. La clave en claro nunca se almacena,
+// solo su hash.
+type APIKey struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	KeyHash   string    `json:"key_hash"`
+	Scopes    []string  `json:"scopes"`
+	Disabled  bool      `json:"disabled"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// HashAPIKey calcula el hash con el que se almacena una clave de API.
+// Las claves son tokens aleatorios de alta entropía, por lo que basta con SHA-256.
+func HashAPIKey(rawKey string) string {
+	sum := sha256.Sum256([]byte(rawKey))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsValid verifica si la clave tiene datos válidos.
+func (k *APIKey) IsValid() bool {
+	return k.ID != "" && len(k.KeyHash) == sha256.Size*2 && len(k.Scopes) > 0
+}
+
+// HasScope verifica si la clave otorga un scope. El scope admin otorga todos.
+func (k *APIKey) HasScope(scope string) bool {
+	for _, granted := range k.Scopes {
+		if granted == scope || granted == ScopeAdmin {
+			return true
+		}
+	}
+	return false
+}
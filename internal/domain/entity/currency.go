@@ -7,11 +7,11 @@ import (
 
 // Currency representa una moneda con su valor de cambio.
 type Currency struct {
-	ID        string    `json:"id"`
-	Name      string    `json:"name"`
-	Value     float64   `json:"value"`
-	UpdatedAt time.Time `json:"updated_at"`
-	Source    string    `json:"source"`
+	ID        string    `json:"id" xml:"id"`
+	Name      string    `json:"name" xml:"name"`
+	Value     float64   `json:"value" xml:"value"`
+	UpdatedAt time.Time `json:"updated_at" xml:"updated_at"`
+	Source    string    `json:"source" xml:"source"`
 }
 
 // NewCurrency crea una nueva instancia de Currency.
@@ -0,0 +1,58 @@
+// Package entity contiene las entidades de dominio del sistema.
+package entity
+
+import (
+	"time"
+)
+
+// RateChange representa un cambio en el valor de una moneda detectado al actualizar.
+// Sequence es asignado por el historial y crece de forma estricta, por lo que sirve
+// para reanudar un flujo de eventos desde el último recibido.
+type RateChange struct {
+	Sequence      uint64    `json:"sequence" xml:"sequence"`
+	CurrencyID    string    `json:"currency_id" xml:"currency_id"`
+	Name          string    `json:"name" xml:"name"`
+	Value         float64   `json:"value" xml:"value"`
+	PreviousValue float64   `json:"previous_value,omitempty" xml:"previous_value,omitempty"`
+	Source        string    `json:"source" xml:"source"`
+	ChangedAt     time.Time `json:"changed_at" xml:"changed_at"`
+}
+
+// NewRateChange crea el cambio de una moneda respecto de su valor anterior.
+// previous puede ser nil si la moneda no existía.
+func NewRateChange(current, previous *Currency) *RateChange {
+	change := &RateChange{
+		CurrencyID: current.ID,
+		Name:       current.Name,
+		Value:      current.Value,
+		Source:     current.Source,
+		ChangedAt:  time.Now(),
+	}
+	if previous != nil {
+		change.PreviousValue = previous.Value
+	}
+	return change
+}
+
+// ChangePercent retorna la variación porcentual respecto del valor anterior,
+// o 0 si no había valor anterior.
+func (c *RateChange) ChangePercent() float64 {
+	if c.PreviousValue == 0 {
+		return 0
+	}
+	return (c.Value - c.PreviousValue) / c.PreviousValue * 100
+}
+
+// MatchesCurrency verifica si el cambio corresponde a alguna de las monedas indicadas.
+// Una lista vacía coincide con todas.
+func (c *RateChange) MatchesCurrency(currencyIDs []string) bool {
+	if len(currencyIDs) == 0 {
+		return true
+	}
+	for _, id := range currencyIDs {
+		if id == c.CurrencyID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,75 @@
+// Package entity contiene las entidades de dominio del sistema.
+package entity
+
+import (
+	"net/netip"
+	"net/url"
+	"time"
+)
+
+// WebhookEventRateChanged es el evento enviado cuando cambia la tasa de una moneda.
+const WebhookEventRateChanged = "rate.changed"
+
+// Webhook representa una suscripción HTTP a los cambios de tasas. El secreto se
+// conserva en claro porque se necesita para firmar cada entrega.
+type Webhook struct {
+	ID                  string    `json:"id"`
+	URL                 string    `json:"url"`
+	Secret              string    `json:"secret,omitempty"`
+	Currencies          []string  `json:"currencies,omitempty"`
+	Description         string    `json:"description,omitempty"`
+	Disabled            bool      `json:"disabled"`
+	DisabledReason      string    `json:"disabled_reason,omitempty"`
+	ConsecutiveFailures int       `json:"consecutive_failures"`
+	CreatedAt           time.Time `json:"created_at"`
+	UpdatedAt           time.Time `json:"updated_at"`
+}
+
+// IsValid verifica si el webhook tiene datos válidos.
+func (w *Webhook) IsValid() bool {
+	return w.ID != "" && w.Secret != "" && IsValidWebhookURL(w.URL)
+}
+
+// Accepts verifica si el webhook está activo y suscrito a la moneda del cambio.
+func (w *Webhook) Accepts(change *RateChange) bool {
+	return !w.Disabled && change.MatchesCurrency(w.Currencies)
+}
+
+// IsValidWebhookURL verifica que la URL sea absoluta y use http o https.
+func IsValidWebhookURL(rawURL string) bool {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	return (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != ""
+}
+
+// IsPublicWebhookAddress verifica que una IP de destino de webhooks sea pública: se
+// rechazan las de loopback, enlace local, redes privadas, no especificadas y multicast
+// para que los webhooks no alcancen servicios internos.
+func IsPublicWebhookAddress(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsValid() &&
+		!addr.IsLoopback() &&
+		!addr.IsLinkLocalUnicast() &&
+		!addr.IsLinkLocalMulticast() &&
+		!addr.IsInterfaceLocalMulticast() &&
+		!addr.IsMulticast() &&
+		!addr.IsPrivate() &&
+		!addr.IsUnspecified()
+}
+
+// WebhookDelivery registra un intento de entrega de un evento a un webhook.
+// Los reintentos de una misma entrega comparten DeliveryID.
+type WebhookDelivery struct {
+	DeliveryID  string    `json:"delivery_id"`
+	WebhookID   string    `json:"webhook_id"`
+	Event       string    `json:"event"`
+	Sequence    uint64    `json:"sequence"`
+	Attempt     int       `json:"attempt"`
+	StatusCode  int       `json:"status_code,omitempty"`
+	Success     bool      `json:"success"`
+	Error       string    `json:"error,omitempty"`
+	DurationMs  int64     `json:"duration_ms"`
+	AttemptedAt time.Time `json:"attempted_at"`
+}
@@ -0,0 +1,30 @@
+// Package repository define los puertos para el acceso a datos.
+package repository
+
+import (
+	"context"
+
+	"gobcv/internal/domain/entity"
+)
+
+// AlertRepository define el puerto para las reglas de alerta y las alertas disparadas.
+type AlertRepository interface {
+	// SaveRule guarda o actualiza una regla.
+	SaveRule(ctx context.Context, rule *entity.AlertRule) error
+
+	// FindRuleByID busca una regla por su ID.
+	FindRuleByID(ctx context.Context, id string) (*entity.AlertRule, error)
+
+	// FindRules obtiene todas las reglas registradas.
+	FindRules(ctx context.Context) ([]*entity.AlertRule, error)
+
+	// DeleteRule elimina una regla por su ID.
+	DeleteRule(ctx context.Context, id string) error
+
+	// AppendAlert registra una alerta disparada.
+	AppendAlert(ctx context.Context, alert *entity.Alert) error
+
+	// FindAlerts obtiene las alertas más recientes, de la más nueva a la más antigua.
+	// limit <= 0 no limita.
+	FindAlerts(ctx context.Context, limit int) ([]*entity.Alert, error)
+}
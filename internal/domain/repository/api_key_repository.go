@@ -0,0 +1,23 @@
+// Package repository define los puertos para el acceso a datos.
+package repository
+
+import (
+	"context"
+
+	"gobcv/internal/domain/entity"
+)
+
+// APIKeyRepository define el puerto para el repositorio de claves de API.
+type APIKeyRepository interface {
+	// Save guarda o actualiza una clave de API.
+	Save(ctx context.Context, key *entity.APIKey) error
+
+	// FindByHash busca una clave por el hash de su valor.
+	FindByHash(ctx context.Context, keyHash string) (*entity.APIKey, error)
+
+	// FindAll obtiene todas las claves registradas.
+	FindAll(ctx context.Context) ([]*entity.APIKey, error)
+
+	// Delete elimina una clave por su ID.
+	Delete(ctx context.Context, id string) error
+}
@@ -0,0 +1,51 @@
+// Package repository define los puertos para el acceso a datos.
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gobcv/internal/domain/entity"
+)
+
+// RateHistoryRepository define el puerto para el historial de cambios de tasas.
+type RateHistoryRepository interface {
+	// Append agrega un cambio al historial y le asigna el siguiente número de secuencia.
+	Append(ctx context.Context, change *entity.RateChange) error
+
+	// FindSince obtiene, en orden, hasta limit cambios posteriores a la secuencia indicada,
+	// filtrados por moneda si currencyIDs no está vacío. limit <= 0 no limita.
+	FindSince(ctx context.Context, afterSequence uint64, currencyIDs []string, limit int) ([]*entity.RateChange, error)
+
+	// FindByCurrency obtiene los cambios de una moneda dentro del rango [from, to].
+	FindByCurrency(ctx context.Context, currencyID string, from, to time.Time) ([]*entity.RateChange, error)
+
+	// LastSequence retorna la secuencia del último cambio registrado, o 0 si no hay ninguno.
+	LastSequence(ctx context.Context) (uint64, error)
+}
+
+// FindResumeBacklog obtiene los cambios posteriores a afterSequence para reanudar una
+// suscripción. Si afterSequence supera la última secuencia del historial, las secuencias
+// se reiniciaron (por ejemplo, el historial en memoria tras reiniciar el proceso): retorna
+// reset en true y el suscriptor debe descartar su cursor y partir del estado actual.
+func FindResumeBacklog(
+	ctx context.Context,
+	history RateHistoryRepository,
+	afterSequence uint64,
+	currencyIDs []string,
+) (changes []*entity.RateChange, reset bool, err error) {
+	if afterSequence == 0 {
+		return nil, false, nil
+	}
+
+	last, err := history.LastSequence(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	if afterSequence > last {
+		return nil, true, nil
+	}
+
+	changes, err = history.FindSince(ctx, afterSequence, currencyIDs, 0)
+	return changes, false, err
+}
@@ -0,0 +1,36 @@
+// Package repository define los puertos para el acceso a datos.
+package repository
+
+import (
+	"context"
+
+	"gobcv/internal/domain/entity"
+)
+
+// WebhookRepository define el puerto para el repositorio de webhooks.
+type WebhookRepository interface {
+	// Save guarda o actualiza un webhook.
+	Save(ctx context.Context, webhook *entity.Webhook) error
+
+	// FindByID busca un webhook por su ID.
+	FindByID(ctx context.Context, id string) (*entity.Webhook, error)
+
+	// FindAll obtiene todos los webhooks registrados.
+	FindAll(ctx context.Context) ([]*entity.Webhook, error)
+
+	// Delete elimina un webhook por su ID.
+	Delete(ctx context.Context, id string) error
+}
+
+// WebhookDeliveryRepository define el puerto para el registro de entregas de webhooks.
+type WebhookDeliveryRepository interface {
+	// Append registra un intento de entrega.
+	Append(ctx context.Context, delivery *entity.WebhookDelivery) error
+
+	// FindByWebhook obtiene los intentos más recientes de un webhook, del más nuevo al
+	// más antiguo. limit <= 0 no limita.
+	FindByWebhook(ctx context.Context, webhookID string, limit int) ([]*entity.WebhookDelivery, error)
+
+	// DeleteByWebhook elimina los intentos registrados de un webhook.
+	DeleteByWebhook(ctx context.Context, webhookID string) error
+}
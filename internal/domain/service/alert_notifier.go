@@ -0,0 +1,23 @@
+// Package service define los puertos para notificar alertas y observar actualizaciones.
+package service
+
+import (
+	"context"
+
+	"gobcv/internal/domain/entity"
+)
+
+// AlertNotifier define el puerto para enviar alertas por un canal.
+type AlertNotifier interface {
+	// Name identifica el canal en las reglas de alerta (log, webhook, smtp).
+	Name() string
+
+	// Notify envía la alerta por el canal.
+	Notify(ctx context.Context, alert *entity.Alert) error
+}
+
+// RefreshObserver define el puerto para reaccionar al final de cada actualización de monedas.
+type RefreshObserver interface {
+	// OnRefresh recibe los cambios de tasas detectados en la actualización, posiblemente ninguno.
+	OnRefresh(ctx context.Context, changes []*entity.RateChange)
+}
@@ -8,15 +8,29 @@ import (
 
 // CacheService define el puerto para el servicio de caché.
 type CacheService interface {
-	// Set almacena un valor en el caché con una clave y TTL especificados.
-	Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error
+	// Set almacena un valor en el caché con una clave, TTL y etiquetas opcionales.
+	Set(ctx context.Context, key string, value interface{}, ttl time.Duration, tags ...string) error
+	
+	// SetWithStale almacena un valor que se considera fresco durante softTTL y que
+	// puede seguir sirviéndose como obsoleto hasta cumplirse hardTTL.
+	SetWithStale(ctx context.Context, key string, value interface{}, softTTL, hardTTL time.Duration, tags ...string) error
 	
 	// Get obtiene un valor del caché por su clave.
 	Get(ctx context.Context, key string) (interface{}, error)
 	
+	// GetEntry obtiene un valor junto con sus metadatos de frescura, incluyendo
+	// entradas obsoletas que aún no superan su TTL duro.
+	GetEntry(ctx context.Context, key string) (*CacheEntry, error)
+	
 	// Delete elimina un valor del caché.
 	Delete(ctx context.Context, key string) error
 	
+	// DeletePrefix elimina todos los valores cuya clave comienza con el prefijo indicado.
+	DeletePrefix(ctx context.Context, prefix string) error
+	
+	// InvalidateTag elimina todos los valores almacenados con la etiqueta indicada.
+	InvalidateTag(ctx context.Context, tag string) error
+	
 	// Exists verifica si una clave existe en el caché.
 	Exists(ctx context.Context, key string) (bool, error)
 	
@@ -27,9 +41,35 @@ type CacheService interface {
 	GetStats(ctx context.Context) (CacheStats, error)
 }
 
+// CurrenciesTag agrupa los valores en caché derivados del listado de monedas.
+const CurrenciesTag = "currencies"
+
+// CurrencyTag retorna la etiqueta que agrupa los valores en caché derivados de una moneda.
+func CurrencyTag(currencyID string) string {
+	return "currency:" + currencyID
+}
+
+// CacheEntry representa un valor del caché con sus metadatos de frescura.
+type CacheEntry struct {
+	Value    interface{} `json:"value"`
+	Tags     []string    `json:"tags,omitempty"`
+	StoredAt time.Time   `json:"stored_at"`
+	StaleAt  time.Time   `json:"stale_at"`
+	Stale    bool        `json:"stale"`
+}
+
+// Age retorna el tiempo transcurrido desde que se almacenó la entrada.
+func (e *CacheEntry) Age() time.Duration {
+	return time.Since(e.StoredAt)
+}
+
 // CacheStats representa las estadísticas del caché.
 type CacheStats struct {
-	Hits   int64 `json:"hits"`
-	Misses int64 `json:"misses"`
-	Keys   int64 `json:"keys"`
+	Hits      int64 `json:"hits"`
+	Misses    int64 `json:"misses"`
+	StaleHits int64 `json:"stale_hits"`
+	Keys      int64 `json:"keys"`
+
+	// Tiers contiene las estadísticas de cada nivel en cachés compuestos.
+	Tiers map[string]CacheStats `json:"tiers,omitempty"`
 }
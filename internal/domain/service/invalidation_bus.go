@@ -0,0 +1,30 @@
+// Package service define los puertos para la invalidación de caché entre réplicas.
+package service
+
+import (
+	"context"
+	"time"
+)
+
+// InvalidationEvent representa un conjunto de claves y etiquetas de caché invalidadas por una réplica.
+type InvalidationEvent struct {
+	Keys      []string  `json:"keys,omitempty"`
+	Tags      []string  `json:"tags,omitempty"`
+	Source    string    `json:"source"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// InvalidationHandler procesa los eventos de invalidación recibidos.
+type InvalidationHandler func(ctx context.Context, event InvalidationEvent)
+
+// InvalidationBus define el puerto para propagar invalidaciones de caché entre réplicas.
+type InvalidationBus interface {
+	// Publish notifica a todas las réplicas suscritas que las claves del evento fueron invalidadas.
+	Publish(ctx context.Context, event InvalidationEvent) error
+
+	// Subscribe registra un handler que recibe los eventos publicados hasta que se cancele el contexto.
+	Subscribe(ctx context.Context, handler InvalidationHandler) error
+
+	// Close libera los recursos del bus.
+	Close() error
+}
@@ -0,0 +1,24 @@
+// Package service define los puertos para la difusión de cambios de tasas.
+package service
+
+import (
+	"context"
+
+	"gobcv/internal/domain/entity"
+)
+
+// RateEventBroker define el puerto para difundir dentro del proceso los cambios
+// de tasas detectados al actualizar las monedas.
+type RateEventBroker interface {
+	// Publish entrega los cambios a todos los suscriptores interesados sin bloquear.
+	Publish(ctx context.Context, changes []*entity.RateChange)
+
+	// Subscribe retorna un canal con los cambios de las monedas indicadas (todas si
+	// currencyIDs está vacío). El canal se cierra al cancelar el contexto, al cerrar
+	// el broker o cuando el suscriptor no consume a tiempo y se llena su buffer; en
+	// ese caso puede reanudar desde el historial con la última secuencia recibida.
+	Subscribe(ctx context.Context, currencyIDs []string, buffer int) <-chan *entity.RateChange
+
+	// Close cierra todas las suscripciones.
+	Close() error
+}
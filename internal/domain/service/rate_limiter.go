@@ -0,0 +1,37 @@
+// Package service define los puertos para la limitación de peticiones.
+package service
+
+import (
+	"context"
+	"time"
+)
+
+// RateLimit define un token bucket: hasta Burst peticiones seguidas, recargando
+// Burst tokens de forma continua cada Period.
+type RateLimit struct {
+	Burst  int
+	Period time.Duration
+}
+
+// RefillInterval retorna el tiempo necesario para recargar un token.
+func (l RateLimit) RefillInterval() time.Duration {
+	if l.Burst <= 0 {
+		return l.Period
+	}
+	return l.Period / time.Duration(l.Burst)
+}
+
+// RateLimitResult contiene el resultado de consumir un token.
+type RateLimitResult struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	RetryAfter time.Duration // Tiempo hasta el próximo token si la petición fue rechazada
+	ResetAfter time.Duration // Tiempo hasta que el bucket vuelva a estar lleno
+}
+
+// RateLimitStore define el puerto para almacenar los token buckets de los clientes.
+type RateLimitStore interface {
+	// Take consume un token del bucket de la clave indicada.
+	Take(ctx context.Context, key string, limit RateLimit) (RateLimitResult, error)
+}
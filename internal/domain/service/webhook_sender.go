@@ -0,0 +1,23 @@
+// Package service define el puerto para el envío de webhooks.
+package service
+
+import (
+	"context"
+
+	"gobcv/internal/domain/entity"
+)
+
+// WebhookRequest es un intento de entrega de un evento a un webhook.
+type WebhookRequest struct {
+	DeliveryID string
+	Event      string
+	Attempt    int
+	Payload    []byte
+}
+
+// WebhookSender define el puerto para enviar eventos firmados a los webhooks.
+type WebhookSender interface {
+	// Send entrega el evento y retorna el código de estado recibido. Retorna error si
+	// la petición falla o el receptor no responde con un código 2xx.
+	Send(ctx context.Context, webhook *entity.Webhook, request WebhookRequest) (int, error)
+}
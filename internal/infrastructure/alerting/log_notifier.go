@@ -0,0 +1,32 @@
+// Package alerting implementa los canales de notificación de alertas.
+package alerting
+
+import (
+	"context"
+	"log/slog"
+
+	"gobcv/internal/domain/entity"
+	"gobcv/internal/domain/service"
+)
+
+// LogNotifier registra las alertas en el log de la aplicación.
+type LogNotifier struct{}
+
+// NewLogNotifier crea un notificador que escribe las alertas en el log.
+func NewLogNotifier() *LogNotifier {
+	return &LogNotifier{}
+}
+
+var _ service.AlertNotifier = (*LogNotifier)(nil)
+
+// Name identifica el canal.
+func (n *LogNotifier) Name() string {
+	return "log"
+}
+
+// Notify registra la alerta con nivel warning.
+func (n *LogNotifier) Notify(ctx context.Context, alert *entity.Alert) error {
+	slog.WarnContext(ctx, "Alerta de tasa", "rule", alert.RuleID, "currency", alert.CurrencyID,
+		"type", alert.Type, "value", alert.Value, "message", alert.Message)
+	return nil
+}
@@ -0,0 +1,92 @@
+// Package alerting implementa el envío de alertas por correo electrónico.
+package alerting
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"gobcv/internal/domain/entity"
+	"gobcv/internal/domain/service"
+)
+
+// SMTPConfig contiene los datos del servidor de correo y los destinatarios.
+type SMTPConfig struct {
+	Addr     string // host:puerto; en desarrollo puede ser un servidor local como MailHog
+	Username string // Vacío para servidores sin autenticación
+	Password string
+	From     string
+	To       []string
+}
+
+// SMTPNotifier envía las alertas por correo electrónico.
+type SMTPNotifier struct {
+	config SMTPConfig
+}
+
+// NewSMTPNotifier crea un notificador por correo electrónico.
+func NewSMTPNotifier(config SMTPConfig) *SMTPNotifier {
+	return &SMTPNotifier{config: config}
+}
+
+var _ service.AlertNotifier = (*SMTPNotifier)(nil)
+
+// Name identifica el canal.
+func (n *SMTPNotifier) Name() string {
+	return "smtp"
+}
+
+// Notify envía la alerta a los destinatarios configurados.
+func (n *SMTPNotifier) Notify(ctx context.Context, alert *entity.Alert) error {
+	var auth smtp.Auth
+	if n.config.Username != "" {
+		host, _, err := net.SplitHostPort(n.config.Addr)
+		if err != nil {
+			return fmt.Errorf("invalid smtp address: %w", err)
+		}
+		auth = smtp.PlainAuth("", n.config.Username, n.config.Password, host)
+	}
+
+	// net/smtp no acepta contexto: el envío se abandona al cancelarlo
+	done := make(chan error, 1)
+	go func() {
+		done <- smtp.SendMail(n.config.Addr, auth, n.config.From, n.config.To, n.message(alert))
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			return fmt.Errorf("error sending alert email: %w", err)
+		}
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// message construye el correo de la alerta.
+func (n *SMTPNotifier) message(alert *entity.Alert) []byte {
+	var builder strings.Builder
+
+	fmt.Fprintf(&builder, "From: %s\r\n", n.config.From)
+	fmt.Fprintf(&builder, "To: %s\r\n", strings.Join(n.config.To, ", "))
+	fmt.Fprintf(&builder, "Subject: [gobcv] Alerta %s: %s\r\n", alert.CurrencyID, alert.Type)
+	fmt.Fprintf(&builder, "Date: %s\r\n", alert.TriggeredAt.Format(time.RFC1123Z))
+	builder.WriteString("MIME-Version: 1.0\r\n")
+	builder.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	builder.WriteString("\r\n")
+	fmt.Fprintf(&builder, "%s\r\n\r\n", alert.Message)
+	fmt.Fprintf(&builder, "Regla: %s\r\n", alert.RuleID)
+	fmt.Fprintf(&builder, "Moneda: %s\r\n", alert.CurrencyID)
+	fmt.Fprintf(&builder, "Valor: %.4f\r\n", alert.Value)
+	if alert.ReferenceValue != 0 {
+		fmt.Fprintf(&builder, "Valor de referencia: %.4f (%.2f%%)\r\n", alert.ReferenceValue, alert.ChangePercent)
+	}
+	fmt.Fprintf(&builder, "Umbral: %.4f\r\n", alert.Threshold)
+	fmt.Fprintf(&builder, "Fecha: %s\r\n", alert.TriggeredAt.Format(time.RFC3339))
+
+	return []byte(builder.String())
+}
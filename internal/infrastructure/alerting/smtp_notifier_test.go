@@ -0,0 +1,168 @@
+package alerting
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+
+	"gobcv/internal/domain/entity"
+)
+
+// receivedMail es un correo recibido por el servidor SMTP de prueba.
+type receivedMail struct {
+	from string
+	to   []string
+	data string
+}
+
+// startSMTPServer inicia un servidor SMTP mínimo en proceso que acepta un correo y lo
+// envía por el canal retornado.
+func startSMTPServer(t *testing.T) (string, <-chan receivedMail) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	mails := make(chan receivedMail, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		text := textproto.NewConn(conn)
+		var mail receivedMail
+		text.PrintfLine("220 localhost ESMTP test")
+		for {
+			line, err := text.ReadLine()
+			if err != nil {
+				return
+			}
+
+			command := strings.ToUpper(line)
+			switch {
+			case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+				text.PrintfLine("250 localhost")
+			case strings.HasPrefix(command, "MAIL FROM:"):
+				mail.from = strings.Trim(line[len("MAIL FROM:"):], "<> ")
+				text.PrintfLine("250 OK")
+			case strings.HasPrefix(command, "RCPT TO:"):
+				mail.to = append(mail.to, strings.Trim(line[len("RCPT TO:"):], "<> "))
+				text.PrintfLine("250 OK")
+			case command == "DATA":
+				text.PrintfLine("354 End data with <CR><LF>.<CR><LF>")
+				data, err := text.ReadDotBytes()
+				if err != nil {
+					return
+				}
+				mail.data = string(data)
+				text.PrintfLine("250 OK")
+				mails <- mail
+			case command == "QUIT":
+				text.PrintfLine("221 Bye")
+				return
+			default:
+				text.PrintfLine("502 Command not implemented")
+			}
+		}
+	}()
+
+	return listener.Addr().String(), mails
+}
+
+func TestSMTPNotifierSendsAlert(t *testing.T) {
+	addr, mails := startSMTPServer(t)
+
+	notifier := NewSMTPNotifier(SMTPConfig{
+		Addr: addr,
+		From: "gobcv@example.com",
+		To:   []string{"ops@example.com", "finance@example.com"},
+	})
+
+	triggeredAt := time.Date(2024, 6, 10, 14, 30, 0, 0, time.UTC)
+	alert := &entity.Alert{
+		ID:             "alr_1",
+		RuleID:         "rule_1",
+		CurrencyID:     "USD",
+		Type:           entity.AlertChangePercent,
+		Threshold:      2,
+		Value:          37.23,
+		ReferenceValue: 36.5,
+		ChangePercent:  2,
+		Message:        "USD varió 2.00% en 24 horas (36.5000 -> 37.2300)",
+		TriggeredAt:    triggeredAt,
+	}
+
+	if err := notifier.Notify(context.Background(), alert); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+
+	var mail receivedMail
+	select {
+	case mail = <-mails:
+	case <-time.After(2 * time.Second):
+		t.Fatal("the SMTP server received no mail")
+	}
+
+	if mail.from != "gobcv@example.com" {
+		t.Errorf("MAIL FROM = %q", mail.from)
+	}
+	if strings.Join(mail.to, ",") != "ops@example.com,finance@example.com" {
+		t.Errorf("RCPT TO = %v", mail.to)
+	}
+
+	message, err := textproto.NewReader(bufio.NewReader(strings.NewReader(mail.data))).ReadMIMEHeader()
+	if err != nil {
+		t.Fatalf("ReadMIMEHeader: %v", err)
+	}
+	for name, want := range map[string]string{
+		"From":         "gobcv@example.com",
+		"To":           "ops@example.com, finance@example.com",
+		"Subject":      "[gobcv] Alerta USD: change_percent",
+		"Date":         triggeredAt.Format(time.RFC1123Z),
+		"Mime-Version": "1.0",
+		"Content-Type": "text/plain; charset=UTF-8",
+	} {
+		if got := message.Get(name); got != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+
+	_, body, _ := strings.Cut(mail.data, "\n\n")
+	for _, want := range []string{
+		alert.Message,
+		"Regla: rule_1",
+		"Moneda: USD",
+		"Valor: 37.2300",
+		"Valor de referencia: 36.5000 (2.00%)",
+		"Umbral: 2.0000",
+		"Fecha: 2024-06-10T14:30:00Z",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("body is missing %q:\n%s", want, body)
+		}
+	}
+}
+
+func TestSMTPNotifierReportsServerErrors(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+
+	notifier := NewSMTPNotifier(SMTPConfig{Addr: addr, From: "gobcv@example.com", To: []string{"ops@example.com"}})
+	err = notifier.Notify(context.Background(), &entity.Alert{CurrencyID: "USD", TriggeredAt: time.Now()})
+	if err == nil || !strings.Contains(err.Error(), "error sending alert email") {
+		t.Errorf("Notify without server error = %v", err)
+	}
+}
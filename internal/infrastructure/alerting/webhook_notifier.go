@@ -0,0 +1,57 @@
+// Package alerting implementa el envío de alertas a un webhook.
+package alerting
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"gobcv/internal/domain/entity"
+	"gobcv/internal/domain/service"
+)
+
+// WebhookEventAlert es el evento enviado al webhook de alertas.
+const WebhookEventAlert = "alert.triggered"
+
+// WebhookNotifier envía las alertas a una URL con el mismo formato y firma que los
+// webhooks de cambios de tasas.
+type WebhookNotifier struct {
+	webhook *entity.Webhook
+	sender  service.WebhookSender
+}
+
+// NewWebhookNotifier crea un notificador que envía las alertas firmadas con secret a url.
+func NewWebhookNotifier(url, secret string, sender service.WebhookSender) *WebhookNotifier {
+	return &WebhookNotifier{
+		webhook: &entity.Webhook{ID: "alerts", URL: url, Secret: secret},
+		sender:  sender,
+	}
+}
+
+var _ service.AlertNotifier = (*WebhookNotifier)(nil)
+
+// Name identifica el canal.
+func (n *WebhookNotifier) Name() string {
+	return "webhook"
+}
+
+// Notify envía la alerta al webhook.
+func (n *WebhookNotifier) Notify(ctx context.Context, alert *entity.Alert) error {
+	payload, err := json.Marshal(map[string]interface{}{
+		"id":         alert.ID,
+		"event":      WebhookEventAlert,
+		"created_at": alert.TriggeredAt,
+		"data":       alert,
+	})
+	if err != nil {
+		return fmt.Errorf("error encoding alert: %w", err)
+	}
+
+	_, err = n.sender.Send(ctx, n.webhook, service.WebhookRequest{
+		DeliveryID: alert.ID,
+		Event:      WebhookEventAlert,
+		Attempt:    1,
+		Payload:    payload,
+	})
+	return err
+}
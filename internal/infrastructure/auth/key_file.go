@@ -0,0 +1,39 @@
+// Package auth implementa la carga de claves de API desde archivos.
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"gobcv/internal/domain/entity"
+	"gobcv/internal/domain/repository"
+)
+
+// LoadKeyFile carga en el repositorio las claves definidas en un archivo JSON.
+// El archivo contiene una lista de claves con el hash SHA-256 en hexadecimal de
+// cada valor, nunca el valor en claro:
+//
+//	[{"id": "erp", "name": "ERP", "key_hash": "<sha256>", "scopes": ["read"]}]
+func LoadKeyFile(ctx context.Context, path string, keys repository.APIKeyRepository) (int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, fmt.Errorf("error reading api key file: %w", err)
+	}
+
+	var entries []*entity.APIKey
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return 0, fmt.Errorf("error parsing api key file %s: %w", path, err)
+	}
+
+	for _, key := range entries {
+		key.KeyHash = strings.ToLower(strings.TrimPrefix(key.KeyHash, "sha256:"))
+		if err := keys.Save(ctx, key); err != nil {
+			return 0, fmt.Errorf("error loading api key %q: %w", key.ID, err)
+		}
+	}
+
+	return len(entries), nil
+}
@@ -0,0 +1,136 @@
+// Package cache implementa el repositorio de alertas en memoria.
+package cache
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"gobcv/internal/domain/entity"
+	"gobcv/internal/domain/repository"
+)
+
+// MemoryAlertRepository implementa el repositorio de alertas en memoria.
+// Conserva como máximo historySize alertas disparadas descartando las más antiguas.
+type MemoryAlertRepository struct {
+	rules       map[string]*entity.AlertRule
+	alerts      []*entity.Alert
+	historySize int
+	mutex       sync.RWMutex
+}
+
+// NewMemoryAlertRepository crea un nuevo repositorio de alertas en memoria.
+func NewMemoryAlertRepository(historySize int) repository.AlertRepository {
+	return &MemoryAlertRepository{
+		rules:       make(map[string]*entity.AlertRule),
+		historySize: historySize,
+	}
+}
+
+// SaveRule guarda o actualiza una regla.
+func (r *MemoryAlertRepository) SaveRule(ctx context.Context, rule *entity.AlertRule) error {
+	if rule == nil {
+		return fmt.Errorf("alert rule cannot be nil")
+	}
+
+	if !rule.IsValid() {
+		return fmt.Errorf("alert rule %q is not valid", rule.ID)
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.rules[rule.ID] = copyAlertRule(rule)
+	return nil
+}
+
+// FindRuleByID busca una regla por su ID.
+func (r *MemoryAlertRepository) FindRuleByID(ctx context.Context, id string) (*entity.AlertRule, error) {
+	if id == "" {
+		return nil, fmt.Errorf("id cannot be empty")
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	rule, exists := r.rules[id]
+	if !exists {
+		return nil, nil
+	}
+
+	return copyAlertRule(rule), nil
+}
+
+// FindRules obtiene todas las reglas ordenadas por fecha de creación.
+func (r *MemoryAlertRepository) FindRules(ctx context.Context) ([]*entity.AlertRule, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	rules := make([]*entity.AlertRule, 0, len(r.rules))
+	for _, rule := range r.rules {
+		rules = append(rules, copyAlertRule(rule))
+	}
+
+	sort.Slice(rules, func(i, j int) bool {
+		return rules[i].CreatedAt.Before(rules[j].CreatedAt)
+	})
+
+	return rules, nil
+}
+
+// DeleteRule elimina una regla por su ID.
+func (r *MemoryAlertRepository) DeleteRule(ctx context.Context, id string) error {
+	if id == "" {
+		return fmt.Errorf("id cannot be empty")
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	delete(r.rules, id)
+	return nil
+}
+
+// AppendAlert registra una alerta disparada.
+func (r *MemoryAlertRepository) AppendAlert(ctx context.Context, alert *entity.Alert) error {
+	if alert == nil {
+		return fmt.Errorf("alert cannot be nil")
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	alertCopy := *alert
+	r.alerts = append(r.alerts, &alertCopy)
+	if r.historySize > 0 && len(r.alerts) > r.historySize {
+		r.alerts = append([]*entity.Alert(nil), r.alerts[len(r.alerts)-r.historySize:]...)
+	}
+
+	return nil
+}
+
+// FindAlerts obtiene las alertas más recientes.
+func (r *MemoryAlertRepository) FindAlerts(ctx context.Context, limit int) ([]*entity.Alert, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	alerts := make([]*entity.Alert, 0, len(r.alerts))
+	for i := len(r.alerts) - 1; i >= 0; i-- {
+		alertCopy := *r.alerts[i]
+		alerts = append(alerts, &alertCopy)
+
+		if limit > 0 && len(alerts) == limit {
+			break
+		}
+	}
+
+	return alerts, nil
+}
+
+// copyAlertRule crea una copia para evitar modificaciones externas.
+func copyAlertRule(rule *entity.AlertRule) *entity.AlertRule {
+	ruleCopy := *rule
+	ruleCopy.Notifiers = append([]string(nil), rule.Notifiers...)
+	return &ruleCopy
+}
@@ -0,0 +1,86 @@
+// Package cache implementa el repositorio en memoria para claves de API.
+package cache
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"gobcv/internal/domain/entity"
+	"gobcv/internal/domain/repository"
+)
+
+// MemoryAPIKeyRepository implementa el repositorio de claves de API en memoria.
+type MemoryAPIKeyRepository struct {
+	keys  map[string]*entity.APIKey
+	mutex sync.RWMutex
+}
+
+// NewMemoryAPIKeyRepository crea un nuevo repositorio de claves en memoria.
+func NewMemoryAPIKeyRepository() repository.APIKeyRepository {
+	return &MemoryAPIKeyRepository{
+		keys: make(map[string]*entity.APIKey),
+	}
+}
+
+// Save guarda o actualiza una clave de API.
+func (r *MemoryAPIKeyRepository) Save(ctx context.Context, key *entity.APIKey) error {
+	if key == nil {
+		return fmt.Errorf("api key cannot be nil")
+	}
+
+	if !key.IsValid() {
+		return fmt.Errorf("api key %q is not valid", key.ID)
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	keyCopy := *key
+	keyCopy.Scopes = append([]string(nil), key.Scopes...)
+	r.keys[key.ID] = &keyCopy
+
+	return nil
+}
+
+// FindByHash busca una clave por el hash de su valor.
+func (r *MemoryAPIKeyRepository) FindByHash(ctx context.Context, keyHash string) (*entity.APIKey, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	for _, key := range r.keys {
+		if key.KeyHash == keyHash {
+			keyCopy := *key
+			return &keyCopy, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// FindAll obtiene todas las claves registradas.
+func (r *MemoryAPIKeyRepository) FindAll(ctx context.Context) ([]*entity.APIKey, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	keys := make([]*entity.APIKey, 0, len(r.keys))
+	for _, key := range r.keys {
+		keyCopy := *key
+		keys = append(keys, &keyCopy)
+	}
+
+	return keys, nil
+}
+
+// Delete elimina una clave por su ID.
+func (r *MemoryAPIKeyRepository) Delete(ctx context.Context, id string) error {
+	if id == "" {
+		return fmt.Errorf("id cannot be empty")
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	delete(r.keys, id)
+	return nil
+}
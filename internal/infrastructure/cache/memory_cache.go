@@ -3,26 +3,36 @@ package cache
 
 import (
 	"context"
+	"strings"
 	"sync"
 	"time"
 
 	"gobcv/internal/domain/service"
 )
 
-// cacheItem representa un elemento en el caché con su tiempo de expiración.
+// cacheItem representa un elemento en el caché con sus tiempos de expiración.
 type cacheItem struct {
 	value     interface{}
+	tags      []string
+	storedAt  time.Time
+	staleAt   time.Time
 	expiresAt time.Time
 }
 
-// isExpired verifica si el elemento ha expirado.
+// isExpired verifica si el elemento superó su TTL duro.
 func (item *cacheItem) isExpired() bool {
 	return time.Now().After(item.expiresAt)
 }
 
+// isStale verifica si el elemento superó su TTL suave.
+func (item *cacheItem) isStale() bool {
+	return time.Now().After(item.staleAt)
+}
+
 // MemoryCache implementa el servicio de caché en memoria.
 type MemoryCache struct {
 	items   map[string]*cacheItem
+	tags    map[string]map[string]struct{}
 	mutex   sync.RWMutex
 	stats   service.CacheStats
 	cleaner *time.Ticker
@@ -33,6 +43,7 @@ type MemoryCache struct {
 func NewMemoryCache() *MemoryCache {
 	cache := &MemoryCache{
 		items:  make(map[string]*cacheItem),
+		tags:   make(map[string]map[string]struct{}),
 		stats:  service.CacheStats{},
 		stopCh: make(chan bool),
 	}
@@ -67,20 +78,68 @@ func (c *MemoryCache) cleanup() {
 
 	for key, item := range c.items {
 		if item.isExpired() {
-			delete(c.items, key)
+			c.deleteLocked(key)
+		}
+	}
+}
+
+// deleteLocked elimina un elemento y sus referencias en el índice de etiquetas.
+// Debe llamarse con el mutex de escritura tomado.
+func (c *MemoryCache) deleteLocked(key string) {
+	item, exists := c.items[key]
+	if !exists {
+		return
+	}
+
+	for _, tag := range item.tags {
+		if keys, ok := c.tags[tag]; ok {
+			delete(keys, key)
+			if len(keys) == 0 {
+				delete(c.tags, tag)
+			}
 		}
 	}
+
+	delete(c.items, key)
 }
 
 // Set almacena un valor en el caché con una clave y TTL especificados.
-func (c *MemoryCache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+func (c *MemoryCache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration, tags ...string) error {
+	return c.SetWithStale(ctx, key, value, ttl, ttl, tags...)
+}
+
+// SetWithStale almacena un valor fresco durante softTTL y servible como obsoleto hasta hardTTL.
+func (c *MemoryCache) SetWithStale(
+	ctx context.Context,
+	key string,
+	value interface{},
+	softTTL, hardTTL time.Duration,
+	tags ...string,
+) error {
+	if hardTTL < softTTL {
+		hardTTL = softTTL
+	}
+
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 
-	expiresAt := time.Now().Add(ttl)
+	// Reemplazar el elemento anterior junto con sus etiquetas
+	c.deleteLocked(key)
+
+	now := time.Now()
 	c.items[key] = &cacheItem{
 		value:     value,
-		expiresAt: expiresAt,
+		tags:      tags,
+		storedAt:  now,
+		staleAt:   now.Add(softTTL),
+		expiresAt: now.Add(hardTTL),
+	}
+
+	for _, tag := range tags {
+		if _, ok := c.tags[tag]; !ok {
+			c.tags[tag] = make(map[string]struct{})
+		}
+		c.tags[tag][key] = struct{}{}
 	}
 
 	return nil
@@ -88,8 +147,8 @@ func (c *MemoryCache) Set(ctx context.Context, key string, value interface{}, tt
 
 // Get obtiene un valor del caché por su clave.
 func (c *MemoryCache) Get(ctx context.Context, key string) (interface{}, error) {
-	c.mutex.RLock()
-	defer c.mutex.RUnlock()
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
 
 	item, exists := c.items[key]
 	if !exists {
@@ -100,11 +159,13 @@ func (c *MemoryCache) Get(ctx context.Context, key string) (interface{}, error)
 	if item.isExpired() {
 		c.stats.Misses++
 		// Eliminar el elemento expirado
-		go func() {
-			c.mutex.Lock()
-			delete(c.items, key)
-			c.mutex.Unlock()
-		}()
+		c.deleteLocked(key)
+		return nil, nil
+	}
+
+	// Los elementos obsoletos solo se sirven a través de GetEntry
+	if item.isStale() {
+		c.stats.Misses++
 		return nil, nil
 	}
 
@@ -112,12 +173,71 @@ func (c *MemoryCache) Get(ctx context.Context, key string) (interface{}, error)
 	return item.value, nil
 }
 
+// GetEntry obtiene un valor con sus metadatos, incluyendo elementos obsoletos no expirados.
+func (c *MemoryCache) GetEntry(ctx context.Context, key string) (*service.CacheEntry, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	item, exists := c.items[key]
+	if !exists {
+		c.stats.Misses++
+		return nil, nil
+	}
+
+	if item.isExpired() {
+		c.stats.Misses++
+		c.deleteLocked(key)
+		return nil, nil
+	}
+
+	stale := item.isStale()
+	if stale {
+		c.stats.StaleHits++
+	} else {
+		c.stats.Hits++
+	}
+
+	return &service.CacheEntry{
+		Value:    item.value,
+		Tags:     item.tags,
+		StoredAt: item.storedAt,
+		StaleAt:  item.staleAt,
+		Stale:    stale,
+	}, nil
+}
+
 // Delete elimina un valor del caché.
 func (c *MemoryCache) Delete(ctx context.Context, key string) error {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 
-	delete(c.items, key)
+	c.deleteLocked(key)
+	return nil
+}
+
+// DeletePrefix elimina todos los valores cuya clave comienza con el prefijo indicado.
+func (c *MemoryCache) DeletePrefix(ctx context.Context, prefix string) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for key := range c.items {
+		if strings.HasPrefix(key, prefix) {
+			c.deleteLocked(key)
+		}
+	}
+
+	return nil
+}
+
+// InvalidateTag elimina todos los valores almacenados con la etiqueta indicada.
+func (c *MemoryCache) InvalidateTag(ctx context.Context, tag string) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for key := range c.tags[tag] {
+		c.deleteLocked(key)
+	}
+
 	return nil
 }
 
@@ -135,7 +255,7 @@ func (c *MemoryCache) Exists(ctx context.Context, key string) (bool, error) {
 		// Eliminar el elemento expirado
 		go func() {
 			c.mutex.Lock()
-			delete(c.items, key)
+			c.deleteLocked(key)
 			c.mutex.Unlock()
 		}()
 		return false, nil
@@ -150,6 +270,7 @@ func (c *MemoryCache) Clear(ctx context.Context) error {
 	defer c.mutex.Unlock()
 
 	c.items = make(map[string]*cacheItem)
+	c.tags = make(map[string]map[string]struct{})
 	c.stats = service.CacheStats{}
 	return nil
 }
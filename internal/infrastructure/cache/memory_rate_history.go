@@ -0,0 +1,106 @@
+// Package cache implementa el historial de cambios de tasas en memoria.
+package cache
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"gobcv/internal/domain/entity"
+	"gobcv/internal/domain/repository"
+)
+
+// MemoryRateHistory implementa el historial de cambios de tasas en memoria.
+// Conserva como máximo capacity cambios descartando los más antiguos.
+type MemoryRateHistory struct {
+	changes      []*entity.RateChange
+	capacity     int
+	lastSequence uint64
+	mutex        sync.RWMutex
+}
+
+// NewMemoryRateHistory crea un nuevo historial en memoria. capacity <= 0 no limita
+// la cantidad de cambios conservados.
+func NewMemoryRateHistory(capacity int) repository.RateHistoryRepository {
+	return &MemoryRateHistory{
+		capacity: capacity,
+	}
+}
+
+// Append agrega un cambio al historial y le asigna el siguiente número de secuencia.
+func (h *MemoryRateHistory) Append(ctx context.Context, change *entity.RateChange) error {
+	if change == nil {
+		return fmt.Errorf("rate change cannot be nil")
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.lastSequence++
+	change.Sequence = h.lastSequence
+
+	changeCopy := *change
+	h.changes = append(h.changes, &changeCopy)
+
+	if h.capacity > 0 && len(h.changes) > h.capacity {
+		// Copiar para que el arreglo subyacente no crezca indefinidamente
+		h.changes = append([]*entity.RateChange(nil), h.changes[len(h.changes)-h.capacity:]...)
+	}
+
+	return nil
+}
+
+// FindSince obtiene los cambios posteriores a la secuencia indicada.
+func (h *MemoryRateHistory) FindSince(ctx context.Context, afterSequence uint64, currencyIDs []string, limit int) ([]*entity.RateChange, error) {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	var changes []*entity.RateChange
+
+	for _, change := range h.changes {
+		if change.Sequence <= afterSequence || !change.MatchesCurrency(currencyIDs) {
+			continue
+		}
+
+		changeCopy := *change
+		changes = append(changes, &changeCopy)
+
+		if limit > 0 && len(changes) == limit {
+			break
+		}
+	}
+
+	return changes, nil
+}
+
+// FindByCurrency obtiene los cambios de una moneda dentro del rango [from, to].
+func (h *MemoryRateHistory) FindByCurrency(ctx context.Context, currencyID string, from, to time.Time) ([]*entity.RateChange, error) {
+	if currencyID == "" {
+		return nil, fmt.Errorf("currency id cannot be empty")
+	}
+
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	var changes []*entity.RateChange
+
+	for _, change := range h.changes {
+		if change.CurrencyID != currencyID || change.ChangedAt.Before(from) || change.ChangedAt.After(to) {
+			continue
+		}
+
+		changeCopy := *change
+		changes = append(changes, &changeCopy)
+	}
+
+	return changes, nil
+}
+
+// LastSequence retorna la secuencia del último cambio registrado.
+func (h *MemoryRateHistory) LastSequence(ctx context.Context) (uint64, error) {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	return h.lastSequence, nil
+}
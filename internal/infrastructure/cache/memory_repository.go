@@ -4,6 +4,7 @@ package cache
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"sync"
 	"time"
 
@@ -43,7 +44,9 @@ func (r *MemoryRepository) Save(ctx context.Context, currency *entity.Currency)
 	// Crear una copia para evitar modificaciones externas
 	currencyCopy := *currency
 	r.currencies[currency.ID] = &currencyCopy
-	
+
+	slog.DebugContext(ctx, "Moneda guardada en repositorio", "currency", currency.ID, "value", currency.Value)
+
 	return nil
 }
 
@@ -0,0 +1,160 @@
+// Package cache implementa los repositorios de webhooks en memoria.
+package cache
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"gobcv/internal/domain/entity"
+	"gobcv/internal/domain/repository"
+)
+
+// MemoryWebhookRepository implementa el repositorio de webhooks en memoria.
+type MemoryWebhookRepository struct {
+	webhooks map[string]*entity.Webhook
+	mutex    sync.RWMutex
+}
+
+// NewMemoryWebhookRepository crea un nuevo repositorio de webhooks en memoria.
+func NewMemoryWebhookRepository() repository.WebhookRepository {
+	return &MemoryWebhookRepository{
+		webhooks: make(map[string]*entity.Webhook),
+	}
+}
+
+// Save guarda o actualiza un webhook.
+func (r *MemoryWebhookRepository) Save(ctx context.Context, webhook *entity.Webhook) error {
+	if webhook == nil {
+		return fmt.Errorf("webhook cannot be nil")
+	}
+
+	if !webhook.IsValid() {
+		return fmt.Errorf("webhook %q is not valid", webhook.ID)
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.webhooks[webhook.ID] = copyWebhook(webhook)
+	return nil
+}
+
+// FindByID busca un webhook por su ID.
+func (r *MemoryWebhookRepository) FindByID(ctx context.Context, id string) (*entity.Webhook, error) {
+	if id == "" {
+		return nil, fmt.Errorf("id cannot be empty")
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	webhook, exists := r.webhooks[id]
+	if !exists {
+		return nil, nil
+	}
+
+	return copyWebhook(webhook), nil
+}
+
+// FindAll obtiene todos los webhooks ordenados por fecha de creación.
+func (r *MemoryWebhookRepository) FindAll(ctx context.Context) ([]*entity.Webhook, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	webhooks := make([]*entity.Webhook, 0, len(r.webhooks))
+	for _, webhook := range r.webhooks {
+		webhooks = append(webhooks, copyWebhook(webhook))
+	}
+
+	sort.Slice(webhooks, func(i, j int) bool {
+		return webhooks[i].CreatedAt.Before(webhooks[j].CreatedAt)
+	})
+
+	return webhooks, nil
+}
+
+// Delete elimina un webhook por su ID.
+func (r *MemoryWebhookRepository) Delete(ctx context.Context, id string) error {
+	if id == "" {
+		return fmt.Errorf("id cannot be empty")
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	delete(r.webhooks, id)
+	return nil
+}
+
+// copyWebhook crea una copia para evitar modificaciones externas.
+func copyWebhook(webhook *entity.Webhook) *entity.Webhook {
+	webhookCopy := *webhook
+	webhookCopy.Currencies = append([]string(nil), webhook.Currencies...)
+	return &webhookCopy
+}
+
+// MemoryWebhookDeliveryRepository implementa el registro de entregas en memoria.
+// Conserva como máximo limit intentos por webhook descartando los más antiguos.
+type MemoryWebhookDeliveryRepository struct {
+	deliveries map[string][]*entity.WebhookDelivery
+	limit      int
+	mutex      sync.RWMutex
+}
+
+// NewMemoryWebhookDeliveryRepository crea un nuevo registro de entregas en memoria.
+func NewMemoryWebhookDeliveryRepository(limit int) repository.WebhookDeliveryRepository {
+	return &MemoryWebhookDeliveryRepository{
+		deliveries: make(map[string][]*entity.WebhookDelivery),
+		limit:      limit,
+	}
+}
+
+// Append registra un intento de entrega.
+func (r *MemoryWebhookDeliveryRepository) Append(ctx context.Context, delivery *entity.WebhookDelivery) error {
+	if delivery == nil {
+		return fmt.Errorf("webhook delivery cannot be nil")
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	deliveryCopy := *delivery
+	deliveries := append(r.deliveries[delivery.WebhookID], &deliveryCopy)
+	if r.limit > 0 && len(deliveries) > r.limit {
+		deliveries = append([]*entity.WebhookDelivery(nil), deliveries[len(deliveries)-r.limit:]...)
+	}
+	r.deliveries[delivery.WebhookID] = deliveries
+
+	return nil
+}
+
+// FindByWebhook obtiene los intentos más recientes de un webhook.
+func (r *MemoryWebhookDeliveryRepository) FindByWebhook(ctx context.Context, webhookID string, limit int) ([]*entity.WebhookDelivery, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	stored := r.deliveries[webhookID]
+	deliveries := make([]*entity.WebhookDelivery, 0, len(stored))
+
+	for i := len(stored) - 1; i >= 0; i-- {
+		deliveryCopy := *stored[i]
+		deliveries = append(deliveries, &deliveryCopy)
+
+		if limit > 0 && len(deliveries) == limit {
+			break
+		}
+	}
+
+	return deliveries, nil
+}
+
+// DeleteByWebhook elimina los intentos registrados de un webhook.
+func (r *MemoryWebhookDeliveryRepository) DeleteByWebhook(ctx context.Context, webhookID string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	delete(r.deliveries, webhookID)
+	return nil
+}
@@ -0,0 +1,335 @@
+// Package cache implementa el adaptador de caché sobre Redis.
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"gobcv/internal/domain/service"
+)
+
+// Campos del hash en el que se guarda cada elemento.
+const (
+	redisFieldValue    = "value"
+	redisFieldStoredAt = "stored_at"
+	redisFieldStaleAt  = "stale_at"
+	redisFieldTags     = "tags"
+)
+
+// redisTagNamespace separa los índices de etiquetas de las claves de la aplicación.
+const redisTagNamespace = "__tag__:"
+
+// tagScript agrega una clave al conjunto de una etiqueta y extiende la expiración del
+// conjunto si no tiene o si es menor que la de la clave. Equivale a PEXPIRE NX y GT,
+// que requieren Redis 7, con comandos disponibles desde Redis 2.6.
+//
+// KEYS[1] = conjunto de la etiqueta, ARGV[1] = clave, ARGV[2] = TTL duro (ms)
+var tagScript = redis.NewScript(`
+local ttl = tonumber(ARGV[2])
+redis.call("SADD", KEYS[1], ARGV[1])
+
+local current = redis.call("PTTL", KEYS[1])
+if current < 0 or current < ttl then
+  redis.call("PEXPIRE", KEYS[1], ttl)
+end
+return 1
+`)
+
+// RedisCache implementa el servicio de caché sobre Redis para compartir estado entre réplicas.
+// Los valores se retornan siempre como []byte; los que no lo son se serializan como JSON.
+// Los contadores de aciertos y fallos son de esta réplica, no del servidor de Redis.
+type RedisCache struct {
+	client    *redis.Client
+	prefix    string
+	hits      atomic.Int64
+	misses    atomic.Int64
+	staleHits atomic.Int64
+}
+
+// NewRedisCache crea una nueva instancia del caché sobre Redis.
+func NewRedisCache(addr, password string, db int, prefix string) *RedisCache {
+	return &RedisCache{
+		client: redis.NewClient(&redis.Options{
+			Addr:     addr,
+			Password: password,
+			DB:       db,
+		}),
+		prefix: prefix,
+	}
+}
+
+// Ping verifica que el servidor de Redis esté disponible.
+func (c *RedisCache) Ping(ctx context.Context) error {
+	return c.client.Ping(ctx).Err()
+}
+
+// key retorna la clave con el prefijo de la aplicación.
+func (c *RedisCache) key(key string) string {
+	return c.prefix + key
+}
+
+// tagKey retorna la clave del conjunto que indexa las claves de una etiqueta.
+func (c *RedisCache) tagKey(tag string) string {
+	return c.prefix + redisTagNamespace + tag
+}
+
+// Set almacena un valor en el caché con una clave y TTL especificados.
+func (c *RedisCache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration, tags ...string) error {
+	return c.SetWithStale(ctx, key, value, ttl, ttl, tags...)
+}
+
+// SetWithStale almacena un valor fresco durante softTTL y servible como obsoleto hasta hardTTL.
+// Cada etiqueta se indexa en un conjunto de Redis con las claves que la contienen; el
+// conjunto expira junto con la última de ellas.
+func (c *RedisCache) SetWithStale(
+	ctx context.Context,
+	key string,
+	value interface{},
+	softTTL, hardTTL time.Duration,
+	tags ...string,
+) error {
+	if hardTTL < softTTL {
+		hardTTL = softTTL
+	}
+
+	data, err := encodeRedisValue(value)
+	if err != nil {
+		return err
+	}
+
+	encodedTags, err := json.Marshal(tags)
+	if err != nil {
+		return fmt.Errorf("error encoding cache tags: %w", err)
+	}
+
+	now := time.Now()
+	redisKey := c.key(key)
+
+	_, err = c.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, redisKey)
+		pipe.HSet(ctx, redisKey,
+			redisFieldValue, data,
+			redisFieldStoredAt, now.UnixNano(),
+			redisFieldStaleAt, now.Add(softTTL).UnixNano(),
+			redisFieldTags, encodedTags,
+		)
+		pipe.PExpire(ctx, redisKey, hardTTL)
+		for _, tag := range tags {
+			// EVAL y no EVALSHA: dentro de MULTI no hay reintento ante NOSCRIPT
+			tagScript.Eval(ctx, pipe, []string{c.tagKey(tag)}, key, hardTTL.Milliseconds())
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("error setting key %s: %w", key, err)
+	}
+
+	return nil
+}
+
+// Get obtiene un valor del caché por su clave.
+func (c *RedisCache) Get(ctx context.Context, key string) (interface{}, error) {
+	entry, err := c.getEntry(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	// Los elementos obsoletos solo se sirven a través de GetEntry
+	if entry == nil || entry.Stale {
+		c.misses.Add(1)
+		return nil, nil
+	}
+
+	c.hits.Add(1)
+	return entry.Value, nil
+}
+
+// GetEntry obtiene un valor con sus metadatos, incluyendo elementos obsoletos no expirados.
+func (c *RedisCache) GetEntry(ctx context.Context, key string) (*service.CacheEntry, error) {
+	entry, err := c.getEntry(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case entry == nil:
+		c.misses.Add(1)
+	case entry.Stale:
+		c.staleHits.Add(1)
+	default:
+		c.hits.Add(1)
+	}
+
+	return entry, nil
+}
+
+// getEntry lee el hash de un elemento y lo convierte en una entrada del caché.
+func (c *RedisCache) getEntry(ctx context.Context, key string) (*service.CacheEntry, error) {
+	fields, err := c.client.HMGet(ctx, c.key(key),
+		redisFieldValue, redisFieldStoredAt, redisFieldStaleAt, redisFieldTags).Result()
+	if err != nil {
+		return nil, fmt.Errorf("error getting key %s: %w", key, err)
+	}
+
+	value, ok := fields[0].(string)
+	if !ok {
+		return nil, nil
+	}
+
+	storedAt := parseRedisTime(fields[1])
+	staleAt := parseRedisTime(fields[2])
+
+	var tags []string
+	if encoded, ok := fields[3].(string); ok && encoded != "" {
+		// Las entradas con etiquetas en un formato anterior se tratan como ausentes
+		if err := json.Unmarshal([]byte(encoded), &tags); err != nil {
+			return nil, nil
+		}
+	}
+
+	return &service.CacheEntry{
+		Value:    []byte(value),
+		Tags:     tags,
+		StoredAt: storedAt,
+		StaleAt:  staleAt,
+		Stale:    time.Now().After(staleAt),
+	}, nil
+}
+
+// Delete elimina un valor del caché.
+func (c *RedisCache) Delete(ctx context.Context, key string) error {
+	return c.client.Del(ctx, c.key(key)).Err()
+}
+
+// DeletePrefix elimina todos los valores cuya clave comienza con el prefijo indicado.
+func (c *RedisCache) DeletePrefix(ctx context.Context, prefix string) error {
+	return c.deleteMatching(ctx, escapeRedisPattern(c.key(prefix))+"*")
+}
+
+// InvalidateTag elimina todos los valores indexados con la etiqueta indicada.
+func (c *RedisCache) InvalidateTag(ctx context.Context, tag string) error {
+	tagKey := c.tagKey(tag)
+
+	keys, err := c.client.SMembers(ctx, tagKey).Result()
+	if err != nil {
+		return fmt.Errorf("error getting keys for tag %s: %w", tag, err)
+	}
+
+	redisKeys := make([]string, 0, len(keys)+1)
+	for _, key := range keys {
+		redisKeys = append(redisKeys, c.key(key))
+	}
+	redisKeys = append(redisKeys, tagKey)
+
+	if err := c.client.Del(ctx, redisKeys...).Err(); err != nil {
+		return fmt.Errorf("error invalidating tag %s: %w", tag, err)
+	}
+
+	return nil
+}
+
+// deleteMatching elimina las claves que coinciden con un patrón de SCAN.
+func (c *RedisCache) deleteMatching(ctx context.Context, pattern string) error {
+	iter := c.client.Scan(ctx, 0, pattern, 0).Iterator()
+	for iter.Next(ctx) {
+		if err := c.client.Del(ctx, iter.Val()).Err(); err != nil {
+			return err
+		}
+	}
+
+	return iter.Err()
+}
+
+// Exists verifica si una clave existe en el caché.
+func (c *RedisCache) Exists(ctx context.Context, key string) (bool, error) {
+	count, err := c.client.Exists(ctx, c.key(key)).Result()
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// Clear limpia todas las claves con el prefijo de la aplicación.
+func (c *RedisCache) Clear(ctx context.Context) error {
+	if err := c.deleteMatching(ctx, escapeRedisPattern(c.prefix)+"*"); err != nil {
+		return err
+	}
+
+	c.hits.Store(0)
+	c.misses.Store(0)
+	c.staleHits.Store(0)
+	return nil
+}
+
+// GetStats obtiene estadísticas del caché. Hits y misses son las lecturas de esta réplica
+// y Keys cuenta solo las claves con el prefijo de la aplicación, sin los índices de etiquetas.
+func (c *RedisCache) GetStats(ctx context.Context) (service.CacheStats, error) {
+	stats := service.CacheStats{
+		Hits:      c.hits.Load(),
+		Misses:    c.misses.Load(),
+		StaleHits: c.staleHits.Load(),
+	}
+
+	tagPrefix := c.tagKey("")
+	iter := c.client.Scan(ctx, 0, escapeRedisPattern(c.prefix)+"*", 0).Iterator()
+	for iter.Next(ctx) {
+		if !strings.HasPrefix(iter.Val(), tagPrefix) {
+			stats.Keys++
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return service.CacheStats{}, fmt.Errorf("error counting redis keys: %w", err)
+	}
+
+	return stats, nil
+}
+
+// Close cierra la conexión con Redis.
+func (c *RedisCache) Close() error {
+	return c.client.Close()
+}
+
+// encodeRedisValue convierte un valor en bytes para almacenarlo en Redis.
+func encodeRedisValue(value interface{}) ([]byte, error) {
+	switch v := value.(type) {
+	case []byte:
+		return v, nil
+	case string:
+		return []byte(v), nil
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("error encoding cache value: %w", err)
+		}
+		return data, nil
+	}
+}
+
+// escapeRedisPattern escapa los caracteres especiales de los patrones de SCAN.
+func escapeRedisPattern(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, "*", `\*`, "?", `\?`, "[", `\[`, "]", `\]`)
+	return replacer.Replace(value)
+}
+
+// parseRedisTime convierte un timestamp en nanosegundos almacenado en Redis.
+func parseRedisTime(field interface{}) time.Time {
+	value, ok := field.(string)
+	if !ok {
+		return time.Time{}
+	}
+
+	nanos, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+
+	return time.Unix(0, nanos)
+}
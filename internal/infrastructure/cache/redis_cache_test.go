@@ -0,0 +1,236 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+// newTestRedisCache crea un RedisCache sobre un servidor miniredis en proceso.
+func newTestRedisCache(t *testing.T, prefix string) (*RedisCache, *miniredis.Miniredis) {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	cache := NewRedisCache(server.Addr(), "", 0, prefix)
+	t.Cleanup(func() { cache.Close() })
+
+	return cache, server
+}
+
+func TestRedisCacheSetGet(t *testing.T) {
+	ctx := context.Background()
+	cache, _ := newTestRedisCache(t, "gobcv:")
+
+	if err := cache.Set(ctx, "bytes", []byte("hola"), time.Minute); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if err := cache.Set(ctx, "struct", map[string]int{"n": 1}, time.Minute); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"bytes", "hola"},
+		{"struct", `{"n":1}`},
+	}
+	for _, tt := range tests {
+		value, err := cache.Get(ctx, tt.key)
+		if err != nil {
+			t.Fatalf("Get(%q): %v", tt.key, err)
+		}
+		data, ok := value.([]byte)
+		if !ok || string(data) != tt.want {
+			t.Errorf("Get(%q) = %#v, want %q", tt.key, value, tt.want)
+		}
+	}
+
+	value, err := cache.Get(ctx, "missing")
+	if err != nil || value != nil {
+		t.Errorf("Get(missing) = %v, %v, want nil, nil", value, err)
+	}
+
+	exists, err := cache.Exists(ctx, "bytes")
+	if err != nil || !exists {
+		t.Errorf("Exists(bytes) = %v, %v, want true", exists, err)
+	}
+}
+
+func TestRedisCacheStaleWindow(t *testing.T) {
+	ctx := context.Background()
+	cache, server := newTestRedisCache(t, "gobcv:")
+
+	if err := cache.SetWithStale(ctx, "key", []byte("v"), 20*time.Millisecond, time.Minute, "tag"); err != nil {
+		t.Fatalf("SetWithStale: %v", err)
+	}
+
+	entry, err := cache.GetEntry(ctx, "key")
+	if err != nil || entry == nil || entry.Stale {
+		t.Fatalf("GetEntry fresh = %+v, %v, want fresh entry", entry, err)
+	}
+	if len(entry.Tags) != 1 || entry.Tags[0] != "tag" {
+		t.Errorf("Tags = %v, want [tag]", entry.Tags)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+
+	if value, _ := cache.Get(ctx, "key"); value != nil {
+		t.Errorf("Get after soft TTL = %v, want nil", value)
+	}
+	entry, err = cache.GetEntry(ctx, "key")
+	if err != nil || entry == nil || !entry.Stale {
+		t.Fatalf("GetEntry stale = %+v, %v, want stale entry", entry, err)
+	}
+
+	server.FastForward(time.Minute)
+
+	entry, err = cache.GetEntry(ctx, "key")
+	if err != nil || entry != nil {
+		t.Errorf("GetEntry after hard TTL = %+v, %v, want nil", entry, err)
+	}
+}
+
+func TestRedisCacheInvalidation(t *testing.T) {
+	ctx := context.Background()
+	cache, _ := newTestRedisCache(t, "gobcv:")
+
+	cache.Set(ctx, "currency:USD", []byte("usd"), time.Minute, "currency:USD", "currencies")
+	cache.Set(ctx, "currency:EUR", []byte("eur"), time.Minute, "currency:EUR", "currencies")
+	cache.Set(ctx, "conversion:USD:VES", []byte("1"), time.Minute, "currency:USD")
+	cache.Set(ctx, "other", []byte("x"), time.Minute)
+
+	if err := cache.InvalidateTag(ctx, "currency:USD"); err != nil {
+		t.Fatalf("InvalidateTag: %v", err)
+	}
+	for key, want := range map[string]bool{
+		"currency:USD":       false,
+		"conversion:USD:VES": false,
+		"currency:EUR":       true,
+		"other":              true,
+	} {
+		if exists, _ := cache.Exists(ctx, key); exists != want {
+			t.Errorf("after InvalidateTag Exists(%q) = %v, want %v", key, exists, want)
+		}
+	}
+
+	if err := cache.DeletePrefix(ctx, "currency:"); err != nil {
+		t.Fatalf("DeletePrefix: %v", err)
+	}
+	if exists, _ := cache.Exists(ctx, "currency:EUR"); exists {
+		t.Error("DeletePrefix kept currency:EUR")
+	}
+	if exists, _ := cache.Exists(ctx, "other"); !exists {
+		t.Error("DeletePrefix removed other")
+	}
+
+	if err := cache.Clear(ctx); err != nil {
+		t.Fatalf("Clear: %v", err)
+	}
+	if exists, _ := cache.Exists(ctx, "other"); exists {
+		t.Error("Clear kept other")
+	}
+}
+
+func TestRedisCacheStatsScopedToPrefix(t *testing.T) {
+	ctx := context.Background()
+	cache, server := newTestRedisCache(t, "gobcv:")
+
+	// Claves de otra aplicación en la misma base
+	server.Set("other:key", "x")
+	server.Set("other:key2", "y")
+
+	cache.SetWithStale(ctx, "fresh", []byte("1"), time.Minute, time.Hour, "tag")
+	cache.SetWithStale(ctx, "stale", []byte("2"), time.Nanosecond, time.Hour)
+	time.Sleep(time.Millisecond)
+
+	cache.Get(ctx, "fresh")      // hit
+	cache.Get(ctx, "stale")      // miss: obsoleto
+	cache.Get(ctx, "missing")    // miss
+	cache.GetEntry(ctx, "fresh") // hit
+	cache.GetEntry(ctx, "stale") // stale hit
+	cache.GetEntry(ctx, "none")  // miss
+
+	// Lecturas de otro cliente no deben contarse
+	other := NewRedisCache(server.Addr(), "", 0, "other:")
+	defer other.Close()
+	other.Get(ctx, "key")
+
+	stats, err := cache.GetStats(ctx)
+	if err != nil {
+		t.Fatalf("GetStats: %v", err)
+	}
+
+	if stats.Hits != 2 || stats.Misses != 3 || stats.StaleHits != 1 {
+		t.Errorf("stats = %+v, want 2 hits, 3 misses, 1 stale hit", stats)
+	}
+	if stats.Keys != 2 {
+		t.Errorf("Keys = %d, want 2 (without other prefixes or tag sets)", stats.Keys)
+	}
+
+	if err := cache.Clear(ctx); err != nil {
+		t.Fatalf("Clear: %v", err)
+	}
+	stats, _ = cache.GetStats(ctx)
+	if stats.Hits != 0 || stats.Misses != 0 || stats.StaleHits != 0 || stats.Keys != 0 {
+		t.Errorf("stats after Clear = %+v, want zero", stats)
+	}
+	if !server.Exists("other:key") {
+		t.Error("Clear removed keys outside the prefix")
+	}
+}
+
+func TestRedisCacheTagSets(t *testing.T) {
+	ctx := context.Background()
+	cache, server := newTestRedisCache(t, "gobcv:")
+
+	tags := []string{"a,b", "c"}
+	if err := cache.SetWithStale(ctx, "key", []byte("v"), time.Minute, time.Hour, tags...); err != nil {
+		t.Fatalf("SetWithStale: %v", err)
+	}
+
+	entry, err := cache.GetEntry(ctx, "key")
+	if err != nil || entry == nil {
+		t.Fatalf("GetEntry = %+v, %v", entry, err)
+	}
+	if len(entry.Tags) != 2 || entry.Tags[0] != "a,b" || entry.Tags[1] != "c" {
+		t.Errorf("Tags = %q, want %q", entry.Tags, tags)
+	}
+
+	// El conjunto de la etiqueta dura al menos tanto como la clave más longeva
+	if ttl := server.TTL("gobcv:__tag__:c"); ttl != time.Hour {
+		t.Errorf("tag set TTL = %v, want 1h", ttl)
+	}
+	cache.SetWithStale(ctx, "longer", []byte("v"), time.Minute, 2*time.Hour, "c")
+	cache.SetWithStale(ctx, "shorter", []byte("v"), time.Minute, time.Minute, "c")
+	if ttl := server.TTL("gobcv:__tag__:c"); ttl != 2*time.Hour {
+		t.Errorf("tag set TTL = %v, want 2h", ttl)
+	}
+
+	if err := cache.InvalidateTag(ctx, "a,b"); err != nil {
+		t.Fatalf("InvalidateTag: %v", err)
+	}
+	if exists, _ := cache.Exists(ctx, "key"); exists {
+		t.Error("InvalidateTag(a,b) kept key")
+	}
+	if exists, _ := cache.Exists(ctx, "longer"); !exists {
+		t.Error("InvalidateTag(a,b) removed longer")
+	}
+
+	server.FastForward(2 * time.Hour)
+	if server.Exists("gobcv:__tag__:c") {
+		t.Error("tag set outlived every key it indexes")
+	}
+
+	// Un conjunto sin expiración, como los de versiones anteriores, la recibe
+	server.SAdd("gobcv:__tag__:legacy", "old")
+	cache.SetWithStale(ctx, "key", []byte("v"), time.Minute, time.Hour, "legacy")
+	if ttl := server.TTL("gobcv:__tag__:legacy"); ttl != time.Hour {
+		t.Errorf("legacy tag set TTL = %v, want 1h", ttl)
+	}
+	if members, _ := server.Members("gobcv:__tag__:legacy"); len(members) != 2 {
+		t.Errorf("legacy tag set members = %v, want old and key", members)
+	}
+}
@@ -0,0 +1,211 @@
+// Package cache implementa la composición de cachés en dos niveles.
+package cache
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"gobcv/internal/domain/service"
+)
+
+// negativeEntry marca en L1 una clave que no existe en L2.
+type negativeEntry struct{}
+
+// l1Entry es el valor guardado en L1. Conserva los tiempos de la entrada original para
+// que una promoción desde L2 no la haga parecer más reciente de lo que es.
+type l1Entry struct {
+	value    interface{}
+	storedAt time.Time
+	staleAt  time.Time
+}
+
+// TieredCache compone un caché L1 de TTL corto delante de un caché L2 compartido.
+// Las lecturas pasan por L1 antes de consultar L2, las escrituras se propagan a
+// ambos niveles y las ausencias en L2 se recuerdan en L1 durante negativeTTL.
+// L1 debe conservar los valores sin serializarlos, como MemoryCache.
+type TieredCache struct {
+	l1          service.CacheService
+	l2          service.CacheService
+	l1TTL       time.Duration
+	negativeTTL time.Duration
+	hits        atomic.Int64
+	misses      atomic.Int64
+	staleHits   atomic.Int64
+}
+
+// NewTieredCache crea un nuevo caché de dos niveles.
+func NewTieredCache(l1, l2 service.CacheService, l1TTL, negativeTTL time.Duration) *TieredCache {
+	return &TieredCache{
+		l1:          l1,
+		l2:          l2,
+		l1TTL:       l1TTL,
+		negativeTTL: negativeTTL,
+	}
+}
+
+// Set almacena el valor en L2 y en L1 con el menor de los TTL.
+func (c *TieredCache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration, tags ...string) error {
+	if err := c.l2.Set(ctx, key, value, ttl, tags...); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	return c.l1.Set(ctx, key, l1Entry{value: value, storedAt: now, staleAt: now.Add(ttl)}, min(ttl, c.l1TTL), tags...)
+}
+
+// SetWithStale almacena el valor en L2 con su ventana de obsolescencia y en L1 como fresco.
+func (c *TieredCache) SetWithStale(
+	ctx context.Context,
+	key string,
+	value interface{},
+	softTTL, hardTTL time.Duration,
+	tags ...string,
+) error {
+	if err := c.l2.SetWithStale(ctx, key, value, softTTL, hardTTL, tags...); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	return c.l1.Set(ctx, key, l1Entry{value: value, storedAt: now, staleAt: now.Add(softTTL)}, min(softTTL, c.l1TTL), tags...)
+}
+
+// Get obtiene un valor desde L1 o, si no está, desde L2 repoblando L1.
+func (c *TieredCache) Get(ctx context.Context, key string) (interface{}, error) {
+	entry, err := c.GetEntry(ctx, key)
+	if err != nil || entry == nil || entry.Stale {
+		return nil, err
+	}
+
+	return entry.Value, nil
+}
+
+// GetEntry obtiene un valor con sus metadatos desde L1 o, si no está fresco, desde L2.
+func (c *TieredCache) GetEntry(ctx context.Context, key string) (*service.CacheEntry, error) {
+	if entry, err := c.l1.GetEntry(ctx, key); err == nil && entry != nil && !entry.Stale {
+		if _, negative := entry.Value.(negativeEntry); negative {
+			c.misses.Add(1)
+			return nil, nil
+		}
+
+		if item, ok := entry.Value.(l1Entry); ok {
+			c.hits.Add(1)
+			return &service.CacheEntry{
+				Value:    item.value,
+				Tags:     entry.Tags,
+				StoredAt: item.storedAt,
+				StaleAt:  item.staleAt,
+			}, nil
+		}
+	}
+
+	entry, err := c.l2.GetEntry(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	if entry == nil {
+		c.misses.Add(1)
+		c.l1.Set(ctx, key, negativeEntry{}, c.negativeTTL)
+		return nil, nil
+	}
+
+	// Las entradas obsoletas se sirven desde L2 sin promoverlas a L1
+	if entry.Stale {
+		c.staleHits.Add(1)
+		return entry, nil
+	}
+
+	c.hits.Add(1)
+	c.promote(ctx, key, entry)
+	return entry, nil
+}
+
+// promote copia en L1 una entrada fresca de L2 solo por lo que le queda de frescura,
+// sin superar l1TTL y conservando su fecha de almacenamiento.
+func (c *TieredCache) promote(ctx context.Context, key string, entry *service.CacheEntry) {
+	ttl := c.l1TTL
+	if !entry.StaleAt.IsZero() {
+		ttl = min(ttl, time.Until(entry.StaleAt))
+	}
+	if ttl <= 0 {
+		return
+	}
+
+	item := l1Entry{value: entry.Value, storedAt: entry.StoredAt, staleAt: entry.StaleAt}
+	c.l1.Set(ctx, key, item, ttl, entry.Tags...)
+}
+
+// Delete elimina el valor de ambos niveles.
+func (c *TieredCache) Delete(ctx context.Context, key string) error {
+	if err := c.l2.Delete(ctx, key); err != nil {
+		return err
+	}
+
+	return c.l1.Delete(ctx, key)
+}
+
+// DeletePrefix elimina los valores con el prefijo indicado de ambos niveles.
+func (c *TieredCache) DeletePrefix(ctx context.Context, prefix string) error {
+	if err := c.l2.DeletePrefix(ctx, prefix); err != nil {
+		return err
+	}
+
+	return c.l1.DeletePrefix(ctx, prefix)
+}
+
+// InvalidateTag elimina los valores con la etiqueta indicada de ambos niveles.
+func (c *TieredCache) InvalidateTag(ctx context.Context, tag string) error {
+	if err := c.l2.InvalidateTag(ctx, tag); err != nil {
+		return err
+	}
+
+	return c.l1.InvalidateTag(ctx, tag)
+}
+
+// Exists verifica si una clave existe en alguno de los niveles.
+func (c *TieredCache) Exists(ctx context.Context, key string) (bool, error) {
+	if entry, err := c.l1.GetEntry(ctx, key); err == nil && entry != nil && !entry.Stale {
+		_, negative := entry.Value.(negativeEntry)
+		return !negative, nil
+	}
+
+	return c.l2.Exists(ctx, key)
+}
+
+// Clear limpia ambos niveles.
+func (c *TieredCache) Clear(ctx context.Context) error {
+	if err := c.l2.Clear(ctx); err != nil {
+		return err
+	}
+
+	c.hits.Store(0)
+	c.misses.Store(0)
+	c.staleHits.Store(0)
+	return c.l1.Clear(ctx)
+}
+
+// GetStats combina las estadísticas de ambos niveles.
+// Hits y misses corresponden a las lecturas del caché compuesto y Keys a las claves en L2.
+func (c *TieredCache) GetStats(ctx context.Context) (service.CacheStats, error) {
+	l1Stats, err := c.l1.GetStats(ctx)
+	if err != nil {
+		return service.CacheStats{}, err
+	}
+
+	l2Stats, err := c.l2.GetStats(ctx)
+	if err != nil {
+		return service.CacheStats{}, err
+	}
+
+	return service.CacheStats{
+		Hits:      c.hits.Load(),
+		Misses:    c.misses.Load(),
+		StaleHits: c.staleHits.Load(),
+		Keys:      l2Stats.Keys,
+		Tiers: map[string]service.CacheStats{
+			"l1": l1Stats,
+			"l2": l2Stats,
+		},
+	}, nil
+}
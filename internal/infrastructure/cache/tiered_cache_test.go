@@ -0,0 +1,100 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// newTestTieredCache crea un TieredCache con dos MemoryCache.
+func newTestTieredCache(t *testing.T, l1TTL time.Duration) (*TieredCache, *MemoryCache, *MemoryCache) {
+	t.Helper()
+
+	l1, l2 := NewMemoryCache(), NewMemoryCache()
+	t.Cleanup(func() {
+		l1.Close()
+		l2.Close()
+	})
+
+	return NewTieredCache(l1, l2, l1TTL, time.Minute), l1, l2
+}
+
+func TestTieredCachePromotionKeepsRemainingFreshness(t *testing.T) {
+	ctx := context.Background()
+	tiered, l1, l2 := newTestTieredCache(t, time.Hour)
+
+	// Otra réplica escribió la entrada en L2 hace un rato
+	l2.SetWithStale(ctx, "key", []byte("v"), 50*time.Millisecond, time.Hour, "tag")
+	storedAt := time.Now()
+	time.Sleep(20 * time.Millisecond)
+
+	entry, err := tiered.GetEntry(ctx, "key")
+	if err != nil || entry == nil || entry.Stale {
+		t.Fatalf("GetEntry = %+v, %v, want fresh entry", entry, err)
+	}
+
+	promoted, _ := l1.GetEntry(ctx, "key")
+	if promoted == nil {
+		t.Fatal("fresh L2 entry was not promoted to L1")
+	}
+
+	entry, _ = tiered.GetEntry(ctx, "key")
+	if entry.StoredAt.After(storedAt) {
+		t.Errorf("StoredAt = %v, want the L2 write time (before %v)", entry.StoredAt, storedAt)
+	}
+
+	// Cumplida la frescura de L2, L1 no debe seguir sirviéndola como fresca
+	time.Sleep(40 * time.Millisecond)
+
+	entry, err = tiered.GetEntry(ctx, "key")
+	if err != nil || entry == nil || !entry.Stale {
+		t.Fatalf("GetEntry after soft TTL = %+v, %v, want stale entry from L2", entry, err)
+	}
+	if value, _ := tiered.Get(ctx, "key"); value != nil {
+		t.Errorf("Get after soft TTL = %v, want nil", value)
+	}
+	if promoted, _ := l1.GetEntry(ctx, "key"); promoted != nil {
+		t.Errorf("stale entry was promoted to L1: %+v", promoted)
+	}
+}
+
+func TestTieredCacheSetAndNegativeEntries(t *testing.T) {
+	ctx := context.Background()
+	tiered, _, l2 := newTestTieredCache(t, time.Minute)
+
+	if value, _ := tiered.Get(ctx, "key"); value != nil {
+		t.Fatalf("Get(missing) = %v, want nil", value)
+	}
+
+	// La ausencia queda recordada en L1 aunque L2 cambie por fuera
+	l2.Set(ctx, "key", []byte("other replica"), time.Minute)
+	if exists, _ := tiered.Exists(ctx, "key"); exists {
+		t.Error("Exists ignored the negative entry in L1")
+	}
+
+	if err := tiered.SetWithStale(ctx, "key", []byte("v"), time.Minute, time.Hour, "tag"); err != nil {
+		t.Fatalf("SetWithStale: %v", err)
+	}
+	entry, err := tiered.GetEntry(ctx, "key")
+	if err != nil || entry == nil || string(entry.Value.([]byte)) != "v" || entry.Stale {
+		t.Fatalf("GetEntry = %+v, %v, want fresh v", entry, err)
+	}
+	if len(entry.Tags) != 1 || entry.Tags[0] != "tag" {
+		t.Errorf("Tags = %v, want [tag]", entry.Tags)
+	}
+
+	if err := tiered.InvalidateTag(ctx, "tag"); err != nil {
+		t.Fatalf("InvalidateTag: %v", err)
+	}
+	if value, _ := tiered.Get(ctx, "key"); value != nil {
+		t.Errorf("Get after InvalidateTag = %v, want nil", value)
+	}
+
+	stats, err := tiered.GetStats(ctx)
+	if err != nil {
+		t.Fatalf("GetStats: %v", err)
+	}
+	if stats.Hits != 1 || stats.Misses != 2 {
+		t.Errorf("stats = %+v, want 1 hit and 2 misses", stats)
+	}
+}
@@ -0,0 +1,120 @@
+// Package events implementa la difusión de cambios de tasas dentro del proceso.
+package events
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+
+	"gobcv/internal/domain/entity"
+	"gobcv/internal/domain/service"
+)
+
+// subscription representa un suscriptor del broker.
+type subscription struct {
+	currencyIDs []string
+	events      chan *entity.RateChange
+}
+
+// Broker implementa service.RateEventBroker en memoria. Publish nunca bloquea:
+// si el buffer de un suscriptor está lleno se cierra su canal para que reanude
+// desde el historial en lugar de frenar la actualización de monedas.
+type Broker struct {
+	subscribers map[int]*subscription
+	nextID      int
+	closed      bool
+	mutex       sync.Mutex
+}
+
+// NewBroker crea un nuevo broker de eventos en memoria.
+func NewBroker() *Broker {
+	return &Broker{
+		subscribers: make(map[int]*subscription),
+	}
+}
+
+var _ service.RateEventBroker = (*Broker)(nil)
+
+// Publish entrega los cambios a los suscriptores interesados.
+func (b *Broker) Publish(ctx context.Context, changes []*entity.RateChange) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	for id, sub := range b.subscribers {
+		for _, change := range changes {
+			if !change.MatchesCurrency(sub.currencyIDs) {
+				continue
+			}
+
+			select {
+			case sub.events <- change:
+			default:
+				slog.WarnContext(ctx, "Suscriptor de eventos lento, cerrando suscripción", "subscriber", id)
+				b.remove(id)
+			}
+
+			if _, ok := b.subscribers[id]; !ok {
+				break
+			}
+		}
+	}
+}
+
+// Subscribe registra un suscriptor hasta que se cancele el contexto.
+func (b *Broker) Subscribe(ctx context.Context, currencyIDs []string, buffer int) <-chan *entity.RateChange {
+	if buffer <= 0 {
+		buffer = 1
+	}
+	events := make(chan *entity.RateChange, buffer)
+
+	b.mutex.Lock()
+	if b.closed {
+		b.mutex.Unlock()
+		close(events)
+		return events
+	}
+	id := b.nextID
+	b.nextID++
+	b.subscribers[id] = &subscription{currencyIDs: currencyIDs, events: events}
+	b.mutex.Unlock()
+
+	go func() {
+		<-ctx.Done()
+
+		b.mutex.Lock()
+		b.remove(id)
+		b.mutex.Unlock()
+	}()
+
+	return events
+}
+
+// Subscribers retorna la cantidad de suscriptores activos.
+func (b *Broker) Subscribers() int {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	return len(b.subscribers)
+}
+
+// Close cierra todas las suscripciones y rechaza las nuevas.
+func (b *Broker) Close() error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.closed = true
+	for id := range b.subscribers {
+		b.remove(id)
+	}
+	return nil
+}
+
+// remove elimina un suscriptor y cierra su canal. Debe llamarse con el mutex tomado.
+func (b *Broker) remove(id int) {
+	sub, ok := b.subscribers[id]
+	if !ok {
+		return
+	}
+	delete(b.subscribers, id)
+	close(sub.events)
+}
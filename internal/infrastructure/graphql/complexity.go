@@ -0,0 +1,158 @@
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// listMultipliers es la cantidad de elementos que se estima para cada campo de lista
+// cuando la consulta no indica limit.
+var listMultipliers = map[string]int{
+	"currencies": 10,
+	"history":    defaultHistoryLimit,
+}
+
+// maxIntrospectionDepth es la profundidad máxima de los campos __schema y __type, que
+// admite la consulta de introspección de herramientas como GraphiQL (unos 13 niveles por
+// los ofType anidados) aunque supere MaxDepth.
+const maxIntrospectionDepth = 15
+
+// Limits limita el costo de las consultas antes de ejecutarlas.
+type Limits struct {
+	// MaxDepth es la profundidad máxima de campos anidados; 0 no la limita.
+	MaxDepth int
+	// MaxComplexity es el costo máximo estimado de la consulta; 0 no lo limita.
+	MaxComplexity int
+}
+
+// complexityCalculator estima el costo de una operación: cada campo cuesta 1 más el
+// costo de sus subcampos, multiplicado por la cantidad de elementos si es una lista.
+// Los campos de introspección cuestan como cualquier otro, pero la profundidad de
+// __schema y __type se mide aparte. El documento ya está validado, por lo que los
+// fragmentos no forman ciclos.
+type complexityCalculator struct {
+	fragments map[string]*ast.FragmentDefinition
+	variables map[string]interface{}
+}
+
+// checkLimits retorna un error si la operación supera la profundidad o el costo máximos.
+func checkLimits(limits Limits, doc *ast.Document, operation *ast.OperationDefinition, variables map[string]interface{}) error {
+	calculator := complexityCalculator{
+		fragments: make(map[string]*ast.FragmentDefinition),
+		variables: variables,
+	}
+	for _, definition := range doc.Definitions {
+		if fragment, ok := definition.(*ast.FragmentDefinition); ok {
+			calculator.fragments[fragment.Name.Value] = fragment
+		}
+	}
+
+	cost, depth, introspectionDepth := calculator.selectionSet(operation.SelectionSet)
+
+	if limits.MaxDepth > 0 && depth > limits.MaxDepth {
+		return limitError(fmt.Sprintf("query depth %d exceeds the maximum of %d", depth, limits.MaxDepth))
+	}
+	if limits.MaxDepth > 0 && introspectionDepth > max(limits.MaxDepth, maxIntrospectionDepth) {
+		return limitError(fmt.Sprintf("introspection depth %d exceeds the maximum of %d",
+			introspectionDepth, max(limits.MaxDepth, maxIntrospectionDepth)))
+	}
+	if limits.MaxComplexity > 0 && cost > limits.MaxComplexity {
+		return limitError(fmt.Sprintf("query complexity %d exceeds the maximum of %d", cost, limits.MaxComplexity))
+	}
+	return nil
+}
+
+// selectionSet retorna el costo y la profundidad de un conjunto de selecciones, con la
+// profundidad de los campos de introspección por separado.
+func (c *complexityCalculator) selectionSet(set *ast.SelectionSet) (cost, depth, introspectionDepth int) {
+	if set == nil {
+		return 0, 0, 0
+	}
+
+	for _, selection := range set.Selections {
+		var selectionCost, selectionDepth, selectionIntrospection int
+
+		switch node := selection.(type) {
+		case *ast.Field:
+			childCost, childDepth, childIntrospection := c.selectionSet(node.SelectionSet)
+			selectionCost = 1 + c.multiplier(node)*childCost
+			if isIntrospectionRoot(node) {
+				selectionIntrospection = 1 + max(childDepth, childIntrospection)
+			} else {
+				selectionDepth = 1 + childDepth
+				if childIntrospection > 0 {
+					selectionIntrospection = 1 + childIntrospection
+				}
+			}
+		case *ast.InlineFragment:
+			selectionCost, selectionDepth, selectionIntrospection = c.selectionSet(node.SelectionSet)
+		case *ast.FragmentSpread:
+			if fragment, ok := c.fragments[node.Name.Value]; ok {
+				selectionCost, selectionDepth, selectionIntrospection = c.selectionSet(fragment.SelectionSet)
+			}
+		}
+
+		cost += selectionCost
+		depth = max(depth, selectionDepth)
+		introspectionDepth = max(introspectionDepth, selectionIntrospection)
+	}
+	return cost, depth, introspectionDepth
+}
+
+// isIntrospectionRoot verifica si el campo inicia una consulta de introspección.
+// __typename es un campo escalar y cuenta como cualquier otro.
+func isIntrospectionRoot(field *ast.Field) bool {
+	return field.Name.Value == "__schema" || field.Name.Value == "__type"
+}
+
+// multiplier retorna la cantidad estimada de elementos de un campo: el argumento limit
+// o la cantidad de ids si se indican, o el valor de listMultipliers. Los campos que no
+// son listas retornan 1.
+func (c *complexityCalculator) multiplier(field *ast.Field) int {
+	multiplier, isList := listMultipliers[field.Name.Value]
+	if !isList {
+		return 1
+	}
+
+	for _, argument := range field.Arguments {
+		switch argument.Name.Value {
+		case "limit":
+			if limit, ok := c.intValue(argument.Value); ok && limit > 0 {
+				multiplier = limit
+			}
+		case "ids":
+			if list, ok := argument.Value.(*ast.ListValue); ok && len(list.Values) > 0 {
+				multiplier = len(list.Values)
+			}
+		}
+	}
+	return multiplier
+}
+
+// intValue obtiene el valor entero de un literal o de una variable.
+func (c *complexityCalculator) intValue(value ast.Value) (int, bool) {
+	switch node := value.(type) {
+	case *ast.IntValue:
+		n, err := strconv.Atoi(node.Value)
+		return n, err == nil
+	case *ast.Variable:
+		switch n := c.variables[node.Name.Value].(type) {
+		case int:
+			return n, true
+		case float64:
+			return int(n), true
+		}
+	}
+	return 0, false
+}
+
+// limitError crea el error GraphQL de una consulta rechazada por sus límites.
+func limitError(message string) error {
+	return gqlerrors.FormattedError{
+		Message:    message,
+		Extensions: map[string]interface{}{"code": "QUERY_TOO_COMPLEX"},
+	}
+}
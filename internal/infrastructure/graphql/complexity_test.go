@@ -0,0 +1,65 @@
+package graphql
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/testutil"
+)
+
+// checkQuery analiza la consulta y aplica los límites a su primera operación.
+func checkQuery(t *testing.T, limits Limits, query string) error {
+	t.Helper()
+
+	doc, err := parser.Parse(parser.ParseParams{Source: query})
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	operation, err := findOperation(doc, "")
+	if err != nil {
+		t.Fatalf("findOperation: %v", err)
+	}
+	return checkLimits(limits, doc, operation, nil)
+}
+
+// nestedOfType retorna una consulta __type con levels campos ofType anidados.
+func nestedOfType(levels int) string {
+	return `{ __type(name: "Currency") { ` + strings.Repeat("ofType { ", levels) + "name" +
+		strings.Repeat(" }", levels) + " } }"
+}
+
+func TestCheckLimitsCountsIntrospection(t *testing.T) {
+	defaults := Limits{MaxDepth: 8, MaxComplexity: 10000}
+
+	tests := []struct {
+		name   string
+		limits Limits
+		query  string
+		error  string
+	}{
+		{"standard introspection query", defaults, testutil.IntrospectionQuery, ""},
+		{"introspection within the cap", defaults, nestedOfType(maxIntrospectionDepth - 2), ""},
+		{"introspection beyond the cap", defaults, nestedOfType(maxIntrospectionDepth), "introspection depth"},
+		{"introspection without limits", Limits{}, nestedOfType(50), ""},
+		{"introspection counts toward complexity", Limits{MaxComplexity: 3}, `{ __schema { types { name kind } } }`, "query complexity 4"},
+		{"typename counts toward complexity", Limits{MaxComplexity: 2}, `{ currencies { __typename } __typename }`, "query complexity"},
+		{"typename counts toward depth", Limits{MaxDepth: 1}, `{ currencies { __typename } }`, "query depth 2"},
+		{"regular depth", Limits{MaxDepth: 2}, `{ convert(from: "USD", amount: 1) { result } }`, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkQuery(t, tt.limits, tt.query)
+			if tt.error == "" {
+				if err != nil {
+					t.Fatalf("checkLimits: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.error) {
+				t.Fatalf("checkLimits error = %v, want %q", err, tt.error)
+			}
+		})
+	}
+}
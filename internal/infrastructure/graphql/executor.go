@@ -0,0 +1,192 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+
+	"gobcv/internal/application/query"
+	"gobcv/internal/domain/repository"
+	"gobcv/internal/domain/service"
+)
+
+// ErrSubscriptionOverHTTP indica que se envió una suscripción por HTTP en lugar de WebSocket.
+var ErrSubscriptionOverHTTP = errors.New("subscriptions require a WebSocket connection using the graphql-transport-ws protocol")
+
+// Request es una operación GraphQL tal como la envían los clientes.
+type Request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+// Response es el resultado de una operación GraphQL. Data se omite si la operación
+// no llegó a ejecutarse.
+type Response struct {
+	Data   interface{}                `json:"data,omitempty"`
+	Errors []gqlerrors.FormattedError `json:"errors,omitempty"`
+}
+
+// Executor ejecuta las operaciones GraphQL sobre el esquema de monedas.
+type Executor struct {
+	schema graphql.Schema
+	limits Limits
+}
+
+// NewExecutor construye el esquema con los mismos handlers de consultas que la API
+// REST, y la suscripción rateChanged con el broker y el historial de cambios. buffer
+// es la cantidad de cambios que puede acumular una suscripción lenta antes de cerrarla.
+func NewExecutor(
+	getCurrencyHandler *query.GetCurrencyHandler,
+	getAllHandler *query.GetAllCurrenciesHandler,
+	convertHandler *query.ConvertCurrencyHandler,
+	historyHandler *query.GetRateHistoryHandler,
+	events service.RateEventBroker,
+	history repository.RateHistoryRepository,
+	buffer int,
+	limits Limits,
+) (*Executor, error) {
+	schema, err := newSchema(&resolver{
+		getCurrencyHandler: getCurrencyHandler,
+		getAllHandler:      getAllHandler,
+		convertHandler:     convertHandler,
+		historyHandler:     historyHandler,
+		events:             events,
+		history:            history,
+		buffer:             buffer,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to build GraphQL schema: %w", err)
+	}
+
+	return &Executor{schema: schema, limits: limits}, nil
+}
+
+// Execute ejecuta una consulta. Las suscripciones se rechazan con ErrSubscriptionOverHTTP.
+func (e *Executor) Execute(ctx context.Context, req Request) *Response {
+	doc, operation, errResponse := e.prepare(req)
+	if errResponse != nil {
+		return errResponse
+	}
+	if operation.Operation == ast.OperationTypeSubscription {
+		return NewErrorResponse(ErrSubscriptionOverHTTP)
+	}
+
+	return e.execute(ctx, doc, req)
+}
+
+// Subscribe ejecuta una operación y retorna un canal con sus resultados, que se cierra
+// al terminar la suscripción o cancelarse el contexto. Las consultas producen un único
+// resultado. Si la operación no es válida retorna nil y la respuesta con los errores.
+func (e *Executor) Subscribe(ctx context.Context, req Request) (<-chan *Response, *Response) {
+	doc, operation, errResponse := e.prepare(req)
+	if errResponse != nil {
+		return nil, errResponse
+	}
+
+	out := make(chan *Response, 1)
+	if operation.Operation != ast.OperationTypeSubscription {
+		out <- e.execute(ctx, doc, req)
+		close(out)
+		return out, nil
+	}
+
+	results := graphql.ExecuteSubscription(graphql.ExecuteParams{
+		Schema:        e.schema,
+		AST:           doc,
+		OperationName: req.OperationName,
+		Args:          req.Variables,
+		Context:       ctx,
+	})
+
+	go func() {
+		defer close(out)
+		// La librería envía los resultados sin observar el contexto, por lo que el
+		// canal se vacía hasta que lo cierre aunque el suscriptor ya no los reciba
+		for result := range results {
+			select {
+			case out <- &Response{Data: result.Data, Errors: result.Errors}:
+			case <-ctx.Done():
+			}
+		}
+	}()
+
+	return out, nil
+}
+
+// prepare analiza y valida la operación y comprueba sus límites.
+func (e *Executor) prepare(req Request) (*ast.Document, *ast.OperationDefinition, *Response) {
+	doc, err := parser.Parse(parser.ParseParams{
+		Source: source.NewSource(&source.Source{Body: []byte(req.Query), Name: "GraphQL request"}),
+	})
+	if err != nil {
+		return nil, nil, NewErrorResponse(err)
+	}
+
+	validation := graphql.ValidateDocument(&e.schema, doc, nil)
+	if !validation.IsValid {
+		return nil, nil, &Response{Errors: validation.Errors}
+	}
+
+	operation, err := findOperation(doc, req.OperationName)
+	if err != nil {
+		return nil, nil, NewErrorResponse(err)
+	}
+
+	if err := checkLimits(e.limits, doc, operation, req.Variables); err != nil {
+		return nil, nil, NewErrorResponse(err)
+	}
+
+	return doc, operation, nil
+}
+
+// execute ejecuta una consulta ya validada.
+func (e *Executor) execute(ctx context.Context, doc *ast.Document, req Request) *Response {
+	result := graphql.Execute(graphql.ExecuteParams{
+		Schema:        e.schema,
+		AST:           doc,
+		OperationName: req.OperationName,
+		Args:          req.Variables,
+		Context:       ctx,
+	})
+	return &Response{Data: result.Data, Errors: result.Errors}
+}
+
+// findOperation obtiene la operación indicada por nombre, o la única del documento.
+func findOperation(doc *ast.Document, name string) (*ast.OperationDefinition, error) {
+	var found *ast.OperationDefinition
+	for _, definition := range doc.Definitions {
+		operation, ok := definition.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+
+		if name == "" {
+			if found != nil {
+				return nil, errors.New("must provide operation name if query contains multiple operations")
+			}
+			found = operation
+		} else if operation.Name != nil && operation.Name.Value == name {
+			return operation, nil
+		}
+	}
+
+	if found == nil {
+		if name != "" {
+			return nil, fmt.Errorf("unknown operation named %q", name)
+		}
+		return nil, errors.New("must provide an operation")
+	}
+	return found, nil
+}
+
+// NewErrorResponse crea la respuesta de una operación que no llegó a ejecutarse.
+func NewErrorResponse(err error) *Response {
+	return &Response{Errors: gqlerrors.FormatErrors(err)}
+}
@@ -0,0 +1,399 @@
+// Package graphql implementa el esquema GraphQL sobre las consultas de la aplicación.
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/graphql-go/graphql"
+
+	"gobcv/internal/application/query"
+	"gobcv/internal/domain/entity"
+	"gobcv/internal/domain/repository"
+	"gobcv/internal/domain/service"
+)
+
+const (
+	// defaultHistoryLimit es la cantidad de cambios que retorna history sin limit.
+	defaultHistoryLimit = 100
+	// maxHistoryLimit es el máximo de cambios que retorna history.
+	maxHistoryLimit = 1000
+)
+
+// resolver contiene las dependencias de los resolvers del esquema.
+type resolver struct {
+	getCurrencyHandler *query.GetCurrencyHandler
+	getAllHandler      *query.GetAllCurrenciesHandler
+	convertHandler     *query.ConvertCurrencyHandler
+	historyHandler     *query.GetRateHistoryHandler
+	events             service.RateEventBroker
+	history            repository.RateHistoryRepository
+	buffer             int
+}
+
+// newSchema construye el esquema:
+//
+//	type Query {
+//	  currencies(ids: [String!], includeStale: Boolean = false): [Currency!]!
+//	  currency(id: String!): Currency
+//	  history(currencyId: String!, from: DateTime, to: DateTime, limit: Int = 100): [RateChange!]!
+//	  convert(from: String!, to: String = "VES", amount: Float = 1): Conversion
+//	}
+//	type Subscription {
+//	  rateChanged(currencies: [String!], since: Int): RateChange!
+//	}
+//
+// Currency expone además history y convert para combinar tasas, historial y
+// conversiones en una sola consulta.
+func newSchema(r *resolver) (graphql.Schema, error) {
+	historyArgs := graphql.FieldConfigArgument{
+		"from":  {Type: graphql.DateTime, Description: "Inicio del rango; por defecto el cambio más antiguo"},
+		"to":    {Type: graphql.DateTime, Description: "Fin del rango; por defecto ahora"},
+		"limit": {Type: graphql.Int, DefaultValue: defaultHistoryLimit, Description: "Cambios más recientes del rango, hasta 1000"},
+	}
+
+	rateChangeType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "RateChange",
+		Description: "Cambio de valor de una moneda con su secuencia en el historial",
+		Fields: graphql.Fields{
+			"sequence":      {Type: graphql.NewNonNull(graphql.Int), Resolve: rateChangeField(func(c *entity.RateChange) interface{} { return int(c.Sequence) })},
+			"currencyId":    {Type: graphql.NewNonNull(graphql.String), Resolve: rateChangeField(func(c *entity.RateChange) interface{} { return c.CurrencyID })},
+			"name":          {Type: graphql.NewNonNull(graphql.String), Resolve: rateChangeField(func(c *entity.RateChange) interface{} { return c.Name })},
+			"value":         {Type: graphql.NewNonNull(graphql.Float), Resolve: rateChangeField(func(c *entity.RateChange) interface{} { return c.Value })},
+			"previousValue": {Type: graphql.Float, Resolve: rateChangeField(func(c *entity.RateChange) interface{} { return optionalFloat(c.PreviousValue) })},
+			"changePercent": {Type: graphql.Float, Resolve: rateChangeField(func(c *entity.RateChange) interface{} { return optionalFloat(c.ChangePercent()) })},
+			"source":        {Type: graphql.NewNonNull(graphql.String), Resolve: rateChangeField(func(c *entity.RateChange) interface{} { return c.Source })},
+			"changedAt":     {Type: graphql.NewNonNull(graphql.DateTime), Resolve: rateChangeField(func(c *entity.RateChange) interface{} { return c.ChangedAt })},
+		},
+	})
+
+	conversionType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Conversion",
+		Description: "Conversión de un monto entre dos monedas a partir de sus tasas en VES",
+		Fields: graphql.Fields{
+			"from":      {Type: graphql.NewNonNull(graphql.String), Resolve: conversionField(func(c *query.ConvertCurrencyResult) interface{} { return c.From })},
+			"to":        {Type: graphql.NewNonNull(graphql.String), Resolve: conversionField(func(c *query.ConvertCurrencyResult) interface{} { return c.To })},
+			"amount":    {Type: graphql.NewNonNull(graphql.Float), Resolve: conversionField(func(c *query.ConvertCurrencyResult) interface{} { return c.Amount })},
+			"result":    {Type: graphql.NewNonNull(graphql.Float), Resolve: conversionField(func(c *query.ConvertCurrencyResult) interface{} { return c.Result })},
+			"rate":      {Type: graphql.NewNonNull(graphql.Float), Description: "Unidades de to por unidad de from", Resolve: conversionField(func(c *query.ConvertCurrencyResult) interface{} { return c.Rate })},
+			"ratesAt":   {Type: graphql.DateTime, Description: "Fecha de la tasa más antigua usada", Resolve: conversionField(func(c *query.ConvertCurrencyResult) interface{} { return optionalTime(c.RatesAt) })},
+			"fromCache": {Type: graphql.NewNonNull(graphql.Boolean), Resolve: conversionField(func(c *query.ConvertCurrencyResult) interface{} { return c.FromCache })},
+			"stale":     {Type: graphql.NewNonNull(graphql.Boolean), Resolve: conversionField(func(c *query.ConvertCurrencyResult) interface{} { return c.Stale })},
+		},
+	})
+
+	currencyType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Currency",
+		Description: "Moneda con su valor en VES publicado por el BCV",
+		Fields: graphql.Fields{
+			"id":        {Type: graphql.NewNonNull(graphql.String), Resolve: currencyField(func(c *entity.Currency) interface{} { return c.ID })},
+			"name":      {Type: graphql.NewNonNull(graphql.String), Resolve: currencyField(func(c *entity.Currency) interface{} { return c.Name })},
+			"value":     {Type: graphql.NewNonNull(graphql.Float), Resolve: currencyField(func(c *entity.Currency) interface{} { return c.Value })},
+			"updatedAt": {Type: graphql.NewNonNull(graphql.DateTime), Resolve: currencyField(func(c *entity.Currency) interface{} { return c.UpdatedAt })},
+			"source":    {Type: graphql.NewNonNull(graphql.String), Resolve: currencyField(func(c *entity.Currency) interface{} { return c.Source })},
+			"history": {
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(rateChangeType))),
+				Description: "Cambios de la moneda en el rango",
+				Args:        historyArgs,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return r.resolveHistory(p.Context, p.Source.(*entity.Currency).ID, p.Args)
+				},
+			},
+			"convert": {
+				Type:        conversionType,
+				Description: "Convierte amount de esta moneda a to",
+				Args: graphql.FieldConfigArgument{
+					"to":     {Type: graphql.String, DefaultValue: query.BaseCurrency},
+					"amount": {Type: graphql.Float, DefaultValue: 1.0},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					to, _ := p.Args["to"].(string)
+					amount, _ := p.Args["amount"].(float64)
+					return r.resolveConvert(p.Context, p.Source.(*entity.Currency).ID, to, amount)
+				},
+			},
+		},
+	})
+
+	queryType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"currencies": {
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(currencyType))),
+				Description: "Monedas vigentes, filtradas por ids si se indican",
+				Args: graphql.FieldConfigArgument{
+					"ids":          {Type: graphql.NewList(graphql.NewNonNull(graphql.String))},
+					"includeStale": {Type: graphql.Boolean, DefaultValue: false},
+				},
+				Resolve: r.resolveCurrencies,
+			},
+			"currency": {
+				Type:        currencyType,
+				Description: "Moneda por su código ISO 4217, o null si no existe",
+				Args: graphql.FieldConfigArgument{
+					"id": {Type: graphql.NewNonNull(graphql.String)},
+				},
+				Resolve: r.resolveCurrency,
+			},
+			"history": {
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(rateChangeType))),
+				Description: "Cambios de una moneda en el rango",
+				Args: graphql.FieldConfigArgument{
+					"currencyId": {Type: graphql.NewNonNull(graphql.String)},
+					"from":       historyArgs["from"],
+					"to":         historyArgs["to"],
+					"limit":      historyArgs["limit"],
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					currencyID, _ := p.Args["currencyId"].(string)
+					return r.resolveHistory(p.Context, currencyID, p.Args)
+				},
+			},
+			"convert": {
+				Type:        conversionType,
+				Description: "Convierte un monto entre dos monedas (incluido VES)",
+				Args: graphql.FieldConfigArgument{
+					"from":   {Type: graphql.NewNonNull(graphql.String)},
+					"to":     {Type: graphql.String, DefaultValue: query.BaseCurrency},
+					"amount": {Type: graphql.Float, DefaultValue: 1.0},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					from, _ := p.Args["from"].(string)
+					to, _ := p.Args["to"].(string)
+					amount, _ := p.Args["amount"].(float64)
+					return r.resolveConvert(p.Context, from, to, amount)
+				},
+			},
+		},
+	})
+
+	subscriptionType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Subscription",
+		Fields: graphql.Fields{
+			"rateChanged": {
+				Type: graphql.NewNonNull(rateChangeType),
+				Description: "Un mensaje por cada cambio de tasa de las monedas indicadas (todas si se omite). " +
+					"since reanuda desde el historial con la última secuencia recibida",
+				Args: graphql.FieldConfigArgument{
+					"currencies": {Type: graphql.NewList(graphql.NewNonNull(graphql.String))},
+					"since":      {Type: graphql.Int},
+				},
+				Subscribe: r.subscribeRateChanged,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source, nil
+				},
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{
+		Query:        queryType,
+		Subscription: subscriptionType,
+	})
+}
+
+// resolveCurrencies obtiene las monedas con la consulta de todas las monedas.
+func (r *resolver) resolveCurrencies(p graphql.ResolveParams) (interface{}, error) {
+	includeStale, _ := p.Args["includeStale"].(bool)
+
+	result, err := r.getAllHandler.Handle(p.Context, query.GetAllCurrenciesQuery{UseCache: true, IncludeStale: includeStale})
+	if err != nil {
+		return nil, err
+	}
+
+	ids := stringList(p.Args["ids"])
+	if len(ids) == 0 {
+		return result.Currencies, nil
+	}
+
+	currencies := make([]*entity.Currency, 0, len(ids))
+	for _, currency := range result.Currencies {
+		for _, id := range ids {
+			if currency.ID == id {
+				currencies = append(currencies, currency)
+				break
+			}
+		}
+	}
+	return currencies, nil
+}
+
+// resolveCurrency obtiene una moneda con la consulta de monedas individuales.
+func (r *resolver) resolveCurrency(p graphql.ResolveParams) (interface{}, error) {
+	id, _ := p.Args["id"].(string)
+	id = strings.ToUpper(strings.TrimSpace(id))
+
+	result, err := r.getCurrencyHandler.Handle(p.Context, query.GetCurrencyQuery{CurrencyID: id, UseCache: true})
+	if err != nil {
+		return nil, err
+	}
+	if !result.Success {
+		return nil, nil
+	}
+	return result.Currency, nil
+}
+
+// resolveHistory obtiene los cambios más recientes de la moneda dentro del rango.
+func (r *resolver) resolveHistory(ctx context.Context, currencyID string, args map[string]interface{}) (interface{}, error) {
+	from, _ := args["from"].(time.Time)
+	to, _ := args["to"].(time.Time)
+
+	limit, _ := args["limit"].(int)
+	if limit <= 0 || limit > maxHistoryLimit {
+		return nil, fmt.Errorf("limit must be between 1 and %d", maxHistoryLimit)
+	}
+
+	result, err := r.historyHandler.Handle(ctx, query.GetRateHistoryQuery{
+		CurrencyID: strings.ToUpper(strings.TrimSpace(currencyID)),
+		From:       from,
+		To:         to,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	changes := result.Changes
+	if len(changes) > limit {
+		changes = changes[len(changes)-limit:]
+	}
+	return changes, nil
+}
+
+// resolveConvert convierte un monto con la consulta de conversión. Una moneda
+// inexistente se reporta como error del campo.
+func (r *resolver) resolveConvert(ctx context.Context, from, to string, amount float64) (interface{}, error) {
+	result, err := r.convertHandler.Handle(ctx, query.ConvertCurrencyQuery{
+		From:     strings.ToUpper(strings.TrimSpace(from)),
+		To:       strings.ToUpper(strings.TrimSpace(to)),
+		Amount:   amount,
+		UseCache: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !result.Success {
+		return nil, fmt.Errorf("%s", result.Message)
+	}
+	return result, nil
+}
+
+// subscribeRateChanged suscribe la operación al broker de cambios de tasas, enviando
+// antes los cambios del historial posteriores a since. El canal se cierra al cancelarse
+// la operación o si el broker descarta la suscripción por lenta.
+func (r *resolver) subscribeRateChanged(p graphql.ResolveParams) (interface{}, error) {
+	ctx := p.Context
+	ids := stringList(p.Args["currencies"])
+
+	since, _ := p.Args["since"].(int)
+	if since < 0 {
+		return nil, fmt.Errorf("since must not be negative")
+	}
+
+	// Suscribirse antes de leer el historial para no perder cambios intermedios
+	events := r.events.Subscribe(ctx, ids, r.buffer)
+
+	backlog, reset, err := repository.FindResumeBacklog(ctx, r.history, uint64(since), ids)
+	if err != nil {
+		return nil, err
+	}
+	if reset {
+		// Las secuencias se reiniciaron: se descarta el cursor para no omitir los cambios nuevos
+		since = 0
+	}
+
+	out := make(chan interface{})
+	go func() {
+		defer close(out)
+
+		lastSequence := uint64(since)
+		send := func(change *entity.RateChange) bool {
+			select {
+			case out <- change:
+				if change.Sequence > lastSequence {
+					lastSequence = change.Sequence
+				}
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		for _, change := range backlog {
+			if !send(change) {
+				return
+			}
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case change, ok := <-events:
+				if !ok {
+					return
+				}
+				// Los cambios ya enviados desde el historial llegan también por la suscripción
+				if change.Sequence != 0 && change.Sequence <= lastSequence {
+					continue
+				}
+				if !send(change) {
+					return
+				}
+			}
+		}
+	}()
+
+	return out, nil
+}
+
+// currencyField crea un resolver que lee un campo de entity.Currency.
+func currencyField(get func(*entity.Currency) interface{}) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		return get(p.Source.(*entity.Currency)), nil
+	}
+}
+
+// rateChangeField crea un resolver que lee un campo de entity.RateChange.
+func rateChangeField(get func(*entity.RateChange) interface{}) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		return get(p.Source.(*entity.RateChange)), nil
+	}
+}
+
+// conversionField crea un resolver que lee un campo del resultado de una conversión.
+func conversionField(get func(*query.ConvertCurrencyResult) interface{}) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		return get(p.Source.(*query.ConvertCurrencyResult)), nil
+	}
+}
+
+// optionalFloat retorna nil para el valor cero, que en RateChange indica un dato desconocido.
+func optionalFloat(value float64) interface{} {
+	if value == 0 {
+		return nil
+	}
+	return value
+}
+
+// optionalTime retorna nil para la fecha cero.
+func optionalTime(value time.Time) interface{} {
+	if value.IsZero() {
+		return nil
+	}
+	return value
+}
+
+// stringList convierte un argumento de tipo [String!] en códigos de moneda en mayúsculas.
+func stringList(arg interface{}) []string {
+	values, _ := arg.([]interface{})
+
+	list := make([]string, 0, len(values))
+	for _, value := range values {
+		if s, ok := value.(string); ok && strings.TrimSpace(s) != "" {
+			list = append(list, strings.ToUpper(strings.TrimSpace(s)))
+		}
+	}
+	return list
+}
@@ -0,0 +1,70 @@
+// Package grpc implementa la autenticación por claves de API.
+package grpc
+
+import (
+	"context"
+	"log/slog"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"gobcv/internal/domain/entity"
+	"gobcv/internal/domain/repository"
+)
+
+// apiKeyMetadata es la clave de metadata con la clave de API, equivalente a la cabecera X-API-Key.
+const apiKeyMetadata = "x-api-key"
+
+// Authenticator exige en las llamadas a CurrencyService una clave de API con el
+// scope read, con las mismas claves y scopes anónimos que la API REST.
+type Authenticator struct {
+	keys            repository.APIKeyRepository
+	anonymousScopes []string
+}
+
+// NewAuthenticator crea el autenticador. anonymousScopes son los scopes concedidos a
+// las llamadas sin clave de API.
+func NewAuthenticator(keys repository.APIKeyRepository, anonymousScopes []string) *Authenticator {
+	return &Authenticator{
+		keys:            keys,
+		anonymousScopes: anonymousScopes,
+	}
+}
+
+// authenticate valida la clave de la llamada y la agrega al contexto.
+func (a *Authenticator) authenticate(ctx context.Context, md metadata.MD) (context.Context, error) {
+	rawKey := firstValue(md, apiKeyMetadata)
+	if rawKey == "" {
+		anonymous := entity.APIKey{Scopes: a.anonymousScopes}
+		if anonymous.HasScope(entity.ScopeRead) {
+			return ctx, nil
+		}
+		return ctx, status.Error(codes.Unauthenticated, "missing "+apiKeyMetadata+" metadata")
+	}
+
+	key, err := a.keys.FindByHash(ctx, entity.HashAPIKey(rawKey))
+	if err != nil {
+		slog.ErrorContext(ctx, "Error buscando clave de API", "error", err)
+		return ctx, status.Error(codes.Internal, "error validating API key")
+	}
+
+	if key == nil || key.Disabled {
+		return ctx, status.Error(codes.Unauthenticated, "invalid API key")
+	}
+
+	if !key.HasScope(entity.ScopeRead) {
+		return ctx, status.Error(codes.PermissionDenied, "required scope: "+entity.ScopeRead)
+	}
+
+	return context.WithValue(ctx, apiKeyContextKey{}, key), nil
+}
+
+// apiKeyContextKey es la clave del contexto con la clave de API autenticada.
+type apiKeyContextKey struct{}
+
+// APIKeyFromContext retorna la clave de API autenticada en la llamada, si existe.
+func APIKeyFromContext(ctx context.Context) (*entity.APIKey, bool) {
+	key, ok := ctx.Value(apiKeyContextKey{}).(*entity.APIKey)
+	return key, ok
+}
@@ -0,0 +1,46 @@
+// Package grpc implementa la API gRPC de la aplicación.
+package grpc
+
+import (
+	"fmt"
+
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// CodecName es el subtipo de contenido que codifica los mensajes en JSON
+// (application/grpc+json) en lugar de protobuf, el formato por defecto. Los clientes en
+// Go lo seleccionan con grpc.CallContentSubtype(CodecName).
+const CodecName = "json"
+
+// Codec codifica los mensajes protobuf en su representación JSON canónica, con los
+// nombres de campo del archivo .proto.
+type Codec struct{}
+
+func init() {
+	encoding.RegisterCodec(Codec{})
+}
+
+// Marshal codifica el mensaje.
+func (Codec) Marshal(v any) ([]byte, error) {
+	message, ok := v.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("failed to marshal, message is %T, want proto.Message", v)
+	}
+	return protojson.MarshalOptions{UseProtoNames: true}.Marshal(message)
+}
+
+// Unmarshal decodifica el mensaje.
+func (Codec) Unmarshal(data []byte, v any) error {
+	message, ok := v.(proto.Message)
+	if !ok {
+		return fmt.Errorf("failed to unmarshal, message is %T, want proto.Message", v)
+	}
+	return protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(data, message)
+}
+
+// Name retorna el subtipo de contenido del codec.
+func (Codec) Name() string {
+	return CodecName
+}
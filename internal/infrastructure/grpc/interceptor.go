@@ -0,0 +1,167 @@
+// Package grpc implementa los interceptores de trazas, autenticación y logging.
+package grpc
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"gobcv/pkg/requestid"
+	"gobcv/pkg/telemetry"
+)
+
+// requestIDKey es la clave de metadata que transporta el ID de petición.
+const requestIDKey = "x-request-id"
+
+// interceptor aplica a cada llamada lo mismo que la cadena de middlewares HTTP:
+// ID de petición, traza, autenticación y registro en el log.
+type interceptor struct {
+	auth   *Authenticator
+	tracer trace.Tracer
+}
+
+// newInterceptor crea el interceptor. auth puede ser nil.
+func newInterceptor(auth *Authenticator) *interceptor {
+	return &interceptor{
+		auth:   auth,
+		tracer: telemetry.Tracer(),
+	}
+}
+
+// unary intercepta las llamadas unarias.
+func (i *interceptor) unary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	var resp any
+	err := i.intercept(ctx, info.FullMethod, func(ctx context.Context) error {
+		var err error
+		resp, err = handler(ctx, req)
+		return err
+	})
+	return resp, err
+}
+
+// stream intercepta las llamadas de streaming.
+func (i *interceptor) stream(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return i.intercept(stream.Context(), info.FullMethod, func(ctx context.Context) error {
+		return handler(srv, &contextStream{ServerStream: stream, ctx: ctx})
+	})
+}
+
+// intercept ejecuta call con el contexto de la llamada enriquecido.
+func (i *interceptor) intercept(ctx context.Context, fullMethod string, call func(ctx context.Context) error) error {
+	start := time.Now()
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	// Reutilizar el ID de petición del cliente si es válido, igual que en HTTP
+	id := firstValue(md, requestIDKey)
+	if !requestid.IsValid(id) {
+		id = requestid.New()
+	}
+	ctx = requestid.WithID(ctx, id)
+	grpc.SetHeader(ctx, metadata.Pairs(requestIDKey, id))
+
+	ctx = otel.GetTextMapPropagator().Extract(ctx, metadataCarrier(md))
+	ctx, span := i.tracer.Start(ctx, fullMethod,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			attribute.String("rpc.system", "grpc"),
+			attribute.String("rpc.method", fullMethod),
+		),
+	)
+	defer span.End()
+
+	var err error
+	if i.auth != nil && !isHealthMethod(fullMethod) {
+		ctx, err = i.auth.authenticate(ctx, md)
+	}
+	if err == nil {
+		err = call(ctx)
+	}
+
+	code := status.Code(err)
+	span.SetAttributes(attribute.String("rpc.grpc.status_code", code.String()))
+	if isServerError(code) {
+		span.SetStatus(otelcodes.Error, err.Error())
+	}
+
+	// Los health checks se registran en nivel debug para evitar spam
+	level := slog.LevelInfo
+	if isHealthMethod(fullMethod) {
+		level = slog.LevelDebug
+	}
+
+	remoteAddr := ""
+	if p, ok := peer.FromContext(ctx); ok {
+		remoteAddr = p.Addr.String()
+	}
+
+	slog.Log(ctx, level, "gRPC request",
+		"method", fullMethod,
+		"code", code.String(),
+		"duration", time.Since(start),
+		"remote_addr", remoteAddr,
+	)
+	return err
+}
+
+// isServerError indica si el código corresponde a un fallo del servidor.
+func isServerError(code codes.Code) bool {
+	switch code {
+	case codes.Unknown, codes.Internal, codes.Unavailable, codes.DataLoss, codes.Unimplemented:
+		return true
+	}
+	return false
+}
+
+// contextStream reemplaza el contexto de un ServerStream.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context retorna el contexto enriquecido por el interceptor.
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
+
+// metadataCarrier adapta metadata.MD a propagation.TextMapCarrier.
+type metadataCarrier metadata.MD
+
+var _ propagation.TextMapCarrier = metadataCarrier(nil)
+
+// Get retorna el primer valor de la clave.
+func (c metadataCarrier) Get(key string) string {
+	return firstValue(metadata.MD(c), key)
+}
+
+// Set reemplaza el valor de la clave.
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+// Keys retorna las claves presentes.
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// firstValue retorna el primer valor de la clave en la metadata, o vacío.
+func firstValue(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
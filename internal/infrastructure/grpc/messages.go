@@ -0,0 +1,69 @@
+// Package grpc convierte las entidades de dominio en los mensajes de CurrencyService.
+package grpc
+
+import (
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	gobcvv1 "gobcv/api/gobcv/v1"
+	"gobcv/internal/domain/entity"
+)
+
+// Tipos de RateEvent enviados por WatchRates.
+const (
+	RateEventRate     = "rate"
+	RateEventSnapshot = "snapshot"
+)
+
+// currencyMessage convierte una moneda en su mensaje.
+func currencyMessage(currency *entity.Currency) *gobcvv1.Currency {
+	if currency == nil {
+		return nil
+	}
+	return &gobcvv1.Currency{
+		Id:        currency.ID,
+		Name:      currency.Name,
+		Value:     currency.Value,
+		UpdatedAt: timestampMessage(currency.UpdatedAt),
+		Source:    currency.Source,
+	}
+}
+
+// currencyMessages convierte una lista de monedas en sus mensajes.
+func currencyMessages(currencies []*entity.Currency) []*gobcvv1.Currency {
+	messages := make([]*gobcvv1.Currency, 0, len(currencies))
+	for _, currency := range currencies {
+		messages = append(messages, currencyMessage(currency))
+	}
+	return messages
+}
+
+// rateChangeMessage convierte un cambio de tasa en su mensaje.
+func rateChangeMessage(change *entity.RateChange) *gobcvv1.RateChange {
+	return &gobcvv1.RateChange{
+		Sequence:      change.Sequence,
+		CurrencyId:    change.CurrencyID,
+		Name:          change.Name,
+		Value:         change.Value,
+		PreviousValue: change.PreviousValue,
+		Source:        change.Source,
+		ChangedAt:     timestampMessage(change.ChangedAt),
+	}
+}
+
+// timestampMessage convierte una fecha en Timestamp. La fecha cero se omite.
+func timestampMessage(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+// timestampTime convierte un Timestamp en fecha. Un Timestamp ausente es la fecha cero.
+func timestampTime(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
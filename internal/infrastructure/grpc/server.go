@@ -0,0 +1,123 @@
+// Package grpc implementa el servidor gRPC con health checking.
+package grpc
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/reflection"
+
+	gobcvv1 "gobcv/api/gobcv/v1"
+	appService "gobcv/internal/application/service"
+)
+
+// ReadinessChecker expone el estado de las dependencias de la aplicación.
+type ReadinessChecker interface {
+	Readiness(ctx context.Context) appService.HealthReport
+}
+
+// Server es el servidor gRPC de la aplicación: CurrencyService, grpc.health.v1.Health y
+// reflection para que herramientas como grpcurl descubran los servicios.
+type Server struct {
+	server *grpc.Server
+	health *health.Server
+}
+
+// NewServer crea el servidor gRPC. auth puede ser nil para no exigir claves de API y
+// keepaliveInterval es el intervalo de los pings HTTP/2 que mantienen vivas las
+// conexiones ociosas, como las de WatchRates.
+func NewServer(currencies gobcvv1.CurrencyServiceServer, auth *Authenticator, keepaliveInterval time.Duration) *Server {
+	interceptor := newInterceptor(auth)
+
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(interceptor.unary),
+		grpc.ChainStreamInterceptor(interceptor.stream),
+		grpc.KeepaliveParams(keepalive.ServerParameters{
+			Time:    keepaliveInterval,
+			Timeout: 2 * keepaliveInterval,
+		}),
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             keepaliveInterval / 2,
+			PermitWithoutStream: true,
+		}),
+	)
+
+	healthServer := health.NewServer()
+	gobcvv1.RegisterCurrencyServiceServer(server, currencies)
+	healthpb.RegisterHealthServer(server, healthServer)
+	reflection.Register(server)
+
+	healthServer.SetServingStatus(ServiceName, healthpb.HealthCheckResponse_SERVING)
+
+	return &Server{
+		server: server,
+		health: healthServer,
+	}
+}
+
+// Serve atiende las conexiones de lis hasta que se llama a Shutdown.
+func (s *Server) Serve(lis net.Listener) error {
+	return s.server.Serve(lis)
+}
+
+// WatchReadiness actualiza cada interval el estado de health checking de CurrencyService
+// con la sonda de readiness, hasta que se cancela ctx. El servicio "" (el servidor en
+// general) siempre responde SERVING mientras el proceso atiende peticiones, igual que /health/live.
+func (s *Server) WatchReadiness(ctx context.Context, checker ReadinessChecker, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	serving := true
+	for {
+		report := checker.Readiness(ctx)
+		if report.Ready != serving {
+			serving = report.Ready
+			status := healthpb.HealthCheckResponse_SERVING
+			if !serving {
+				status = healthpb.HealthCheckResponse_NOT_SERVING
+			}
+			slog.InfoContext(ctx, "Estado de health checking gRPC actualizado", "service", ServiceName, "status", status.String())
+			s.health.SetServingStatus(ServiceName, status)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Shutdown marca todos los servicios como NOT_SERVING y espera a que terminen las
+// llamadas en curso. Si ctx vence antes, cierra las conexiones restantes.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.health.Shutdown()
+
+	done := make(chan struct{})
+	go func() {
+		s.server.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		s.server.Stop()
+		<-done
+		return ctx.Err()
+	}
+}
+
+// isHealthMethod indica si el método pertenece al health checking, que es público y
+// se registra en nivel debug.
+func isHealthMethod(fullMethod string) bool {
+	return strings.HasPrefix(fullMethod, "/"+healthpb.Health_ServiceDesc.ServiceName+"/")
+}
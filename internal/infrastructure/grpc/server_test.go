@@ -0,0 +1,179 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	gobcvv1 "gobcv/api/gobcv/v1"
+	"gobcv/internal/application/query"
+	"gobcv/internal/domain/entity"
+	"gobcv/internal/infrastructure/cache"
+	"gobcv/internal/infrastructure/events"
+)
+
+// startTestServer inicia el servidor gRPC en memoria con USD y EUR y retorna una conexión
+// y el broker de cambios de tasas.
+func startTestServer(t *testing.T) (*grpc.ClientConn, *events.Broker) {
+	t.Helper()
+
+	ctx := context.Background()
+	repo := cache.NewMemoryRepository()
+	repo.Save(ctx, entity.NewCurrency("USD", "Dólar", 36.5, "test"))
+	repo.Save(ctx, entity.NewCurrency("EUR", "Euro", 40, "test"))
+	memoryCache := cache.NewMemoryCache()
+	t.Cleanup(func() { memoryCache.Close() })
+
+	history := cache.NewMemoryRateHistory(10)
+	history.Append(ctx, &entity.RateChange{CurrencyID: "USD", Value: 36.5, PreviousValue: 36.2, ChangedAt: time.Now().Add(-time.Hour)})
+
+	broker := events.NewBroker()
+	getCurrency := query.NewGetCurrencyHandler(repo, memoryCache, 0)
+	currencies := NewCurrencyServer(
+		getCurrency,
+		query.NewGetAllCurrenciesHandler(repo, memoryCache, 0),
+		query.NewConvertCurrencyHandler(getCurrency),
+		query.NewGetRateHistoryHandler(history),
+		broker,
+		history,
+		10,
+	)
+
+	server := NewServer(currencies, nil, time.Minute)
+	listener := bufconn.Listen(1 << 20)
+	go server.Serve(listener)
+	t.Cleanup(func() { server.Shutdown(context.Background()) })
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return conn, broker
+}
+
+func TestCurrencyServiceCodecs(t *testing.T) {
+	conn, _ := startTestServer(t)
+	client := gobcvv1.NewCurrencyServiceClient(conn)
+
+	// protobuf es el codec por defecto y JSON se elige con el subtipo de contenido
+	for name, opts := range map[string][]grpc.CallOption{
+		"proto": nil,
+		"json":  {grpc.CallContentSubtype(CodecName)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			converted, err := client.Convert(ctx, &gobcvv1.ConvertRequest{From: "USD", To: "EUR", Amount: 80}, opts...)
+			if err != nil {
+				t.Fatalf("Convert: %v", err)
+			}
+			if converted.GetResult() != 73 || converted.GetTo() != "EUR" || converted.GetRatesAt() == nil {
+				t.Errorf("Convert = %v, want 73 EUR", converted)
+			}
+
+			currency, err := client.GetCurrency(ctx, &gobcvv1.GetCurrencyRequest{CurrencyId: "usd"}, opts...)
+			if err != nil {
+				t.Fatalf("GetCurrency: %v", err)
+			}
+			if currency.GetCurrency().GetId() != "USD" || currency.GetCurrency().GetValue() != 36.5 {
+				t.Errorf("GetCurrency = %v", currency)
+			}
+
+			list, err := client.ListCurrencies(ctx, &gobcvv1.ListCurrenciesRequest{}, opts...)
+			if err != nil || list.GetCount() != 2 || len(list.GetCurrencies()) != 2 {
+				t.Errorf("ListCurrencies = %v, %v, want 2 currencies", list, err)
+			}
+
+			history, err := client.GetHistory(ctx, &gobcvv1.GetHistoryRequest{CurrencyId: "USD"}, opts...)
+			if err != nil || history.GetCount() != 1 || history.GetChanges()[0].GetPreviousValue() != 36.2 {
+				t.Errorf("GetHistory = %v, %v, want one change", history, err)
+			}
+
+			_, err = client.GetCurrency(ctx, &gobcvv1.GetCurrencyRequest{CurrencyId: "GBP"}, opts...)
+			if status.Code(err) != codes.NotFound {
+				t.Errorf("GetCurrency(GBP) code = %v, want NotFound", status.Code(err))
+			}
+		})
+	}
+}
+
+func TestCurrencyServiceWatchRatesSnapshot(t *testing.T) {
+	conn, broker := startTestServer(t)
+	client := gobcvv1.NewCurrencyServiceClient(conn)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream, err := client.WatchRates(ctx, &gobcvv1.WatchRatesRequest{Currencies: []string{"usd"}, Snapshot: true})
+	if err != nil {
+		t.Fatalf("WatchRates: %v", err)
+	}
+
+	snapshot, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("Recv snapshot: %v", err)
+	}
+	if snapshot.GetType() != RateEventSnapshot || len(snapshot.GetCurrencies()) != 1 || snapshot.GetCurrencies()[0].GetId() != "USD" {
+		t.Fatalf("first event = %v, want a USD snapshot", snapshot)
+	}
+
+	// Los cambios publicados después del snapshot llegan filtrados por moneda
+	broker.Publish(ctx, []*entity.RateChange{
+		{Sequence: 2, CurrencyID: "EUR", Value: 41, ChangedAt: time.Now()},
+		{Sequence: 3, CurrencyID: "USD", Value: 36.9, PreviousValue: 36.5, ChangedAt: time.Now()},
+	})
+
+	change, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("Recv change: %v", err)
+	}
+	if change.GetType() != RateEventRate || change.GetChange().GetSequence() != 3 || change.GetChange().GetChangedAt() == nil {
+		t.Errorf("second event = %v, want the USD change of sequence 3", change)
+	}
+}
+
+func TestServerRegistersReflection(t *testing.T) {
+	conn, _ := startTestServer(t)
+
+	stream, err := reflectionpb.NewServerReflectionClient(conn).ServerReflectionInfo(context.Background())
+	if err != nil {
+		t.Fatalf("ServerReflectionInfo: %v", err)
+	}
+	defer stream.CloseSend()
+
+	err = stream.Send(&reflectionpb.ServerReflectionRequest{
+		MessageRequest: &reflectionpb.ServerReflectionRequest_ListServices{},
+	})
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	response, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("Recv: %v", err)
+	}
+
+	services := make(map[string]bool)
+	for _, service := range response.GetListServicesResponse().GetService() {
+		services[service.GetName()] = true
+	}
+	for _, want := range []string{ServiceName, healthpb.Health_ServiceDesc.ServiceName} {
+		if !services[want] {
+			t.Errorf("reflection does not list %s: %v", want, services)
+		}
+	}
+}
@@ -0,0 +1,266 @@
+// Package grpc implementa CurrencyService sobre las consultas de la aplicación.
+package grpc
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	gobcvv1 "gobcv/api/gobcv/v1"
+	"gobcv/internal/application/query"
+	"gobcv/internal/domain/entity"
+	"gobcv/internal/domain/repository"
+	"gobcv/internal/domain/service"
+)
+
+// ServiceName es el nombre completo del servicio gRPC.
+const ServiceName = "gobcv.v1.CurrencyService"
+
+// CurrencyServer implementa CurrencyService con los mismos handlers de consultas que
+// la API REST, y WatchRates con el broker y el historial de cambios.
+type CurrencyServer struct {
+	gobcvv1.UnimplementedCurrencyServiceServer
+
+	getCurrencyHandler *query.GetCurrencyHandler
+	getAllHandler      *query.GetAllCurrenciesHandler
+	convertHandler     *query.ConvertCurrencyHandler
+	historyHandler     *query.GetRateHistoryHandler
+	events             service.RateEventBroker
+	history            repository.RateHistoryRepository
+	buffer             int
+}
+
+// NewCurrencyServer crea la implementación de CurrencyService. buffer es la cantidad
+// de cambios que puede acumular un suscriptor lento de WatchRates antes de desconectarlo.
+func NewCurrencyServer(
+	getCurrencyHandler *query.GetCurrencyHandler,
+	getAllHandler *query.GetAllCurrenciesHandler,
+	convertHandler *query.ConvertCurrencyHandler,
+	historyHandler *query.GetRateHistoryHandler,
+	events service.RateEventBroker,
+	history repository.RateHistoryRepository,
+	buffer int,
+) *CurrencyServer {
+	return &CurrencyServer{
+		getCurrencyHandler: getCurrencyHandler,
+		getAllHandler:      getAllHandler,
+		convertHandler:     convertHandler,
+		historyHandler:     historyHandler,
+		events:             events,
+		history:            history,
+		buffer:             buffer,
+	}
+}
+
+var _ gobcvv1.CurrencyServiceServer = (*CurrencyServer)(nil)
+
+// GetCurrency obtiene una moneda.
+func (s *CurrencyServer) GetCurrency(ctx context.Context, req *gobcvv1.GetCurrencyRequest) (*gobcvv1.GetCurrencyResponse, error) {
+	currencyID := strings.ToUpper(strings.TrimSpace(req.CurrencyId))
+	if len(currencyID) != 3 {
+		return nil, status.Error(codes.InvalidArgument, "currency_id must be a three-letter code")
+	}
+
+	result, err := s.getCurrencyHandler.Handle(ctx, query.GetCurrencyQuery{
+		CurrencyID: currencyID,
+		UseCache:   !req.SkipCache,
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	if !result.Success {
+		return nil, status.Errorf(codes.NotFound, "currency %s not found", currencyID)
+	}
+
+	return &gobcvv1.GetCurrencyResponse{
+		Currency:   currencyMessage(result.Currency),
+		FromCache:  result.FromCache,
+		Stale:      result.Stale,
+		AgeSeconds: int64(result.Age.Seconds()),
+	}, nil
+}
+
+// ListCurrencies obtiene todas las monedas.
+func (s *CurrencyServer) ListCurrencies(ctx context.Context, req *gobcvv1.ListCurrenciesRequest) (*gobcvv1.ListCurrenciesResponse, error) {
+	result, err := s.getAllHandler.Handle(ctx, query.GetAllCurrenciesQuery{
+		UseCache:     !req.SkipCache,
+		IncludeStale: req.IncludeStale,
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &gobcvv1.ListCurrenciesResponse{
+		Currencies: currencyMessages(result.Currencies),
+		Count:      int32(result.Count),
+		FromCache:  result.FromCache,
+		Stale:      result.Stale,
+		AgeSeconds: int64(result.Age.Seconds()),
+	}, nil
+}
+
+// Convert convierte un monto entre dos monedas.
+func (s *CurrencyServer) Convert(ctx context.Context, req *gobcvv1.ConvertRequest) (*gobcvv1.ConvertResponse, error) {
+	to := req.To
+	if to == "" {
+		to = query.BaseCurrency
+	}
+
+	result, err := s.convertHandler.Handle(ctx, query.ConvertCurrencyQuery{
+		From:     req.From,
+		To:       to,
+		Amount:   req.Amount,
+		UseCache: !req.SkipCache,
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	if !result.Success {
+		return nil, status.Error(codes.NotFound, result.Message)
+	}
+
+	return &gobcvv1.ConvertResponse{
+		From:      result.From,
+		To:        result.To,
+		Amount:    result.Amount,
+		Result:    result.Result,
+		Rate:      result.Rate,
+		RatesAt:   timestampMessage(result.RatesAt),
+		FromCache: result.FromCache,
+		Stale:     result.Stale,
+	}, nil
+}
+
+// GetHistory obtiene los cambios de una moneda en un rango de fechas.
+func (s *CurrencyServer) GetHistory(ctx context.Context, req *gobcvv1.GetHistoryRequest) (*gobcvv1.GetHistoryResponse, error) {
+	result, err := s.historyHandler.Handle(ctx, query.GetRateHistoryQuery{
+		CurrencyID: req.CurrencyId,
+		From:       timestampTime(req.From),
+		To:         timestampTime(req.To),
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	changes := make([]*gobcvv1.RateChange, 0, len(result.Changes))
+	for _, change := range result.Changes {
+		changes = append(changes, rateChangeMessage(change))
+	}
+
+	return &gobcvv1.GetHistoryResponse{
+		CurrencyId: result.CurrencyID,
+		From:       timestampMessage(result.From),
+		To:         timestampMessage(result.To),
+		Changes:    changes,
+		Count:      int32(result.Count),
+	}, nil
+}
+
+// WatchRates envía los cambios de tasas a medida que se publican. Si el cliente no
+// los consume a tiempo, o el servidor se apaga, el stream termina con Unavailable y
+// el cliente puede reanudar con la última secuencia recibida.
+func (s *CurrencyServer) WatchRates(req *gobcvv1.WatchRatesRequest, stream grpc.ServerStreamingServer[gobcvv1.RateEvent]) error {
+	ctx := stream.Context()
+
+	var currencyIDs []string
+	for _, id := range req.Currencies {
+		if id = strings.ToUpper(strings.TrimSpace(id)); id != "" {
+			currencyIDs = append(currencyIDs, id)
+		}
+	}
+
+	// Suscribirse antes de leer el historial para no perder cambios intermedios
+	subCtx, unsubscribe := context.WithCancel(ctx)
+	defer unsubscribe()
+	events := s.events.Subscribe(subCtx, currencyIDs, s.buffer)
+
+	lastSequence := req.SinceSequence
+	backlog, reset, err := repository.FindResumeBacklog(ctx, s.history, lastSequence, currencyIDs)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error leyendo historial para reanudar WatchRates", "error", err)
+		return status.Error(codes.Internal, "error reading rate history")
+	}
+	if reset {
+		// Las secuencias se reiniciaron: se descarta el cursor y se envía el estado actual
+		slog.InfoContext(ctx, "since_sequence posterior al historial, reanudando desde un snapshot", "since_sequence", lastSequence)
+		lastSequence = 0
+	}
+
+	if (req.Snapshot || reset) && lastSequence == 0 {
+		if err := s.sendSnapshot(ctx, stream, currencyIDs); err != nil {
+			return err
+		}
+	}
+
+	for _, change := range backlog {
+		if err := stream.Send(&gobcvv1.RateEvent{Type: RateEventRate, Change: rateChangeMessage(change)}); err != nil {
+			return err
+		}
+		lastSequence = change.Sequence
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case change, ok := <-events:
+			if !ok {
+				return status.Errorf(codes.Unavailable, "subscription closed, resume with since_sequence %d", lastSequence)
+			}
+			// Los cambios ya enviados desde el historial llegan también por la suscripción
+			if change.Sequence != 0 && change.Sequence <= lastSequence {
+				continue
+			}
+			if err := stream.Send(&gobcvv1.RateEvent{Type: RateEventRate, Change: rateChangeMessage(change)}); err != nil {
+				return err
+			}
+			if change.Sequence != 0 {
+				lastSequence = change.Sequence
+			}
+		}
+	}
+}
+
+// sendSnapshot envía el valor actual de las monedas solicitadas como primer mensaje.
+func (s *CurrencyServer) sendSnapshot(ctx context.Context, stream grpc.ServerStreamingServer[gobcvv1.RateEvent], currencyIDs []string) error {
+	result, err := s.getAllHandler.Handle(ctx, query.GetAllCurrenciesQuery{UseCache: true})
+	if err != nil {
+		return toStatus(err)
+	}
+
+	currencies := make([]*entity.Currency, 0, len(result.Currencies))
+	for _, currency := range result.Currencies {
+		if len(currencyIDs) == 0 || containsCurrency(currencyIDs, currency.ID) {
+			currencies = append(currencies, currency)
+		}
+	}
+
+	return stream.Send(&gobcvv1.RateEvent{Type: RateEventSnapshot, Currencies: currencyMessages(currencies)})
+}
+
+// containsCurrency verifica si la moneda está en la lista.
+func containsCurrency(currencyIDs []string, id string) bool {
+	for _, candidate := range currencyIDs {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
+
+// toStatus convierte los errores de las consultas en estados gRPC.
+func toStatus(err error) error {
+	switch {
+	case errors.Is(err, query.ErrInvalidQuery):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return status.FromContextError(err).Err()
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
@@ -0,0 +1,183 @@
+// Package http implementa los endpoints de administración de alertas.
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	appService "gobcv/internal/application/service"
+	"gobcv/internal/domain/entity"
+)
+
+// AlertManager administra las reglas de alerta.
+type AlertManager interface {
+	CreateRule(ctx context.Context, input appService.AlertRuleInput) (*entity.AlertRule, error)
+	UpdateRule(ctx context.Context, id string, input appService.AlertRuleInput) (*entity.AlertRule, error)
+	GetRule(ctx context.Context, id string) (*entity.AlertRule, error)
+	ListRules(ctx context.Context) ([]*entity.AlertRule, error)
+	DeleteRule(ctx context.Context, id string) error
+	Alerts(ctx context.Context, limit int) ([]*entity.Alert, error)
+}
+
+// ListAlertRules maneja el endpoint para listar las reglas de alerta.
+func (h *Handlers) ListAlertRules(w http.ResponseWriter, r *http.Request) {
+	rules, err := h.alerts.ListRules(r.Context())
+	if err != nil {
+		writeAlertError(w, err, "Error getting alert rules")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, APIResponse{
+		Success:   true,
+		Message:   "Alert rules retrieved",
+		Data:      rules,
+		Timestamp: time.Now(),
+	})
+}
+
+// CreateAlertRule maneja el endpoint para registrar una regla de alerta.
+func (h *Handlers) CreateAlertRule(w http.ResponseWriter, r *http.Request) {
+	input, ok := decodeAlertRuleInput(w, r)
+	if !ok {
+		return
+	}
+
+	rule, err := h.alerts.CreateRule(r.Context(), input)
+	if err != nil {
+		writeAlertError(w, err, "Error creating alert rule")
+		return
+	}
+
+	w.Header().Set("Location", apiPrefix+"/alerts/rules/"+rule.ID)
+	writeJSON(w, http.StatusCreated, APIResponse{
+		Success:   true,
+		Message:   "Alert rule created",
+		Data:      rule,
+		Timestamp: time.Now(),
+	})
+}
+
+// GetAlertRule maneja el endpoint para obtener una regla de alerta.
+func (h *Handlers) GetAlertRule(w http.ResponseWriter, r *http.Request) {
+	rule, err := h.alerts.GetRule(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		writeAlertError(w, err, "Error getting alert rule")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, APIResponse{
+		Success:   true,
+		Message:   "Alert rule retrieved",
+		Data:      rule,
+		Timestamp: time.Now(),
+	})
+}
+
+// UpdateAlertRule maneja el endpoint para modificar una regla de alerta.
+func (h *Handlers) UpdateAlertRule(w http.ResponseWriter, r *http.Request) {
+	input, ok := decodeAlertRuleInput(w, r)
+	if !ok {
+		return
+	}
+
+	rule, err := h.alerts.UpdateRule(r.Context(), mux.Vars(r)["id"], input)
+	if err != nil {
+		writeAlertError(w, err, "Error updating alert rule")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, APIResponse{
+		Success:   true,
+		Message:   "Alert rule updated",
+		Data:      rule,
+		Timestamp: time.Now(),
+	})
+}
+
+// DeleteAlertRule maneja el endpoint para eliminar una regla de alerta.
+func (h *Handlers) DeleteAlertRule(w http.ResponseWriter, r *http.Request) {
+	if err := h.alerts.DeleteRule(r.Context(), mux.Vars(r)["id"]); err != nil {
+		writeAlertError(w, err, "Error deleting alert rule")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, APIResponse{
+		Success:   true,
+		Message:   "Alert rule deleted",
+		Timestamp: time.Now(),
+	})
+}
+
+// GetAlerts maneja el endpoint con las alertas disparadas más recientes.
+func (h *Handlers) GetAlerts(w http.ResponseWriter, r *http.Request) {
+	limit := 50
+	if value := r.URL.Query().Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			writeJSON(w, http.StatusBadRequest, APIResponse{
+				Success:   false,
+				Message:   "Invalid limit",
+				Error:     "limit must be a positive integer",
+				Timestamp: time.Now(),
+			})
+			return
+		}
+		limit = parsed
+	}
+
+	alerts, err := h.alerts.Alerts(r.Context(), limit)
+	if err != nil {
+		writeAlertError(w, err, "Error getting alerts")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, APIResponse{
+		Success:   true,
+		Message:   "Alerts retrieved",
+		Data:      alerts,
+		Timestamp: time.Now(),
+	})
+}
+
+// decodeAlertRuleInput lee el cuerpo JSON de la petición. Si es inválido responde 400 y retorna false.
+func decodeAlertRuleInput(w http.ResponseWriter, r *http.Request) (appService.AlertRuleInput, bool) {
+	var input appService.AlertRuleInput
+
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxWebhookBody))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&input); err != nil {
+		writeJSON(w, http.StatusBadRequest, APIResponse{
+			Success:   false,
+			Message:   "Invalid request body",
+			Error:     err.Error(),
+			Timestamp: time.Now(),
+		})
+		return input, false
+	}
+
+	return input, true
+}
+
+// writeAlertError responde con el código de estado que corresponde al error.
+func writeAlertError(w http.ResponseWriter, err error, message string) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, appService.ErrAlertRuleNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, appService.ErrInvalidAlertRule):
+		status = http.StatusBadRequest
+	}
+
+	writeJSON(w, status, APIResponse{
+		Success:   false,
+		Message:   message,
+		Error:     err.Error(),
+		Timestamp: time.Now(),
+	})
+}
@@ -0,0 +1,112 @@
+// Package http implementa la autenticación por claves de API.
+package http
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"gobcv/internal/domain/entity"
+	"gobcv/internal/domain/repository"
+)
+
+// Ubicaciones aceptadas para la clave de API.
+const (
+	apiKeyHeader     = "X-API-Key"
+	apiKeyQueryParam = "api_key"
+)
+
+// apiKeyContextKey es la clave del contexto con la clave de API autenticada.
+type apiKeyContextKey struct{}
+
+// APIKeyFromContext retorna la clave de API autenticada en la petición, si existe.
+func APIKeyFromContext(ctx context.Context) (*entity.APIKey, bool) {
+	key, ok := ctx.Value(apiKeyContextKey{}).(*entity.APIKey)
+	return key, ok
+}
+
+// AuthMiddleware autentica las peticiones mediante claves de API y verifica
+// que otorguen el scope requerido por cada ruta.
+type AuthMiddleware struct {
+	keys            repository.APIKeyRepository
+	anonymousScopes []string
+}
+
+// NewAuthMiddleware crea el middleware de autenticación. anonymousScopes son los
+// scopes concedidos a las peticiones sin clave de API.
+func NewAuthMiddleware(keys repository.APIKeyRepository, anonymousScopes []string) *AuthMiddleware {
+	return &AuthMiddleware{
+		keys:            keys,
+		anonymousScopes: anonymousScopes,
+	}
+}
+
+// Wrap exige el scope de la ruta. Las rutas sin scope son públicas.
+func (m *AuthMiddleware) Wrap(route RouteInfo, next http.Handler) http.Handler {
+	if route.Scope == "" {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rawKey := r.Header.Get(apiKeyHeader)
+		if rawKey == "" {
+			rawKey = r.URL.Query().Get(apiKeyQueryParam)
+		}
+
+		if rawKey == "" {
+			if m.anonymousAllowed(route.Scope) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set("WWW-Authenticate", `APIKey header="`+apiKeyHeader+`"`)
+			writeJSON(w, http.StatusUnauthorized, APIResponse{
+				Success:   false,
+				Message:   "API key required",
+				Error:     "missing " + apiKeyHeader + " header or " + apiKeyQueryParam + " query parameter",
+				Timestamp: time.Now(),
+			})
+			return
+		}
+
+		key, err := m.keys.FindByHash(r.Context(), entity.HashAPIKey(rawKey))
+		if err != nil {
+			slog.ErrorContext(r.Context(), "Error buscando clave de API", "error", err)
+			writeJSON(w, http.StatusInternalServerError, APIResponse{
+				Success:   false,
+				Message:   "Error validating API key",
+				Timestamp: time.Now(),
+			})
+			return
+		}
+
+		if key == nil || key.Disabled {
+			w.Header().Set("WWW-Authenticate", `APIKey header="`+apiKeyHeader+`"`)
+			writeJSON(w, http.StatusUnauthorized, APIResponse{
+				Success:   false,
+				Message:   "Invalid API key",
+				Timestamp: time.Now(),
+			})
+			return
+		}
+
+		if !key.HasScope(route.Scope) {
+			writeJSON(w, http.StatusForbidden, APIResponse{
+				Success:   false,
+				Message:   "API key does not grant the required scope",
+				Error:     "required scope: " + route.Scope,
+				Timestamp: time.Now(),
+			})
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), apiKeyContextKey{}, key)))
+	})
+}
+
+// anonymousAllowed verifica si las peticiones sin clave tienen el scope indicado.
+func (m *AuthMiddleware) anonymousAllowed(scope string) bool {
+	anonymous := entity.APIKey{Scopes: m.anonymousScopes}
+	return anonymous.HasScope(scope)
+}
@@ -0,0 +1,129 @@
+// Package http implementa el soporte de peticiones condicionales HTTP.
+package http
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gobcv/internal/domain/entity"
+)
+
+// RefreshSchedule expone el momento de la próxima actualización programada de monedas.
+type RefreshSchedule interface {
+	NextRefreshAt() time.Time
+}
+
+// validators contiene los validadores de una representación para peticiones condicionales.
+type validators struct {
+	etag         string
+	lastModified time.Time
+}
+
+// currencyValidators calcula el ETag a partir del formato de salida, los valores de las
+// monedas y su fecha de actualización, y el Last-Modified a partir de la más reciente.
+// El ETag es débil porque el cuerpo incluye metadatos que cambian sin que cambien las
+// tasas (age_seconds, from_cache, stale).
+func currencyValidators(format string, currencies ...*entity.Currency) validators {
+	lines := make([]string, 0, len(currencies))
+	var lastModified time.Time
+
+	for _, currency := range currencies {
+		lines = append(lines, currency.ID+"="+strconv.FormatFloat(currency.Value, 'f', -1, 64)+
+			"@"+currency.UpdatedAt.UTC().Format(time.RFC3339Nano))
+		if currency.UpdatedAt.After(lastModified) {
+			lastModified = currency.UpdatedAt
+		}
+	}
+
+	// Ordenar para que el ETag no dependa del orden del repositorio
+	sort.Strings(lines)
+	// Cada representación tiene su propio ETag
+	lines = append([]string{format}, lines...)
+	sum := sha256.Sum256([]byte(strings.Join(lines, "\n")))
+
+	return validators{
+		etag:         `W/"` + hex.EncodeToString(sum[:16]) + `"`,
+		lastModified: lastModified,
+	}
+}
+
+// writeConditionalHeaders escribe ETag, Last-Modified y Cache-Control, y evalúa
+// If-None-Match/If-Modified-Since. Retorna true si ya respondió con 304 Not Modified.
+func (h *Handlers) writeConditionalHeaders(w http.ResponseWriter, r *http.Request, v validators) bool {
+	header := w.Header()
+	header.Set("ETag", v.etag)
+	if !v.lastModified.IsZero() {
+		header.Set("Last-Modified", v.lastModified.UTC().Format(http.TimeFormat))
+	}
+	header.Set("Cache-Control", fmt.Sprintf("public, max-age=%d", h.cacheMaxAge()))
+
+	if !isNotModified(r, v) {
+		return false
+	}
+
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
+
+// cacheMaxAge calcula los segundos que faltan para la próxima actualización programada.
+func (h *Handlers) cacheMaxAge() int64 {
+	if h.refreshSchedule == nil {
+		return 0
+	}
+
+	next := h.refreshSchedule.NextRefreshAt()
+	if next.IsZero() {
+		return 0
+	}
+
+	remaining := time.Until(next)
+	if remaining < 0 {
+		return 0
+	}
+
+	return int64(remaining.Seconds())
+}
+
+// isNotModified evalúa las precondiciones de la petición siguiendo RFC 9110:
+// If-None-Match tiene prioridad y, si está presente, If-Modified-Since se ignora.
+func isNotModified(r *http.Request, v validators) bool {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+
+	if ifNoneMatch := r.Header.Get("If-None-Match"); ifNoneMatch != "" {
+		return etagMatches(ifNoneMatch, v.etag)
+	}
+
+	ifModifiedSince := r.Header.Get("If-Modified-Since")
+	if ifModifiedSince == "" || v.lastModified.IsZero() {
+		return false
+	}
+
+	since, err := http.ParseTime(ifModifiedSince)
+	if err != nil {
+		return false
+	}
+
+	// Las fechas HTTP tienen resolución de segundos
+	return !v.lastModified.Truncate(time.Second).After(since)
+}
+
+// etagMatches verifica si alguna de las etiquetas de If-None-Match coincide,
+// usando comparación débil como exige If-None-Match.
+func etagMatches(ifNoneMatch, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,192 @@
+// Package http implementa la política CORS configurable de la API.
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// CORSPolicy define qué orígenes externos pueden consumir la API desde un navegador.
+type CORSPolicy struct {
+	// AllowedOrigins admite comodines: "*" permite cualquier origen y
+	// "https://*.example.com" cualquier subdominio. "*" no admite credenciales.
+	AllowedOrigins []string
+	// AllowedMethods restringe los métodos anunciados; vacío anuncia todos los de la ruta.
+	AllowedMethods []string
+	// AllowedHeaders son las cabeceras que el cliente puede enviar; "*" permite cualquiera.
+	AllowedHeaders   []string
+	ExposedHeaders   []string
+	AllowCredentials bool
+	MaxAge           int // Segundos que el navegador puede cachear el preflight
+}
+
+// CORSMiddleware aplica la política CORS. Solo refleja en Access-Control-Allow-Origin
+// los orígenes permitidos, y responde los preflight con los métodos reales de cada ruta.
+type CORSMiddleware struct {
+	policy CORSPolicy
+}
+
+// NewCORSMiddleware crea el middleware CORS con la política indicada.
+func NewCORSMiddleware(policy CORSPolicy) *CORSMiddleware {
+	return &CORSMiddleware{policy: policy}
+}
+
+// Wrap agrega las cabeceras CORS a las respuestas de la ruta. Debe registrarse
+// primero para que también las respuestas 401 y 429 sean legibles por el navegador.
+func (m *CORSMiddleware) Wrap(route RouteInfo, next http.Handler) http.Handler {
+	methods := m.allowedMethods(route.Methods)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		header := w.Header()
+		header.Add("Vary", "Origin")
+
+		if !m.policy.AllowsOrigin(origin) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if route.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			header.Add("Vary", "Access-Control-Request-Method")
+			header.Add("Vary", "Access-Control-Request-Headers")
+			m.preflight(w, r, methods)
+			return
+		}
+
+		m.writeOrigin(header, origin)
+		if len(m.policy.ExposedHeaders) > 0 {
+			header.Set("Access-Control-Expose-Headers", strings.Join(m.policy.ExposedHeaders, ", "))
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// preflight responde una petición preflight. Si el método o las cabeceras solicitadas
+// no están permitidos se omiten las cabeceras CORS y el navegador bloquea la petición.
+func (m *CORSMiddleware) preflight(w http.ResponseWriter, r *http.Request, methods []string) {
+	header := w.Header()
+	header.Set("Allow", strings.Join(append([]string{http.MethodOptions}, methods...), ", "))
+
+	requestedMethod := r.Header.Get("Access-Control-Request-Method")
+	requestedHeaders := parseHeaderList(r.Header.Get("Access-Control-Request-Headers"))
+
+	if !containsFold(methods, requestedMethod) || !m.headersAllowed(requestedHeaders) {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	m.writeOrigin(header, r.Header.Get("Origin"))
+	header.Set("Access-Control-Allow-Methods", strings.Join(methods, ", "))
+	if len(requestedHeaders) > 0 {
+		header.Set("Access-Control-Allow-Headers", strings.Join(requestedHeaders, ", "))
+	}
+	if m.policy.MaxAge > 0 {
+		header.Set("Access-Control-Max-Age", strconv.Itoa(m.policy.MaxAge))
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeOrigin refleja el origen permitido y, si corresponde, habilita las credenciales.
+// Con el origen "*" y sin credenciales se envía el comodín literal en lugar del origen.
+func (m *CORSMiddleware) writeOrigin(header http.Header, origin string) {
+	if !m.policy.AllowCredentials && containsFold(m.policy.AllowedOrigins, "*") {
+		header.Set("Access-Control-Allow-Origin", "*")
+		return
+	}
+
+	header.Set("Access-Control-Allow-Origin", origin)
+	if m.policy.AllowCredentials {
+		header.Set("Access-Control-Allow-Credentials", "true")
+	}
+}
+
+// allowedMethods retorna los métodos de la ruta permitidos por la política.
+func (m *CORSMiddleware) allowedMethods(routeMethods []string) []string {
+	if len(m.policy.AllowedMethods) == 0 {
+		return routeMethods
+	}
+
+	var methods []string
+	for _, method := range routeMethods {
+		if containsFold(m.policy.AllowedMethods, method) {
+			methods = append(methods, method)
+		}
+	}
+	return methods
+}
+
+// AllowsOrigin verifica si el origen coincide con alguno de los patrones permitidos.
+func (p CORSPolicy) AllowsOrigin(origin string) bool {
+	for _, pattern := range p.AllowedOrigins {
+		if matchWildcard(strings.ToLower(pattern), strings.ToLower(origin)) {
+			return true
+		}
+	}
+	return false
+}
+
+// headersAllowed verifica que todas las cabeceras solicitadas estén permitidas.
+func (m *CORSMiddleware) headersAllowed(requested []string) bool {
+	if containsFold(m.policy.AllowedHeaders, "*") {
+		return true
+	}
+	for _, name := range requested {
+		if !containsFold(m.policy.AllowedHeaders, name) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchWildcard compara un valor con un patrón donde "*" representa cualquier secuencia.
+func matchWildcard(pattern, value string) bool {
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return pattern == value
+	}
+
+	if !strings.HasPrefix(value, parts[0]) {
+		return false
+	}
+	value = value[len(parts[0]):]
+
+	last := parts[len(parts)-1]
+	for _, part := range parts[1 : len(parts)-1] {
+		index := strings.Index(value, part)
+		if index < 0 {
+			return false
+		}
+		value = value[index+len(part):]
+	}
+
+	return len(value) >= len(last) && strings.HasSuffix(value, last)
+}
+
+// parseHeaderList separa una lista de cabeceras separadas por coma.
+func parseHeaderList(value string) []string {
+	var names []string
+	for _, name := range strings.Split(value, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// containsFold verifica si la lista contiene el valor sin distinguir mayúsculas.
+func containsFold(values []string, value string) bool {
+	for _, candidate := range values {
+		if strings.EqualFold(candidate, value) {
+			return true
+		}
+	}
+	return false
+}
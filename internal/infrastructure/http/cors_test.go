@@ -0,0 +1,159 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+// newCORSRouter crea el router completo con la política CORS indicada como único middleware.
+func newCORSRouter(policy CORSPolicy) *mux.Router {
+	return SetupRouter(newTestHandlers(), testRouterOptions(), NewCORSMiddleware(policy))
+}
+
+// corsRequest envía una petición con el origen y las cabeceras indicadas.
+func corsRequest(router http.Handler, method, path, origin string, headers map[string]string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(method, path, nil)
+	if origin != "" {
+		request.Header.Set("Origin", origin)
+	}
+	for name, value := range headers {
+		request.Header.Set(name, value)
+	}
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestCORSAllowedOrigins(t *testing.T) {
+	router := newCORSRouter(CORSPolicy{
+		AllowedOrigins:   []string{"https://app.example.com", "https://*.example.org"},
+		ExposedHeaders:   []string{"ETag"},
+		AllowCredentials: true,
+	})
+
+	tests := []struct {
+		origin  string
+		allowed bool
+	}{
+		{"https://app.example.com", true},
+		{"HTTPS://APP.EXAMPLE.COM", true},
+		{"https://a.example.org", true},
+		{"https://a.b.example.org", true},
+		{"https://example.org", false},
+		{"https://evil.com", false},
+		{"https://app.example.com.evil.com", false},
+		{"http://app.example.com", false},
+	}
+	for _, tt := range tests {
+		recorder := corsRequest(router, http.MethodGet, "/api/v1/openapi.json", tt.origin, nil)
+
+		allowOrigin := recorder.Header().Get("Access-Control-Allow-Origin")
+		if tt.allowed {
+			if allowOrigin != tt.origin {
+				t.Errorf("%s: Allow-Origin = %q, want the origin", tt.origin, allowOrigin)
+			}
+			if recorder.Header().Get("Access-Control-Allow-Credentials") != "true" {
+				t.Errorf("%s: missing Allow-Credentials", tt.origin)
+			}
+			if recorder.Header().Get("Access-Control-Expose-Headers") != "ETag" {
+				t.Errorf("%s: Expose-Headers = %q", tt.origin, recorder.Header().Get("Access-Control-Expose-Headers"))
+			}
+		} else if allowOrigin != "" || recorder.Header().Get("Access-Control-Allow-Credentials") != "" {
+			t.Errorf("%s: denied origin got CORS headers %v", tt.origin, recorder.Header())
+		}
+
+		// La respuesta depende del origen en ambos casos
+		if vary := recorder.Header().Values("Vary"); !containsFold(vary, "Origin") {
+			t.Errorf("%s: Vary = %v, want Origin", tt.origin, vary)
+		}
+	}
+
+	recorder := corsRequest(router, http.MethodGet, "/api/v1/openapi.json", "", nil)
+	if recorder.Header().Get("Access-Control-Allow-Origin") != "" || len(recorder.Header().Values("Vary")) != 0 {
+		t.Errorf("request without Origin got CORS headers %v", recorder.Header())
+	}
+}
+
+func TestCORSWildcardOriginWithoutCredentials(t *testing.T) {
+	router := newCORSRouter(CORSPolicy{AllowedOrigins: []string{"*"}})
+
+	recorder := corsRequest(router, http.MethodGet, "/api/v1/openapi.json", "https://any.example", nil)
+	if got := recorder.Header().Get("Access-Control-Allow-Origin"); got != "*" {
+		t.Errorf("Allow-Origin = %q, want *", got)
+	}
+	if got := recorder.Header().Get("Access-Control-Allow-Credentials"); got != "" {
+		t.Errorf("Allow-Credentials = %q, want none with *", got)
+	}
+}
+
+func TestCORSPreflight(t *testing.T) {
+	router := newCORSRouter(CORSPolicy{
+		AllowedOrigins: []string{"https://app.example.com"},
+		AllowedHeaders: []string{"Content-Type", "X-API-Key"},
+		MaxAge:         600,
+	})
+
+	recorder := corsRequest(router, http.MethodOptions, "/api/v1/webhooks", "https://app.example.com", map[string]string{
+		"Access-Control-Request-Method":  "POST",
+		"Access-Control-Request-Headers": "content-type, x-api-key",
+	})
+	if recorder.Code != http.StatusNoContent {
+		t.Fatalf("status = %d, want 204", recorder.Code)
+	}
+	header := recorder.Header()
+	if got := header.Get("Access-Control-Allow-Origin"); got != "https://app.example.com" {
+		t.Errorf("Allow-Origin = %q", got)
+	}
+	if got := header.Get("Access-Control-Allow-Methods"); got != "GET, POST" {
+		t.Errorf("Allow-Methods = %q, want the route methods", got)
+	}
+	if got := header.Get("Access-Control-Allow-Headers"); got != "content-type, x-api-key" {
+		t.Errorf("Allow-Headers = %q", got)
+	}
+	if got := header.Get("Access-Control-Max-Age"); got != "600" {
+		t.Errorf("Max-Age = %q, want 600", got)
+	}
+	vary := strings.Join(header.Values("Vary"), ", ")
+	for _, name := range []string{"Origin", "Access-Control-Request-Method", "Access-Control-Request-Headers"} {
+		if !strings.Contains(vary, name) {
+			t.Errorf("Vary = %q, want %s", vary, name)
+		}
+	}
+
+	// Métodos y cabeceras no permitidos dejan el preflight sin cabeceras CORS
+	for name, headers := range map[string]map[string]string{
+		"method": {"Access-Control-Request-Method": "DELETE"},
+		"header": {"Access-Control-Request-Method": "POST", "Access-Control-Request-Headers": "X-Other"},
+	} {
+		recorder := corsRequest(router, http.MethodOptions, "/api/v1/webhooks", "https://app.example.com", headers)
+		if recorder.Code != http.StatusNoContent {
+			t.Errorf("denied %s: status = %d, want 204", name, recorder.Code)
+		}
+		if got := recorder.Header().Get("Access-Control-Allow-Origin"); got != "" {
+			t.Errorf("denied %s: Allow-Origin = %q, want none", name, got)
+		}
+	}
+}
+
+func TestCORSCoversRootAndMetrics(t *testing.T) {
+	router := newCORSRouter(CORSPolicy{AllowedOrigins: []string{"https://app.example.com"}})
+
+	for _, path := range []string{"/", "/metrics"} {
+		recorder := corsRequest(router, http.MethodGet, path, "https://app.example.com", nil)
+		if got := recorder.Header().Get("Access-Control-Allow-Origin"); got != "https://app.example.com" {
+			t.Errorf("GET %s: Allow-Origin = %q, want the origin", path, got)
+		}
+
+		recorder = corsRequest(router, http.MethodOptions, path, "https://app.example.com", map[string]string{
+			"Access-Control-Request-Method": "GET",
+		})
+		if got := recorder.Header().Get("Access-Control-Allow-Methods"); got != "GET" {
+			t.Errorf("preflight %s: Allow-Methods = %q, want GET", path, got)
+		}
+	}
+}
@@ -0,0 +1,317 @@
+// Package http implementa la negociación de contenido de la API REST.
+package http
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gobcv/internal/application/query"
+	"gobcv/internal/domain/entity"
+)
+
+// Formatos de salida soportados por la API.
+const (
+	FormatJSON = "json"
+	FormatCSV  = "csv"
+	FormatXML  = "xml"
+	FormatText = "text"
+)
+
+// ErrNotAcceptable indica que ninguno de los formatos solicitados está soportado.
+var ErrNotAcceptable = errors.New("none of the requested formats is supported")
+
+// Encoder serializa respuestas de la API en un formato concreto.
+type Encoder interface {
+	// ContentType retorna el tipo de contenido de las respuestas codificadas.
+	ContentType() string
+
+	// Encode escribe la respuesta en el writer.
+	Encode(w io.Writer, response APIResponse) error
+}
+
+// EncoderRegistry asocia formatos y tipos de medio con sus encoders.
+type EncoderRegistry struct {
+	encoders      map[string]Encoder
+	mediaTypes    map[string]string
+	order         []string
+	defaultFormat string
+}
+
+// NewEncoderRegistry crea un registro con los formatos JSON, CSV, XML y texto plano.
+// JSON es el formato por defecto.
+func NewEncoderRegistry() *EncoderRegistry {
+	registry := &EncoderRegistry{
+		encoders:      make(map[string]Encoder),
+		mediaTypes:    make(map[string]string),
+		defaultFormat: FormatJSON,
+	}
+
+	registry.Register(FormatJSON, jsonEncoder{}, "application/json")
+	registry.Register(FormatCSV, csvEncoder{}, "text/csv")
+	registry.Register(FormatXML, xmlEncoder{}, "application/xml", "text/xml")
+	registry.Register(FormatText, textEncoder{}, "text/plain")
+
+	return registry
+}
+
+// Register agrega un encoder para un formato y los tipos de medio que lo identifican.
+func (r *EncoderRegistry) Register(format string, encoder Encoder, mediaTypes ...string) {
+	if _, exists := r.encoders[format]; !exists {
+		r.order = append(r.order, format)
+	}
+
+	r.encoders[format] = encoder
+	for _, mediaType := range mediaTypes {
+		r.mediaTypes[mediaType] = format
+	}
+}
+
+// Default retorna el formato y encoder por defecto.
+func (r *EncoderRegistry) Default() (string, Encoder) {
+	return r.defaultFormat, r.encoders[r.defaultFormat]
+}
+
+// Negotiate elige el formato de respuesta. El parámetro format tiene prioridad
+// sobre la cabecera Accept, que se evalúa según sus valores de calidad.
+func (r *EncoderRegistry) Negotiate(req *http.Request) (string, Encoder, error) {
+	if format := req.URL.Query().Get("format"); format != "" {
+		if encoder, ok := r.encoders[format]; ok {
+			return format, encoder, nil
+		}
+		return "", nil, fmt.Errorf("%w: format %q", ErrNotAcceptable, format)
+	}
+
+	accept := req.Header.Get("Accept")
+	if strings.TrimSpace(accept) == "" {
+		format, encoder := r.Default()
+		return format, encoder, nil
+	}
+
+	for _, mediaRange := range parseAccept(accept) {
+		if format, ok := r.match(mediaRange); ok {
+			return format, r.encoders[format], nil
+		}
+	}
+
+	return "", nil, fmt.Errorf("%w: %s", ErrNotAcceptable, accept)
+}
+
+// match busca el formato que corresponde a un rango de tipos de medio.
+func (r *EncoderRegistry) match(mediaRange string) (string, bool) {
+	if mediaRange == "*/*" {
+		return r.defaultFormat, true
+	}
+
+	if format, ok := r.mediaTypes[mediaRange]; ok {
+		return format, true
+	}
+
+	// Rangos del tipo "text/*" eligen el primer formato registrado con ese tipo
+	if prefix, found := strings.CutSuffix(mediaRange, "/*"); found {
+		for _, format := range r.order {
+			if strings.HasPrefix(r.encoders[format].ContentType(), prefix+"/") {
+				return format, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// acceptEntry representa un rango de la cabecera Accept con su calidad.
+type acceptEntry struct {
+	mediaRange string
+	quality    float64
+}
+
+// parseAccept retorna los rangos de la cabecera Accept ordenados por calidad descendente,
+// descartando los que tienen calidad cero.
+func parseAccept(accept string) []string {
+	var entries []acceptEntry
+
+	for _, part := range strings.Split(accept, ",") {
+		mediaRange, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		quality := 1.0
+		if q, ok := params["q"]; ok {
+			if parsed, err := strconv.ParseFloat(q, 64); err == nil {
+				quality = parsed
+			}
+		}
+
+		if quality > 0 {
+			entries = append(entries, acceptEntry{mediaRange: mediaRange, quality: quality})
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].quality > entries[j].quality
+	})
+
+	mediaRanges := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		mediaRanges = append(mediaRanges, entry.mediaRange)
+	}
+
+	return mediaRanges
+}
+
+// currencyPayload es implementado por los datos de respuesta que contienen monedas.
+type currencyPayload interface {
+	currencyList() []*entity.Currency
+}
+
+// currencyData representa los datos de respuesta de una moneda individual.
+type currencyData struct {
+	Currency   *entity.Currency `json:"currency" xml:"currency"`
+	FromCache  bool             `json:"from_cache" xml:"from_cache"`
+	Stale      bool             `json:"stale" xml:"stale"`
+	AgeSeconds int64            `json:"age_seconds" xml:"age_seconds"`
+}
+
+func (d *currencyData) currencyList() []*entity.Currency {
+	return []*entity.Currency{d.Currency}
+}
+
+// currenciesData representa los datos de respuesta de un listado de monedas.
+type currenciesData struct {
+	Currencies []*entity.Currency `json:"currencies" xml:"currencies>currency"`
+	Count      int                `json:"count" xml:"count"`
+	FromCache  bool               `json:"from_cache" xml:"from_cache"`
+	Stale      bool               `json:"stale" xml:"stale"`
+	AgeSeconds int64              `json:"age_seconds" xml:"age_seconds"`
+}
+
+func (d *currenciesData) currencyList() []*entity.Currency {
+	return d.Currencies
+}
+
+// jsonEncoder codifica las respuestas como JSON.
+type jsonEncoder struct{}
+
+func (jsonEncoder) ContentType() string { return "application/json" }
+
+func (jsonEncoder) Encode(w io.Writer, response APIResponse) error {
+	return json.NewEncoder(w).Encode(response)
+}
+
+// xmlEncoder codifica las respuestas como XML.
+type xmlEncoder struct{}
+
+func (xmlEncoder) ContentType() string { return "application/xml; charset=utf-8" }
+
+func (xmlEncoder) Encode(w io.Writer, response APIResponse) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	return xml.NewEncoder(w).Encode(response)
+}
+
+// csvEncoder codifica las monedas de la respuesta como filas CSV.
+type csvEncoder struct{}
+
+func (csvEncoder) ContentType() string { return "text/csv; charset=utf-8" }
+
+func (csvEncoder) Encode(w io.Writer, response APIResponse) error {
+	writer := csv.NewWriter(w)
+
+	switch data := response.Data.(type) {
+	case currencyPayload:
+		writer.Write([]string{"id", "name", "value", "updated_at", "source"})
+		for _, currency := range data.currencyList() {
+			writer.Write([]string{
+				currency.ID,
+				currency.Name,
+				formatFloat(currency.Value),
+				currency.UpdatedAt.UTC().Format(time.RFC3339),
+				currency.Source,
+			})
+		}
+	case *query.ConvertCurrencyResult:
+		writer.Write([]string{"from", "to", "amount", "result", "rate", "rates_at"})
+		writer.Write([]string{
+			data.From,
+			data.To,
+			formatFloat(data.Amount),
+			formatFloat(data.Result),
+			formatFloat(data.Rate),
+			data.RatesAt.UTC().Format(time.RFC3339),
+		})
+	case *query.GetRateHistoryResult:
+		writer.Write([]string{"sequence", "currency_id", "name", "value", "previous_value", "source", "changed_at"})
+		for _, change := range data.Changes {
+			writer.Write([]string{
+				strconv.FormatUint(change.Sequence, 10),
+				change.CurrencyID,
+				change.Name,
+				formatFloat(change.Value),
+				formatFloat(change.PreviousValue),
+				change.Source,
+				change.ChangedAt.UTC().Format(time.RFC3339),
+			})
+		}
+	default:
+		writer.Write([]string{"success", "message", "error"})
+		writer.Write([]string{strconv.FormatBool(response.Success), response.Message, response.Error})
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// textEncoder codifica las respuestas como texto plano para scripts de shell:
+// el valor de una moneda individual o el resultado de una conversión, una línea
+// "ID VALOR" por moneda, o una línea "FECHA VALOR" por cambio del historial.
+type textEncoder struct{}
+
+func (textEncoder) ContentType() string { return "text/plain; charset=utf-8" }
+
+func (textEncoder) Encode(w io.Writer, response APIResponse) error {
+	switch data := response.Data.(type) {
+	case *currencyData:
+		_, err := fmt.Fprintln(w, formatFloat(data.Currency.Value))
+		return err
+	case currencyPayload:
+		for _, currency := range data.currencyList() {
+			if _, err := fmt.Fprintf(w, "%s %s\n", currency.ID, formatFloat(currency.Value)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case *query.ConvertCurrencyResult:
+		_, err := fmt.Fprintln(w, formatFloat(data.Result))
+		return err
+	case *query.GetRateHistoryResult:
+		for _, change := range data.Changes {
+			if _, err := fmt.Fprintf(w, "%s %s\n", change.ChangedAt.UTC().Format(time.RFC3339), formatFloat(change.Value)); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		message := response.Message
+		if response.Error != "" {
+			message = fmt.Sprintf("%s: %s", message, response.Error)
+		}
+		_, err := fmt.Fprintln(w, message)
+		return err
+	}
+}
+
+// formatFloat formatea un valor con la precisión mínima que lo representa.
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
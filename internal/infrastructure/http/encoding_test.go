@@ -0,0 +1,263 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"gobcv/internal/application/query"
+	"gobcv/internal/domain/entity"
+	"gobcv/internal/infrastructure/cache"
+)
+
+// newEncodingRouter crea un router con las consultas de monedas, la conversión y el
+// historial sobre datos en memoria.
+func newEncodingRouter(t *testing.T) http.Handler {
+	t.Helper()
+
+	ctx := context.Background()
+	repo := cache.NewMemoryRepository()
+	repo.Save(ctx, entity.NewCurrency("USD", "Dólar", 36.5, "test"))
+	repo.Save(ctx, entity.NewCurrency("EUR", "Euro", 40, "test"))
+	memoryCache := cache.NewMemoryCache()
+	t.Cleanup(func() { memoryCache.Close() })
+
+	history := cache.NewMemoryRateHistory(10)
+	changedAt := time.Date(2024, 6, 10, 14, 30, 0, 0, time.UTC)
+	history.Append(ctx, &entity.RateChange{CurrencyID: "USD", Name: "Dólar", Value: 36.5, PreviousValue: 36.2, Source: "test", ChangedAt: changedAt})
+	history.Append(ctx, &entity.RateChange{CurrencyID: "USD", Name: "Dólar", Value: 36.8, PreviousValue: 36.5, Source: "test", ChangedAt: changedAt.Add(24 * time.Hour)})
+
+	getCurrency := query.NewGetCurrencyHandler(repo, memoryCache, 0)
+	getAll := query.NewGetAllCurrenciesHandler(repo, memoryCache, 0)
+	handlers := NewHandlers(nil, getCurrency, getAll, query.NewConvertCurrencyHandler(getCurrency),
+		query.NewGetRateHistoryHandler(history), nil, nil, nil, nil, nil, nil, nil, nil)
+
+	return SetupRouter(handlers, testRouterOptions())
+}
+
+// negotiationCase es una petición y la respuesta negociada que se espera.
+type negotiationCase struct {
+	name        string
+	target      string
+	accept      string
+	status      int
+	contentType string
+	body        []string
+}
+
+// runNegotiationCases envía cada petición al router y verifica estado, tipo y cuerpo.
+func runNegotiationCases(t *testing.T, router http.Handler, tests []negotiationCase) {
+	t.Helper()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			if tt.accept != "" {
+				request.Header.Set("Accept", tt.accept)
+			}
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, request)
+
+			if recorder.Code != tt.status {
+				t.Fatalf("status = %d, want %d: %s", recorder.Code, tt.status, recorder.Body)
+			}
+			if contentType := recorder.Header().Get("Content-Type"); !strings.HasPrefix(contentType, tt.contentType) {
+				t.Errorf("Content-Type = %q, want %s", contentType, tt.contentType)
+			}
+			for _, want := range tt.body {
+				if !strings.Contains(recorder.Body.String(), want) {
+					t.Errorf("body is missing %q:\n%s", want, recorder.Body)
+				}
+			}
+		})
+	}
+}
+
+func TestCurrenciesNegotiateFormat(t *testing.T) {
+	runNegotiationCases(t, newEncodingRouter(t), []negotiationCase{
+		{
+			name:        "list default json",
+			target:      "/api/v1/currencies",
+			status:      http.StatusOK,
+			contentType: "application/json",
+			body:        []string{`"currencies":[`, `"count":2`},
+		},
+		{
+			name:        "list csv",
+			target:      "/api/v1/currencies",
+			accept:      "text/csv",
+			status:      http.StatusOK,
+			contentType: "text/csv",
+			body:        []string{"id,name,value,updated_at,source\n", "\nUSD,Dólar,36.5,", "\nEUR,Euro,40,"},
+		},
+		{
+			name:        "list highest quality",
+			target:      "/api/v1/currencies",
+			accept:      "application/xml;q=0.5, text/plain;q=0.9, application/json;q=0.1",
+			status:      http.StatusOK,
+			contentType: "text/plain",
+			body:        []string{"USD 36.5\n", "EUR 40\n"},
+		},
+		{
+			name:        "list text wildcard",
+			target:      "/api/v1/currencies",
+			accept:      "text/*",
+			status:      http.StatusOK,
+			contentType: "text/csv",
+			body:        []string{"id,name,value,updated_at,source\n"},
+		},
+		{
+			name:        "list xml",
+			target:      "/api/v1/currencies",
+			accept:      "text/xml",
+			status:      http.StatusOK,
+			contentType: "application/xml",
+			body:        []string{"<?xml", "<response><success>true</success>", "<currencies><currency>", "<count>2</count>"},
+		},
+		{
+			name:        "list zero quality skipped",
+			target:      "/api/v1/currencies",
+			accept:      "text/csv;q=0, */*;q=0.2",
+			status:      http.StatusOK,
+			contentType: "application/json",
+		},
+		{
+			name:        "list unsupported accept",
+			target:      "/api/v1/currencies",
+			accept:      "image/png",
+			status:      http.StatusNotAcceptable,
+			contentType: "application/json",
+			body:        []string{`"success":false`},
+		},
+		{
+			name:        "single text",
+			target:      "/api/v1/currencies/USD",
+			accept:      "text/plain",
+			status:      http.StatusOK,
+			contentType: "text/plain",
+			body:        []string{"36.5\n"},
+		},
+		{
+			name:        "single format overrides accept",
+			target:      "/api/v1/currencies/USD?format=csv",
+			accept:      "text/plain",
+			status:      http.StatusOK,
+			contentType: "text/csv",
+			body:        []string{"id,name,value,updated_at,source\nUSD,Dólar,36.5,"},
+		},
+		{
+			name:        "single xml",
+			target:      "/api/v1/currencies/USD?format=xml",
+			accept:      "application/json",
+			status:      http.StatusOK,
+			contentType: "application/xml",
+			body:        []string{"<response>", "<data><currency>", "<id>USD</id>"},
+		},
+		{
+			name:        "single unsupported accept",
+			target:      "/api/v1/currencies/USD",
+			accept:      "application/pdf",
+			status:      http.StatusNotAcceptable,
+			contentType: "application/json",
+		},
+	})
+}
+
+func TestParseAcceptOrdersByQuality(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   []string
+	}{
+		{accept: "text/csv", want: []string{"text/csv"}},
+		{accept: "text/plain;q=0.2, application/xml;q=0.8, text/csv", want: []string{"text/csv", "application/xml", "text/plain"}},
+		{accept: "text/csv;q=0, application/json", want: []string{"application/json"}},
+		{accept: "application/xml, text/plain", want: []string{"application/xml", "text/plain"}},
+		{accept: "not a media type;;, text/plain;q=x", want: []string{"text/plain"}},
+	}
+
+	for _, tt := range tests {
+		got := parseAccept(tt.accept)
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("parseAccept(%q) = %v, want %v", tt.accept, got, tt.want)
+		}
+	}
+}
+
+func TestConvertAndHistoryNegotiateFormat(t *testing.T) {
+	router := newEncodingRouter(t)
+
+	runNegotiationCases(t, router, []negotiationCase{
+		{
+			name:        "convert csv",
+			target:      "/api/v1/currencies/convert?from=USD&amount=2&format=csv",
+			status:      http.StatusOK,
+			contentType: "text/csv",
+			body:        []string{"from,to,amount,result,rate,rates_at\n", "USD,VES,2,73,36.5,"},
+		},
+		{
+			name:        "convert text",
+			target:      "/api/v1/currencies/convert?from=USD&amount=2",
+			accept:      "text/plain",
+			status:      http.StatusOK,
+			contentType: "text/plain",
+			body:        []string{"73\n"},
+		},
+		{
+			name:        "convert xml",
+			target:      "/api/v1/currencies/convert?from=USD&amount=2",
+			accept:      "application/xml",
+			status:      http.StatusOK,
+			contentType: "application/xml",
+			body:        []string{"<from>USD</from>", "<result>73</result>"},
+		},
+		{
+			name:        "convert error as text",
+			target:      "/api/v1/currencies/convert?from=USD&amount=x&format=text",
+			status:      http.StatusBadRequest,
+			contentType: "text/plain",
+			body:        []string{"Invalid amount: amount must be a number\n"},
+		},
+		{
+			name:        "convert unsupported format",
+			target:      "/api/v1/currencies/convert?from=USD&format=yaml",
+			status:      http.StatusNotAcceptable,
+			contentType: "application/json",
+		},
+		{
+			name:        "history csv",
+			target:      "/api/v1/currencies/USD/history?format=csv",
+			status:      http.StatusOK,
+			contentType: "text/csv",
+			body: []string{
+				"sequence,currency_id,name,value,previous_value,source,changed_at\n",
+				"1,USD,Dólar,36.5,36.2,test,2024-06-10T14:30:00Z\n",
+				"2,USD,Dólar,36.8,36.5,test,2024-06-11T14:30:00Z\n",
+			},
+		},
+		{
+			name:        "history text",
+			target:      "/api/v1/currencies/USD/history",
+			accept:      "text/plain",
+			status:      http.StatusOK,
+			contentType: "text/plain",
+			body:        []string{"2024-06-10T14:30:00Z 36.5\n2024-06-11T14:30:00Z 36.8\n"},
+		},
+		{
+			name:        "history xml",
+			target:      "/api/v1/currencies/USD/history?format=xml",
+			status:      http.StatusOK,
+			contentType: "application/xml",
+			body:        []string{"<changes><change><sequence>1</sequence>", "<count>2</count>"},
+		},
+		{
+			name:        "history error as csv",
+			target:      "/api/v1/currencies/USD/history?from=yesterday&format=csv",
+			status:      http.StatusBadRequest,
+			contentType: "text/csv",
+			body:        []string{"success,message,error\n", "false,Invalid history range,"},
+		},
+	})
+}
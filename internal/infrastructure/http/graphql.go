@@ -0,0 +1,499 @@
+// Package http implementa el endpoint GraphQL por HTTP y WebSocket.
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	gql "gobcv/internal/infrastructure/graphql"
+)
+
+// graphqlProtocol es el subprotocolo WebSocket de las suscripciones GraphQL.
+const graphqlProtocol = "graphql-transport-ws"
+
+// Tipos de mensaje del protocolo graphql-transport-ws.
+const (
+	graphqlConnectionInit = "connection_init"
+	graphqlConnectionAck  = "connection_ack"
+	graphqlPing           = "ping"
+	graphqlPong           = "pong"
+	graphqlSubscribe      = "subscribe"
+	graphqlNext           = "next"
+	graphqlError          = "error"
+	graphqlComplete       = "complete"
+)
+
+// Códigos de cierre del protocolo graphql-transport-ws.
+const (
+	graphqlCloseInvalidMessage     = 4400
+	graphqlCloseUnauthorized       = 4401
+	graphqlCloseBadProtocol        = 4406
+	graphqlCloseInitTimeout        = 4408
+	graphqlCloseDuplicateID        = 4409
+	graphqlCloseTooManyInitRequest = 4429
+)
+
+const (
+	// maxGraphQLBody limita el tamaño de las operaciones recibidas por HTTP.
+	maxGraphQLBody = 1 << 20
+	// graphqlInitTimeout es el tiempo que tiene el cliente para enviar connection_init.
+	graphqlInitTimeout = 10 * time.Second
+	// graphqlSendBuffer es la cantidad de mensajes pendientes por conexión; cuando se
+	// llena las operaciones esperan y el broker descarta las suscripciones lentas.
+	graphqlSendBuffer = 16
+)
+
+// GraphQLExecutor ejecuta las operaciones GraphQL.
+type GraphQLExecutor interface {
+	// Execute ejecuta una consulta.
+	Execute(ctx context.Context, req gql.Request) *gql.Response
+	// Subscribe ejecuta una operación y retorna el canal con sus resultados, o nil y
+	// la respuesta con los errores si no es válida.
+	Subscribe(ctx context.Context, req gql.Request) (<-chan *gql.Response, *gql.Response)
+}
+
+// graphqlMessage es un mensaje del protocolo graphql-transport-ws.
+type graphqlMessage struct {
+	ID      string          `json:"id,omitempty"`
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// GraphQLEndpoint atiende las operaciones GraphQL y mantiene el registro de conexiones
+// WebSocket abiertas para cerrarlas durante el apagado del servidor.
+type GraphQLEndpoint struct {
+	executor GraphQLExecutor
+	upgrader websocket.Upgrader
+	ping     time.Duration
+	conns    map[*graphqlConn]struct{}
+	closing  bool
+	wg       sync.WaitGroup
+	mutex    sync.Mutex
+}
+
+// NewGraphQLEndpoint crea el endpoint GraphQL. Solo se aceptan conexiones WebSocket de
+// navegadores de los orígenes de la política CORS; ping es el intervalo de los pings
+// de control.
+func NewGraphQLEndpoint(executor GraphQLExecutor, origins CORSPolicy, ping time.Duration) *GraphQLEndpoint {
+	return &GraphQLEndpoint{
+		executor: executor,
+		upgrader: websocket.Upgrader{
+			Subprotocols: []string{graphqlProtocol},
+			CheckOrigin: func(r *http.Request) bool {
+				origin := r.Header.Get("Origin")
+				return origin == "" || origins.AllowsOrigin(origin)
+			},
+		},
+		ping:  ping,
+		conns: make(map[*graphqlConn]struct{}),
+	}
+}
+
+// Shutdown rechaza nuevas conexiones WebSocket, envía un cierre 1001 (going away) a las
+// abiertas y espera a que terminen o a que se cancele el contexto.
+func (e *GraphQLEndpoint) Shutdown(ctx context.Context) error {
+	e.mutex.Lock()
+	e.closing = true
+	for conn := range e.conns {
+		conn.close(websocket.CloseGoingAway, "server shutting down")
+	}
+	e.mutex.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		e.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// register agrega una conexión al registro salvo que el servidor se esté apagando.
+func (e *GraphQLEndpoint) register(conn *graphqlConn) bool {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if e.closing {
+		return false
+	}
+	e.conns[conn] = struct{}{}
+	e.wg.Add(1)
+	return true
+}
+
+// unregister elimina una conexión del registro.
+func (e *GraphQLEndpoint) unregister(conn *graphqlConn) {
+	e.mutex.Lock()
+	delete(e.conns, conn)
+	e.mutex.Unlock()
+	e.wg.Done()
+}
+
+// GraphQL maneja el endpoint GraphQL: consultas por GET (parámetros query,
+// operationName y variables) y POST (application/json o application/graphql), y
+// suscripciones por WebSocket con el protocolo graphql-transport-ws.
+func (h *Handlers) GraphQL(w http.ResponseWriter, r *http.Request) {
+	if websocket.IsWebSocketUpgrade(r) {
+		h.subscribeGraphQL(w, r)
+		return
+	}
+
+	req, err := decodeGraphQLRequest(w, r)
+	if err != nil {
+		writeGraphQL(w, http.StatusBadRequest, gql.NewErrorResponse(err))
+		return
+	}
+
+	response := h.graphql.executor.Execute(r.Context(), req)
+	writeGraphQL(w, http.StatusOK, response)
+}
+
+// decodeGraphQLRequest obtiene la operación de la petición HTTP.
+func decodeGraphQLRequest(w http.ResponseWriter, r *http.Request) (gql.Request, error) {
+	var req gql.Request
+
+	if r.Method == http.MethodGet {
+		params := r.URL.Query()
+		req.Query = params.Get("query")
+		req.OperationName = params.Get("operationName")
+		if variables := params.Get("variables"); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &req.Variables); err != nil {
+				return req, errors.New("variables must be a JSON object")
+			}
+		}
+	} else {
+		body := http.MaxBytesReader(w, r.Body, maxGraphQLBody)
+		mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+
+		switch mediaType {
+		case "application/graphql":
+			query, err := io.ReadAll(body)
+			if err != nil {
+				return req, errors.New("failed to read request body")
+			}
+			req.Query = string(query)
+		case "application/json", "":
+			if err := json.NewDecoder(body).Decode(&req); err != nil {
+				return req, errors.New("request body must be a JSON object with query, operationName and variables")
+			}
+		default:
+			return req, errors.New("content type must be application/json or application/graphql")
+		}
+	}
+
+	if req.Query == "" {
+		return req, errors.New("query is required")
+	}
+	return req, nil
+}
+
+// writeGraphQL escribe una respuesta GraphQL.
+func writeGraphQL(w http.ResponseWriter, status int, response *gql.Response) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(response)
+}
+
+// subscribeGraphQL actualiza la conexión a WebSocket y atiende el protocolo
+// graphql-transport-ws.
+func (h *Handlers) subscribeGraphQL(w http.ResponseWriter, r *http.Request) {
+	endpoint := h.graphql
+
+	endpoint.mutex.Lock()
+	closing := endpoint.closing
+	endpoint.mutex.Unlock()
+	if closing {
+		writeJSON(w, http.StatusServiceUnavailable, APIResponse{
+			Success:   false,
+			Message:   "Server is shutting down",
+			Timestamp: time.Now(),
+		})
+		return
+	}
+
+	ws, err := endpoint.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// El upgrader ya respondió al cliente con el error
+		slog.DebugContext(r.Context(), "Error aceptando conexión GraphQL WebSocket", "error", err)
+		return
+	}
+
+	conn := &graphqlConn{
+		ws:         ws,
+		endpoint:   endpoint,
+		send:       make(chan graphqlMessage, graphqlSendBuffer),
+		done:       make(chan struct{}),
+		operations: make(map[string]*graphqlOperation),
+	}
+	if !endpoint.register(conn) {
+		conn.close(websocket.CloseGoingAway, "server shutting down")
+		conn.writePump()
+		return
+	}
+	defer endpoint.unregister(conn)
+
+	if ws.Subprotocol() != graphqlProtocol {
+		conn.close(graphqlCloseBadProtocol, "Subprotocol not acceptable")
+		conn.writePump()
+		return
+	}
+
+	slog.InfoContext(r.Context(), "Conexión GraphQL WebSocket abierta", "remote_addr", r.RemoteAddr)
+	conn.serve(r.Context())
+	slog.InfoContext(r.Context(), "Conexión GraphQL WebSocket cerrada", "remote_addr", r.RemoteAddr, "reason", conn.closeReason)
+}
+
+// graphqlOperation es una operación en curso en una conexión WebSocket.
+type graphqlOperation struct {
+	cancel context.CancelFunc
+}
+
+// graphqlConn es una conexión WebSocket con el protocolo graphql-transport-ws. El
+// lector procesa los mensajes del cliente, cada operación se ejecuta en su propia
+// goroutine y un escritor es el único que escribe en el socket.
+type graphqlConn struct {
+	ws       *websocket.Conn
+	endpoint *GraphQLEndpoint
+	send     chan graphqlMessage
+	done     chan struct{}
+
+	closeOnce   sync.Once
+	closeCode   int
+	closeReason string
+
+	mutex        sync.Mutex
+	acknowledged bool
+	operations   map[string]*graphqlOperation
+	running      sync.WaitGroup
+}
+
+// serve atiende la conexión hasta que el cliente o el servidor la cierran.
+func (c *graphqlConn) serve(ctx context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	writerDone := make(chan struct{})
+	go func() {
+		c.writePump()
+		close(writerDone)
+	}()
+
+	initTimer := time.AfterFunc(graphqlInitTimeout, func() {
+		c.mutex.Lock()
+		acknowledged := c.acknowledged
+		c.mutex.Unlock()
+		if !acknowledged {
+			c.close(graphqlCloseInitTimeout, "Connection initialisation timeout")
+		}
+	})
+	defer initTimer.Stop()
+
+	c.readPump(ctx)
+
+	// Cancelar las operaciones en curso y esperar a que terminen
+	cancel()
+	c.running.Wait()
+	<-writerDone
+}
+
+// readPump lee y procesa los mensajes del cliente.
+func (c *graphqlConn) readPump(ctx context.Context) {
+	c.ws.SetReadLimit(maxGraphQLBody)
+	pongWait := 2 * c.endpoint.ping
+	c.ws.SetReadDeadline(time.Now().Add(pongWait))
+	c.ws.SetPongHandler(func(string) error {
+		return c.ws.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		_, data, err := c.ws.ReadMessage()
+		if err != nil {
+			c.close(websocket.CloseNormalClosure, "connection closed")
+			return
+		}
+
+		// Cualquier mensaje del cliente también indica que sigue vivo
+		c.ws.SetReadDeadline(time.Now().Add(pongWait))
+
+		var message graphqlMessage
+		if err := json.Unmarshal(data, &message); err != nil {
+			c.close(graphqlCloseInvalidMessage, "Invalid message received")
+			return
+		}
+		if !c.handleMessage(ctx, message) {
+			return
+		}
+	}
+}
+
+// handleMessage procesa un mensaje del cliente. Retorna false si la conexión debe cerrarse.
+func (c *graphqlConn) handleMessage(ctx context.Context, message graphqlMessage) bool {
+	switch message.Type {
+	case graphqlConnectionInit:
+		c.mutex.Lock()
+		duplicated := c.acknowledged
+		c.acknowledged = true
+		c.mutex.Unlock()
+
+		if duplicated {
+			c.close(graphqlCloseTooManyInitRequest, "Too many initialisation requests")
+			return false
+		}
+		c.enqueue(graphqlMessage{Type: graphqlConnectionAck})
+	case graphqlPing:
+		c.enqueue(graphqlMessage{Type: graphqlPong, Payload: message.Payload})
+	case graphqlPong:
+	case graphqlSubscribe:
+		return c.startOperation(ctx, message)
+	case graphqlComplete:
+		c.mutex.Lock()
+		if operation, ok := c.operations[message.ID]; ok {
+			operation.cancel()
+			delete(c.operations, message.ID)
+		}
+		c.mutex.Unlock()
+	default:
+		c.close(graphqlCloseInvalidMessage, "Invalid message received")
+		return false
+	}
+	return true
+}
+
+// startOperation inicia la operación de un mensaje subscribe. Retorna false si la
+// conexión debe cerrarse.
+func (c *graphqlConn) startOperation(ctx context.Context, message graphqlMessage) bool {
+	var req gql.Request
+	if message.ID == "" || json.Unmarshal(message.Payload, &req) != nil || req.Query == "" {
+		c.close(graphqlCloseInvalidMessage, "Invalid message received")
+		return false
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	operation := &graphqlOperation{cancel: cancel}
+
+	c.mutex.Lock()
+	if !c.acknowledged {
+		c.mutex.Unlock()
+		cancel()
+		c.close(graphqlCloseUnauthorized, "Unauthorized")
+		return false
+	}
+	if _, exists := c.operations[message.ID]; exists {
+		c.mutex.Unlock()
+		cancel()
+		c.close(graphqlCloseDuplicateID, "Subscriber for "+message.ID+" already exists")
+		return false
+	}
+	c.operations[message.ID] = operation
+	c.mutex.Unlock()
+
+	c.running.Add(1)
+	go func() {
+		defer c.running.Done()
+		defer cancel()
+		c.runOperation(ctx, message.ID, operation, req)
+	}()
+	return true
+}
+
+// runOperation envía los resultados de una operación y la completa. Si el cliente la
+// completó antes no se envía complete.
+func (c *graphqlConn) runOperation(ctx context.Context, id string, operation *graphqlOperation, req gql.Request) {
+	results, errResponse := c.endpoint.executor.Subscribe(ctx, req)
+	if errResponse != nil {
+		c.finishOperation(id, operation)
+		c.enqueue(graphqlMessage{ID: id, Type: graphqlError, Payload: marshalPayload(errResponse.Errors)})
+		return
+	}
+
+	for result := range results {
+		if ctx.Err() == nil {
+			c.enqueue(graphqlMessage{ID: id, Type: graphqlNext, Payload: marshalPayload(result)})
+		}
+	}
+
+	if c.finishOperation(id, operation) {
+		c.enqueue(graphqlMessage{ID: id, Type: graphqlComplete})
+	}
+}
+
+// finishOperation libera el identificador de la operación. Retorna false si el cliente
+// ya la había completado.
+func (c *graphqlConn) finishOperation(id string, operation *graphqlOperation) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.operations[id] != operation {
+		return false
+	}
+	delete(c.operations, id)
+	return true
+}
+
+// enqueue encola un mensaje, esperando si el buffer está lleno, salvo que la conexión
+// se esté cerrando.
+func (c *graphqlConn) enqueue(message graphqlMessage) {
+	select {
+	case <-c.done:
+	case c.send <- message:
+	}
+}
+
+// close marca la conexión para cierre con el código indicado; el escritor envía el
+// frame de cierre y libera el socket.
+func (c *graphqlConn) close(code int, reason string) {
+	c.closeOnce.Do(func() {
+		c.closeCode = code
+		c.closeReason = reason
+		close(c.done)
+	})
+}
+
+// writePump es el único que escribe en el socket: mensajes, pings y el frame de cierre.
+func (c *graphqlConn) writePump() {
+	ping := time.NewTicker(c.endpoint.ping)
+	defer func() {
+		ping.Stop()
+		c.ws.Close()
+	}()
+
+	for {
+		select {
+		case message := <-c.send:
+			c.ws.SetWriteDeadline(time.Now().Add(socketWriteWait))
+			if err := c.ws.WriteJSON(message); err != nil {
+				c.close(websocket.CloseAbnormalClosure, "write error")
+				return
+			}
+		case <-ping.C:
+			if err := c.ws.WriteControl(websocket.PingMessage, nil, time.Now().Add(socketWriteWait)); err != nil {
+				c.close(websocket.CloseAbnormalClosure, "ping error")
+				return
+			}
+		case <-c.done:
+			frame := websocket.FormatCloseMessage(c.closeCode, c.closeReason)
+			c.ws.WriteControl(websocket.CloseMessage, frame, time.Now().Add(socketWriteWait))
+			return
+		}
+	}
+}
+
+// marshalPayload codifica el payload de un mensaje del servidor.
+func marshalPayload(payload interface{}) json.RawMessage {
+	data, _ := json.Marshal(payload)
+	return data
+}
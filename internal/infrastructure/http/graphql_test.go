@@ -0,0 +1,261 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"gobcv/internal/application/query"
+	"gobcv/internal/domain/entity"
+	"gobcv/internal/infrastructure/cache"
+	"gobcv/internal/infrastructure/events"
+	gql "gobcv/internal/infrastructure/graphql"
+)
+
+// newGraphQLRouter monta el endpoint GraphQL sobre los handlers de consultas reales con
+// USD y EUR en un repositorio en memoria, y retorna el router y el broker de cambios.
+func newGraphQLRouter(t *testing.T) (http.Handler, *events.Broker) {
+	t.Helper()
+	ctx := context.Background()
+
+	repo := cache.NewMemoryRepository()
+	repo.Save(ctx, entity.NewCurrency("USD", "Dólar", 36.5, "test"))
+	repo.Save(ctx, entity.NewCurrency("EUR", "Euro", 40, "test"))
+	memoryCache := cache.NewMemoryCache()
+	t.Cleanup(func() { memoryCache.Close() })
+
+	history := cache.NewMemoryRateHistory(10)
+	broker := events.NewBroker()
+	getCurrency := query.NewGetCurrencyHandler(repo, memoryCache, 0)
+	getAll := query.NewGetAllCurrenciesHandler(repo, memoryCache, 0)
+
+	executor, err := gql.NewExecutor(getCurrency, getAll, query.NewConvertCurrencyHandler(getCurrency),
+		query.NewGetRateHistoryHandler(history), broker, history, 8, gql.Limits{MaxDepth: 8, MaxComplexity: 1000})
+	if err != nil {
+		t.Fatalf("NewExecutor: %v", err)
+	}
+
+	endpoint := NewGraphQLEndpoint(executor, CORSPolicy{}, time.Minute)
+	handlers := NewHandlers(nil, getCurrency, getAll, nil, nil, nil, nil, nil, nil, nil, endpoint, nil, nil)
+
+	return SetupRouter(handlers, testRouterOptions()), broker
+}
+
+// graphqlResult es el cuerpo de una respuesta GraphQL tal como la recibe un cliente.
+type graphqlResult struct {
+	Data   map[string]json.RawMessage `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+func TestGraphQLQueries(t *testing.T) {
+	router, _ := newGraphQLRouter(t)
+
+	variables := url.QueryEscape(`{"id":"EUR"}`)
+	getQuery := url.QueryEscape(`query One($id: String!) { currency(id: $id) { id name } }`)
+
+	tests := []struct {
+		name        string
+		method      string
+		target      string
+		contentType string
+		body        string
+		want        string
+	}{
+		{
+			name:        "post json",
+			method:      http.MethodPost,
+			target:      "/graphql",
+			contentType: "application/json",
+			body:        `{"query":"{ currencies(ids: [\"USD\"]) { id value } }"}`,
+			want:        `"currencies":[{"id":"USD","value":36.5}]`,
+		},
+		{
+			name:        "post graphql",
+			method:      http.MethodPost,
+			target:      "/api/v1/graphql",
+			contentType: "application/graphql",
+			body:        `{ convert(from: "EUR", to: "USD", amount: 73) { from to result } }`,
+			want:        `"convert":{"from":"EUR","result":80,"to":"USD"}`,
+		},
+		{
+			name:   "get with variables",
+			method: http.MethodGet,
+			target: "/graphql?query=" + getQuery + "&operationName=One&variables=" + variables,
+			want:   `"currency":{"id":"EUR","name":"Euro"}`,
+		},
+		{
+			name:   "unknown currency is null",
+			method: http.MethodGet,
+			target: "/graphql?query=" + url.QueryEscape(`{ currency(id: "XYZ") { id } }`),
+			want:   `"currency":null`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				request.Header.Set("Content-Type", tt.contentType)
+			}
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, request)
+
+			if recorder.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200: %s", recorder.Code, recorder.Body)
+			}
+			if contentType := recorder.Header().Get("Content-Type"); contentType != "application/json" {
+				t.Errorf("Content-Type = %q", contentType)
+			}
+			if body := recorder.Body.String(); !strings.Contains(body, tt.want) || strings.Contains(body, `"errors"`) {
+				t.Errorf("body = %s, want %s", body, tt.want)
+			}
+		})
+	}
+}
+
+func TestGraphQLErrorEnvelopes(t *testing.T) {
+	router, _ := newGraphQLRouter(t)
+
+	tests := []struct {
+		name        string
+		method      string
+		target      string
+		contentType string
+		body        string
+		status      int
+		error       string
+		data        bool
+	}{
+		{"missing query", http.MethodGet, "/graphql", "", "", http.StatusBadRequest, "query is required", false},
+		{"invalid variables", http.MethodGet, "/graphql?query=%7B__typename%7D&variables=%5B1%5D", "", "", http.StatusBadRequest, "variables must be a JSON object", false},
+		{"invalid json body", http.MethodPost, "/graphql", "application/json", `{"query":`, http.StatusBadRequest, "request body must be a JSON object", false},
+		{"unsupported content type", http.MethodPost, "/graphql", "text/plain", "{ currencies { id } }", http.StatusBadRequest, "content type must be", false},
+		{"syntax error", http.MethodPost, "/graphql", "application/graphql", "{ currencies { id }", http.StatusOK, "Syntax Error", false},
+		{"unknown field", http.MethodPost, "/graphql", "application/graphql", "{ rates { id } }", http.StatusOK, `Cannot query field "rates"`, false},
+		{"subscription over http", http.MethodPost, "/graphql", "application/graphql", "subscription { rateChanged { value } }", http.StatusOK, "subscriptions require a WebSocket", false},
+		{"resolver error keeps data", http.MethodPost, "/graphql", "application/graphql", `{ currencies { id } convert(from: "XYZ") { result } }`, http.StatusOK, "XYZ", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				request.Header.Set("Content-Type", tt.contentType)
+			}
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, request)
+
+			if recorder.Code != tt.status {
+				t.Errorf("status = %d, want %d", recorder.Code, tt.status)
+			}
+
+			var result graphqlResult
+			if err := json.Unmarshal(recorder.Body.Bytes(), &result); err != nil {
+				t.Fatalf("decoding %s: %v", recorder.Body, err)
+			}
+			if len(result.Errors) == 0 || !strings.Contains(result.Errors[0].Message, tt.error) {
+				t.Errorf("errors = %+v, want %q", result.Errors, tt.error)
+			}
+			if (result.Data != nil) != tt.data {
+				t.Errorf("data = %v, want present %v", result.Data, tt.data)
+			}
+		})
+	}
+}
+
+// graphqlSend envía un mensaje del protocolo graphql-transport-ws.
+func graphqlSend(t *testing.T, conn *websocket.Conn, message graphqlMessage) {
+	t.Helper()
+
+	if err := conn.WriteJSON(message); err != nil {
+		t.Fatalf("WriteJSON(%+v): %v", message, err)
+	}
+}
+
+// graphqlReceive lee el siguiente mensaje y verifica su tipo y operación.
+func graphqlReceive(t *testing.T, conn *websocket.Conn, messageType, id string) graphqlMessage {
+	t.Helper()
+
+	var message graphqlMessage
+	if err := conn.ReadJSON(&message); err != nil {
+		t.Fatalf("waiting for %s: %v", messageType, err)
+	}
+	if message.Type != messageType || message.ID != id {
+		t.Fatalf("message = %s %q %s, want %s %q", message.Type, message.ID, message.Payload, messageType, id)
+	}
+	return message
+}
+
+// waitSubscribers espera a que el broker tenga la cantidad de suscriptores indicada.
+func waitSubscribers(t *testing.T, broker *events.Broker, want int) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for broker.Subscribers() != want {
+		if time.Now().After(deadline) {
+			t.Fatalf("Subscribers = %d, want %d", broker.Subscribers(), want)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestGraphQLSubscription(t *testing.T) {
+	router, broker := newGraphQLRouter(t)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	dialer := websocket.Dialer{Subprotocols: []string{graphqlProtocol}}
+	conn, _, err := dialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/graphql", nil)
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	if conn.Subprotocol() != graphqlProtocol {
+		t.Fatalf("Subprotocol = %q, want %s", conn.Subprotocol(), graphqlProtocol)
+	}
+
+	graphqlSend(t, conn, graphqlMessage{Type: graphqlConnectionInit})
+	graphqlReceive(t, conn, graphqlConnectionAck, "")
+
+	subscription := marshalPayload(gql.Request{
+		Query: `subscription { rateChanged(currencies: ["USD"]) { sequence currencyId value previousValue } }`,
+	})
+
+	// El cliente completa la primera operación: el servidor libera la suscripción sin responder
+	graphqlSend(t, conn, graphqlMessage{ID: "1", Type: graphqlSubscribe, Payload: subscription})
+	waitSubscribers(t, broker, 1)
+	broker.Publish(context.Background(), []*entity.RateChange{
+		{Sequence: 1, CurrencyID: "EUR", Value: 41, ChangedAt: time.Now()},
+		{Sequence: 2, CurrencyID: "USD", Value: 36.6, PreviousValue: 36.5, ChangedAt: time.Now()},
+	})
+
+	next := graphqlReceive(t, conn, graphqlNext, "1")
+	want := `{"data":{"rateChanged":{"currencyId":"USD","previousValue":36.5,"sequence":2,"value":36.6}}}`
+	if string(next.Payload) != want {
+		t.Errorf("next payload = %s, want %s", next.Payload, want)
+	}
+
+	graphqlSend(t, conn, graphqlMessage{ID: "1", Type: graphqlComplete})
+	waitSubscribers(t, broker, 0)
+
+	// El servidor completa la segunda operación cuando el broker cierra la suscripción
+	graphqlSend(t, conn, graphqlMessage{ID: "2", Type: graphqlSubscribe, Payload: subscription})
+	waitSubscribers(t, broker, 1)
+	broker.Close()
+	graphqlReceive(t, conn, graphqlComplete, "2")
+
+	// Una operación inválida recibe error y no complete
+	graphqlSend(t, conn, graphqlMessage{ID: "3", Type: graphqlSubscribe, Payload: marshalPayload(gql.Request{Query: "subscription { nope }"})})
+	graphqlReceive(t, conn, graphqlError, "3")
+}
@@ -2,55 +2,170 @@
 package http
 
 import (
+	"bufio"
+	"context"
 	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gorilla/mux"
 
 	"gobcv/internal/application/command"
 	"gobcv/internal/application/query"
+	appService "gobcv/internal/application/service"
+	"gobcv/internal/domain/service"
 )
 
+// CacheStatsProvider expone las estadísticas del caché de la aplicación.
+type CacheStatsProvider interface {
+	GetCacheStats(ctx context.Context) (service.CacheStats, error)
+}
+
+// HealthChecker expone el estado de las dependencias de la aplicación.
+type HealthChecker interface {
+	Uptime() time.Duration
+	Readiness(ctx context.Context) appService.HealthReport
+}
+
 // Handlers contiene todos los handlers HTTP de la aplicación.
 type Handlers struct {
 	refreshHandler     *command.RefreshCurrenciesHandler
 	getCurrencyHandler *query.GetCurrencyHandler
 	getAllHandler      *query.GetAllCurrenciesHandler
+	convertHandler     *query.ConvertCurrencyHandler
+	historyHandler     *query.GetRateHistoryHandler
+	refreshSchedule    RefreshSchedule
+	cacheStats         CacheStatsProvider
+	health             HealthChecker
+	stream             *RateStream
+	socket             *RateSocket
+	graphql            *GraphQLEndpoint
+	webhooks           WebhookManager
+	alerts             AlertManager
+	encoders           *EncoderRegistry
+	openAPI            *OpenAPIDocument
 }
 
 // NewHandlers crea una nueva instancia de handlers.
+// refreshSchedule se usa para calcular el max-age de las respuestas cacheables.
 func NewHandlers(
 	refreshHandler *command.RefreshCurrenciesHandler,
 	getCurrencyHandler *query.GetCurrencyHandler,
 	getAllHandler *query.GetAllCurrenciesHandler,
+	convertHandler *query.ConvertCurrencyHandler,
+	historyHandler *query.GetRateHistoryHandler,
+	refreshSchedule RefreshSchedule,
+	cacheStats CacheStatsProvider,
+	health HealthChecker,
+	stream *RateStream,
+	socket *RateSocket,
+	graphql *GraphQLEndpoint,
+	webhooks WebhookManager,
+	alerts AlertManager,
 ) *Handlers {
 	return &Handlers{
 		refreshHandler:     refreshHandler,
 		getCurrencyHandler: getCurrencyHandler,
 		getAllHandler:      getAllHandler,
+		convertHandler:     convertHandler,
+		historyHandler:     historyHandler,
+		refreshSchedule:    refreshSchedule,
+		cacheStats:         cacheStats,
+		health:             health,
+		stream:             stream,
+		socket:             socket,
+		graphql:            graphql,
+		webhooks:           webhooks,
+		alerts:             alerts,
+		encoders:           NewEncoderRegistry(),
 	}
 }
 
 // APIResponse representa la estructura estándar de respuesta de la API.
 type APIResponse struct {
-	Success   bool        `json:"success"`
-	Message   string      `json:"message"`
-	Data      interface{} `json:"data,omitempty"`
-	Error     string      `json:"error,omitempty"`
-	Timestamp time.Time   `json:"timestamp"`
+	XMLName   xml.Name    `json:"-" xml:"response"`
+	Success   bool        `json:"success" xml:"success"`
+	Message   string      `json:"message" xml:"message"`
+	Data      interface{} `json:"data,omitempty" xml:"data,omitempty"`
+	Error     string      `json:"error,omitempty" xml:"error,omitempty"`
+	Timestamp time.Time   `json:"timestamp" xml:"timestamp"`
+}
+
+// writeJSON escribe una respuesta JSON con el código de estado indicado.
+func writeJSON(w http.ResponseWriter, status int, response APIResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(response)
 }
 
-// HealthCheck maneja el endpoint de verificación de salud.
-func (h *Handlers) HealthCheck(w http.ResponseWriter, r *http.Request) {
+// negotiate elige el encoder de la petición. Si ningún formato es aceptable
+// responde 406 en JSON y retorna false.
+func (h *Handlers) negotiate(w http.ResponseWriter, r *http.Request) (string, Encoder, bool) {
+	w.Header().Add("Vary", "Accept")
+
+	format, encoder, err := h.encoders.Negotiate(r)
+	if err != nil {
+		_, defaultEncoder := h.encoders.Default()
+		h.writeResponse(w, http.StatusNotAcceptable, APIResponse{
+			Success:   false,
+			Message:   "Requested format is not supported",
+			Error:     err.Error(),
+			Timestamp: time.Now(),
+		}, defaultEncoder)
+		return "", nil, false
+	}
+
+	return format, encoder, true
+}
+
+// writeResponse escribe la respuesta con el encoder y código de estado indicados.
+func (h *Handlers) writeResponse(w http.ResponseWriter, status int, response APIResponse, encoder Encoder) {
+	w.Header().Set("Content-Type", encoder.ContentType())
+	w.WriteHeader(status)
+	encoder.Encode(w, response)
+}
+
+// Liveness maneja la sonda de liveness: solo indica que el proceso atiende peticiones,
+// sin verificar dependencias, para que un fallo externo no provoque reinicios.
+func (h *Handlers) Liveness(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Message: "API is running",
+		Data: map[string]interface{}{
+			"uptime_seconds": int64(h.health.Uptime().Seconds()),
+		},
+		Timestamp: time.Now(),
+	})
+}
+
+// Readiness maneja la sonda de readiness con el estado de cada componente.
+// Responde 503 si algún componente crítico no está disponible.
+func (h *Handlers) Readiness(w http.ResponseWriter, r *http.Request) {
+	report := h.health.Readiness(r.Context())
+
 	response := APIResponse{
-		Success:   true,
-		Message:   "API is running",
+		Success:   report.Ready,
+		Message:   "API is ready",
+		Data:      report,
 		Timestamp: time.Now(),
 	}
+	status := http.StatusOK
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
+	if !report.Ready {
+		response.Message = "API is not ready"
+		response.Error = "one or more critical components are down"
+		status = http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, status, response)
 }
 
 // RefreshCurrencies maneja el endpoint para refrescar monedas.
@@ -87,6 +202,11 @@ func (h *Handlers) RefreshCurrencies(w http.ResponseWriter, r *http.Request) {
 
 // GetCurrency maneja el endpoint para obtener una moneda específica.
 func (h *Handlers) GetCurrency(w http.ResponseWriter, r *http.Request) {
+	format, encoder, ok := h.negotiate(w, r)
+	if !ok {
+		return
+	}
+
 	vars := mux.Vars(r)
 	currencyID := vars["id"]
 
@@ -102,31 +222,42 @@ func (h *Handlers) GetCurrency(w http.ResponseWriter, r *http.Request) {
 	response := APIResponse{
 		Timestamp: time.Now(),
 	}
+	status := http.StatusOK
 
 	if err != nil {
 		response.Success = false
 		response.Error = err.Error()
 		response.Message = "Error getting currency"
-		w.WriteHeader(http.StatusInternalServerError)
+		status = http.StatusInternalServerError
 	} else if !result.Success {
 		response.Success = false
 		response.Message = result.Message
-		w.WriteHeader(http.StatusNotFound)
+		status = http.StatusNotFound
 	} else {
+		if h.writeConditionalHeaders(w, r, currencyValidators(format, result.Currency)) {
+			return
+		}
+
 		response.Success = true
 		response.Message = result.Message
-		response.Data = map[string]interface{}{
-			"currency":   result.Currency,
-			"from_cache": result.FromCache,
+		response.Data = &currencyData{
+			Currency:   result.Currency,
+			FromCache:  result.FromCache,
+			Stale:      result.Stale,
+			AgeSeconds: int64(result.Age.Seconds()),
 		}
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
+	h.writeResponse(w, status, response, encoder)
 }
 
 // GetAllCurrencies maneja el endpoint para obtener todas las monedas.
 func (h *Handlers) GetAllCurrencies(w http.ResponseWriter, r *http.Request) {
+	format, encoder, ok := h.negotiate(w, r)
+	if !ok {
+		return
+	}
+
 	useCache := r.URL.Query().Get("cache") != "false" // Por defecto usa caché
 	includeStale := r.URL.Query().Get("include_stale") == "true"
 
@@ -140,54 +271,185 @@ func (h *Handlers) GetAllCurrencies(w http.ResponseWriter, r *http.Request) {
 	response := APIResponse{
 		Timestamp: time.Now(),
 	}
+	status := http.StatusOK
 
 	if err != nil {
 		response.Success = false
 		response.Error = err.Error()
 		response.Message = "Error getting currencies"
-		w.WriteHeader(http.StatusInternalServerError)
+		status = http.StatusInternalServerError
 	} else {
+		if h.writeConditionalHeaders(w, r, currencyValidators(format, result.Currencies...)) {
+			return
+		}
+
 		response.Success = true
 		response.Message = result.Message
-		response.Data = map[string]interface{}{
-			"currencies": result.Currencies,
-			"count":      result.Count,
-			"from_cache": result.FromCache,
+		response.Data = &currenciesData{
+			Currencies: result.Currencies,
+			Count:      result.Count,
+			FromCache:  result.FromCache,
+			Stale:      result.Stale,
+			AgeSeconds: int64(result.Age.Seconds()),
 		}
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
+	h.writeResponse(w, status, response, encoder)
 }
 
-// GetCacheStats maneja el endpoint para obtener estadísticas del caché.
-func (h *Handlers) GetCacheStats(w http.ResponseWriter, r *http.Request) {
-	// Este endpoint requiere acceso directo al servicio de caché
-	// Por simplicidad, lo implementaremos más adelante en el servicio de aplicación
-	response := APIResponse{
+// ConvertCurrency maneja el endpoint para convertir un monto entre dos monedas.
+func (h *Handlers) ConvertCurrency(w http.ResponseWriter, r *http.Request) {
+	_, encoder, ok := h.negotiate(w, r)
+	if !ok {
+		return
+	}
+
+	params := r.URL.Query()
+
+	amount := 1.0
+	if value := params.Get("amount"); value != "" {
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			h.writeResponse(w, http.StatusBadRequest, APIResponse{
+				Success:   false,
+				Message:   "Invalid amount",
+				Error:     "amount must be a number",
+				Timestamp: time.Now(),
+			}, encoder)
+			return
+		}
+		amount = parsed
+	}
+
+	to := params.Get("to")
+	if to == "" {
+		to = query.BaseCurrency
+	}
+
+	result, err := h.convertHandler.Handle(r.Context(), query.ConvertCurrencyQuery{
+		From:     params.Get("from"),
+		To:       to,
+		Amount:   amount,
+		UseCache: params.Get("cache") != "false",
+	})
+	if err != nil {
+		h.writeQueryError(w, encoder, err, "Error converting currency")
+		return
+	}
+
+	if !result.Success {
+		h.writeResponse(w, http.StatusNotFound, APIResponse{
+			Success:   false,
+			Message:   result.Message,
+			Timestamp: time.Now(),
+		}, encoder)
+		return
+	}
+
+	h.writeResponse(w, http.StatusOK, APIResponse{
 		Success:   true,
-		Message:   "Cache stats endpoint - to be implemented",
+		Message:   result.Message,
+		Data:      result,
 		Timestamp: time.Now(),
+	}, encoder)
+}
+
+// GetCurrencyHistory maneja el endpoint con los cambios de una moneda en un rango de fechas.
+func (h *Handlers) GetCurrencyHistory(w http.ResponseWriter, r *http.Request) {
+	_, encoder, ok := h.negotiate(w, r)
+	if !ok {
+		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
+	params := r.URL.Query()
+
+	from, err := parseTimeParam(params.Get("from"), false)
+	if err != nil {
+		h.writeQueryError(w, encoder, err, "Invalid history range")
+		return
+	}
+	to, err := parseTimeParam(params.Get("to"), true)
+	if err != nil {
+		h.writeQueryError(w, encoder, err, "Invalid history range")
+		return
+	}
+
+	result, err := h.historyHandler.Handle(r.Context(), query.GetRateHistoryQuery{
+		CurrencyID: mux.Vars(r)["id"],
+		From:       from,
+		To:         to,
+	})
+	if err != nil {
+		h.writeQueryError(w, encoder, err, "Error getting currency history")
+		return
+	}
+
+	h.writeResponse(w, http.StatusOK, APIResponse{
+		Success:   true,
+		Message:   result.Message,
+		Data:      result,
+		Timestamp: time.Now(),
+	}, encoder)
 }
 
-// CORS middleware para permitir requests desde el frontend.
-func (h *Handlers) CORSMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+// writeQueryError responde 400 a los parámetros inválidos y 500 al resto de errores.
+func (h *Handlers) writeQueryError(w http.ResponseWriter, encoder Encoder, err error, message string) {
+	status := http.StatusInternalServerError
+	if errors.Is(err, query.ErrInvalidQuery) {
+		status = http.StatusBadRequest
+	}
 
-		if r.Method == "OPTIONS" {
-			w.WriteHeader(http.StatusOK)
-			return
-		}
+	h.writeResponse(w, status, APIResponse{
+		Success:   false,
+		Message:   message,
+		Error:     err.Error(),
+		Timestamp: time.Now(),
+	}, encoder)
+}
 
-		next.ServeHTTP(w, r)
-	})
+// parseTimeParam interpreta una fecha RFC 3339 o AAAA-MM-DD. Las fechas sin hora
+// se toman al inicio del día, o al final si endOfDay es true. Un valor vacío retorna cero.
+func parseTimeParam(value string, endOfDay bool) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	day, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %q is not an RFC 3339 timestamp or YYYY-MM-DD date", query.ErrInvalidQuery, value)
+	}
+	if endOfDay {
+		return day.Add(24*time.Hour - time.Nanosecond), nil
+	}
+	return day, nil
+}
+
+// GetCacheStats maneja el endpoint para obtener estadísticas del caché.
+func (h *Handlers) GetCacheStats(w http.ResponseWriter, r *http.Request) {
+	stats, err := h.cacheStats.GetCacheStats(r.Context())
+
+	response := APIResponse{
+		Timestamp: time.Now(),
+	}
+
+	if err != nil {
+		response.Success = false
+		response.Error = err.Error()
+		response.Message = "Error getting cache stats"
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+	} else {
+		response.Success = true
+		response.Message = "Cache stats retrieved"
+		response.Data = stats
+		w.Header().Set("Content-Type", "application/json")
+	}
+
+	json.NewEncoder(w).Encode(response)
 }
 
 // LoggingMiddleware registra todas las requests HTTP.
@@ -200,17 +462,19 @@ func (h *Handlers) LoggingMiddleware(next http.Handler) http.Handler {
 
 		next.ServeHTTP(rw, r)
 
-		duration := time.Since(start)
-
-		// Log básico - en una aplicación real usarías un logger estructurado
-		status := rw.statusCode
-		method := r.Method
-		path := r.URL.Path
-
-		// Solo log si no es un health check para evitar spam
-		if path != "/health" {
-			println("HTTP", method, path, status, duration.String())
+		// Los health checks se registran en nivel debug para evitar spam
+		level := slog.LevelInfo
+		if strings.HasPrefix(r.URL.Path, apiPrefix+"/health") {
+			level = slog.LevelDebug
 		}
+
+		slog.Log(r.Context(), level, "HTTP request",
+			"method", r.Method,
+			"path", r.URL.Path,
+			"status", rw.statusCode,
+			"duration", time.Since(start),
+			"remote_addr", r.RemoteAddr,
+		)
 	})
 }
 
@@ -224,3 +488,16 @@ func (rw *responseWriter) WriteHeader(code int) {
 	rw.statusCode = code
 	rw.ResponseWriter.WriteHeader(code)
 }
+
+// Unwrap expone el ResponseWriter original para http.ResponseController,
+// necesario en respuestas de larga duración como los streams de eventos.
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
+// Hijack cede la conexión subyacente para protocolos como WebSocket y registra
+// el cambio de protocolo como código de estado.
+func (rw *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	rw.statusCode = http.StatusSwitchingProtocols
+	return http.NewResponseController(rw.ResponseWriter).Hijack()
+}
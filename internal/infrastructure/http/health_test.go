@@ -0,0 +1,87 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	appService "gobcv/internal/application/service"
+	"gobcv/internal/domain/entity"
+	"gobcv/internal/domain/service"
+	"gobcv/internal/infrastructure/cache"
+)
+
+// unhealthyScraper es un scraper cuyo sitio no responde.
+type unhealthyScraper struct {
+	fixedScraper
+}
+
+func (unhealthyScraper) IsHealthy(ctx context.Context) error {
+	return errors.New("BCV website returned status 503")
+}
+
+// readiness consulta la sonda de readiness y decodifica su reporte.
+func readiness(t *testing.T, router http.Handler) (int, http.Header, appService.HealthReport) {
+	t.Helper()
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/health/ready", nil))
+
+	var response struct {
+		Success bool                    `json:"success"`
+		Error   string                  `json:"error"`
+		Data    appService.HealthReport `json:"data"`
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+		t.Fatalf("readiness body is not an envelope: %v: %s", err, recorder.Body)
+	}
+	if response.Success != response.Data.Ready || response.Success == (response.Error != "") {
+		t.Errorf("envelope success = %t, error = %q for ready = %t", response.Success, response.Error, response.Data.Ready)
+	}
+	return recorder.Code, recorder.Header(), response.Data
+}
+
+func TestReadinessRoute(t *testing.T) {
+	ctx := context.Background()
+	repo := cache.NewMemoryRepository()
+	memoryCache := cache.NewMemoryCache()
+	t.Cleanup(memoryCache.Close)
+
+	// router crea un HealthService nuevo para no reutilizar la verificación del scraper
+	router := func(scraper service.CurrencyScraper) http.Handler {
+		health := appService.NewHealthService(repo, memoryCache, scraper, time.Minute)
+		handlers := NewHandlers(nil, nil, nil, nil, nil, nil, nil, health, nil, nil, nil, nil, nil)
+		return SetupRouter(handlers, RouterOptions{})
+	}
+
+	// Sin monedas cargadas la réplica no está lista
+	status, header, report := readiness(t, router(fixedScraper{}))
+	if status != http.StatusServiceUnavailable || report.Ready || report.Status != appService.HealthStatusDown {
+		t.Errorf("without data: status = %d, report = %s ready=%t, want 503 down", status, report.Status, report.Ready)
+	}
+	if data := report.Components["data"]; data == nil || data.Status != appService.HealthStatusDown || !data.Critical ||
+		data.Error != "no currencies loaded yet" || data.Details["currencies"] != 0.0 {
+		t.Errorf("data component = %+v, want down with no currencies", data)
+	}
+	if header.Get("Cache-Control") != "no-store" {
+		t.Errorf("Cache-Control = %q, want no-store", header.Get("Cache-Control"))
+	}
+
+	repo.Save(ctx, entity.NewCurrency("USD", "Dólar", 36.5, "test"))
+	if status, _, report := readiness(t, router(fixedScraper{})); status != http.StatusOK || report.Status != appService.HealthStatusUp {
+		t.Errorf("with data: status = %d, report = %s, want 200 up", status, report.Status)
+	}
+
+	// Si BCV no responde, la réplica sigue lista con el estado degradado
+	status, _, report = readiness(t, router(unhealthyScraper{}))
+	if status != http.StatusOK || !report.Ready || report.Status != appService.HealthStatusDegraded {
+		t.Errorf("scraper down: status = %d, report = %s ready=%t, want 200 degraded", status, report.Status, report.Ready)
+	}
+	if scraper := report.Components["scraper"]; scraper.Status != appService.HealthStatusDown || scraper.Error != "BCV website returned status 503" {
+		t.Errorf("scraper component = %+v", scraper)
+	}
+}
@@ -0,0 +1,53 @@
+// Package http implementa las métricas Prometheus de las peticiones HTTP.
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// HTTPMetrics registra la cantidad y latencia de las peticiones por ruta, método y estado.
+// La etiqueta route usa la plantilla de la ruta para mantener acotada la cardinalidad.
+type HTTPMetrics struct {
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+}
+
+// NewHTTPMetrics crea las métricas HTTP y las registra.
+func NewHTTPMetrics(registerer prometheus.Registerer) *HTTPMetrics {
+	labels := []string{"route", "method", "status"}
+	m := &HTTPMetrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "gobcv",
+			Name:      "http_requests_total",
+			Help:      "Peticiones HTTP atendidas.",
+		}, labels),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "gobcv",
+			Name:      "http_request_duration_seconds",
+			Help:      "Latencia de las peticiones HTTP.",
+			Buckets:   prometheus.DefBuckets,
+		}, labels),
+	}
+
+	registerer.MustRegister(m.requests, m.duration)
+
+	return m
+}
+
+// Wrap mide las peticiones de la ruta usando el mismo responseWriter que LoggingMiddleware.
+func (m *HTTPMetrics) Wrap(route RouteInfo, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rw := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+
+		next.ServeHTTP(rw, r)
+
+		status := strconv.Itoa(rw.statusCode)
+		m.requests.WithLabelValues(route.Path, route.Method, status).Inc()
+		m.duration.WithLabelValues(route.Path, route.Method, status).Observe(time.Since(start).Seconds())
+	})
+}
@@ -0,0 +1,155 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	appService "gobcv/internal/application/service"
+	"gobcv/internal/domain/entity"
+	"gobcv/internal/infrastructure/cache"
+	"gobcv/internal/infrastructure/events"
+	"gobcv/internal/infrastructure/invalidation"
+	"gobcv/internal/infrastructure/metrics"
+)
+
+// fixedScraper retorna siempre las mismas monedas, actualizadas al momento de consultar.
+type fixedScraper struct {
+	currencies map[string]float64
+}
+
+func (s fixedScraper) ScrapeCurrencies(ctx context.Context) ([]*entity.Currency, error) {
+	currencies := make([]*entity.Currency, 0, len(s.currencies))
+	for id, value := range s.currencies {
+		currencies = append(currencies, entity.NewCurrency(id, id, value, "test"))
+	}
+	return currencies, nil
+}
+
+func (s fixedScraper) ScrapeCurrency(ctx context.Context, currencyID string) (*entity.Currency, error) {
+	if value, ok := s.currencies[currencyID]; ok {
+		return entity.NewCurrency(currencyID, currencyID, value, "test"), nil
+	}
+	return nil, errors.New("currency not found")
+}
+
+func (s fixedScraper) IsHealthy(ctx context.Context) error {
+	return nil
+}
+
+// scrapeMetrics retorna el cuerpo de /metrics.
+func scrapeMetrics(t *testing.T, router http.Handler) string {
+	t.Helper()
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("GET /metrics: status = %d", recorder.Code)
+	}
+	return recorder.Body.String()
+}
+
+// metricValue retorna el valor de la serie con el nombre y las etiquetas exactas indicadas.
+func metricValue(t *testing.T, body, series string) float64 {
+	t.Helper()
+
+	pattern := regexp.MustCompile("(?m)^" + regexp.QuoteMeta(series) + ` (\S+)$`)
+	match := pattern.FindStringSubmatch(body)
+	if match == nil {
+		t.Fatalf("series %s not found in:\n%s", series, body)
+	}
+	value, err := strconv.ParseFloat(match[1], 64)
+	if err != nil {
+		t.Fatalf("series %s has value %q", series, match[1])
+	}
+	return value
+}
+
+func TestMetricsEndpoint(t *testing.T) {
+	registry := metrics.NewRegistry()
+	repo := cache.NewMemoryRepository()
+	memoryCache := cache.NewMemoryCache()
+	t.Cleanup(memoryCache.Close)
+
+	scraper := metrics.NewInstrumentedScraper(fixedScraper{currencies: map[string]float64{"USD": 36.5, "EUR": 40}}, registry)
+	registry.MustRegister(metrics.NewCacheCollector(memoryCache), metrics.NewCurrencyCollector(repo))
+
+	currencies := appService.NewCurrencyService(repo, scraper, memoryCache, invalidation.NewMemoryBus(),
+		cache.NewMemoryRateHistory(10), events.NewBroker(), "test", time.Hour)
+	handlers := NewHandlers(currencies.GetRefreshHandler(), currencies.GetCurrencyHandler(), currencies.GetAllCurrenciesHandler(),
+		currencies.GetConvertHandler(), currencies.GetRateHistoryHandler(), currencies, currencies,
+		nil, nil, nil, nil, nil, nil)
+	router := SetupRouter(handlers, RouterOptions{MetricsPath: "/metrics", MetricsHandler: metrics.Handler(registry)},
+		NewHTTPMetrics(registry))
+
+	refreshedAt := time.Now()
+	requests := []struct {
+		method, target string
+		status         int
+	}{
+		{http.MethodPost, "/api/v1/currencies/refresh", http.StatusOK},
+		{http.MethodGet, "/api/v1/currencies/USD", http.StatusOK},
+		{http.MethodGet, "/api/v1/currencies/EUR", http.StatusOK},
+		{http.MethodGet, "/api/v1/currencies/USD", http.StatusOK},
+		{http.MethodGet, "/api/v1/currencies/GBP", http.StatusNotFound},
+	}
+	for _, request := range requests {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(request.method, request.target, nil))
+		if recorder.Code != request.status {
+			t.Fatalf("%s %s: status = %d, want %d", request.method, request.target, recorder.Code, request.status)
+		}
+	}
+
+	body := scrapeMetrics(t, router)
+
+	// Las peticiones se agrupan por la plantilla de la ruta, no por la URL
+	httpSeries := map[string]float64{
+		`gobcv_http_requests_total{method="GET",route="/api/v1/currencies/{id}",status="200"}`:                 3,
+		`gobcv_http_requests_total{method="GET",route="/api/v1/currencies/{id}",status="404"}`:                 1,
+		`gobcv_http_requests_total{method="POST",route="/api/v1/currencies/refresh",status="200"}`:             1,
+		`gobcv_http_request_duration_seconds_count{method="GET",route="/api/v1/currencies/{id}",status="200"}`: 3,
+	}
+	for series, want := range httpSeries {
+		if got := metricValue(t, body, series); got != want {
+			t.Errorf("%s = %v, want %v", series, got, want)
+		}
+	}
+	if strings.Contains(body, `route="/api/v1/currencies/USD"`) {
+		t.Error("request metrics use the URL instead of the route template")
+	}
+	if !strings.Contains(body, `gobcv_http_request_duration_seconds_bucket{method="GET",route="/api/v1/currencies/{id}",status="200",le="+Inf"} 3`) {
+		t.Error("duration histogram has no +Inf bucket for the currency route")
+	}
+
+	// El primer acceso a cada moneda falla en caché y el segundo a USD acierta
+	if hits := metricValue(t, body, `gobcv_cache_hits_total{tier="total"}`); hits < 1 {
+		t.Errorf("cache hits = %v, want at least 1", hits)
+	}
+	if misses := metricValue(t, body, `gobcv_cache_misses_total{tier="total"}`); misses < 3 {
+		t.Errorf("cache misses = %v, want at least 3", misses)
+	}
+	if keys := metricValue(t, body, `gobcv_cache_keys{tier="total"}`); keys < 2 {
+		t.Errorf("cache keys = %v, want at least the two cached currencies", keys)
+	}
+
+	for currency, want := range map[string]float64{"USD": 36.5, "EUR": 40} {
+		if rate := metricValue(t, body, `gobcv_currency_rate{currency="`+currency+`"}`); rate != want {
+			t.Errorf("%s rate = %v, want %v", currency, rate, want)
+		}
+		refreshed := metricValue(t, body, `gobcv_currency_last_refresh_timestamp_seconds{currency="`+currency+`"}`)
+		if delta := refreshed - float64(refreshedAt.UnixNano())/1e9; delta < -1 || delta > 5 {
+			t.Errorf("%s last refresh = %v, want about %v", currency, refreshed, refreshedAt.Unix())
+		}
+	}
+
+	if attempts := metricValue(t, body, `gobcv_scrape_attempts_total{operation="currencies"}`); attempts != 1 {
+		t.Errorf("scrape attempts = %v, want 1", attempts)
+	}
+}
@@ -0,0 +1,614 @@
+// Package http implementa la especificación OpenAPI 3 de la API REST.
+package http
+
+import (
+	"strings"
+
+	"gobcv/internal/domain/entity"
+)
+
+// OpenAPIDocument representa el subconjunto de OpenAPI 3 usado para describir la API.
+type OpenAPIDocument struct {
+	OpenAPI    string                           `json:"openapi"`
+	Info       OpenAPIInfo                      `json:"info"`
+	Servers    []OpenAPIServer                  `json:"servers,omitempty"`
+	Paths      map[string]map[string]*Operation `json:"paths"`
+	Components OpenAPIComponents                `json:"components"`
+}
+
+// OpenAPIInfo contiene los metadatos de la API.
+type OpenAPIInfo struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// OpenAPIServer describe un servidor de la API.
+type OpenAPIServer struct {
+	URL string `json:"url"`
+}
+
+// OpenAPIComponents contiene los esquemas reutilizables.
+type OpenAPIComponents struct {
+	Schemas         map[string]*Schema         `json:"schemas"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+// SecurityScheme describe un mecanismo de autenticación.
+type SecurityScheme struct {
+	Type        string `json:"type"`
+	Name        string `json:"name"`
+	In          string `json:"in"`
+	Description string `json:"description,omitempty"`
+}
+
+// Operation describe una operación de un endpoint.
+type Operation struct {
+	OperationID string                `json:"operationId"`
+	Summary     string                `json:"summary"`
+	Description string                `json:"description,omitempty"`
+	Tags        []string              `json:"tags,omitempty"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Security    []map[string][]string `json:"security,omitempty"`
+	Responses   map[string]*Response  `json:"responses"`
+}
+
+// Parameter describe un parámetro de una operación.
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+// RequestBody describe el cuerpo de una petición.
+type RequestBody struct {
+	Description string               `json:"description,omitempty"`
+	Required    bool                 `json:"required,omitempty"`
+	Content     map[string]MediaType `json:"content"`
+}
+
+// Response describe una respuesta de una operación.
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// MediaType describe el contenido de una respuesta para un tipo de medio.
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Schema describe un esquema JSON de OpenAPI.
+type Schema struct {
+	Ref         string             `json:"$ref,omitempty"`
+	Type        string             `json:"type,omitempty"`
+	Format      string             `json:"format,omitempty"`
+	Description string             `json:"description,omitempty"`
+	Pattern     string             `json:"pattern,omitempty"`
+	Enum        []string           `json:"enum,omitempty"`
+	Default     interface{}        `json:"default,omitempty"`
+	Items       *Schema            `json:"items,omitempty"`
+	Properties  map[string]*Schema `json:"properties,omitempty"`
+	AllOf       []*Schema          `json:"allOf,omitempty"`
+	Nullable    bool               `json:"nullable,omitempty"`
+
+	AdditionalProperties *Schema `json:"additionalProperties,omitempty"`
+}
+
+// schemaRef retorna una referencia a un esquema de los componentes.
+func schemaRef(name string) *Schema {
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+// envelope retorna el esquema de APIResponse con data del esquema indicado.
+func envelope(data *Schema) *Schema {
+	if data == nil {
+		return schemaRef("APIResponse")
+	}
+
+	return &Schema{
+		AllOf: []*Schema{
+			schemaRef("APIResponse"),
+			{Type: "object", Properties: map[string]*Schema{"data": data}},
+		},
+	}
+}
+
+// jsonResponse retorna una respuesta JSON con el envoltorio estándar.
+func jsonResponse(description string, data *Schema) *Response {
+	return &Response{
+		Description: description,
+		Content:     map[string]MediaType{"application/json": {Schema: envelope(data)}},
+	}
+}
+
+// jsonRequestBody retorna un cuerpo de petición JSON obligatorio con el esquema indicado.
+func jsonRequestBody(description string, schema *Schema) *RequestBody {
+	return &RequestBody{
+		Description: description,
+		Required:    true,
+		Content:     map[string]MediaType{"application/json": {Schema: schema}},
+	}
+}
+
+// graphqlResponse retorna una respuesta GraphQL, que no usa el envoltorio estándar.
+func graphqlResponse(description string) *Response {
+	return &Response{
+		Description: description,
+		Content:     map[string]MediaType{"application/json": {Schema: schemaRef("GraphQLResponse")}},
+	}
+}
+
+// negotiatedResponse retorna una respuesta disponible en todos los formatos del registro de encoders.
+func negotiatedResponse(description string, data *Schema) *Response {
+	text := &Schema{Type: "string"}
+	return &Response{
+		Description: description,
+		Content: map[string]MediaType{
+			"application/json": {Schema: envelope(data)},
+			"application/xml":  {Schema: envelope(data)},
+			"text/csv":         {Schema: text},
+			"text/plain":       {Schema: text},
+		},
+	}
+}
+
+// Parámetros compartidos por las operaciones.
+var (
+	cacheParam = Parameter{
+		Name:        "cache",
+		In:          "query",
+		Description: "false para omitir el caché",
+		Schema:      &Schema{Type: "boolean", Default: true},
+	}
+	includeStaleParam = Parameter{
+		Name:        "include_stale",
+		In:          "query",
+		Description: "true para incluir monedas fuera de la ventana de obsolescencia",
+		Schema:      &Schema{Type: "boolean", Default: false},
+	}
+	forceParam = Parameter{
+		Name:        "force",
+		In:          "query",
+		Description: "true para forzar la actualización",
+		Schema:      &Schema{Type: "boolean", Default: false},
+	}
+	formatParam = Parameter{
+		Name:        "format",
+		In:          "query",
+		Description: "Formato de salida; tiene prioridad sobre la cabecera Accept",
+		Schema:      &Schema{Type: "string", Enum: []string{FormatJSON, FormatCSV, FormatXML, FormatText}},
+	}
+	currencyIDParam = Parameter{
+		Name:        "id",
+		In:          "path",
+		Description: "Código ISO 4217 de la moneda",
+		Required:    true,
+		Schema:      &Schema{Type: "string", Pattern: "^[A-Z]{3}$"},
+	}
+	convertFromParam = Parameter{
+		Name:        "from",
+		In:          "query",
+		Description: "Moneda de origen (USD, EUR, VES)",
+		Required:    true,
+		Schema:      &Schema{Type: "string", Pattern: "^[A-Za-z]{3}$"},
+	}
+	convertToParam = Parameter{
+		Name:        "to",
+		In:          "query",
+		Description: "Moneda de destino",
+		Schema:      &Schema{Type: "string", Pattern: "^[A-Za-z]{3}$", Default: "VES"},
+	}
+	amountParam = Parameter{
+		Name:        "amount",
+		In:          "query",
+		Description: "Monto a convertir",
+		Schema:      &Schema{Type: "number", Format: "double", Default: 1},
+	}
+	historyFromParam = Parameter{
+		Name:        "from",
+		In:          "query",
+		Description: "Inicio del rango, RFC 3339 o AAAA-MM-DD; por defecto el cambio más antiguo",
+		Schema:      &Schema{Type: "string"},
+	}
+	historyToParam = Parameter{
+		Name:        "to",
+		In:          "query",
+		Description: "Fin del rango, RFC 3339 o AAAA-MM-DD (incluye el día completo); por defecto ahora",
+		Schema:      &Schema{Type: "string"},
+	}
+	webhookIDParam = Parameter{
+		Name:        "id",
+		In:          "path",
+		Description: "ID del webhook",
+		Required:    true,
+		Schema:      &Schema{Type: "string"},
+	}
+	alertRuleIDParam = Parameter{
+		Name:        "id",
+		In:          "path",
+		Description: "ID de la regla de alerta",
+		Required:    true,
+		Schema:      &Schema{Type: "string"},
+	}
+	limitParam = Parameter{
+		Name:        "limit",
+		In:          "query",
+		Description: "Cantidad máxima de resultados",
+		Schema:      &Schema{Type: "integer", Default: 50},
+	}
+	currenciesParam = Parameter{
+		Name:        "currencies",
+		In:          "query",
+		Description: "Monedas separadas por comas (USD,EUR); por defecto todas",
+		Schema:      &Schema{Type: "string"},
+	}
+	snapshotParam = Parameter{
+		Name:        "snapshot",
+		In:          "query",
+		Description: "true para recibir primero un evento snapshot con los valores actuales",
+		Schema:      &Schema{Type: "boolean", Default: false},
+	}
+	graphqlQueryParam = Parameter{
+		Name:        "query",
+		In:          "query",
+		Description: "Documento GraphQL con la consulta",
+		Required:    true,
+		Schema:      &Schema{Type: "string"},
+	}
+	graphqlOperationNameParam = Parameter{
+		Name:        "operationName",
+		In:          "query",
+		Description: "Operación a ejecutar si el documento contiene varias",
+		Schema:      &Schema{Type: "string"},
+	}
+	graphqlVariablesParam = Parameter{
+		Name:        "variables",
+		In:          "query",
+		Description: "Variables de la operación como objeto JSON",
+		Schema:      &Schema{Type: "string"},
+	}
+	lastEventIDParam = Parameter{
+		Name:        "Last-Event-ID",
+		In:          "header",
+		Description: "Secuencia del último evento recibido; también se acepta el parámetro last_event_id",
+		Schema:      &Schema{Type: "integer", Format: "int64"},
+	}
+)
+
+// graphqlRequestBody es el cuerpo de las operaciones GraphQL por POST.
+var graphqlRequestBody = &RequestBody{
+	Description: "Operación GraphQL",
+	Required:    true,
+	Content: map[string]MediaType{
+		"application/json":    {Schema: schemaRef("GraphQLRequest")},
+		"application/graphql": {Schema: &Schema{Type: "string"}},
+	},
+}
+
+// openAPISchemas retorna los esquemas de los componentes de la API.
+func openAPISchemas() map[string]*Schema {
+	return map[string]*Schema{
+		"APIResponse": {
+			Type: "object",
+			Properties: map[string]*Schema{
+				"success":   {Type: "boolean"},
+				"message":   {Type: "string"},
+				"data":      {Description: "Datos específicos de cada endpoint"},
+				"error":     {Type: "string"},
+				"timestamp": {Type: "string", Format: "date-time"},
+			},
+		},
+		"Currency": {
+			Type: "object",
+			Properties: map[string]*Schema{
+				"id":         {Type: "string", Pattern: "^[A-Z]{3}$"},
+				"name":       {Type: "string"},
+				"value":      {Type: "number", Format: "double"},
+				"updated_at": {Type: "string", Format: "date-time"},
+				"source":     {Type: "string"},
+			},
+		},
+		"CurrencyData": {
+			Type: "object",
+			Properties: map[string]*Schema{
+				"currency":    schemaRef("Currency"),
+				"from_cache":  {Type: "boolean"},
+				"stale":       {Type: "boolean"},
+				"age_seconds": {Type: "integer", Format: "int64"},
+			},
+		},
+		"CurrenciesData": {
+			Type: "object",
+			Properties: map[string]*Schema{
+				"currencies":  {Type: "array", Items: schemaRef("Currency")},
+				"count":       {Type: "integer"},
+				"from_cache":  {Type: "boolean"},
+				"stale":       {Type: "boolean"},
+				"age_seconds": {Type: "integer", Format: "int64"},
+			},
+		},
+		"RefreshResult": {
+			Type: "object",
+			Properties: map[string]*Schema{
+				"updated_count": {Type: "integer"},
+				"changed_count": {Type: "integer"},
+				"currencies":    {Type: "array", Items: &Schema{Type: "string"}},
+				"success":       {Type: "boolean"},
+				"message":       {Type: "string"},
+			},
+		},
+		"RateChange": {
+			Type: "object",
+			Properties: map[string]*Schema{
+				"sequence":       {Type: "integer", Format: "int64"},
+				"currency_id":    {Type: "string", Pattern: "^[A-Z]{3}$"},
+				"name":           {Type: "string"},
+				"value":          {Type: "number", Format: "double"},
+				"previous_value": {Type: "number", Format: "double"},
+				"source":         {Type: "string"},
+				"changed_at":     {Type: "string", Format: "date-time"},
+			},
+		},
+		"Webhook": {
+			Type: "object",
+			Properties: map[string]*Schema{
+				"id":                   {Type: "string"},
+				"url":                  {Type: "string", Format: "uri"},
+				"secret":               {Type: "string", Description: "Solo se incluye al crear el webhook"},
+				"currencies":           {Type: "array", Items: &Schema{Type: "string", Pattern: "^[A-Z]{3}$"}},
+				"description":          {Type: "string"},
+				"disabled":             {Type: "boolean"},
+				"disabled_reason":      {Type: "string"},
+				"consecutive_failures": {Type: "integer"},
+				"created_at":           {Type: "string", Format: "date-time"},
+				"updated_at":           {Type: "string", Format: "date-time"},
+			},
+		},
+		"WebhookInput": {
+			Type: "object",
+			Properties: map[string]*Schema{
+				"url":         {Type: "string", Format: "uri", Description: "Obligatorio al crear"},
+				"secret":      {Type: "string", Description: "Secreto de firma; se genera si se omite al crear"},
+				"currencies":  {Type: "array", Items: &Schema{Type: "string", Pattern: "^[A-Z]{3}$"}, Description: "Vacío para todas"},
+				"description": {Type: "string"},
+				"disabled":    {Type: "boolean", Description: "false reactiva el webhook y reinicia el contador de fallos"},
+			},
+		},
+		"WebhookDelivery": {
+			Type: "object",
+			Properties: map[string]*Schema{
+				"delivery_id":  {Type: "string"},
+				"webhook_id":   {Type: "string"},
+				"event":        {Type: "string"},
+				"sequence":     {Type: "integer", Format: "int64"},
+				"attempt":      {Type: "integer"},
+				"status_code":  {Type: "integer"},
+				"success":      {Type: "boolean"},
+				"error":        {Type: "string"},
+				"duration_ms":  {Type: "integer", Format: "int64"},
+				"attempted_at": {Type: "string", Format: "date-time"},
+			},
+		},
+		"Conversion": {
+			Type: "object",
+			Properties: map[string]*Schema{
+				"from":       {Type: "string"},
+				"to":         {Type: "string"},
+				"amount":     {Type: "number", Format: "double"},
+				"result":     {Type: "number", Format: "double"},
+				"rate":       {Type: "number", Format: "double", Description: "Unidades de to por unidad de from"},
+				"rates_at":   {Type: "string", Format: "date-time", Description: "Fecha de la tasa más antigua usada"},
+				"from_cache": {Type: "boolean"},
+				"stale":      {Type: "boolean"},
+				"success":    {Type: "boolean"},
+				"message":    {Type: "string"},
+			},
+		},
+		"GraphQLRequest": {
+			Type: "object",
+			Properties: map[string]*Schema{
+				"query":         {Type: "string"},
+				"operationName": {Type: "string"},
+				"variables":     {Type: "object", AdditionalProperties: &Schema{}},
+			},
+		},
+		"GraphQLResponse": {
+			Type: "object",
+			Properties: map[string]*Schema{
+				"data":   {Type: "object", Nullable: true, AdditionalProperties: &Schema{}},
+				"errors": {Type: "array", Items: schemaRef("GraphQLError")},
+			},
+		},
+		"GraphQLError": {
+			Type: "object",
+			Properties: map[string]*Schema{
+				"message":    {Type: "string"},
+				"locations":  {Type: "array", Items: &Schema{Type: "object", AdditionalProperties: &Schema{Type: "integer"}}},
+				"path":       {Type: "array", Items: &Schema{}},
+				"extensions": {Type: "object", AdditionalProperties: &Schema{}},
+			},
+		},
+		"RateHistory": {
+			Type: "object",
+			Properties: map[string]*Schema{
+				"currency_id": {Type: "string"},
+				"from":        {Type: "string", Format: "date-time"},
+				"to":          {Type: "string", Format: "date-time"},
+				"changes":     {Type: "array", Items: schemaRef("RateChange")},
+				"count":       {Type: "integer"},
+				"success":     {Type: "boolean"},
+				"message":     {Type: "string"},
+			},
+		},
+		"AlertRule": {
+			Type: "object",
+			Properties: map[string]*Schema{
+				"id":                {Type: "string"},
+				"currency_id":       {Type: "string", Pattern: "^[A-Z]{3}$"},
+				"type":              {Type: "string", Enum: []string{"change_percent", "above", "below"}},
+				"threshold":         {Type: "number", Format: "double"},
+				"cooldown_seconds":  {Type: "integer", Format: "int64"},
+				"notifiers":         {Type: "array", Items: &Schema{Type: "string"}},
+				"description":       {Type: "string"},
+				"disabled":          {Type: "boolean"},
+				"last_triggered_at": {Type: "string", Format: "date-time"},
+				"created_at":        {Type: "string", Format: "date-time"},
+				"updated_at":        {Type: "string", Format: "date-time"},
+			},
+		},
+		"AlertRuleInput": {
+			Type: "object",
+			Properties: map[string]*Schema{
+				"currency_id": {Type: "string", Pattern: "^[A-Z]{3}$", Description: "Obligatorio al crear"},
+				"type": {
+					Type:        "string",
+					Enum:        []string{"change_percent", "above", "below"},
+					Description: "change_percent: variación en 24 horas; above/below: cruce del umbral. Obligatorio al crear",
+				},
+				"threshold":   {Type: "number", Format: "double", Description: "Porcentaje o valor absoluto. Obligatorio al crear"},
+				"cooldown":    {Type: "string", Description: "Tiempo mínimo entre disparos, por ejemplo 1h"},
+				"notifiers":   {Type: "array", Items: &Schema{Type: "string", Enum: []string{"log", "webhook", "smtp"}}, Description: "Vacío para todos los configurados"},
+				"description": {Type: "string"},
+				"disabled":    {Type: "boolean"},
+			},
+		},
+		"Alert": {
+			Type: "object",
+			Properties: map[string]*Schema{
+				"id":              {Type: "string"},
+				"rule_id":         {Type: "string"},
+				"currency_id":     {Type: "string"},
+				"type":            {Type: "string"},
+				"threshold":       {Type: "number", Format: "double"},
+				"value":           {Type: "number", Format: "double"},
+				"previous_value":  {Type: "number", Format: "double"},
+				"reference_value": {Type: "number", Format: "double"},
+				"change_percent":  {Type: "number", Format: "double"},
+				"sequence":        {Type: "integer", Format: "int64"},
+				"message":         {Type: "string"},
+				"notified":        {Type: "array", Items: &Schema{Type: "string"}},
+				"errors":          {Type: "array", Items: &Schema{Type: "string"}},
+				"triggered_at":    {Type: "string", Format: "date-time"},
+			},
+		},
+		"HealthReport": {
+			Type: "object",
+			Properties: map[string]*Schema{
+				"status":     {Type: "string", Enum: []string{"up", "degraded", "down"}},
+				"ready":      {Type: "boolean"},
+				"checked_at": {Type: "string", Format: "date-time"},
+				"components": {
+					Type: "object",
+					AdditionalProperties: &Schema{
+						Type: "object",
+						Properties: map[string]*Schema{
+							"status":   {Type: "string", Enum: []string{"up", "degraded", "down"}},
+							"critical": {Type: "boolean"},
+							"error":    {Type: "string"},
+							"details":  {Type: "object"},
+						},
+					},
+				},
+			},
+		},
+		"CacheStats": {
+			Type: "object",
+			Properties: map[string]*Schema{
+				"hits":       {Type: "integer", Format: "int64"},
+				"misses":     {Type: "integer", Format: "int64"},
+				"stale_hits": {Type: "integer", Format: "int64"},
+				"keys":       {Type: "integer", Format: "int64"},
+				"tiers": {
+					Type:                 "object",
+					AdditionalProperties: schemaRef("CacheStats"),
+				},
+			},
+		},
+	}
+}
+
+// BuildOpenAPI genera la especificación OpenAPI a partir de las rutas de la API. Las
+// rutas se documentan completas, con el prefijo /api/v1 cuando lo llevan, y los
+// requisitos de seguridad solo se declaran con la autenticación habilitada.
+func BuildOpenAPI(routes []apiRoute, options RouterOptions) *OpenAPIDocument {
+	doc := &OpenAPIDocument{
+		OpenAPI: "3.0.3",
+		Info: OpenAPIInfo{
+			Title:       "BCV Currency API",
+			Version:     "1.0.0",
+			Description: "API para obtener tipos de cambio del Banco Central de Venezuela",
+		},
+		Servers:    []OpenAPIServer{{URL: "/"}},
+		Paths:      make(map[string]map[string]*Operation),
+		Components: OpenAPIComponents{Schemas: openAPISchemas()},
+	}
+
+	if options.AuthEnabled {
+		doc.Components.SecuritySchemes = map[string]*SecurityScheme{
+			"apiKeyHeader": {Type: "apiKey", In: "header", Name: apiKeyHeader},
+			"apiKeyQuery":  {Type: "apiKey", In: "query", Name: apiKeyQueryParam},
+		}
+	}
+	anonymous := entity.APIKey{Scopes: options.AnonymousScopes}
+
+	for _, route := range routes {
+		path := openAPIPath(route.path)
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = make(map[string]*Operation)
+		}
+
+		operation := route.operation
+		if options.AuthEnabled && route.scope != "" {
+			operation.Description = strings.TrimSpace(operation.Description + " Requiere el scope " + route.scope + ".")
+			operation.Security = []map[string][]string{{"apiKeyHeader": {}}, {"apiKeyQuery": {}}}
+			// Un requisito vacío indica que la clave es opcional
+			if anonymous.HasScope(route.scope) {
+				operation.Security = append(operation.Security, map[string][]string{})
+			}
+			operation.Responses["401"] = jsonResponse("Clave de API ausente o inválida", nil)
+			operation.Responses["403"] = jsonResponse("La clave de API no otorga el scope requerido", nil)
+		}
+
+		if route.class != "" {
+			operation.Responses["429"] = jsonResponse("Límite de peticiones excedido; ver Retry-After y RateLimit-*", nil)
+		}
+
+		doc.Paths[path][strings.ToLower(route.method)] = operation
+	}
+
+	return doc
+}
+
+// openAPIPath convierte una plantilla de mux en una ruta OpenAPI eliminando
+// las expresiones regulares de las variables: /currencies/{id:[A-Z]{3}} -> /currencies/{id}.
+func openAPIPath(template string) string {
+	var builder strings.Builder
+	depth := 0
+	skipping := false
+
+	for _, char := range template {
+		switch {
+		case char == '{':
+			depth++
+			if depth == 1 {
+				builder.WriteRune(char)
+			}
+		case char == '}':
+			depth--
+			if depth == 0 {
+				skipping = false
+				builder.WriteRune(char)
+			}
+		case char == ':' && depth == 1:
+			skipping = true
+		case !skipping && depth <= 1:
+			builder.WriteRune(char)
+		}
+	}
+
+	return builder.String()
+}
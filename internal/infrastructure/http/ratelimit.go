@@ -0,0 +1,267 @@
+// Package http implementa la limitación de peticiones por cliente.
+package http
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gobcv/internal/domain/service"
+)
+
+// Clases de rutas con límites de peticiones independientes. RouteClassAuth no es una
+// clase de ruta: limita las autenticaciones fallidas de cada IP.
+const (
+	RouteClassRead    = "read"
+	RouteClassRefresh = "refresh"
+	RouteClassAuth    = "auth"
+)
+
+// RateLimitMiddleware limita las peticiones de cada cliente con token buckets.
+// El cliente se identifica por su clave de API autenticada o, en su defecto, por su IP.
+type RateLimitMiddleware struct {
+	store          service.RateLimitStore
+	limits         map[string]service.RateLimit
+	trustedProxies []*net.IPNet
+
+	// blocked guarda hasta cuándo se rechazan las claves de cada IP que agotó su
+	// bucket de autenticaciones fallidas
+	blockedMu sync.Mutex
+	blocked   map[string]time.Time
+}
+
+// NewRateLimitMiddleware crea el middleware de límites. limits asocia cada clase de
+// ruta con su límite; las rutas de clases sin límite no se limitan. X-Forwarded-For
+// solo se considera cuando la petición llega desde uno de los trustedProxies.
+func NewRateLimitMiddleware(
+	store service.RateLimitStore,
+	limits map[string]service.RateLimit,
+	trustedProxies []*net.IPNet,
+) *RateLimitMiddleware {
+	return &RateLimitMiddleware{
+		store:          store,
+		limits:         limits,
+		trustedProxies: trustedProxies,
+		blocked:        make(map[string]time.Time),
+	}
+}
+
+// ParseTrustedProxies convierte una lista de IPs o rangos CIDR en redes.
+func ParseTrustedProxies(values []string) ([]*net.IPNet, error) {
+	networks := make([]*net.IPNet, 0, len(values))
+
+	for _, value := range values {
+		if !strings.Contains(value, "/") {
+			ip := net.ParseIP(value)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q", value)
+			}
+			bits := 8 * len(ip.To16())
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 32
+			}
+			networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+
+		_, network, err := net.ParseCIDR(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", value, err)
+		}
+		networks = append(networks, network)
+	}
+
+	return networks, nil
+}
+
+// Wrap aplica el límite de la clase de la ruta.
+func (m *RateLimitMiddleware) Wrap(route RouteInfo, next http.Handler) http.Handler {
+	limit, ok := m.limits[route.Class]
+	if !ok || limit.Burst <= 0 {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		result, err := m.store.Take(r.Context(), route.Class+":"+m.clientKey(r), limit)
+		if err != nil {
+			// Ante fallas del almacén se prefiere servir la petición
+			slog.ErrorContext(r.Context(), "Error aplicando límite de peticiones", "class", route.Class, "error", err)
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		header := w.Header()
+		header.Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+		header.Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		header.Set("RateLimit-Reset", strconv.FormatInt(ceilSeconds(result.ResetAfter), 10))
+		header.Set("RateLimit-Policy", fmt.Sprintf("%d;w=%d", limit.Burst, ceilSeconds(limit.Period)))
+
+		if !result.Allowed {
+			header.Set("Retry-After", strconv.FormatInt(ceilSeconds(result.RetryAfter), 10))
+			writeJSON(w, http.StatusTooManyRequests, APIResponse{
+				Success:   false,
+				Message:   "Rate limit exceeded",
+				Error:     fmt.Sprintf("limit of %d requests per %s exceeded for %s routes", limit.Burst, limit.Period, route.Class),
+				Timestamp: time.Now(),
+			})
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// AuthFailures retorna el middleware que cuenta las autenticaciones fallidas de cada IP
+// en el bucket de RouteClassAuth. Debe ir antes de AuthMiddleware: al agotarse el bucket,
+// las peticiones con clave de esa IP se rechazan con 429 sin llegar a validarla.
+func (m *RateLimitMiddleware) AuthFailures() RouteMiddleware {
+	return authFailureLimiter{m}
+}
+
+// authFailureLimiter limita los intentos de autenticación fallidos por IP.
+type authFailureLimiter struct {
+	m *RateLimitMiddleware
+}
+
+// Wrap cuenta las respuestas 401 de las peticiones que envían una clave de API.
+func (l authFailureLimiter) Wrap(route RouteInfo, next http.Handler) http.Handler {
+	limit, ok := l.m.limits[RouteClassAuth]
+	if route.Scope == "" || !ok || limit.Burst <= 0 {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Las peticiones sin clave no prueban claves: las limita la clase de la ruta
+		if r.Header.Get(apiKeyHeader) == "" && r.URL.Query().Get(apiKeyQueryParam) == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ip := l.m.clientIP(r)
+		if retryAfter := l.m.blockedFor(ip); retryAfter > 0 {
+			w.Header().Set("Retry-After", strconv.FormatInt(ceilSeconds(retryAfter), 10))
+			writeJSON(w, http.StatusTooManyRequests, APIResponse{
+				Success:   false,
+				Message:   "Too many failed authentications",
+				Error:     fmt.Sprintf("more than %d failed authentications per %s from this client", limit.Burst, limit.Period),
+				Timestamp: time.Now(),
+			})
+			return
+		}
+
+		rw := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+		next.ServeHTTP(rw, r)
+
+		if rw.statusCode != http.StatusUnauthorized {
+			return
+		}
+
+		result, err := l.m.store.Take(r.Context(), RouteClassAuth+":ip:"+ip, limit)
+		if err != nil {
+			slog.ErrorContext(r.Context(), "Error registrando autenticación fallida", "error", err)
+			return
+		}
+
+		// Con el último token consumido, bloquear hasta que se recargue uno
+		if !result.Allowed || result.Remaining == 0 {
+			retryAfter := result.RetryAfter
+			if retryAfter <= 0 {
+				retryAfter = limit.RefillInterval()
+			}
+			l.m.block(ip, retryAfter)
+			slog.WarnContext(r.Context(), "Cliente bloqueado por autenticaciones fallidas", "ip", ip, "retry_after", retryAfter)
+		}
+	})
+}
+
+// blockedFor retorna cuánto falta para desbloquear las claves de la IP, o cero.
+func (m *RateLimitMiddleware) blockedFor(ip string) time.Duration {
+	m.blockedMu.Lock()
+	defer m.blockedMu.Unlock()
+
+	until, ok := m.blocked[ip]
+	if !ok {
+		return 0
+	}
+
+	remaining := time.Until(until)
+	if remaining <= 0 {
+		delete(m.blocked, ip)
+		return 0
+	}
+	return remaining
+}
+
+// block rechaza las claves de la IP durante d y descarta los bloqueos vencidos.
+func (m *RateLimitMiddleware) block(ip string, d time.Duration) {
+	m.blockedMu.Lock()
+	defer m.blockedMu.Unlock()
+
+	now := time.Now()
+	for blockedIP, until := range m.blocked {
+		if now.After(until) {
+			delete(m.blocked, blockedIP)
+		}
+	}
+	m.blocked[ip] = now.Add(d)
+}
+
+// clientKey identifica al cliente de la petición.
+func (m *RateLimitMiddleware) clientKey(r *http.Request) string {
+	if key, ok := APIKeyFromContext(r.Context()); ok {
+		return "key:" + key.ID
+	}
+	return "ip:" + m.clientIP(r)
+}
+
+// clientIP retorna la IP del cliente. Si la conexión proviene de un proxy de confianza
+// recorre X-Forwarded-For de derecha a izquierda y toma la primera IP que no es de confianza.
+func (m *RateLimitMiddleware) clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	if !m.isTrusted(net.ParseIP(host)) {
+		return host
+	}
+
+	hops := strings.Split(r.Header.Get("X-Forwarded-For"), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		ip := net.ParseIP(hop)
+		if ip == nil {
+			break
+		}
+		host = hop
+		if !m.isTrusted(ip) {
+			break
+		}
+	}
+
+	return host
+}
+
+// isTrusted verifica si la IP pertenece a un proxy de confianza.
+func (m *RateLimitMiddleware) isTrusted(ip net.IP) bool {
+	if ip == nil {
+		return false
+	}
+	for _, network := range m.trustedProxies {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// ceilSeconds redondea una duración hacia arriba a segundos enteros.
+func ceilSeconds(d time.Duration) int64 {
+	return int64(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,293 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"gobcv/internal/domain/entity"
+	"gobcv/internal/domain/service"
+	"gobcv/internal/infrastructure/cache"
+	"gobcv/internal/infrastructure/ratelimit"
+)
+
+// newAuthFailureChain encadena el límite de autenticaciones fallidas, la autenticación
+// y el límite por clase sobre un handler que responde 200, como en el servidor.
+func newAuthFailureChain(t *testing.T, failures int) (http.Handler, string) {
+	t.Helper()
+
+	keys := cache.NewMemoryAPIKeyRepository()
+	rawKey := "valid-key"
+	err := keys.Save(context.Background(), &entity.APIKey{
+		ID:        "key-1",
+		Name:      "test",
+		KeyHash:   entity.HashAPIKey(rawKey),
+		Scopes:    []string{"refresh"},
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	limiter := NewRateLimitMiddleware(ratelimit.NewMemoryStore(), map[string]service.RateLimit{
+		RouteClassRefresh: {Burst: 100, Period: time.Minute},
+		RouteClassAuth:    {Burst: failures, Period: time.Hour},
+	}, nil)
+
+	route := RouteInfo{Method: http.MethodPost, Path: "/refresh", Scope: "refresh", Class: RouteClassRefresh}
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	middlewares := []RouteMiddleware{limiter.AuthFailures(), NewAuthMiddleware(keys, nil), limiter}
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i].Wrap(route, handler)
+	}
+
+	return handler, rawKey
+}
+
+// doWithKey envía una petición con la clave indicada desde la IP remoteIP.
+func doWithKey(handler http.Handler, key, remoteIP string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(http.MethodPost, "/refresh", nil)
+	request.RemoteAddr = remoteIP + ":1234"
+	if key != "" {
+		request.Header.Set(apiKeyHeader, key)
+	}
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestAuthFailuresBlockClientIP(t *testing.T) {
+	handler, validKey := newAuthFailureChain(t, 3)
+
+	for i := 0; i < 3; i++ {
+		if code := doWithKey(handler, "wrong-key", "192.0.2.1").Code; code != http.StatusUnauthorized {
+			t.Fatalf("attempt %d: status = %d, want 401", i+1, code)
+		}
+	}
+
+	// Agotado el bucket, la IP no puede seguir probando claves, ni siquiera válidas
+	recorder := doWithKey(handler, "wrong-key", "192.0.2.1")
+	if recorder.Code != http.StatusTooManyRequests {
+		t.Fatalf("status after failures = %d, want 429", recorder.Code)
+	}
+	if recorder.Header().Get("Retry-After") == "" {
+		t.Error("429 without Retry-After")
+	}
+	if code := doWithKey(handler, validKey, "192.0.2.1").Code; code != http.StatusTooManyRequests {
+		t.Errorf("valid key from blocked IP: status = %d, want 429", code)
+	}
+
+	// Otras IPs no se ven afectadas
+	if code := doWithKey(handler, validKey, "192.0.2.2").Code; code != http.StatusOK {
+		t.Errorf("valid key from other IP: status = %d, want 200", code)
+	}
+	if code := doWithKey(handler, "wrong-key", "192.0.2.2").Code; code != http.StatusUnauthorized {
+		t.Errorf("wrong key from other IP: status = %d, want 401", code)
+	}
+}
+
+func TestAuthFailuresIgnoreSuccessfulAndAnonymousRequests(t *testing.T) {
+	handler, validKey := newAuthFailureChain(t, 1)
+
+	for i := 0; i < 5; i++ {
+		if code := doWithKey(handler, validKey, "192.0.2.1").Code; code != http.StatusOK {
+			t.Fatalf("valid key attempt %d: status = %d, want 200", i+1, code)
+		}
+		// Sin clave la respuesta es 401, pero no prueba claves
+		if code := doWithKey(handler, "", "192.0.2.1").Code; code != http.StatusUnauthorized {
+			t.Fatalf("anonymous attempt %d: status = %d, want 401", i+1, code)
+		}
+	}
+
+	if code := doWithKey(handler, "wrong-key", "192.0.2.1").Code; code != http.StatusUnauthorized {
+		t.Fatalf("first failure: status = %d, want 401", code)
+	}
+	if code := doWithKey(handler, validKey, "192.0.2.1").Code; code != http.StatusTooManyRequests {
+		t.Errorf("after the only allowed failure: status = %d, want 429", code)
+	}
+}
+
+// newClassLimiter crea el límite por clase con lecturas y refrescos independientes,
+// confiando en los proxies de 10.0.0.0/8.
+func newClassLimiter(t *testing.T) *RateLimitMiddleware {
+	t.Helper()
+
+	proxies, err := ParseTrustedProxies([]string{"10.0.0.0/8", "2001:db8::1"})
+	if err != nil {
+		t.Fatalf("ParseTrustedProxies: %v", err)
+	}
+	return NewRateLimitMiddleware(ratelimit.NewMemoryStore(), map[string]service.RateLimit{
+		RouteClassRead:    {Burst: 2, Period: time.Minute},
+		RouteClassRefresh: {Burst: 1, Period: time.Hour},
+	}, proxies)
+}
+
+// wrapClass aplica el límite a un handler que responde 200 para la clase indicada.
+func wrapClass(limiter *RateLimitMiddleware, class string) http.Handler {
+	route := RouteInfo{Method: http.MethodGet, Path: "/" + class, Class: class}
+	return limiter.Wrap(route, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+}
+
+// doFrom envía una petición desde remoteAddr con la cabecera X-Forwarded-For indicada.
+func doFrom(handler http.Handler, remoteAddr, forwardedFor string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request.RemoteAddr = remoteAddr
+	if forwardedFor != "" {
+		request.Header.Set("X-Forwarded-For", forwardedFor)
+	}
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestRateLimitPerClass(t *testing.T) {
+	limiter := newClassLimiter(t)
+	read := wrapClass(limiter, RouteClassRead)
+	refresh := wrapClass(limiter, RouteClassRefresh)
+	unlimited := wrapClass(limiter, "health")
+
+	for i, wantRemaining := range []string{"1", "0"} {
+		recorder := doFrom(read, "192.0.2.1:1234", "")
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("read %d: status = %d, want 200", i+1, recorder.Code)
+		}
+		header := recorder.Header()
+		if header.Get("RateLimit-Limit") != "2" || header.Get("RateLimit-Remaining") != wantRemaining ||
+			header.Get("RateLimit-Policy") != "2;w=60" || header.Get("RateLimit-Reset") == "" {
+			t.Errorf("read %d headers = %v, want limit 2 and %s remaining", i+1, header, wantRemaining)
+		}
+	}
+
+	recorder := doFrom(read, "192.0.2.1:1234", "")
+	if recorder.Code != http.StatusTooManyRequests {
+		t.Fatalf("third read: status = %d, want 429", recorder.Code)
+	}
+	if retryAfter := recorder.Header().Get("Retry-After"); retryAfter != "30" {
+		t.Errorf("Retry-After = %q, want 30", retryAfter)
+	}
+	if reset := recorder.Header().Get("RateLimit-Reset"); reset != "60" {
+		t.Errorf("RateLimit-Reset = %q, want 60", reset)
+	}
+
+	var response APIResponse
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+		t.Fatalf("429 body is not an envelope: %v", err)
+	}
+	if response.Success || response.Message != "Rate limit exceeded" || !strings.Contains(response.Error, "read routes") {
+		t.Errorf("429 envelope = %+v", response)
+	}
+
+	// Cada clase tiene su propio bucket y las clases sin límite no se limitan
+	if code := doFrom(refresh, "192.0.2.1:1234", "").Code; code != http.StatusOK {
+		t.Errorf("refresh after exhausting reads: status = %d, want 200", code)
+	}
+	if code := doFrom(refresh, "192.0.2.1:1234", "").Code; code != http.StatusTooManyRequests {
+		t.Errorf("second refresh: status = %d, want 429", code)
+	}
+	for i := 0; i < 5; i++ {
+		if recorder := doFrom(unlimited, "192.0.2.1:1234", ""); recorder.Code != http.StatusOK || recorder.Header().Get("RateLimit-Limit") != "" {
+			t.Fatalf("unlimited route: status = %d, headers = %v", recorder.Code, recorder.Header())
+		}
+	}
+
+	// Otro cliente tiene su propio bucket
+	if code := doFrom(read, "192.0.2.2:1234", "").Code; code != http.StatusOK {
+		t.Errorf("read from another client: status = %d, want 200", code)
+	}
+}
+
+func TestRateLimitKeysAuthenticatedClients(t *testing.T) {
+	limiter := newClassLimiter(t)
+	refresh := wrapClass(limiter, RouteClassRefresh)
+
+	withKey := func(id, remoteAddr string) int {
+		request := httptest.NewRequest(http.MethodGet, "/", nil)
+		request.RemoteAddr = remoteAddr
+		key := &entity.APIKey{ID: id}
+		request = request.WithContext(context.WithValue(request.Context(), apiKeyContextKey{}, key))
+
+		recorder := httptest.NewRecorder()
+		refresh.ServeHTTP(recorder, request)
+		return recorder.Code
+	}
+
+	// La clave identifica al cliente aunque cambie de IP, y dos claves de la misma IP
+	// tienen buckets distintos
+	if code := withKey("key-1", "192.0.2.1:1"); code != http.StatusOK {
+		t.Fatalf("first request: status = %d", code)
+	}
+	if code := withKey("key-1", "192.0.2.9:1"); code != http.StatusTooManyRequests {
+		t.Errorf("same key from another IP: status = %d, want 429", code)
+	}
+	if code := withKey("key-2", "192.0.2.1:1"); code != http.StatusOK {
+		t.Errorf("another key from the same IP: status = %d, want 200", code)
+	}
+}
+
+func TestRateLimitClientIP(t *testing.T) {
+	limiter := newClassLimiter(t)
+
+	tests := []struct {
+		name         string
+		remoteAddr   string
+		forwardedFor string
+		want         string
+	}{
+		{name: "direct", remoteAddr: "192.0.2.1:1234", want: "192.0.2.1"},
+		{name: "untrusted peer ignores header", remoteAddr: "192.0.2.1:1234", forwardedFor: "198.51.100.7", want: "192.0.2.1"},
+		{name: "trusted proxy", remoteAddr: "10.0.0.1:1234", forwardedFor: "198.51.100.7", want: "198.51.100.7"},
+		{name: "spoofed leftmost entry", remoteAddr: "10.0.0.1:1234", forwardedFor: "203.0.113.9, 198.51.100.7", want: "198.51.100.7"},
+		{name: "chain of trusted proxies", remoteAddr: "10.0.0.1:1234", forwardedFor: "203.0.113.9, 198.51.100.7, 10.0.0.2, 10.1.0.3", want: "198.51.100.7"},
+		{name: "invalid hop stops the walk", remoteAddr: "10.0.0.1:1234", forwardedFor: "198.51.100.7, garbage, 10.0.0.2", want: "10.0.0.2"},
+		{name: "only trusted hops", remoteAddr: "10.0.0.1:1234", forwardedFor: "10.0.0.2", want: "10.0.0.2"},
+		{name: "trusted without header", remoteAddr: "10.0.0.1:1234", want: "10.0.0.1"},
+		{name: "ipv6 proxy", remoteAddr: "[2001:db8::1]:1234", forwardedFor: "2001:db8::7", want: "2001:db8::7"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, "/", nil)
+			request.RemoteAddr = tt.remoteAddr
+			if tt.forwardedFor != "" {
+				request.Header.Set("X-Forwarded-For", tt.forwardedFor)
+			}
+			if got := limiter.clientIP(request); got != tt.want {
+				t.Errorf("clientIP = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRateLimitSpoofedForwardedForSharesBucket(t *testing.T) {
+	limiter := newClassLimiter(t)
+	refresh := wrapClass(limiter, RouteClassRefresh)
+
+	// Cambiar la entrada de la izquierda no evade el límite del cliente real
+	if code := doFrom(refresh, "10.0.0.1:1234", "203.0.113.1, 198.51.100.7").Code; code != http.StatusOK {
+		t.Fatalf("first request: status = %d, want 200", code)
+	}
+	if code := doFrom(refresh, "10.0.0.1:1234", "203.0.113.2, 198.51.100.7").Code; code != http.StatusTooManyRequests {
+		t.Errorf("spoofed leftmost entry: status = %d, want 429", code)
+	}
+	if code := doFrom(refresh, "10.0.0.1:1234", "198.51.100.8").Code; code != http.StatusOK {
+		t.Errorf("another client behind the proxy: status = %d, want 200", code)
+	}
+}
+
+func TestParseTrustedProxiesRejectsInvalidValues(t *testing.T) {
+	for _, value := range []string{"proxy.local", "10.0.0.0/33", ""} {
+		if _, err := ParseTrustedProxies([]string{value}); err == nil {
+			t.Errorf("ParseTrustedProxies(%q) succeeded", value)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+// Package http implementa la propagación del ID de petición.
+package http
+
+import (
+	"net/http"
+
+	"gobcv/pkg/requestid"
+)
+
+// RequestIDMiddleware asigna un ID a cada petición, reutilizando el X-Request-ID
+// del cliente si es válido, lo propaga en el contexto y lo devuelve en la respuesta.
+func RequestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(requestid.Header)
+		if !requestid.IsValid(id) {
+			id = requestid.New()
+		}
+
+		w.Header().Set(requestid.Header, id)
+		next.ServeHTTP(w, r.WithContext(requestid.WithID(r.Context(), id)))
+	})
+}
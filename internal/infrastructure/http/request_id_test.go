@@ -0,0 +1,101 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"gobcv/internal/infrastructure/logging"
+	"gobcv/pkg/requestid"
+)
+
+func TestRequestIDMiddleware(t *testing.T) {
+	tests := []struct {
+		name     string
+		inbound  string
+		want     string
+		generate bool
+	}{
+		{name: "generated", generate: true},
+		{name: "echoed", inbound: "client-id_1.2:3", want: "client-id_1.2:3"},
+		{name: "longest accepted", inbound: strings.Repeat("a", 128), want: strings.Repeat("a", 128)},
+		{name: "oversized replaced", inbound: strings.Repeat("a", 129), generate: true},
+		{name: "unsafe characters replaced", inbound: "id\r\nSet-Cookie: x", generate: true},
+		{name: "spaces replaced", inbound: "two words", generate: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fromContext string
+			handler := RequestIDMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fromContext = requestid.FromContext(r.Context())
+			}))
+
+			request := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.inbound != "" {
+				request.Header.Set(requestid.Header, tt.inbound)
+			}
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+
+			id := recorder.Header().Get(requestid.Header)
+			if tt.generate {
+				if len(id) != 32 || id == tt.inbound || !requestid.IsValid(id) {
+					t.Errorf("%s = %q, want a generated 32-character ID", requestid.Header, id)
+				}
+			} else if id != tt.want {
+				t.Errorf("%s = %q, want %q", requestid.Header, id, tt.want)
+			}
+			if fromContext != id {
+				t.Errorf("context ID = %q, response ID = %q", fromContext, id)
+			}
+		})
+	}
+
+	// Cada petición sin ID recibe uno distinto
+	first, second := httptest.NewRecorder(), httptest.NewRecorder()
+	handler := RequestIDMiddleware(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	handler.ServeHTTP(first, httptest.NewRequest(http.MethodGet, "/", nil))
+	handler.ServeHTTP(second, httptest.NewRequest(http.MethodGet, "/", nil))
+	if first.Header().Get(requestid.Header) == second.Header().Get(requestid.Header) {
+		t.Error("two requests received the same generated ID")
+	}
+}
+
+func TestRequestIDReachesLogRecords(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := logging.New(&buf, logging.FormatJSON, "info")
+	if err != nil {
+		t.Fatalf("logging.New: %v", err)
+	}
+
+	handler := RequestIDMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logger.InfoContext(r.Context(), "Consultando monedas")
+		// Sin contexto el registro no tiene ID de petición
+		logger.Info("Sin contexto")
+	}))
+
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request.Header.Set(requestid.Header, "trace-me")
+	handler.ServeHTTP(httptest.NewRecorder(), request)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("log output = %q, want 2 records", buf.String())
+	}
+
+	var record map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &record); err != nil {
+		t.Fatalf("record is not JSON: %v", err)
+	}
+	if record["request_id"] != "trace-me" || record[slog.MessageKey] != "Consultando monedas" {
+		t.Errorf("record = %v, want request_id trace-me", record)
+	}
+	if strings.Contains(lines[1], "request_id") {
+		t.Errorf("record without context has a request_id: %s", lines[1])
+	}
+}
@@ -2,54 +2,705 @@
 package http
 
 import (
+	"encoding/json"
 	"net/http"
+	"slices"
+	"strings"
 
 	"github.com/gorilla/mux"
+
+	"gobcv/internal/domain/entity"
 )
 
-// SetupRouter configura todas las rutas de la API.
-func SetupRouter(handlers *Handlers) *mux.Router {
+// apiPrefix es el prefijo común de las rutas de la API.
+const apiPrefix = "/api/v1"
+
+// apiRoute describe un endpoint de la API junto con su documentación OpenAPI.
+// Las rutas se registran en el router y en la especificación desde la misma tabla;
+// path es la plantilla completa, con el prefijo de la API cuando corresponde.
+type apiRoute struct {
+	method    string
+	path      string
+	scope     string
+	class     string
+	handler   http.HandlerFunc
+	operation *Operation
+}
+
+// RouteInfo describe una ruta de la API para los middlewares por ruta.
+type RouteInfo struct {
+	Method  string
+	Path    string // Plantilla sin expresiones regulares: /api/v1/currencies/{id}
+	Scope   string
+	Class   string
+	Methods []string // Métodos registrados en la misma ruta, para Allow y preflight CORS
+}
+
+// RouteMiddleware envuelve el handler de cada ruta conociendo su descripción.
+type RouteMiddleware interface {
+	Wrap(route RouteInfo, next http.Handler) http.Handler
+}
+
+// RouterOptions configura las rutas del router que dependen de otros componentes.
+type RouterOptions struct {
+	// MetricsPath es la ruta en la que se sirve MetricsHandler; sin handler no se expone.
+	MetricsPath    string
+	MetricsHandler http.Handler
+
+	// AuthEnabled indica si las rutas con scope exigen una clave de API; la especificación
+	// solo declara seguridad en ese caso, y sin ella no se montan las rutas de gestión.
+	// AnonymousScopes son los scopes sin clave.
+	AuthEnabled     bool
+	AnonymousScopes []string
+}
+
+// apiRoutes retorna la tabla de rutas de la API, incluyendo las que no llevan el prefijo.
+func (h *Handlers) apiRoutes(options RouterOptions) []apiRoute {
+	routes := []apiRoute{
+		{
+			method:  http.MethodGet,
+			path:    "/",
+			handler: h.APIIndex,
+			operation: &Operation{
+				OperationID: "apiIndex",
+				Summary:     "Resumen de los endpoints de la API",
+				Tags:        []string{"docs"},
+				Responses:   map[string]*Response{"200": jsonResponse("Resumen de la API", nil)},
+			},
+		},
+		{
+			method:  http.MethodGet,
+			path:    apiPrefix + "/health",
+			handler: h.Liveness,
+			operation: &Operation{
+				OperationID: "healthCheck",
+				Summary:     "Verificación de salud del servicio (equivale a /health/live)",
+				Tags:        []string{"health"},
+				Responses:   map[string]*Response{"200": jsonResponse("Servicio disponible", nil)},
+			},
+		},
+		{
+			method:  http.MethodGet,
+			path:    apiPrefix + "/health/live",
+			handler: h.Liveness,
+			operation: &Operation{
+				OperationID: "liveness",
+				Summary:     "Sonda de liveness",
+				Tags:        []string{"health"},
+				Responses:   map[string]*Response{"200": jsonResponse("El proceso atiende peticiones", nil)},
+			},
+		},
+		{
+			method:  http.MethodGet,
+			path:    apiPrefix + "/health/ready",
+			handler: h.Readiness,
+			operation: &Operation{
+				OperationID: "readiness",
+				Summary:     "Sonda de readiness con el estado de cada componente",
+				Tags:        []string{"health"},
+				Responses: map[string]*Response{
+					"200": jsonResponse("Listo para atender tráfico", schemaRef("HealthReport")),
+					"503": jsonResponse("Algún componente crítico no está disponible", schemaRef("HealthReport")),
+				},
+			},
+		},
+		{
+			method:  http.MethodGet,
+			path:    apiPrefix + "/currencies",
+			scope:   entity.ScopeRead,
+			class:   RouteClassRead,
+			handler: h.GetAllCurrencies,
+			operation: &Operation{
+				OperationID: "getAllCurrencies",
+				Summary:     "Obtener todas las monedas",
+				Tags:        []string{"currencies"},
+				Parameters:  []Parameter{cacheParam, includeStaleParam, formatParam},
+				Responses: map[string]*Response{
+					"200": negotiatedResponse("Listado de monedas", schemaRef("CurrenciesData")),
+					"304": {Description: "El listado no cambió desde la versión indicada por el cliente"},
+					"406": jsonResponse("Formato solicitado no soportado", nil),
+					"500": jsonResponse("Error obteniendo las monedas", nil),
+				},
+			},
+		},
+		{
+			method:  http.MethodGet,
+			path:    apiPrefix + "/currencies/stream",
+			scope:   entity.ScopeRead,
+			class:   RouteClassRead,
+			handler: h.StreamCurrencies,
+			operation: &Operation{
+				OperationID: "streamCurrencies",
+				Summary:     "Stream de cambios de tasas (Server-Sent Events)",
+				Description: "Envía un evento rate por cada cambio de tasa guardado, con su secuencia como id. " +
+					"Los comentarios heartbeat mantienen viva la conexión. Con Last-Event-ID se reanuda desde el historial.",
+				Tags:       []string{"currencies"},
+				Parameters: []Parameter{currenciesParam, snapshotParam, lastEventIDParam},
+				Responses: map[string]*Response{
+					"200": {
+						Description: "Flujo de eventos rate y snapshot",
+						Content:     map[string]MediaType{"text/event-stream": {Schema: schemaRef("RateChange")}},
+					},
+					"400": jsonResponse("Last-Event-ID inválido", nil),
+				},
+			},
+		},
+		{
+			method:  http.MethodGet,
+			path:    apiPrefix + "/currencies/ws",
+			scope:   entity.ScopeRead,
+			class:   RouteClassRead,
+			handler: h.SubscribeCurrencies,
+			operation: &Operation{
+				OperationID: "subscribeCurrencies",
+				Summary:     "Suscripción WebSocket a cambios de tasas",
+				Description: "Protocolo JSON: el cliente envía {\"type\":\"subscribe|unsubscribe|snapshot|ping\",\"currencies\":[...]} " +
+					"y recibe subscribed, unsubscribed, snapshot, pong y un mensaje rate por cada cambio suscrito.",
+				Tags: []string{"currencies"},
+				Responses: map[string]*Response{
+					"101": {Description: "Conexión actualizada a WebSocket"},
+					"400": {Description: "La petición no es un handshake WebSocket válido"},
+					"403": {Description: "Origen no permitido"},
+					"503": jsonResponse("El servidor se está apagando", nil),
+				},
+			},
+		},
+		{
+			method:  http.MethodGet,
+			path:    apiPrefix + "/currencies/convert",
+			scope:   entity.ScopeRead,
+			class:   RouteClassRead,
+			handler: h.ConvertCurrency,
+			operation: &Operation{
+				OperationID: "convertCurrency",
+				Summary:     "Convertir un monto entre dos monedas (incluido VES)",
+				Tags:        []string{"currencies"},
+				Parameters:  []Parameter{convertFromParam, convertToParam, amountParam, cacheParam, formatParam},
+				Responses: map[string]*Response{
+					"200": negotiatedResponse("Resultado de la conversión", schemaRef("Conversion")),
+					"400": negotiatedResponse("Monedas o monto inválidos", nil),
+					"404": negotiatedResponse("Moneda no encontrada", nil),
+					"406": jsonResponse("Formato solicitado no soportado", nil),
+					"500": negotiatedResponse("Error obteniendo las tasas", nil),
+				},
+			},
+		},
+		{
+			method:  http.MethodGet,
+			path:    apiPrefix + "/currencies/{id:[A-Z]{3}}",
+			scope:   entity.ScopeRead,
+			class:   RouteClassRead,
+			handler: h.GetCurrency,
+			operation: &Operation{
+				OperationID: "getCurrency",
+				Summary:     "Obtener una moneda específica (EUR, USD)",
+				Tags:        []string{"currencies"},
+				Parameters:  []Parameter{currencyIDParam, cacheParam, formatParam},
+				Responses: map[string]*Response{
+					"200": negotiatedResponse("Moneda solicitada", schemaRef("CurrencyData")),
+					"304": {Description: "La moneda no cambió desde la versión indicada por el cliente"},
+					"404": negotiatedResponse("Moneda no encontrada", nil),
+					"406": jsonResponse("Formato solicitado no soportado", nil),
+					"500": jsonResponse("Error obteniendo la moneda", nil),
+				},
+			},
+		},
+		{
+			method:  http.MethodGet,
+			path:    apiPrefix + "/currencies/{id:[A-Z]{3}}/history",
+			scope:   entity.ScopeRead,
+			class:   RouteClassRead,
+			handler: h.GetCurrencyHistory,
+			operation: &Operation{
+				OperationID: "getCurrencyHistory",
+				Summary:     "Cambios de una moneda en un rango de fechas",
+				Tags:        []string{"currencies"},
+				Parameters:  []Parameter{currencyIDParam, historyFromParam, historyToParam, formatParam},
+				Responses: map[string]*Response{
+					"200": negotiatedResponse("Cambios registrados en el rango", schemaRef("RateHistory")),
+					"400": negotiatedResponse("Rango inválido", nil),
+					"406": jsonResponse("Formato solicitado no soportado", nil),
+					"500": negotiatedResponse("Error obteniendo el historial", nil),
+				},
+			},
+		},
+		{
+			method:  http.MethodPost,
+			path:    apiPrefix + "/currencies/refresh",
+			scope:   entity.ScopeRefresh,
+			class:   RouteClassRefresh,
+			handler: h.RefreshCurrencies,
+			operation: &Operation{
+				OperationID: "refreshCurrencies",
+				Summary:     "Actualizar monedas desde BCV",
+				Tags:        []string{"currencies"},
+				Parameters:  []Parameter{forceParam},
+				Responses: map[string]*Response{
+					"200": jsonResponse("Resultado de la actualización", schemaRef("RefreshResult")),
+					"500": jsonResponse("Error actualizando las monedas", schemaRef("RefreshResult")),
+				},
+			},
+		},
+		{
+			method:  http.MethodGet,
+			path:    apiPrefix + "/graphql",
+			scope:   entity.ScopeRead,
+			class:   RouteClassRead,
+			handler: h.GraphQL,
+			operation: &Operation{
+				OperationID: "graphqlQuery",
+				Summary:     "Consulta GraphQL por GET, o suscripción por WebSocket (graphql-transport-ws)",
+				Description: "Esquema con currencies, currency, history y convert, y la suscripción rateChanged. " +
+					"Las suscripciones usan el subprotocolo graphql-transport-ws. Las consultas que superan la " +
+					"profundidad o complejidad máximas se rechazan con el código QUERY_TOO_COMPLEX.",
+				Tags:       []string{"graphql"},
+				Parameters: []Parameter{graphqlQueryParam, graphqlOperationNameParam, graphqlVariablesParam},
+				Responses: map[string]*Response{
+					"200": graphqlResponse("Resultado de la operación, con sus errores si los hubo"),
+					"101": {Description: "Conexión actualizada a WebSocket con graphql-transport-ws"},
+					"400": graphqlResponse("Petición GraphQL mal formada"),
+					"503": jsonResponse("El servidor se está apagando", nil),
+				},
+			},
+		},
+		{
+			method:  http.MethodPost,
+			path:    apiPrefix + "/graphql",
+			scope:   entity.ScopeRead,
+			class:   RouteClassRead,
+			handler: h.GraphQL,
+			operation: &Operation{
+				OperationID: "graphqlExecute",
+				Summary:     "Ejecutar una consulta GraphQL",
+				Description: "Acepta application/json con query, operationName y variables, o el documento como application/graphql.",
+				Tags:        []string{"graphql"},
+				RequestBody: graphqlRequestBody,
+				Responses: map[string]*Response{
+					"200": graphqlResponse("Resultado de la operación, con sus errores si los hubo"),
+					"400": graphqlResponse("Petición GraphQL mal formada"),
+				},
+			},
+		},
+		{
+			method:  http.MethodGet,
+			path:    "/graphql",
+			scope:   entity.ScopeRead,
+			class:   RouteClassRead,
+			handler: h.GraphQL,
+			operation: &Operation{
+				OperationID: "graphqlQueryRoot",
+				Summary:     "Consulta o suscripción GraphQL (equivale a GET /api/v1/graphql)",
+				Tags:        []string{"graphql"},
+				Parameters:  []Parameter{graphqlQueryParam, graphqlOperationNameParam, graphqlVariablesParam},
+				Responses: map[string]*Response{
+					"200": graphqlResponse("Resultado de la operación, con sus errores si los hubo"),
+					"101": {Description: "Conexión actualizada a WebSocket con graphql-transport-ws"},
+					"400": graphqlResponse("Petición GraphQL mal formada"),
+					"503": jsonResponse("El servidor se está apagando", nil),
+				},
+			},
+		},
+		{
+			method:  http.MethodPost,
+			path:    "/graphql",
+			scope:   entity.ScopeRead,
+			class:   RouteClassRead,
+			handler: h.GraphQL,
+			operation: &Operation{
+				OperationID: "graphqlExecuteRoot",
+				Summary:     "Ejecutar una consulta GraphQL (equivale a POST /api/v1/graphql)",
+				Tags:        []string{"graphql"},
+				RequestBody: graphqlRequestBody,
+				Responses: map[string]*Response{
+					"200": graphqlResponse("Resultado de la operación, con sus errores si los hubo"),
+					"400": graphqlResponse("Petición GraphQL mal formada"),
+				},
+			},
+		},
+		{
+			method:  http.MethodGet,
+			path:    apiPrefix + "/webhooks",
+			scope:   entity.ScopeAdmin,
+			class:   RouteClassRead,
+			handler: h.ListWebhooks,
+			operation: &Operation{
+				OperationID: "listWebhooks",
+				Summary:     "Listar webhooks",
+				Tags:        []string{"webhooks"},
+				Responses: map[string]*Response{
+					"200": jsonResponse("Webhooks registrados", &Schema{Type: "array", Items: schemaRef("Webhook")}),
+					"500": jsonResponse("Error obteniendo los webhooks", nil),
+				},
+			},
+		},
+		{
+			method:  http.MethodPost,
+			path:    apiPrefix + "/webhooks",
+			scope:   entity.ScopeAdmin,
+			class:   RouteClassRead,
+			handler: h.CreateWebhook,
+			operation: &Operation{
+				OperationID: "createWebhook",
+				Summary:     "Registrar un webhook de cambios de tasas",
+				Tags:        []string{"webhooks"},
+				RequestBody: jsonRequestBody("Webhook a registrar", schemaRef("WebhookInput")),
+				Responses: map[string]*Response{
+					"201": jsonResponse("Webhook registrado, con su secreto de firma", schemaRef("Webhook")),
+					"400": jsonResponse("Webhook inválido", nil),
+					"500": jsonResponse("Error registrando el webhook", nil),
+				},
+			},
+		},
+		{
+			method:  http.MethodGet,
+			path:    apiPrefix + "/webhooks/{id}",
+			scope:   entity.ScopeAdmin,
+			class:   RouteClassRead,
+			handler: h.GetWebhook,
+			operation: &Operation{
+				OperationID: "getWebhook",
+				Summary:     "Obtener un webhook",
+				Tags:        []string{"webhooks"},
+				Parameters:  []Parameter{webhookIDParam},
+				Responses: map[string]*Response{
+					"200": jsonResponse("Webhook solicitado", schemaRef("Webhook")),
+					"404": jsonResponse("Webhook no encontrado", nil),
+					"500": jsonResponse("Error obteniendo el webhook", nil),
+				},
+			},
+		},
+		{
+			method:  http.MethodPatch,
+			path:    apiPrefix + "/webhooks/{id}",
+			scope:   entity.ScopeAdmin,
+			class:   RouteClassRead,
+			handler: h.UpdateWebhook,
+			operation: &Operation{
+				OperationID: "updateWebhook",
+				Summary:     "Modificar un webhook",
+				Tags:        []string{"webhooks"},
+				Parameters:  []Parameter{webhookIDParam},
+				RequestBody: jsonRequestBody("Campos a modificar", schemaRef("WebhookInput")),
+				Responses: map[string]*Response{
+					"200": jsonResponse("Webhook modificado", schemaRef("Webhook")),
+					"400": jsonResponse("Webhook inválido", nil),
+					"404": jsonResponse("Webhook no encontrado", nil),
+					"500": jsonResponse("Error modificando el webhook", nil),
+				},
+			},
+		},
+		{
+			method:  http.MethodDelete,
+			path:    apiPrefix + "/webhooks/{id}",
+			scope:   entity.ScopeAdmin,
+			class:   RouteClassRead,
+			handler: h.DeleteWebhook,
+			operation: &Operation{
+				OperationID: "deleteWebhook",
+				Summary:     "Eliminar un webhook",
+				Tags:        []string{"webhooks"},
+				Parameters:  []Parameter{webhookIDParam},
+				Responses: map[string]*Response{
+					"200": jsonResponse("Webhook eliminado", nil),
+					"404": jsonResponse("Webhook no encontrado", nil),
+					"500": jsonResponse("Error eliminando el webhook", nil),
+				},
+			},
+		},
+		{
+			method:  http.MethodGet,
+			path:    apiPrefix + "/webhooks/{id}/deliveries",
+			scope:   entity.ScopeAdmin,
+			class:   RouteClassRead,
+			handler: h.GetWebhookDeliveries,
+			operation: &Operation{
+				OperationID: "getWebhookDeliveries",
+				Summary:     "Registro de entregas de un webhook, de la más reciente a la más antigua",
+				Tags:        []string{"webhooks"},
+				Parameters:  []Parameter{webhookIDParam, limitParam},
+				Responses: map[string]*Response{
+					"200": jsonResponse("Intentos de entrega", &Schema{Type: "array", Items: schemaRef("WebhookDelivery")}),
+					"404": jsonResponse("Webhook no encontrado", nil),
+					"500": jsonResponse("Error obteniendo las entregas", nil),
+				},
+			},
+		},
+		{
+			method:  http.MethodGet,
+			path:    apiPrefix + "/alerts/rules",
+			scope:   entity.ScopeAdmin,
+			class:   RouteClassRead,
+			handler: h.ListAlertRules,
+			operation: &Operation{
+				OperationID: "listAlertRules",
+				Summary:     "Listar reglas de alerta",
+				Tags:        []string{"alerts"},
+				Responses: map[string]*Response{
+					"200": jsonResponse("Reglas registradas", &Schema{Type: "array", Items: schemaRef("AlertRule")}),
+					"500": jsonResponse("Error obteniendo las reglas", nil),
+				},
+			},
+		},
+		{
+			method:  http.MethodPost,
+			path:    apiPrefix + "/alerts/rules",
+			scope:   entity.ScopeAdmin,
+			class:   RouteClassRead,
+			handler: h.CreateAlertRule,
+			operation: &Operation{
+				OperationID: "createAlertRule",
+				Summary:     "Registrar una regla de alerta",
+				Tags:        []string{"alerts"},
+				RequestBody: jsonRequestBody("Regla a registrar", schemaRef("AlertRuleInput")),
+				Responses: map[string]*Response{
+					"201": jsonResponse("Regla registrada", schemaRef("AlertRule")),
+					"400": jsonResponse("Regla inválida", nil),
+					"500": jsonResponse("Error registrando la regla", nil),
+				},
+			},
+		},
+		{
+			method:  http.MethodGet,
+			path:    apiPrefix + "/alerts/rules/{id}",
+			scope:   entity.ScopeAdmin,
+			class:   RouteClassRead,
+			handler: h.GetAlertRule,
+			operation: &Operation{
+				OperationID: "getAlertRule",
+				Summary:     "Obtener una regla de alerta",
+				Tags:        []string{"alerts"},
+				Parameters:  []Parameter{alertRuleIDParam},
+				Responses: map[string]*Response{
+					"200": jsonResponse("Regla solicitada", schemaRef("AlertRule")),
+					"404": jsonResponse("Regla no encontrada", nil),
+					"500": jsonResponse("Error obteniendo la regla", nil),
+				},
+			},
+		},
+		{
+			method:  http.MethodPatch,
+			path:    apiPrefix + "/alerts/rules/{id}",
+			scope:   entity.ScopeAdmin,
+			class:   RouteClassRead,
+			handler: h.UpdateAlertRule,
+			operation: &Operation{
+				OperationID: "updateAlertRule",
+				Summary:     "Modificar una regla de alerta",
+				Tags:        []string{"alerts"},
+				Parameters:  []Parameter{alertRuleIDParam},
+				RequestBody: jsonRequestBody("Campos a modificar", schemaRef("AlertRuleInput")),
+				Responses: map[string]*Response{
+					"200": jsonResponse("Regla modificada", schemaRef("AlertRule")),
+					"400": jsonResponse("Regla inválida", nil),
+					"404": jsonResponse("Regla no encontrada", nil),
+					"500": jsonResponse("Error modificando la regla", nil),
+				},
+			},
+		},
+		{
+			method:  http.MethodDelete,
+			path:    apiPrefix + "/alerts/rules/{id}",
+			scope:   entity.ScopeAdmin,
+			class:   RouteClassRead,
+			handler: h.DeleteAlertRule,
+			operation: &Operation{
+				OperationID: "deleteAlertRule",
+				Summary:     "Eliminar una regla de alerta",
+				Tags:        []string{"alerts"},
+				Parameters:  []Parameter{alertRuleIDParam},
+				Responses: map[string]*Response{
+					"200": jsonResponse("Regla eliminada", nil),
+					"404": jsonResponse("Regla no encontrada", nil),
+					"500": jsonResponse("Error eliminando la regla", nil),
+				},
+			},
+		},
+		{
+			method:  http.MethodGet,
+			path:    apiPrefix + "/alerts",
+			scope:   entity.ScopeAdmin,
+			class:   RouteClassRead,
+			handler: h.GetAlerts,
+			operation: &Operation{
+				OperationID: "getAlerts",
+				Summary:     "Alertas disparadas, de la más reciente a la más antigua",
+				Tags:        []string{"alerts"},
+				Parameters:  []Parameter{limitParam},
+				Responses: map[string]*Response{
+					"200": jsonResponse("Alertas disparadas", &Schema{Type: "array", Items: schemaRef("Alert")}),
+					"500": jsonResponse("Error obteniendo las alertas", nil),
+				},
+			},
+		},
+		{
+			method:  http.MethodGet,
+			path:    apiPrefix + "/cache/stats",
+			scope:   entity.ScopeAdmin,
+			class:   RouteClassRead,
+			handler: h.GetCacheStats,
+			operation: &Operation{
+				OperationID: "getCacheStats",
+				Summary:     "Estadísticas del caché",
+				Tags:        []string{"cache"},
+				Responses: map[string]*Response{
+					"200": jsonResponse("Estadísticas del caché", schemaRef("CacheStats")),
+					"500": jsonResponse("Error obteniendo las estadísticas", nil),
+				},
+			},
+		},
+		{
+			method:  http.MethodGet,
+			path:    apiPrefix + "/openapi.json",
+			handler: h.OpenAPISpec,
+			operation: &Operation{
+				OperationID: "getOpenAPISpec",
+				Summary:     "Especificación OpenAPI de la API",
+				Tags:        []string{"docs"},
+				Responses: map[string]*Response{
+					"200": {
+						Description: "Documento OpenAPI 3",
+						Content:     map[string]MediaType{"application/json": {Schema: &Schema{Type: "object"}}},
+					},
+				},
+			},
+		},
+	}
+
+	if options.MetricsHandler != nil {
+		routes = append(routes, apiRoute{
+			method:  http.MethodGet,
+			path:    options.MetricsPath,
+			handler: options.MetricsHandler.ServeHTTP,
+			operation: &Operation{
+				OperationID: "metrics",
+				Summary:     "Métricas en formato Prometheus",
+				Tags:        []string{"observability"},
+				Responses: map[string]*Response{
+					"200": {
+						Description: "Métricas en formato de exposición de texto",
+						Content:     map[string]MediaType{"text/plain": {Schema: &Schema{Type: "string"}}},
+					},
+				},
+			},
+		})
+	}
+
+	if !options.AuthEnabled {
+		routes = slices.DeleteFunc(routes, isManagementRoute)
+	}
+
+	return routes
+}
+
+// managementPaths son los prefijos de las rutas que registran destinos de notificaciones.
+// Sin autenticación no se montan: cualquier cliente podría hacer que el servidor envíe
+// peticiones a destinos arbitrarios.
+var managementPaths = []string{apiPrefix + "/webhooks", apiPrefix + "/alerts"}
+
+// isManagementRoute verifica si la ruta administra webhooks o alertas.
+func isManagementRoute(route apiRoute) bool {
+	for _, prefix := range managementPaths {
+		if route.path == prefix || strings.HasPrefix(route.path, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// SetupRouter configura todas las rutas de la API. Los middlewares por ruta se
+// aplican en orden, de modo que el primero es el más externo.
+func SetupRouter(handlers *Handlers, options RouterOptions, middlewares ...RouteMiddleware) *mux.Router {
 	router := mux.NewRouter()
 
 	// Aplicar middlewares
-	router.Use(handlers.CORSMiddleware)
+	router.Use(RequestIDMiddleware)
 	router.Use(handlers.LoggingMiddleware)
 
-	// API v1 routes
-	api := router.PathPrefix("/api/v1").Subrouter()
-
-	// Health check
-	api.HandleFunc("/health", handlers.HealthCheck).Methods("GET")
-
-	// Currency endpoints
-	api.HandleFunc("/currencies", handlers.GetAllCurrencies).Methods("GET")
-	api.HandleFunc("/currencies/{id:[A-Z]{3}}", handlers.GetCurrency).Methods("GET")
-	api.HandleFunc("/currencies/refresh", handlers.RefreshCurrencies).Methods("POST")
-
-	// Cache endpoints
-	api.HandleFunc("/cache/stats", handlers.GetCacheStats).Methods("GET")
-
-	// Documentación básica en la raíz
-	router.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "application/json")
-		w.Write([]byte(`{
-			"service": "BCV Currency API",
-			"version": "1.0.0",
-			"description": "API para obtener tipos de cambio del Banco Central de Venezuela",
-			"endpoints": {
-				"GET /api/v1/health": "Verificación de salud del servicio",
-				"GET /api/v1/currencies": "Obtener todas las monedas",
-				"GET /api/v1/currencies/{id}": "Obtener una moneda específica (EUR, USD)",
-				"POST /api/v1/currencies/refresh": "Actualizar monedas desde BCV",
-				"GET /api/v1/cache/stats": "Estadísticas del caché"
-			},
-			"parameters": {
-				"cache": "false para omitir caché (por defecto: true)",
-				"include_stale": "true para incluir monedas obsoletas (por defecto: false)",
-				"force": "true para forzar actualización (en refresh, por defecto: false)"
-			}
-		}`))
-	}).Methods("GET")
+	routes := handlers.apiRoutes(options)
+
+	// Agrupar los métodos de cada ruta para responder OPTIONS
+	var paths []string
+	methodsByPath := make(map[string][]string)
+	for _, route := range routes {
+		if _, exists := methodsByPath[route.path]; !exists {
+			paths = append(paths, route.path)
+		}
+		methodsByPath[route.path] = append(methodsByPath[route.path], route.method)
+	}
+
+	wrap := func(info RouteInfo, handler http.Handler) http.Handler {
+		for i := len(middlewares) - 1; i >= 0; i-- {
+			handler = middlewares[i].Wrap(info, handler)
+		}
+		return handler
+	}
+
+	for _, route := range routes {
+		info := RouteInfo{
+			Method:  route.method,
+			Path:    openAPIPath(route.path),
+			Scope:   route.scope,
+			Class:   route.class,
+			Methods: methodsByPath[route.path],
+		}
+		router.Handle(route.path, wrap(info, route.handler)).Methods(route.method)
+	}
+
+	// OPTIONS responde con los métodos reales de la ruta; los preflight CORS los resuelve el middleware
+	for _, path := range paths {
+		info := RouteInfo{Method: http.MethodOptions, Path: openAPIPath(path), Methods: methodsByPath[path]}
+		router.Handle(path, wrap(info, allowHandler(info.Methods))).Methods(http.MethodOptions)
+	}
+
+	handlers.openAPI = BuildOpenAPI(routes, options)
 
 	return router
 }
+
+// allowHandler responde OPTIONS con la cabecera Allow.
+func allowHandler(methods []string) http.Handler {
+	allow := strings.Join(append([]string{http.MethodOptions}, methods...), ", ")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Allow", allow)
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// OpenAPI retorna la especificación generada por SetupRouter.
+func (h *Handlers) OpenAPI() *OpenAPIDocument {
+	return h.openAPI
+}
+
+// OpenAPISpec maneja el endpoint que sirve la especificación OpenAPI.
+func (h *Handlers) OpenAPISpec(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(h.openAPI)
+}
+
+// APIIndex maneja la raíz con un resumen de los endpoints documentados.
+func (h *Handlers) APIIndex(w http.ResponseWriter, r *http.Request) {
+	endpoints := make(map[string]string)
+	parameters := make(map[string]string)
+
+	for path, operations := range h.openAPI.Paths {
+		for method, operation := range operations {
+			endpoints[strings.ToUpper(method)+" "+path] = operation.Summary
+			for _, parameter := range operation.Parameters {
+				if parameter.In == "query" {
+					parameters[parameter.Name] = parameter.Description
+				}
+			}
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"service":     h.openAPI.Info.Title,
+		"version":     h.openAPI.Info.Version,
+		"description": h.openAPI.Info.Description,
+		"openapi":     apiPrefix + "/openapi.json",
+		"endpoints":   endpoints,
+		"parameters":  parameters,
+	})
+}
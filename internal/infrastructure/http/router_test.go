@@ -0,0 +1,206 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+// newTestHandlers crea handlers sin dependencias, suficientes para registrar las rutas.
+func newTestHandlers() *Handlers {
+	return NewHandlers(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+}
+
+// testRouterOptions registra las rutas que el servidor agrega fuera del prefijo de la API
+// y, con la autenticación habilitada, las de gestión.
+func testRouterOptions() RouterOptions {
+	return RouterOptions{
+		MetricsPath:    "/metrics",
+		MetricsHandler: http.NotFoundHandler(),
+		AuthEnabled:    true,
+	}
+}
+
+// registeredRoutes recorre el router y retorna cada "MÉTODO /ruta" registrado, sin OPTIONS.
+func registeredRoutes(t *testing.T, router *mux.Router) map[string]bool {
+	t.Helper()
+
+	routes := make(map[string]bool)
+	err := router.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		template, err := route.GetPathTemplate()
+		if err != nil {
+			return nil
+		}
+
+		methods, err := route.GetMethods()
+		if err != nil {
+			t.Errorf("route %s has no methods", template)
+			return nil
+		}
+
+		for _, method := range methods {
+			if method != http.MethodOptions {
+				routes[method+" "+openAPIPath(template)] = true
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Walk: %v", err)
+	}
+
+	return routes
+}
+
+// documentedRoutes retorna cada "MÉTODO /ruta" de la especificación.
+func documentedRoutes(doc *OpenAPIDocument) map[string]bool {
+	routes := make(map[string]bool)
+	for path, operations := range doc.Paths {
+		for method := range operations {
+			routes[strings.ToUpper(method)+" "+path] = true
+		}
+	}
+	return routes
+}
+
+func TestOpenAPIDocumentsEveryRoute(t *testing.T) {
+	handlers := newTestHandlers()
+	router := SetupRouter(handlers, testRouterOptions())
+
+	registered := registeredRoutes(t, router)
+	documented := documentedRoutes(handlers.OpenAPI())
+
+	var missing, unrouted []string
+	for route := range registered {
+		if !documented[route] {
+			missing = append(missing, route)
+		}
+	}
+	for route := range documented {
+		if !registered[route] {
+			unrouted = append(unrouted, route)
+		}
+	}
+	sort.Strings(missing)
+	sort.Strings(unrouted)
+
+	if len(missing) > 0 {
+		t.Fatalf("routes missing from the OpenAPI spec: %s", strings.Join(missing, ", "))
+	}
+	if len(unrouted) > 0 {
+		t.Fatalf("OpenAPI operations without a route: %s", strings.Join(unrouted, ", "))
+	}
+
+	for _, route := range []string{"GET /", "GET /metrics", "GET /api/v1/currencies", "GET /api/v1/openapi.json", "GET /graphql", "POST /graphql"} {
+		if !registered[route] {
+			t.Errorf("%s is not registered", route)
+		}
+	}
+}
+
+func TestOpenAPIOperationIDsAreUnique(t *testing.T) {
+	handlers := newTestHandlers()
+	SetupRouter(handlers, testRouterOptions())
+
+	seen := make(map[string]string)
+	for path, operations := range handlers.OpenAPI().Paths {
+		for method, operation := range operations {
+			route := strings.ToUpper(method) + " " + path
+			if operation.OperationID == "" {
+				t.Errorf("%s has no operationId", route)
+			}
+			if other, ok := seen[operation.OperationID]; ok {
+				t.Errorf("operationId %q is used by %s and %s", operation.OperationID, other, route)
+			}
+			seen[operation.OperationID] = route
+		}
+	}
+}
+
+func TestRouterServesOptionsWithAllow(t *testing.T) {
+	router := SetupRouter(newTestHandlers(), testRouterOptions())
+
+	request, _ := http.NewRequest(http.MethodOptions, "/api/v1/webhooks", nil)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	if recorder.Code != http.StatusNoContent {
+		t.Fatalf("status = %d, want 204", recorder.Code)
+	}
+	if allow := recorder.Header().Get("Allow"); allow != "OPTIONS, GET, POST" {
+		t.Errorf("Allow = %q, want %q", allow, "OPTIONS, GET, POST")
+	}
+}
+
+func TestManagementRoutesRequireAuth(t *testing.T) {
+	options := testRouterOptions()
+	options.AuthEnabled = false
+	handlers := newTestHandlers()
+	router := SetupRouter(handlers, options)
+
+	registered := registeredRoutes(t, router)
+	for _, route := range []string{"GET /api/v1/webhooks", "POST /api/v1/webhooks", "DELETE /api/v1/webhooks/{id}", "POST /api/v1/alerts/rules", "GET /api/v1/alerts"} {
+		if registered[route] {
+			t.Errorf("%s is mounted without auth", route)
+		}
+	}
+	if !registered["GET /api/v1/currencies"] {
+		t.Error("GET /api/v1/currencies is not mounted without auth")
+	}
+
+	for path := range handlers.OpenAPI().Paths {
+		if strings.HasPrefix(path, "/api/v1/webhooks") || strings.HasPrefix(path, "/api/v1/alerts") {
+			t.Errorf("%s is documented without auth", path)
+		}
+	}
+
+	request := httptest.NewRequest(http.MethodPost, "/api/v1/webhooks", strings.NewReader(`{"url":"http://127.0.0.1/"}`))
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusNotFound {
+		t.Errorf("POST /api/v1/webhooks without auth: status = %d, want 404", recorder.Code)
+	}
+}
+
+func TestOpenAPISecurityFollowsAuth(t *testing.T) {
+	handlers := newTestHandlers()
+	options := testRouterOptions()
+	options.AuthEnabled = false
+	SetupRouter(handlers, options)
+
+	doc := handlers.OpenAPI()
+	if len(doc.Components.SecuritySchemes) != 0 {
+		t.Errorf("security schemes without auth: %v", doc.Components.SecuritySchemes)
+	}
+	for path, operations := range doc.Paths {
+		for method, operation := range operations {
+			if len(operation.Security) > 0 || operation.Responses["401"] != nil {
+				t.Errorf("%s %s declares security without auth", method, path)
+			}
+		}
+	}
+
+	options.AuthEnabled = true
+	options.AnonymousScopes = []string{"read"}
+	SetupRouter(handlers, options)
+
+	doc = handlers.OpenAPI()
+	refresh := doc.Paths["/api/v1/currencies/refresh"]["post"]
+	if len(refresh.Security) != 2 || refresh.Responses["401"] == nil || refresh.Responses["403"] == nil {
+		t.Errorf("refresh security = %v, want a required API key", refresh.Security)
+	}
+
+	// Las consultas admiten peticiones sin clave con el scope anónimo read
+	currencies := doc.Paths["/api/v1/currencies"]["get"]
+	if n := len(currencies.Security); n != 3 || len(currencies.Security[n-1]) != 0 {
+		t.Errorf("currencies security = %v, want an optional API key", currencies.Security)
+	}
+
+	if health := doc.Paths["/api/v1/health"]["get"]; len(health.Security) != 0 {
+		t.Errorf("public health route declares security: %v", health.Security)
+	}
+}
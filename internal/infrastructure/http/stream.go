@@ -0,0 +1,214 @@
+// Package http implementa el stream de cambios de tasas con Server-Sent Events.
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"gobcv/internal/application/query"
+	"gobcv/internal/domain/entity"
+	"gobcv/internal/domain/repository"
+	"gobcv/internal/domain/service"
+)
+
+// Eventos enviados por el stream de tasas.
+const (
+	streamEventRate     = "rate"
+	streamEventSnapshot = "snapshot"
+)
+
+// streamRetry es el tiempo de reconexión sugerido a los clientes del stream.
+const streamRetry = 5 * time.Second
+
+// RateStream difunde a los clientes HTTP los cambios de tasas publicados por el broker,
+// reanudando desde el historial con la cabecera Last-Event-ID.
+type RateStream struct {
+	events    service.RateEventBroker
+	history   repository.RateHistoryRepository
+	heartbeat time.Duration
+	buffer    int
+}
+
+// NewRateStream crea un nuevo stream de tasas. heartbeat es el intervalo de los
+// comentarios que mantienen viva la conexión y buffer la cantidad de eventos que
+// puede acumular un cliente lento antes de cerrar su conexión.
+func NewRateStream(
+	events service.RateEventBroker,
+	history repository.RateHistoryRepository,
+	heartbeat time.Duration,
+	buffer int,
+) *RateStream {
+	return &RateStream{
+		events:    events,
+		history:   history,
+		heartbeat: heartbeat,
+		buffer:    buffer,
+	}
+}
+
+// StreamCurrencies maneja el endpoint de Server-Sent Events con los cambios de tasas.
+func (h *Handlers) StreamCurrencies(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	controller := http.NewResponseController(w)
+
+	// El stream no tiene duración definida: se anula el WriteTimeout del servidor
+	if err := controller.SetWriteDeadline(time.Time{}); err != nil && err != http.ErrNotSupported {
+		slog.WarnContext(ctx, "No se pudo anular el timeout de escritura del stream", "error", err)
+	}
+
+	currencyIDs := parseCurrencyIDs(r.URL.Query().Get("currencies"))
+	lastSequence, err := parseLastEventID(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, APIResponse{
+			Success:   false,
+			Message:   "Invalid Last-Event-ID",
+			Error:     err.Error(),
+			Timestamp: time.Now(),
+		})
+		return
+	}
+
+	// Suscribirse antes de leer el historial para no perder cambios intermedios
+	subCtx, unsubscribe := context.WithCancel(ctx)
+	defer unsubscribe()
+	events := h.stream.events.Subscribe(subCtx, currencyIDs, h.stream.buffer)
+
+	backlog, reset, err := repository.FindResumeBacklog(ctx, h.stream.history, lastSequence, currencyIDs)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error leyendo historial para reanudar stream", "error", err)
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	fmt.Fprintf(w, "retry: %d\n\n", streamRetry.Milliseconds())
+
+	if reset {
+		// Las secuencias se reiniciaron: el snapshot reemplaza los cambios perdidos y su ID
+		// reemplaza el Last-Event-ID del cliente
+		slog.InfoContext(ctx, "Last-Event-ID posterior al historial, reanudando desde un snapshot", "last_event_id", lastSequence)
+		lastSequence, _ = h.stream.history.LastSequence(ctx)
+		h.writeSnapshot(ctx, w, strconv.FormatUint(lastSequence, 10), currencyIDs)
+	} else if r.URL.Query().Get("snapshot") == "true" && lastSequence == 0 {
+		h.writeSnapshot(ctx, w, "", currencyIDs)
+	}
+
+	for _, change := range backlog {
+		writeRateEvent(w, change)
+		lastSequence = change.Sequence
+	}
+
+	if err := controller.Flush(); err != nil {
+		slog.WarnContext(ctx, "El ResponseWriter no soporta flush, no se puede servir el stream", "error", err)
+		return
+	}
+
+	heartbeat := time.NewTicker(h.stream.heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case change, ok := <-events:
+			if !ok {
+				// Cliente lento o servidor cerrando: el cliente reanuda con Last-Event-ID
+				slog.DebugContext(ctx, "Stream de tasas cerrado por el broker", "last_sequence", lastSequence)
+				return
+			}
+			// Los cambios ya enviados desde el historial llegan también por la suscripción
+			if change.Sequence != 0 && change.Sequence <= lastSequence {
+				continue
+			}
+			writeRateEvent(w, change)
+			if change.Sequence != 0 {
+				lastSequence = change.Sequence
+			}
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": heartbeat\n\n")
+		}
+
+		if err := controller.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// writeSnapshot envía el valor actual de las monedas solicitadas como evento inicial,
+// con el ID indicado si no está vacío.
+func (h *Handlers) writeSnapshot(ctx context.Context, w http.ResponseWriter, id string, currencyIDs []string) {
+	result, err := h.getAllHandler.Handle(ctx, query.GetAllCurrenciesQuery{UseCache: true})
+	if err != nil {
+		slog.ErrorContext(ctx, "Error obteniendo monedas para el snapshot del stream", "error", err)
+		return
+	}
+
+	currencies := make([]*entity.Currency, 0, len(result.Currencies))
+	for _, currency := range result.Currencies {
+		if len(currencyIDs) == 0 || containsFold(currencyIDs, currency.ID) {
+			currencies = append(currencies, currency)
+		}
+	}
+
+	writeEvent(w, id, streamEventSnapshot, currencies)
+}
+
+// writeRateEvent escribe un cambio de tasa usando su secuencia como ID del evento.
+func writeRateEvent(w http.ResponseWriter, change *entity.RateChange) {
+	id := ""
+	if change.Sequence != 0 {
+		id = strconv.FormatUint(change.Sequence, 10)
+	}
+	writeEvent(w, id, streamEventRate, change)
+}
+
+// writeEvent escribe un evento SSE con los datos codificados en JSON.
+func writeEvent(w http.ResponseWriter, id, event string, data interface{}) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return
+	}
+
+	if id != "" {
+		fmt.Fprintf(w, "id: %s\n", id)
+	}
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, payload)
+}
+
+// parseLastEventID obtiene la última secuencia recibida por el cliente desde la
+// cabecera Last-Event-ID o, para clientes sin control de cabeceras, el parámetro last_event_id.
+func parseLastEventID(r *http.Request) (uint64, error) {
+	value := r.Header.Get("Last-Event-ID")
+	if value == "" {
+		value = r.URL.Query().Get("last_event_id")
+	}
+	if value == "" {
+		return 0, nil
+	}
+
+	sequence, err := strconv.ParseUint(strings.TrimSpace(value), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("last event id must be a positive integer: %q", value)
+	}
+	return sequence, nil
+}
+
+// parseCurrencyIDs interpreta una lista de monedas separadas por comas.
+func parseCurrencyIDs(value string) []string {
+	var ids []string
+	for _, id := range strings.Split(value, ",") {
+		id = strings.ToUpper(strings.TrimSpace(id))
+		if id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
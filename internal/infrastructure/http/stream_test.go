@@ -0,0 +1,66 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"gobcv/internal/application/query"
+	"gobcv/internal/domain/entity"
+	"gobcv/internal/infrastructure/cache"
+	"gobcv/internal/infrastructure/events"
+)
+
+// readStream abre el stream con el Last-Event-ID indicado y retorna lo recibido antes
+// de cortar la conexión.
+func readStream(t *testing.T, router http.Handler, lastEventID string) string {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	request := httptest.NewRequest(http.MethodGet, "/api/v1/currencies/stream", nil).WithContext(ctx)
+	request.Header.Set("Last-Event-ID", lastEventID)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	return recorder.Body.String()
+}
+
+func TestStreamResumesAfterHistoryRestart(t *testing.T) {
+	ctx := context.Background()
+
+	repo := cache.NewMemoryRepository()
+	repo.Save(ctx, entity.NewCurrency("USD", "Dólar", 36.5, "test"))
+	memoryCache := cache.NewMemoryCache()
+	defer memoryCache.Close()
+
+	// Historial recién reiniciado: solo dos cambios
+	history := cache.NewMemoryRateHistory(10)
+	for _, value := range []float64{36.4, 36.5} {
+		history.Append(ctx, &entity.RateChange{CurrencyID: "USD", Value: value, ChangedAt: time.Now()})
+	}
+
+	stream := NewRateStream(events.NewBroker(), history, time.Minute, 8)
+	getAll := query.NewGetAllCurrenciesHandler(repo, memoryCache, 0)
+	handlers := NewHandlers(nil, nil, getAll, nil, nil, nil, nil, nil, stream, nil, nil, nil, nil)
+	router := SetupRouter(handlers, testRouterOptions())
+
+	// Cursor dentro del historial: se reenvía el cambio posterior
+	body := readStream(t, router, "1")
+	if !strings.Contains(body, "id: 2\nevent: rate\n") || strings.Contains(body, "event: snapshot") {
+		t.Errorf("resume within history:\n%s", body)
+	}
+
+	// Cursor de antes del reinicio: snapshot con la secuencia actual como ID
+	body = readStream(t, router, "500")
+	if !strings.Contains(body, "id: 2\nevent: snapshot\n") || !strings.Contains(body, `"id":"USD"`) {
+		t.Errorf("resume after restart, want snapshot with id 2:\n%s", body)
+	}
+	if strings.Contains(body, "event: rate") {
+		t.Errorf("resume after restart replayed changes:\n%s", body)
+	}
+}
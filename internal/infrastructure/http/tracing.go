@@ -0,0 +1,52 @@
+// Package http implementa las trazas OpenTelemetry de las peticiones HTTP.
+package http
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"gobcv/pkg/telemetry"
+)
+
+// TracingMiddleware crea un span de servidor por petición, continuando la traza
+// recibida en las cabeceras traceparent/tracestate (W3C Trace Context).
+type TracingMiddleware struct {
+	tracer trace.Tracer
+}
+
+// NewTracingMiddleware crea el middleware de trazas con el TracerProvider global.
+func NewTracingMiddleware() *TracingMiddleware {
+	return &TracingMiddleware{tracer: telemetry.Tracer()}
+}
+
+// Wrap traza las peticiones de la ruta. El nombre del span usa la plantilla de la ruta.
+func (m *TracingMiddleware) Wrap(route RouteInfo, next http.Handler) http.Handler {
+	spanName := route.Method + " " + route.Path
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		ctx, span := m.tracer.Start(ctx, spanName,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.request.method", r.Method),
+				attribute.String("http.route", route.Path),
+				attribute.String("url.path", r.URL.Path),
+				attribute.String("client.address", r.RemoteAddr),
+			),
+		)
+		defer span.End()
+
+		rw := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+		next.ServeHTTP(rw, r.WithContext(ctx))
+
+		span.SetAttributes(attribute.Int("http.response.status_code", rw.statusCode))
+		if rw.statusCode >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(rw.statusCode))
+		}
+	})
+}
@@ -0,0 +1,115 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"gobcv/internal/application/query"
+	"gobcv/internal/domain/entity"
+	"gobcv/internal/infrastructure/cache"
+	"gobcv/internal/infrastructure/tracing"
+)
+
+// installTestTracer instala un TracerProvider que guarda los spans en memoria.
+func installTestTracer(t *testing.T) *tracetest.InMemoryExporter {
+	t.Helper()
+
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	previousProvider, previousPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		provider.Shutdown(context.Background())
+		otel.SetTracerProvider(previousProvider)
+		otel.SetTextMapPropagator(previousPropagator)
+	})
+
+	return exporter
+}
+
+func TestTracingSpansFromRequestToRepository(t *testing.T) {
+	exporter := installTestTracer(t)
+
+	repo := cache.NewMemoryRepository()
+	repo.Save(context.Background(), entity.NewCurrency("USD", "Dólar", 36.5, "test"))
+	memoryCache := cache.NewMemoryCache()
+	defer memoryCache.Close()
+
+	getCurrency := query.NewGetCurrencyHandler(tracing.NewTracedRepository(repo), tracing.NewTracedCache(memoryCache), 0)
+	handlers := NewHandlers(nil, getCurrency, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	router := SetupRouter(handlers, testRouterOptions(), NewTracingMiddleware())
+
+	const (
+		traceID      = "4bf92f3577b34da6a3ce929d0e0e4736"
+		parentSpanID = "00f067aa0ba902b7"
+	)
+	request := httptest.NewRequest(http.MethodGet, "/api/v1/currencies/USD", nil)
+	request.Header.Set("traceparent", "00-"+traceID+"-"+parentSpanID+"-01")
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", recorder.Code, recorder.Body)
+	}
+
+	spans := exporter.GetSpans()
+	byName := make(map[string]tracetest.SpanStub)
+	for _, span := range spans {
+		if span.SpanContext.TraceID().String() != traceID {
+			t.Errorf("span %s has trace %s, want the incoming %s", span.Name, span.SpanContext.TraceID(), traceID)
+		}
+		if _, seen := byName[span.Name]; !seen {
+			byName[span.Name] = span
+		}
+	}
+
+	server, ok := byName["GET /api/v1/currencies/{id}"]
+	if !ok {
+		t.Fatalf("no server span in %v", spanNames(spans))
+	}
+	if server.SpanKind != trace.SpanKindServer {
+		t.Errorf("server span kind = %v", server.SpanKind)
+	}
+	if got := server.Parent.SpanID().String(); got != parentSpanID || !server.Parent.IsRemote() {
+		t.Errorf("server span parent = %s (remote %v), want the traceparent span %s", got, server.Parent.IsRemote(), parentSpanID)
+	}
+
+	handler, ok := byName["GetCurrencyHandler.Handle"]
+	if !ok {
+		t.Fatalf("no query handler span in %v", spanNames(spans))
+	}
+	if handler.Parent.SpanID() != server.SpanContext.SpanID() {
+		t.Errorf("query handler span is not a child of the server span")
+	}
+
+	// La primera consulta falla en caché, lee el repositorio y guarda el resultado
+	for _, name := range []string{"cache.get", "repository.FindByID", "cache.set"} {
+		span, ok := byName[name]
+		if !ok {
+			t.Errorf("no %s span in %v", name, spanNames(spans))
+			continue
+		}
+		if span.Parent.SpanID() != handler.SpanContext.SpanID() {
+			t.Errorf("%s span is not a child of the query handler span", name)
+		}
+	}
+}
+
+// spanNames retorna los nombres de los spans para los mensajes de error.
+func spanNames(spans tracetest.SpanStubs) []string {
+	names := make([]string, 0, len(spans))
+	for _, span := range spans {
+		names = append(names, span.Name)
+	}
+	return names
+}
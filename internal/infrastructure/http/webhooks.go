@@ -0,0 +1,186 @@
+// Package http implementa los endpoints de administración de webhooks.
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	appService "gobcv/internal/application/service"
+	"gobcv/internal/domain/entity"
+)
+
+// maxWebhookBody limita el tamaño del cuerpo de las peticiones de administración.
+const maxWebhookBody = 64 << 10
+
+// WebhookManager administra las suscripciones de webhooks.
+type WebhookManager interface {
+	Create(ctx context.Context, input appService.WebhookInput) (*entity.Webhook, error)
+	Update(ctx context.Context, id string, input appService.WebhookInput) (*entity.Webhook, error)
+	Get(ctx context.Context, id string) (*entity.Webhook, error)
+	List(ctx context.Context) ([]*entity.Webhook, error)
+	Delete(ctx context.Context, id string) error
+	Deliveries(ctx context.Context, id string, limit int) ([]*entity.WebhookDelivery, error)
+}
+
+// ListWebhooks maneja el endpoint para listar los webhooks.
+func (h *Handlers) ListWebhooks(w http.ResponseWriter, r *http.Request) {
+	webhooks, err := h.webhooks.List(r.Context())
+	if err != nil {
+		writeWebhookError(w, err, "Error getting webhooks")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, APIResponse{
+		Success:   true,
+		Message:   "Webhooks retrieved",
+		Data:      webhooks,
+		Timestamp: time.Now(),
+	})
+}
+
+// CreateWebhook maneja el endpoint para registrar un webhook.
+func (h *Handlers) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	input, ok := decodeWebhookInput(w, r)
+	if !ok {
+		return
+	}
+
+	webhook, err := h.webhooks.Create(r.Context(), input)
+	if err != nil {
+		writeWebhookError(w, err, "Error creating webhook")
+		return
+	}
+
+	w.Header().Set("Location", apiPrefix+"/webhooks/"+webhook.ID)
+	writeJSON(w, http.StatusCreated, APIResponse{
+		Success:   true,
+		Message:   "Webhook created; store the secret, it will not be shown again",
+		Data:      webhook,
+		Timestamp: time.Now(),
+	})
+}
+
+// GetWebhook maneja el endpoint para obtener un webhook.
+func (h *Handlers) GetWebhook(w http.ResponseWriter, r *http.Request) {
+	webhook, err := h.webhooks.Get(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		writeWebhookError(w, err, "Error getting webhook")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, APIResponse{
+		Success:   true,
+		Message:   "Webhook retrieved",
+		Data:      webhook,
+		Timestamp: time.Now(),
+	})
+}
+
+// UpdateWebhook maneja el endpoint para modificar un webhook.
+func (h *Handlers) UpdateWebhook(w http.ResponseWriter, r *http.Request) {
+	input, ok := decodeWebhookInput(w, r)
+	if !ok {
+		return
+	}
+
+	webhook, err := h.webhooks.Update(r.Context(), mux.Vars(r)["id"], input)
+	if err != nil {
+		writeWebhookError(w, err, "Error updating webhook")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, APIResponse{
+		Success:   true,
+		Message:   "Webhook updated",
+		Data:      webhook,
+		Timestamp: time.Now(),
+	})
+}
+
+// DeleteWebhook maneja el endpoint para eliminar un webhook.
+func (h *Handlers) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	if err := h.webhooks.Delete(r.Context(), mux.Vars(r)["id"]); err != nil {
+		writeWebhookError(w, err, "Error deleting webhook")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, APIResponse{
+		Success:   true,
+		Message:   "Webhook deleted",
+		Timestamp: time.Now(),
+	})
+}
+
+// GetWebhookDeliveries maneja el endpoint con el registro de entregas de un webhook.
+func (h *Handlers) GetWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	limit := 50
+	if value := r.URL.Query().Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			writeJSON(w, http.StatusBadRequest, APIResponse{
+				Success:   false,
+				Message:   "Invalid limit",
+				Error:     "limit must be a positive integer",
+				Timestamp: time.Now(),
+			})
+			return
+		}
+		limit = parsed
+	}
+
+	deliveries, err := h.webhooks.Deliveries(r.Context(), mux.Vars(r)["id"], limit)
+	if err != nil {
+		writeWebhookError(w, err, "Error getting webhook deliveries")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, APIResponse{
+		Success:   true,
+		Message:   "Webhook deliveries retrieved",
+		Data:      deliveries,
+		Timestamp: time.Now(),
+	})
+}
+
+// decodeWebhookInput lee el cuerpo JSON de la petición. Si es inválido responde 400 y retorna false.
+func decodeWebhookInput(w http.ResponseWriter, r *http.Request) (appService.WebhookInput, bool) {
+	var input appService.WebhookInput
+
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxWebhookBody))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&input); err != nil {
+		writeJSON(w, http.StatusBadRequest, APIResponse{
+			Success:   false,
+			Message:   "Invalid request body",
+			Error:     err.Error(),
+			Timestamp: time.Now(),
+		})
+		return input, false
+	}
+
+	return input, true
+}
+
+// writeWebhookError responde con el código de estado que corresponde al error.
+func writeWebhookError(w http.ResponseWriter, err error, message string) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, appService.ErrWebhookNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, appService.ErrInvalidWebhook):
+		status = http.StatusBadRequest
+	}
+
+	writeJSON(w, status, APIResponse{
+		Success:   false,
+		Message:   message,
+		Error:     err.Error(),
+		Timestamp: time.Now(),
+	})
+}
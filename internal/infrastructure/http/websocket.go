@@ -0,0 +1,439 @@
+// Package http implementa las suscripciones a cambios de tasas por WebSocket.
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"gobcv/internal/application/query"
+	"gobcv/internal/domain/entity"
+	"gobcv/internal/domain/repository"
+	"gobcv/internal/domain/service"
+)
+
+// Tipos de mensaje del protocolo WebSocket. El cliente envía subscribe, unsubscribe,
+// snapshot y ping; el servidor responde con subscribed, unsubscribed, snapshot y pong,
+// y envía rate por cada cambio de una moneda suscrita.
+const (
+	socketSubscribe    = "subscribe"
+	socketSubscribed   = "subscribed"
+	socketUnsubscribe  = "unsubscribe"
+	socketUnsubscribed = "unsubscribed"
+	socketSnapshot     = "snapshot"
+	socketPing         = "ping"
+	socketPong         = "pong"
+	socketRate         = "rate"
+	socketError        = "error"
+)
+
+const (
+	// socketWriteWait es el tiempo máximo para escribir un mensaje al cliente.
+	socketWriteWait = 10 * time.Second
+	// socketMaxMessageSize limita el tamaño de los mensajes del cliente.
+	socketMaxMessageSize = 4096
+)
+
+// socketRequest es un mensaje enviado por el cliente.
+type socketRequest struct {
+	Type       string   `json:"type"`
+	ID         string   `json:"id,omitempty"`         // Se repite en la respuesta para correlacionarla
+	Currencies []string `json:"currencies,omitempty"` // Vacío equivale a todas las monedas
+	Since      uint64   `json:"since,omitempty"`      // Secuencia desde la que reanudar al suscribirse
+}
+
+// socketMessage es un mensaje enviado por el servidor.
+type socketMessage struct {
+	Type       string             `json:"type"`
+	ID         string             `json:"id,omitempty"`
+	Currencies []string           `json:"currencies,omitempty"`
+	All        bool               `json:"all,omitempty"`
+	Change     *entity.RateChange `json:"change,omitempty"`
+	Snapshot   []*entity.Currency `json:"snapshot,omitempty"`
+	Error      string             `json:"error,omitempty"`
+}
+
+// RateSocket atiende las suscripciones WebSocket a cambios de tasas y mantiene el
+// registro de conexiones abiertas para cerrarlas durante el apagado del servidor.
+type RateSocket struct {
+	events   service.RateEventBroker
+	history  repository.RateHistoryRepository
+	upgrader websocket.Upgrader
+	ping     time.Duration
+	buffer   int
+	conns    map[*rateConn]struct{}
+	closing  bool
+	wg       sync.WaitGroup
+	mutex    sync.Mutex
+}
+
+// NewRateSocket crea el endpoint WebSocket de tasas. Solo se aceptan navegadores de
+// los orígenes de la política CORS; ping es el intervalo de los pings de control y
+// buffer la cantidad de mensajes pendientes por conexión antes de cerrarla.
+func NewRateSocket(
+	events service.RateEventBroker,
+	history repository.RateHistoryRepository,
+	origins CORSPolicy,
+	ping time.Duration,
+	buffer int,
+) *RateSocket {
+	return &RateSocket{
+		events:  events,
+		history: history,
+		upgrader: websocket.Upgrader{
+			CheckOrigin: func(r *http.Request) bool {
+				origin := r.Header.Get("Origin")
+				return origin == "" || origins.AllowsOrigin(origin)
+			},
+		},
+		ping:   ping,
+		buffer: buffer,
+		conns:  make(map[*rateConn]struct{}),
+	}
+}
+
+// Connections retorna la cantidad de conexiones abiertas.
+func (s *RateSocket) Connections() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return len(s.conns)
+}
+
+// Shutdown rechaza nuevas conexiones, envía un cierre 1001 (going away) a las abiertas
+// y espera a que terminen o a que se cancele el contexto.
+func (s *RateSocket) Shutdown(ctx context.Context) error {
+	s.mutex.Lock()
+	s.closing = true
+	for conn := range s.conns {
+		conn.close(websocket.CloseGoingAway, "server shutting down")
+	}
+	s.mutex.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// register agrega una conexión al registro salvo que el servidor se esté apagando.
+func (s *RateSocket) register(conn *rateConn) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.closing {
+		return false
+	}
+	s.conns[conn] = struct{}{}
+	s.wg.Add(1)
+	return true
+}
+
+// unregister elimina una conexión del registro.
+func (s *RateSocket) unregister(conn *rateConn) {
+	s.mutex.Lock()
+	delete(s.conns, conn)
+	s.mutex.Unlock()
+	s.wg.Done()
+}
+
+// SubscribeCurrencies maneja el endpoint WebSocket de suscripciones a cambios de tasas.
+func (h *Handlers) SubscribeCurrencies(w http.ResponseWriter, r *http.Request) {
+	socket := h.socket
+
+	socket.mutex.Lock()
+	closing := socket.closing
+	socket.mutex.Unlock()
+	if closing {
+		writeJSON(w, http.StatusServiceUnavailable, APIResponse{
+			Success:   false,
+			Message:   "Server is shutting down",
+			Timestamp: time.Now(),
+		})
+		return
+	}
+
+	ws, err := socket.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// El upgrader ya respondió al cliente con el error
+		slog.DebugContext(r.Context(), "Error aceptando conexión WebSocket", "error", err)
+		return
+	}
+
+	conn := &rateConn{
+		ws:       ws,
+		socket:   socket,
+		snapshot: h.getAllHandler,
+		send:     make(chan socketMessage, socket.buffer),
+		requests: make(chan socketRequest),
+		done:     make(chan struct{}),
+		ids:      make(map[string]bool),
+	}
+	if !socket.register(conn) {
+		conn.close(websocket.CloseGoingAway, "server shutting down")
+		conn.writePump()
+		return
+	}
+	defer socket.unregister(conn)
+
+	slog.InfoContext(r.Context(), "Conexión WebSocket abierta", "remote_addr", r.RemoteAddr)
+	conn.serve(r.Context())
+	slog.InfoContext(r.Context(), "Conexión WebSocket cerrada", "remote_addr", r.RemoteAddr, "reason", conn.closeReason)
+}
+
+// rateConn es una conexión WebSocket suscrita a cambios de tasas. Un lector recibe los
+// mensajes del cliente, un escritor es el único que escribe en el socket, y el bucle de
+// serve es el único que modifica la suscripción, por lo que no necesita sincronización.
+type rateConn struct {
+	ws       *websocket.Conn
+	socket   *RateSocket
+	snapshot *query.GetAllCurrenciesHandler
+	send     chan socketMessage
+	requests chan socketRequest
+	done     chan struct{}
+
+	closeOnce   sync.Once
+	closeCode   int
+	closeReason string
+
+	// Estado de la suscripción, propiedad del bucle de serve
+	all          bool
+	ids          map[string]bool
+	lastSequence uint64
+}
+
+// serve atiende la conexión hasta que el cliente o el servidor la cierran.
+func (c *rateConn) serve(ctx context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Todas las monedas: el filtro de la conexión cambia con cada subscribe
+	events := c.socket.events.Subscribe(ctx, nil, c.socket.buffer)
+
+	go c.readPump()
+	writerDone := make(chan struct{})
+	go func() {
+		c.writePump()
+		close(writerDone)
+	}()
+
+	for {
+		select {
+		case <-c.done:
+			<-writerDone
+			return
+		case request := <-c.requests:
+			c.handleRequest(ctx, request)
+		case change, ok := <-events:
+			if !ok {
+				// El broker descartó la suscripción por lenta o se está cerrando;
+				// el cliente puede reanudar con since
+				c.close(websocket.CloseTryAgainLater, "subscription lagged, resubscribe with since")
+				events = nil
+				continue
+			}
+			if c.matches(change) && (change.Sequence == 0 || change.Sequence > c.lastSequence) {
+				c.pushChange(change)
+			}
+		}
+	}
+}
+
+// handleRequest procesa un mensaje del cliente.
+func (c *rateConn) handleRequest(ctx context.Context, request socketRequest) {
+	ids := parseCurrencyIDs(strings.Join(request.Currencies, ","))
+
+	switch request.Type {
+	case socketSubscribe:
+		if len(ids) == 0 {
+			c.all = true
+		}
+		for _, id := range ids {
+			c.ids[id] = true
+		}
+		c.enqueue(c.subscriptionMessage(socketSubscribed, request.ID))
+		if request.Since > 0 {
+			c.replay(ctx, request.ID, request.Since, ids)
+		}
+	case socketUnsubscribe:
+		if len(ids) == 0 {
+			c.all = false
+			c.ids = make(map[string]bool)
+		}
+		for _, id := range ids {
+			delete(c.ids, id)
+		}
+		c.enqueue(c.subscriptionMessage(socketUnsubscribed, request.ID))
+	case socketSnapshot:
+		c.sendSnapshot(ctx, request.ID, ids)
+	case socketPing:
+		c.enqueue(socketMessage{Type: socketPong, ID: request.ID})
+	case socketError:
+		c.enqueue(socketMessage{Type: socketError, Error: "invalid JSON message"})
+	default:
+		c.enqueue(socketMessage{Type: socketError, ID: request.ID, Error: "unknown message type: " + request.Type})
+	}
+}
+
+// replay envía desde el historial los cambios posteriores a since de las monedas indicadas.
+// Si since es posterior al historial, las secuencias se reiniciaron y se envía un snapshot.
+func (c *rateConn) replay(ctx context.Context, requestID string, since uint64, ids []string) {
+	changes, reset, err := repository.FindResumeBacklog(ctx, c.socket.history, since, ids)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error leyendo historial para reanudar suscripción", "error", err)
+		return
+	}
+	if reset {
+		slog.InfoContext(ctx, "since posterior al historial, reanudando desde un snapshot", "since", since)
+		c.sendSnapshot(ctx, requestID, ids)
+		return
+	}
+	for _, change := range changes {
+		if change.Sequence > c.lastSequence {
+			c.pushChange(change)
+		}
+	}
+}
+
+// sendSnapshot envía el valor actual de las monedas indicadas; si no se indica ninguna
+// usa las suscritas y, sin suscripción a monedas concretas, todas.
+func (c *rateConn) sendSnapshot(ctx context.Context, requestID string, ids []string) {
+	result, err := c.snapshot.Handle(ctx, query.GetAllCurrenciesQuery{UseCache: true})
+	if err != nil {
+		c.enqueue(socketMessage{Type: socketError, ID: requestID, Error: err.Error()})
+		return
+	}
+
+	if len(ids) == 0 && !c.all {
+		for id := range c.ids {
+			ids = append(ids, id)
+		}
+	}
+
+	currencies := make([]*entity.Currency, 0, len(result.Currencies))
+	for _, currency := range result.Currencies {
+		if len(ids) == 0 || containsFold(ids, currency.ID) {
+			currencies = append(currencies, currency)
+		}
+	}
+
+	c.enqueue(socketMessage{Type: socketSnapshot, ID: requestID, Snapshot: currencies})
+}
+
+// pushChange encola un cambio y recuerda su secuencia para no repetirlo.
+func (c *rateConn) pushChange(change *entity.RateChange) {
+	if change.Sequence > c.lastSequence {
+		c.lastSequence = change.Sequence
+	}
+	c.enqueue(socketMessage{Type: socketRate, Change: change})
+}
+
+// matches verifica si la conexión está suscrita a la moneda del cambio.
+func (c *rateConn) matches(change *entity.RateChange) bool {
+	return c.all || c.ids[change.CurrencyID]
+}
+
+// subscriptionMessage describe la suscripción actual de la conexión.
+func (c *rateConn) subscriptionMessage(messageType, requestID string) socketMessage {
+	message := socketMessage{Type: messageType, ID: requestID, All: c.all, Currencies: []string{}}
+	for id := range c.ids {
+		message.Currencies = append(message.Currencies, id)
+	}
+	return message
+}
+
+// enqueue encola un mensaje sin bloquear. Si el cliente no consume a tiempo y su
+// buffer está lleno se cierra la conexión con 1013 (try again later).
+func (c *rateConn) enqueue(message socketMessage) {
+	select {
+	case <-c.done:
+	case c.send <- message:
+	default:
+		c.close(websocket.CloseTryAgainLater, "slow consumer")
+	}
+}
+
+// close marca la conexión para cierre con el código indicado; el escritor envía el
+// frame de cierre y libera el socket.
+func (c *rateConn) close(code int, reason string) {
+	c.closeOnce.Do(func() {
+		c.closeCode = code
+		c.closeReason = reason
+		close(c.done)
+	})
+}
+
+// readPump lee los mensajes del cliente y los entrega al bucle de serve.
+func (c *rateConn) readPump() {
+	c.ws.SetReadLimit(socketMaxMessageSize)
+	pongWait := 2 * c.socket.ping
+	c.ws.SetReadDeadline(time.Now().Add(pongWait))
+	c.ws.SetPongHandler(func(string) error {
+		return c.ws.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		_, data, err := c.ws.ReadMessage()
+		if err != nil {
+			c.close(websocket.CloseNormalClosure, "connection closed")
+			return
+		}
+
+		// Cualquier mensaje del cliente también indica que sigue vivo
+		c.ws.SetReadDeadline(time.Now().Add(pongWait))
+
+		var request socketRequest
+		if err := json.Unmarshal(data, &request); err != nil {
+			request = socketRequest{Type: socketError}
+		}
+
+		select {
+		case c.requests <- request:
+		case <-c.done:
+			return
+		}
+	}
+}
+
+// writePump es el único que escribe en el socket: mensajes, pings y el frame de cierre.
+func (c *rateConn) writePump() {
+	ping := time.NewTicker(c.socket.ping)
+	defer func() {
+		ping.Stop()
+		c.ws.Close()
+	}()
+
+	for {
+		select {
+		case message := <-c.send:
+			c.ws.SetWriteDeadline(time.Now().Add(socketWriteWait))
+			if err := c.ws.WriteJSON(message); err != nil {
+				c.close(websocket.CloseAbnormalClosure, "write error")
+				return
+			}
+		case <-ping.C:
+			if err := c.ws.WriteControl(websocket.PingMessage, nil, time.Now().Add(socketWriteWait)); err != nil {
+				c.close(websocket.CloseAbnormalClosure, "ping error")
+				return
+			}
+		case <-c.done:
+			frame := websocket.FormatCloseMessage(c.closeCode, c.closeReason)
+			c.ws.WriteControl(websocket.CloseMessage, frame, time.Now().Add(socketWriteWait))
+			return
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package http
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"gobcv/internal/application/query"
+	"gobcv/internal/domain/entity"
+	"gobcv/internal/infrastructure/cache"
+	"gobcv/internal/infrastructure/events"
+)
+
+// newSocketServer levanta el router con el endpoint WebSocket sobre un repositorio con
+// USD y EUR, y retorna el socket, el broker y la URL ws:// del endpoint.
+func newSocketServer(t *testing.T, buffer int) (*RateSocket, *events.Broker, string) {
+	t.Helper()
+	ctx := context.Background()
+
+	repo := cache.NewMemoryRepository()
+	repo.Save(ctx, entity.NewCurrency("USD", "Dólar", 36.5, "test"))
+	repo.Save(ctx, entity.NewCurrency("EUR", "Euro", 40, "test"))
+	memoryCache := cache.NewMemoryCache()
+	t.Cleanup(func() { memoryCache.Close() })
+
+	broker := events.NewBroker()
+	socket := NewRateSocket(broker, cache.NewMemoryRateHistory(10), CORSPolicy{}, time.Minute, buffer)
+	getAll := query.NewGetAllCurrenciesHandler(repo, memoryCache, 0)
+	handlers := NewHandlers(nil, nil, getAll, nil, nil, nil, nil, nil, nil, socket, nil, nil, nil)
+
+	server := httptest.NewServer(SetupRouter(handlers, testRouterOptions()))
+	t.Cleanup(server.Close)
+
+	return socket, broker, "ws" + strings.TrimPrefix(server.URL, "http") + "/api/v1/currencies/ws"
+}
+
+// dialSocket abre una conexión WebSocket con un plazo de lectura para no colgar el test.
+func dialSocket(t *testing.T, url string) *websocket.Conn {
+	t.Helper()
+
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	return conn
+}
+
+// socketRoundTrip envía un mensaje del protocolo y retorna la respuesta del servidor.
+func socketRoundTrip(t *testing.T, conn *websocket.Conn, message socketRequest) socketMessage {
+	t.Helper()
+
+	if err := conn.WriteJSON(message); err != nil {
+		t.Fatalf("WriteJSON(%+v): %v", message, err)
+	}
+
+	var response socketMessage
+	if err := conn.ReadJSON(&response); err != nil {
+		t.Fatalf("ReadJSON after %s: %v", message.Type, err)
+	}
+	return response
+}
+
+// readClose lee hasta recibir el frame de cierre y retorna su código.
+func readClose(t *testing.T, conn *websocket.Conn) int {
+	t.Helper()
+
+	for {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			closeErr, ok := err.(*websocket.CloseError)
+			if !ok {
+				t.Fatalf("read error = %v, want a close frame", err)
+			}
+			return closeErr.Code
+		}
+	}
+}
+
+func TestSocketSubscribeAndUnsubscribe(t *testing.T) {
+	_, _, url := newSocketServer(t, 8)
+	conn := dialSocket(t, url)
+
+	response := socketRoundTrip(t, conn, socketRequest{Type: socketSubscribe, ID: "1", Currencies: []string{"usd", "EUR"}})
+	if response.Type != socketSubscribed || response.ID != "1" || response.All || len(response.Currencies) != 2 {
+		t.Errorf("subscribe = %+v, want subscribed to USD and EUR", response)
+	}
+
+	response = socketRoundTrip(t, conn, socketRequest{Type: socketUnsubscribe, ID: "2", Currencies: []string{"EUR"}})
+	if response.Type != socketUnsubscribed || response.ID != "2" ||
+		len(response.Currencies) != 1 || response.Currencies[0] != "USD" {
+		t.Errorf("unsubscribe EUR = %+v, want only USD left", response)
+	}
+
+	response = socketRoundTrip(t, conn, socketRequest{Type: socketSubscribe, ID: "3"})
+	if response.Type != socketSubscribed || !response.All {
+		t.Errorf("subscribe without currencies = %+v, want all", response)
+	}
+
+	response = socketRoundTrip(t, conn, socketRequest{Type: socketUnsubscribe, ID: "4"})
+	if response.Type != socketUnsubscribed || response.All || len(response.Currencies) != 0 {
+		t.Errorf("unsubscribe without currencies = %+v, want nothing left", response)
+	}
+
+	response = socketRoundTrip(t, conn, socketRequest{Type: "bogus", ID: "5"})
+	if response.Type != socketError || response.ID != "5" {
+		t.Errorf("unknown type = %+v, want error", response)
+	}
+}
+
+func TestSocketSnapshot(t *testing.T) {
+	_, _, url := newSocketServer(t, 8)
+	conn := dialSocket(t, url)
+
+	response := socketRoundTrip(t, conn, socketRequest{Type: socketSnapshot, ID: "1"})
+	if response.Type != socketSnapshot || response.ID != "1" || len(response.Snapshot) != 2 {
+		t.Errorf("snapshot = %+v, want both currencies", response)
+	}
+
+	// Sin monedas en el mensaje se usan las suscritas
+	socketRoundTrip(t, conn, socketRequest{Type: socketSubscribe, Currencies: []string{"EUR"}})
+	response = socketRoundTrip(t, conn, socketRequest{Type: socketSnapshot, ID: "2"})
+	if len(response.Snapshot) != 1 || response.Snapshot[0].ID != "EUR" {
+		t.Errorf("snapshot of subscribed = %+v, want EUR", response.Snapshot)
+	}
+}
+
+func TestSocketPushesPublishedChanges(t *testing.T) {
+	_, broker, url := newSocketServer(t, 8)
+	conn := dialSocket(t, url)
+
+	socketRoundTrip(t, conn, socketRequest{Type: socketSubscribe, Currencies: []string{"USD"}})
+
+	// EUR no está suscrito y no debe llegar antes que USD
+	broker.Publish(context.Background(), []*entity.RateChange{
+		{Sequence: 1, CurrencyID: "EUR", Value: 41, ChangedAt: time.Now()},
+		{Sequence: 2, CurrencyID: "USD", Value: 36.6, PreviousValue: 36.5, ChangedAt: time.Now()},
+	})
+
+	var message socketMessage
+	if err := conn.ReadJSON(&message); err != nil {
+		t.Fatalf("ReadJSON: %v", err)
+	}
+	if message.Type != socketRate || message.Change == nil ||
+		message.Change.CurrencyID != "USD" || message.Change.Sequence != 2 {
+		t.Errorf("pushed = %+v, want the USD change", message)
+	}
+}
+
+func TestSocketDisconnectsSlowConsumer(t *testing.T) {
+	_, broker, url := newSocketServer(t, 1)
+	conn := dialSocket(t, url)
+
+	socketRoundTrip(t, conn, socketRequest{Type: socketSubscribe})
+
+	changes := make([]*entity.RateChange, 1000)
+	for i := range changes {
+		changes[i] = &entity.RateChange{Sequence: uint64(i + 1), CurrencyID: "USD", Value: float64(i), ChangedAt: time.Now()}
+	}
+	broker.Publish(context.Background(), changes)
+
+	if code := readClose(t, conn); code != websocket.CloseTryAgainLater {
+		t.Errorf("close code = %d, want %d (try again later)", code, websocket.CloseTryAgainLater)
+	}
+}
+
+func TestSocketShutdownClosesConnections(t *testing.T) {
+	socket, _, url := newSocketServer(t, 8)
+	conn := dialSocket(t, url)
+
+	// La respuesta confirma que la conexión ya está registrada
+	socketRoundTrip(t, conn, socketRequest{Type: socketPing, ID: "1"})
+	if socket.Connections() != 1 {
+		t.Fatalf("Connections = %d, want 1", socket.Connections())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := socket.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+
+	if code := readClose(t, conn); code != websocket.CloseGoingAway {
+		t.Errorf("close code = %d, want %d (going away)", code, websocket.CloseGoingAway)
+	}
+	if socket.Connections() != 0 {
+		t.Errorf("Connections after shutdown = %d, want 0", socket.Connections())
+	}
+
+	// Las conexiones nuevas se rechazan antes del upgrade
+	if _, response, err := websocket.DefaultDialer.Dial(url, nil); err == nil || response == nil || response.StatusCode != 503 {
+		t.Errorf("dial after shutdown = %v, %v; want 503", response, err)
+	}
+}
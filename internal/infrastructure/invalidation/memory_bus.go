@@ -0,0 +1,63 @@
+// Package invalidation implementa los adaptadores del bus de invalidación de caché.
+package invalidation
+
+import (
+	"context"
+	"sync"
+
+	"gobcv/internal/domain/service"
+)
+
+// MemoryBus implementa el bus de invalidación dentro del mismo proceso.
+type MemoryBus struct {
+	handlers map[int]service.InvalidationHandler
+	nextID   int
+	mutex    sync.RWMutex
+}
+
+// NewMemoryBus crea un nuevo bus de invalidación en memoria.
+func NewMemoryBus() *MemoryBus {
+	return &MemoryBus{
+		handlers: make(map[int]service.InvalidationHandler),
+	}
+}
+
+// Publish entrega el evento a todos los handlers suscritos.
+func (b *MemoryBus) Publish(ctx context.Context, event service.InvalidationEvent) error {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	for _, handler := range b.handlers {
+		handler(ctx, event)
+	}
+
+	return nil
+}
+
+// Subscribe registra un handler hasta que se cancele el contexto.
+func (b *MemoryBus) Subscribe(ctx context.Context, handler service.InvalidationHandler) error {
+	b.mutex.Lock()
+	id := b.nextID
+	b.nextID++
+	b.handlers[id] = handler
+	b.mutex.Unlock()
+
+	go func() {
+		<-ctx.Done()
+
+		b.mutex.Lock()
+		delete(b.handlers, id)
+		b.mutex.Unlock()
+	}()
+
+	return nil
+}
+
+// Close elimina todas las suscripciones.
+func (b *MemoryBus) Close() error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.handlers = make(map[int]service.InvalidationHandler)
+	return nil
+}
@@ -0,0 +1,45 @@
+package invalidation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gobcv/internal/domain/service"
+)
+
+func TestMemoryBusDeliversToSubscribers(t *testing.T) {
+	bus := NewMemoryBus()
+	defer bus.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var first, second []service.InvalidationEvent
+	bus.Subscribe(ctx, func(ctx context.Context, event service.InvalidationEvent) { first = append(first, event) })
+	bus.Subscribe(context.Background(), func(ctx context.Context, event service.InvalidationEvent) { second = append(second, event) })
+
+	event := service.InvalidationEvent{Keys: []string{"currency:USD"}, Tags: []string{service.CurrenciesTag}, Source: "a"}
+	if err := bus.Publish(context.Background(), event); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	if len(first) != 1 || len(second) != 1 || first[0].Source != "a" || first[0].Keys[0] != "currency:USD" {
+		t.Fatalf("events = %v, %v, want the event on both subscribers", first, second)
+	}
+
+	// Cancelado su contexto, el primer suscriptor se da de baja en background
+	cancel()
+	deadline := time.Now().Add(time.Second)
+	for {
+		received := len(first)
+		bus.Publish(context.Background(), service.InvalidationEvent{Source: "b"})
+		if len(first) == received {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("cancelled subscriber received %d events", len(first))
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if len(second) < 2 {
+		t.Errorf("active subscriber received %d events, want at least 2", len(second))
+	}
+}
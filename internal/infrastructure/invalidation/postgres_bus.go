@@ -0,0 +1,102 @@
+// Package invalidation implementa el bus de invalidación sobre Postgres LISTEN/NOTIFY.
+package invalidation
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/lib/pq"
+
+	"gobcv/internal/domain/service"
+)
+
+// Tiempos de reconexión del listener de Postgres.
+const (
+	postgresMinReconnect = 1 * time.Second
+	postgresMaxReconnect = 1 * time.Minute
+)
+
+// PostgresBus implementa el bus de invalidación sobre Postgres LISTEN/NOTIFY.
+type PostgresBus struct {
+	db      *sql.DB
+	dsn     string
+	channel string
+}
+
+// NewPostgresBus crea un nuevo bus de invalidación sobre Postgres.
+func NewPostgresBus(dsn, channel string) (*PostgresBus, error) {
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("error opening postgres connection: %w", err)
+	}
+
+	return &PostgresBus{
+		db:      db,
+		dsn:     dsn,
+		channel: channel,
+	}, nil
+}
+
+// Publish notifica el evento en el canal de Postgres.
+func (b *PostgresBus) Publish(ctx context.Context, event service.InvalidationEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("error encoding invalidation event: %w", err)
+	}
+
+	if _, err := b.db.ExecContext(ctx, "SELECT pg_notify($1, $2)", b.channel, string(payload)); err != nil {
+		return fmt.Errorf("error publishing invalidation event: %w", err)
+	}
+
+	return nil
+}
+
+// Subscribe escucha el canal de Postgres y entrega los eventos hasta que se cancele el contexto.
+func (b *PostgresBus) Subscribe(ctx context.Context, handler service.InvalidationHandler) error {
+	listener := pq.NewListener(b.dsn, postgresMinReconnect, postgresMaxReconnect,
+		func(event pq.ListenerEventType, err error) {
+			if err != nil {
+				slog.Error("Error en listener de Postgres", "channel", b.channel, "error", err)
+			}
+		})
+
+	if err := listener.Listen(b.channel); err != nil {
+		listener.Close()
+		return fmt.Errorf("error listening on %s: %w", b.channel, err)
+	}
+
+	go func() {
+		defer listener.Close()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case notification := <-listener.Notify:
+				// Una notificación nil indica que la conexión se restableció
+				if notification == nil {
+					continue
+				}
+
+				var event service.InvalidationEvent
+				if err := json.Unmarshal([]byte(notification.Extra), &event); err != nil {
+					slog.Warn("Evento de invalidación inválido", "channel", b.channel, "error", err)
+					continue
+				}
+
+				handler(ctx, event)
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Close cierra la conexión con Postgres.
+func (b *PostgresBus) Close() error {
+	return b.db.Close()
+}
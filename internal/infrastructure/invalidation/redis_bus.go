@@ -0,0 +1,87 @@
+// Package invalidation implementa el bus de invalidación sobre Redis pub/sub.
+package invalidation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/redis/go-redis/v9"
+
+	"gobcv/internal/domain/service"
+)
+
+// RedisBus implementa el bus de invalidación sobre Redis pub/sub.
+type RedisBus struct {
+	client  *redis.Client
+	channel string
+}
+
+// NewRedisBus crea un nuevo bus de invalidación sobre Redis.
+func NewRedisBus(addr, password string, db int, channel string) *RedisBus {
+	return &RedisBus{
+		client: redis.NewClient(&redis.Options{
+			Addr:     addr,
+			Password: password,
+			DB:       db,
+		}),
+		channel: channel,
+	}
+}
+
+// Publish publica el evento en el canal de Redis.
+func (b *RedisBus) Publish(ctx context.Context, event service.InvalidationEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("error encoding invalidation event: %w", err)
+	}
+
+	if err := b.client.Publish(ctx, b.channel, payload).Err(); err != nil {
+		return fmt.Errorf("error publishing invalidation event: %w", err)
+	}
+
+	return nil
+}
+
+// Subscribe se suscribe al canal de Redis y entrega los eventos hasta que se cancele el contexto.
+func (b *RedisBus) Subscribe(ctx context.Context, handler service.InvalidationHandler) error {
+	pubsub := b.client.Subscribe(ctx, b.channel)
+
+	// Esperar la confirmación de la suscripción para reportar errores de conexión
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		return fmt.Errorf("error subscribing to %s: %w", b.channel, err)
+	}
+
+	go func() {
+		defer pubsub.Close()
+
+		messages := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case message, ok := <-messages:
+				if !ok {
+					return
+				}
+
+				var event service.InvalidationEvent
+				if err := json.Unmarshal([]byte(message.Payload), &event); err != nil {
+					slog.Warn("Evento de invalidación inválido", "channel", b.channel, "error", err)
+					continue
+				}
+
+				handler(ctx, event)
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Close cierra la conexión con Redis.
+func (b *RedisBus) Close() error {
+	return b.client.Close()
+}
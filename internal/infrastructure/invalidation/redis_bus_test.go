@@ -0,0 +1,63 @@
+package invalidation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+
+	"gobcv/internal/domain/service"
+)
+
+func TestRedisBusDeliversBetweenReplicas(t *testing.T) {
+	server := miniredis.RunT(t)
+	publisher := NewRedisBus(server.Addr(), "", 0, "gobcv:invalidation")
+	subscriber := NewRedisBus(server.Addr(), "", 0, "gobcv:invalidation")
+	defer publisher.Close()
+	defer subscriber.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events := make(chan service.InvalidationEvent, 1)
+	if err := subscriber.Subscribe(ctx, func(ctx context.Context, event service.InvalidationEvent) { events <- event }); err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+
+	// Un mensaje que no es un evento se descarta sin cortar la suscripción
+	server.Publish("gobcv:invalidation", "not json")
+
+	sent := service.InvalidationEvent{
+		Keys:      []string{"currency:USD"},
+		Tags:      []string{service.CurrenciesTag},
+		Source:    "replica-a",
+		CreatedAt: time.Now().UTC().Truncate(time.Millisecond),
+	}
+	if err := publisher.Publish(context.Background(), sent); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+
+	select {
+	case event := <-events:
+		if event.Source != sent.Source || len(event.Keys) != 1 || event.Keys[0] != "currency:USD" ||
+			len(event.Tags) != 1 || !event.CreatedAt.Equal(sent.CreatedAt) {
+			t.Errorf("event = %+v, want %+v", event, sent)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("event was not delivered")
+	}
+}
+
+func TestRedisBusSubscribeReportsConnectionErrors(t *testing.T) {
+	server := miniredis.RunT(t)
+	bus := NewRedisBus(server.Addr(), "", 0, "gobcv:invalidation")
+	defer bus.Close()
+	server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := bus.Subscribe(ctx, func(context.Context, service.InvalidationEvent) {}); err == nil {
+		t.Error("Subscribe succeeded without a Redis server")
+	}
+}
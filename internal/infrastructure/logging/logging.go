@@ -0,0 +1,73 @@
+// Package logging configura el logging estructurado con log/slog.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"gobcv/pkg/requestid"
+)
+
+// Formatos de salida soportados.
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// New crea un logger con el formato y nivel indicados. Los registros emitidos con
+// un contexto (InfoContext, ErrorContext...) incluyen los atributos request_id y,
+// si hay un span activo, trace_id y span_id.
+func New(w io.Writer, format, level string) (*slog.Logger, error) {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return nil, fmt.Errorf("invalid log level %q: %w", level, err)
+	}
+
+	options := &slog.HandlerOptions{Level: lvl}
+
+	var handler slog.Handler
+	switch strings.ToLower(format) {
+	case FormatText:
+		handler = slog.NewTextHandler(w, options)
+	case FormatJSON:
+		handler = slog.NewJSONHandler(w, options)
+	default:
+		return nil, fmt.Errorf("unknown log format %q", format)
+	}
+
+	return slog.New(contextHandler{Handler: handler}), nil
+}
+
+// contextHandler agrega a cada registro los atributos transportados por el contexto.
+type contextHandler struct {
+	slog.Handler
+}
+
+// Handle agrega el request_id y la traza del contexto antes de delegar.
+func (h contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if id := requestid.FromContext(ctx); id != "" {
+		record.AddAttrs(slog.String("request_id", id))
+	}
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsValid() {
+		record.AddAttrs(
+			slog.String("trace_id", spanContext.TraceID().String()),
+			slog.String("span_id", spanContext.SpanID().String()),
+		)
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+// WithAttrs preserva el comportamiento del handler al derivar loggers.
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+// WithGroup preserva el comportamiento del handler al derivar loggers.
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{Handler: h.Handler.WithGroup(name)}
+}
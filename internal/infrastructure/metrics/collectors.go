@@ -0,0 +1,112 @@
+// Package metrics implementa los collectors que leen el estado del caché y del repositorio.
+package metrics
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"gobcv/internal/domain/repository"
+	"gobcv/internal/domain/service"
+)
+
+// collectTimeout limita la duración de las consultas hechas durante un scrape de Prometheus.
+const collectTimeout = 5 * time.Second
+
+// CacheCollector expone las estadísticas de CacheService.GetStats. Los niveles de un
+// caché en dos niveles se reportan con su propia etiqueta tier; el agregado usa tier="total".
+type CacheCollector struct {
+	cache     service.CacheService
+	hits      *prometheus.Desc
+	misses    *prometheus.Desc
+	staleHits *prometheus.Desc
+	keys      *prometheus.Desc
+}
+
+// NewCacheCollector crea el collector de estadísticas del caché.
+func NewCacheCollector(cache service.CacheService) *CacheCollector {
+	labels := []string{"tier"}
+	return &CacheCollector{
+		cache:     cache,
+		hits:      prometheus.NewDesc(namespace+"_cache_hits_total", "Lecturas del caché que encontraron la clave.", labels, nil),
+		misses:    prometheus.NewDesc(namespace+"_cache_misses_total", "Lecturas del caché que no encontraron la clave.", labels, nil),
+		staleHits: prometheus.NewDesc(namespace+"_cache_stale_hits_total", "Lecturas del caché servidas con datos obsoletos.", labels, nil),
+		keys:      prometheus.NewDesc(namespace+"_cache_keys", "Claves almacenadas en el caché.", labels, nil),
+	}
+}
+
+// Describe envía las descripciones de las métricas.
+func (c *CacheCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.hits
+	ch <- c.misses
+	ch <- c.staleHits
+	ch <- c.keys
+}
+
+// Collect lee las estadísticas actuales del caché.
+func (c *CacheCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), collectTimeout)
+	defer cancel()
+
+	stats, err := c.cache.GetStats(ctx)
+	if err != nil {
+		slog.Warn("Error obteniendo estadísticas del caché para métricas", "error", err)
+		return
+	}
+
+	c.collectStats(ch, "total", stats)
+	for tier, tierStats := range stats.Tiers {
+		c.collectStats(ch, tier, tierStats)
+	}
+}
+
+func (c *CacheCollector) collectStats(ch chan<- prometheus.Metric, tier string, stats service.CacheStats) {
+	ch <- prometheus.MustNewConstMetric(c.hits, prometheus.CounterValue, float64(stats.Hits), tier)
+	ch <- prometheus.MustNewConstMetric(c.misses, prometheus.CounterValue, float64(stats.Misses), tier)
+	ch <- prometheus.MustNewConstMetric(c.staleHits, prometheus.CounterValue, float64(stats.StaleHits), tier)
+	ch <- prometheus.MustNewConstMetric(c.keys, prometheus.GaugeValue, float64(stats.Keys), tier)
+}
+
+// CurrencyCollector expone el valor de cada moneda y el momento de su última
+// actualización exitosa, leídos del repositorio compartido por las réplicas.
+type CurrencyCollector struct {
+	repo        repository.CurrencyRepository
+	rate        *prometheus.Desc
+	lastRefresh *prometheus.Desc
+}
+
+// NewCurrencyCollector crea el collector de monedas.
+func NewCurrencyCollector(repo repository.CurrencyRepository) *CurrencyCollector {
+	labels := []string{"currency"}
+	return &CurrencyCollector{
+		repo:        repo,
+		rate:        prometheus.NewDesc(namespace+"_currency_rate", "Tipo de cambio oficial en bolívares.", labels, nil),
+		lastRefresh: prometheus.NewDesc(namespace+"_currency_last_refresh_timestamp_seconds", "Momento de la última actualización exitosa de la moneda.", labels, nil),
+	}
+}
+
+// Describe envía las descripciones de las métricas.
+func (c *CurrencyCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.rate
+	ch <- c.lastRefresh
+}
+
+// Collect lee las monedas actuales del repositorio.
+func (c *CurrencyCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), collectTimeout)
+	defer cancel()
+
+	currencies, err := c.repo.FindAll(ctx)
+	if err != nil {
+		slog.Warn("Error obteniendo monedas para métricas", "error", err)
+		return
+	}
+
+	for _, currency := range currencies {
+		ch <- prometheus.MustNewConstMetric(c.rate, prometheus.GaugeValue, currency.Value, currency.ID)
+		ch <- prometheus.MustNewConstMetric(c.lastRefresh, prometheus.GaugeValue,
+			float64(currency.UpdatedAt.UnixNano())/1e9, currency.ID)
+	}
+}
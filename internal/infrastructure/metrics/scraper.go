@@ -0,0 +1,102 @@
+// Package metrics implementa la instrumentación del scraper.
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"gobcv/internal/domain/entity"
+	"gobcv/internal/domain/service"
+)
+
+// Operaciones del scraper registradas en la etiqueta operation.
+const (
+	operationHealth     = "health"
+	operationCurrencies = "currencies"
+	operationCurrency   = "currency"
+)
+
+// InstrumentedScraper decora un CurrencyScraper registrando intentos, fallas y
+// duración de cada operación contra BCV, incluida la verificación de salud previa
+// a cada actualización.
+type InstrumentedScraper struct {
+	scraper     service.CurrencyScraper
+	attempts    *prometheus.CounterVec
+	failures    *prometheus.CounterVec
+	duration    *prometheus.HistogramVec
+	lastSuccess prometheus.Gauge
+}
+
+// NewInstrumentedScraper crea el decorador y registra sus métricas.
+func NewInstrumentedScraper(scraper service.CurrencyScraper, registerer prometheus.Registerer) service.CurrencyScraper {
+	s := &InstrumentedScraper{
+		scraper: scraper,
+		attempts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "scrape_attempts_total",
+			Help:      "Intentos de consultar BCV.",
+		}, []string{"operation"}),
+		failures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "scrape_failures_total",
+			Help:      "Consultas fallidas a BCV.",
+		}, []string{"operation"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "scrape_duration_seconds",
+			Help:      "Duración de las consultas a BCV.",
+			Buckets:   []float64{0.25, 0.5, 1, 2.5, 5, 10, 20, 30},
+		}, []string{"operation"}),
+		lastSuccess: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "scrape_last_success_timestamp_seconds",
+			Help:      "Momento del último scraping exitoso en esta réplica.",
+		}),
+	}
+
+	registerer.MustRegister(s.attempts, s.failures, s.duration, s.lastSuccess)
+
+	return s
+}
+
+// ScrapeCurrencies obtiene las monedas registrando el intento.
+func (s *InstrumentedScraper) ScrapeCurrencies(ctx context.Context) ([]*entity.Currency, error) {
+	start := s.begin(operationCurrencies)
+	currencies, err := s.scraper.ScrapeCurrencies(ctx)
+	s.end(operationCurrencies, start, err)
+	return currencies, err
+}
+
+// ScrapeCurrency obtiene una moneda registrando el intento.
+func (s *InstrumentedScraper) ScrapeCurrency(ctx context.Context, currencyID string) (*entity.Currency, error) {
+	start := s.begin(operationCurrency)
+	currency, err := s.scraper.ScrapeCurrency(ctx, currencyID)
+	s.end(operationCurrency, start, err)
+	return currency, err
+}
+
+// IsHealthy verifica la disponibilidad de BCV registrando el intento.
+func (s *InstrumentedScraper) IsHealthy(ctx context.Context) error {
+	start := s.begin(operationHealth)
+	err := s.scraper.IsHealthy(ctx)
+	s.end(operationHealth, start, err)
+	return err
+}
+
+func (s *InstrumentedScraper) begin(operation string) time.Time {
+	s.attempts.WithLabelValues(operation).Inc()
+	return time.Now()
+}
+
+func (s *InstrumentedScraper) end(operation string, start time.Time, err error) {
+	s.duration.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+	if err != nil {
+		s.failures.WithLabelValues(operation).Inc()
+		return
+	}
+	if operation != operationHealth {
+		s.lastSuccess.SetToCurrentTime()
+	}
+}
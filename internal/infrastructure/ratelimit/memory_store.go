@@ -0,0 +1,94 @@
+// Package ratelimit implementa los almacenes de token buckets para la limitación de peticiones.
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"gobcv/internal/domain/service"
+)
+
+// bucket representa el estado de un token bucket.
+type bucket struct {
+	tokens    float64
+	updatedAt time.Time
+	period    time.Duration
+}
+
+// MemoryStore implementa el almacén de token buckets en memoria de la réplica.
+type MemoryStore struct {
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	mutex     sync.Mutex
+}
+
+// NewMemoryStore crea un nuevo almacén de token buckets en memoria.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets:   make(map[string]*bucket),
+		lastSweep: time.Now(),
+	}
+}
+
+// Take consume un token del bucket de la clave indicada.
+func (s *MemoryStore) Take(ctx context.Context, key string, limit service.RateLimit) (service.RateLimitResult, error) {
+	now := time.Now()
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.sweepLocked(now)
+
+	b, exists := s.buckets[key]
+	if !exists {
+		b = &bucket{tokens: float64(limit.Burst), updatedAt: now}
+		s.buckets[key] = b
+	}
+	b.period = limit.Period
+
+	result := takeToken(&b.tokens, b.updatedAt, now, limit)
+	b.updatedAt = now
+
+	return result, nil
+}
+
+// sweepLocked elimina periódicamente los buckets que ya se recargaron por completo,
+// ya que equivalen a un bucket nuevo. Debe llamarse con el mutex tomado.
+func (s *MemoryStore) sweepLocked(now time.Time) {
+	if now.Sub(s.lastSweep) < time.Minute {
+		return
+	}
+
+	for key, b := range s.buckets {
+		if now.Sub(b.updatedAt) >= b.period {
+			delete(s.buckets, key)
+		}
+	}
+	s.lastSweep = now
+}
+
+// takeToken recarga el bucket según el tiempo transcurrido y consume un token si hay disponible.
+func takeToken(tokens *float64, updatedAt time.Time, now time.Time, limit service.RateLimit) service.RateLimitResult {
+	burst := float64(limit.Burst)
+	refill := limit.RefillInterval()
+
+	elapsed := now.Sub(updatedAt)
+	if elapsed > 0 && refill > 0 {
+		*tokens = math.Min(burst, *tokens+float64(elapsed)/float64(refill))
+	}
+
+	result := service.RateLimitResult{Limit: limit.Burst}
+	if *tokens >= 1 {
+		*tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = time.Duration((1 - *tokens) * float64(refill))
+	}
+
+	result.Remaining = int(*tokens)
+	result.ResetAfter = time.Duration((burst - *tokens) * float64(refill))
+
+	return result
+}
@@ -0,0 +1,109 @@
+// Package ratelimit implementa el almacén de token buckets sobre Redis.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"gobcv/internal/domain/service"
+)
+
+// takeScript recarga y consume un token de forma atómica. Los buckets se guardan
+// como hashes con los tokens y la marca de tiempo en microsegundos, y expiran
+// cuando tendrían que estar llenos de nuevo.
+//
+// KEYS[1] = bucket, ARGV[1] = burst, ARGV[2] = intervalo de recarga (µs), ARGV[3] = ahora (µs)
+var takeScript = redis.NewScript(`
+local burst = tonumber(ARGV[1])
+local refill = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+
+local state = redis.call("HMGET", KEYS[1], "tokens", "ts")
+local tokens = tonumber(state[1])
+local ts = tonumber(state[2])
+if tokens == nil then
+  tokens = burst
+  ts = now
+end
+
+if now > ts and refill > 0 then
+  tokens = math.min(burst, tokens + (now - ts) / refill)
+end
+
+local allowed = 0
+if tokens >= 1 then
+  tokens = tokens - 1
+  allowed = 1
+end
+
+redis.call("HSET", KEYS[1], "tokens", tostring(tokens), "ts", now)
+redis.call("PEXPIRE", KEYS[1], math.ceil(burst * refill / 1000) + 1000)
+
+return {allowed, tostring(tokens)}
+`)
+
+// RedisStore implementa el almacén de token buckets compartido entre réplicas sobre Redis.
+type RedisStore struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewRedisStore crea un nuevo almacén de token buckets sobre Redis.
+func NewRedisStore(addr, password string, db int, prefix string) *RedisStore {
+	return &RedisStore{
+		client: redis.NewClient(&redis.Options{
+			Addr:     addr,
+			Password: password,
+			DB:       db,
+		}),
+		prefix: prefix,
+	}
+}
+
+// Ping verifica la conexión con Redis.
+func (s *RedisStore) Ping(ctx context.Context) error {
+	return s.client.Ping(ctx).Err()
+}
+
+// Close cierra la conexión con Redis.
+func (s *RedisStore) Close() error {
+	return s.client.Close()
+}
+
+// Take consume un token del bucket de la clave indicada.
+func (s *RedisStore) Take(ctx context.Context, key string, limit service.RateLimit) (service.RateLimitResult, error) {
+	refill := limit.RefillInterval()
+
+	values, err := takeScript.Run(ctx, s.client, []string{s.prefix + key},
+		limit.Burst, refill.Microseconds(), time.Now().UnixMicro()).Slice()
+	if err != nil {
+		return service.RateLimitResult{}, fmt.Errorf("error taking rate limit token: %w", err)
+	}
+
+	if len(values) != 2 {
+		return service.RateLimitResult{}, fmt.Errorf("unexpected rate limit script result: %v", values)
+	}
+
+	allowed, _ := values[0].(int64)
+	raw, _ := values[1].(string)
+	tokens, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return service.RateLimitResult{}, fmt.Errorf("error parsing rate limit tokens: %w", err)
+	}
+
+	result := service.RateLimitResult{
+		Allowed:    allowed == 1,
+		Limit:      limit.Burst,
+		Remaining:  int(tokens),
+		ResetAfter: time.Duration((float64(limit.Burst) - tokens) * float64(refill)),
+	}
+	if !result.Allowed {
+		result.RetryAfter = time.Duration((1 - tokens) * float64(refill))
+	}
+
+	return result, nil
+}
@@ -0,0 +1,106 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+
+	"gobcv/internal/domain/service"
+)
+
+// testStores retorna los almacenes a probar con el mismo comportamiento.
+func testStores(t *testing.T) map[string]service.RateLimitStore {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	redisStore := NewRedisStore(server.Addr(), "", 0, "gobcv:ratelimit:")
+	t.Cleanup(func() { redisStore.Close() })
+
+	return map[string]service.RateLimitStore{
+		"memory": NewMemoryStore(),
+		"redis":  redisStore,
+	}
+}
+
+func TestStoreConsumesAndRefillsTokens(t *testing.T) {
+	ctx := context.Background()
+	limit := service.RateLimit{Burst: 2, Period: 400 * time.Millisecond}
+
+	for name, store := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			for i, wantRemaining := range []int{1, 0} {
+				result, err := store.Take(ctx, "client", limit)
+				if err != nil {
+					t.Fatalf("Take: %v", err)
+				}
+				if !result.Allowed || result.Limit != 2 || result.Remaining != wantRemaining {
+					t.Errorf("take %d = %+v, want allowed with %d remaining", i+1, result, wantRemaining)
+				}
+			}
+
+			result, err := store.Take(ctx, "client", limit)
+			if err != nil {
+				t.Fatalf("Take: %v", err)
+			}
+			if result.Allowed || result.RetryAfter <= 0 || result.RetryAfter > limit.RefillInterval() {
+				t.Errorf("take over the burst = %+v, want rejected within one refill interval", result)
+			}
+			if result.ResetAfter <= limit.RefillInterval() || result.ResetAfter > limit.Period {
+				t.Errorf("ResetAfter = %s, want up to the period", result.ResetAfter)
+			}
+
+			// Otra clave tiene su propio bucket
+			if other, _ := store.Take(ctx, "other", limit); !other.Allowed {
+				t.Errorf("other key = %+v, want allowed", other)
+			}
+
+			// Pasado un intervalo de recarga hay un token nuevo, y solo uno
+			time.Sleep(limit.RefillInterval() + 20*time.Millisecond)
+			if result, _ := store.Take(ctx, "client", limit); !result.Allowed || result.Remaining != 0 {
+				t.Errorf("take after refill = %+v, want allowed with 0 remaining", result)
+			}
+			if result, _ := store.Take(ctx, "client", limit); result.Allowed {
+				t.Errorf("second take after refill = %+v, want rejected", result)
+			}
+		})
+	}
+}
+
+func TestRedisStoreExpiresFullBuckets(t *testing.T) {
+	ctx := context.Background()
+	server := miniredis.RunT(t)
+	store := NewRedisStore(server.Addr(), "", 0, "gobcv:ratelimit:")
+	defer store.Close()
+
+	limit := service.RateLimit{Burst: 10, Period: time.Minute}
+	if _, err := store.Take(ctx, "read:ip:192.0.2.1", limit); err != nil {
+		t.Fatalf("Take: %v", err)
+	}
+
+	key := "gobcv:ratelimit:read:ip:192.0.2.1"
+	if ttl := server.TTL(key); ttl <= limit.Period || ttl > limit.Period+time.Second {
+		t.Errorf("bucket TTL = %s, want the time to refill the bucket", ttl)
+	}
+	if tokens := server.HGet(key, "tokens"); tokens != "9" {
+		t.Errorf("tokens = %q, want 9", tokens)
+	}
+}
+
+func TestTakeTokenRefillsContinuously(t *testing.T) {
+	limit := service.RateLimit{Burst: 4, Period: 4 * time.Second}
+	start := time.Now()
+	tokens := 0.0
+
+	result := takeToken(&tokens, start, start.Add(500*time.Millisecond), limit)
+	if result.Allowed || result.RetryAfter != 500*time.Millisecond {
+		t.Errorf("half a token = %+v, want rejected for 500ms", result)
+	}
+
+	tokens = 0
+	result = takeToken(&tokens, start, start.Add(time.Hour), limit)
+	if !result.Allowed || result.Remaining != 3 || result.ResetAfter != time.Second {
+		t.Errorf("after a long pause = %+v, want the burst capped at 4", result)
+	}
+}
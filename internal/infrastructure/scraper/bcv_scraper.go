@@ -5,15 +5,22 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"strconv"
 	"strings"
 	"time"
 
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/net/html"
 
 	"gobcv/internal/domain/entity"
 	"gobcv/internal/domain/service"
+	"gobcv/pkg/telemetry"
 )
 
 // BCVScraper implementa el servicio de scraping del Banco Central de Venezuela.
@@ -39,6 +46,12 @@ func (s *BCVScraper) ScrapeCurrencies(ctx context.Context) ([]*entity.Currency,
 		return nil, fmt.Errorf("error getting HTML: %w", err)
 	}
 
+	return s.parseCurrencies(htmlStr)
+}
+
+// parseCurrencies extrae las monedas de una página del BCV. El origen de las monedas
+// es la URL base del scraper.
+func (s *BCVScraper) parseCurrencies(htmlStr string) ([]*entity.Currency, error) {
 	doc, err := html.Parse(strings.NewReader(htmlStr))
 	if err != nil {
 		return nil, fmt.Errorf("error parsing HTML: %w", err)
@@ -77,6 +90,11 @@ func (s *BCVScraper) ScrapeCurrency(ctx context.Context, currencyID string) (*en
 		return nil, err
 	}
 
+	return findCurrency(currencies, currencyID)
+}
+
+// findCurrency busca una moneda por su ID entre las obtenidas por el scraper.
+func findCurrency(currencies []*entity.Currency, currencyID string) (*entity.Currency, error) {
 	for _, currency := range currencies {
 		if currency.ID == currencyID {
 			return currency, nil
@@ -108,25 +126,47 @@ func (s *BCVScraper) IsHealthy(ctx context.Context) error {
 
 // getHTML obtiene el HTML de una URL.
 func (s *BCVScraper) getHTML(ctx context.Context, url string) (string, error) {
+	ctx, span := telemetry.Tracer().Start(ctx, "BCVScraper.getHTML",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.request.method", http.MethodGet),
+			attribute.String("url.full", url),
+		),
+	)
+	defer span.End()
+
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return "", err
 	}
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
 
+	start := time.Now()
 	resp, err := s.httpClient.Do(req)
 	if err != nil {
+		slog.WarnContext(ctx, "Error consultando BCV", "url", url, "error", err)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
 		return "", err
 	}
 	defer resp.Body.Close()
 
+	slog.DebugContext(ctx, "Respuesta de BCV", "url", url, "status", resp.StatusCode, "duration", time.Since(start))
+	span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("HTTP request failed with status %d", resp.StatusCode)
+		err := fmt.Errorf("HTTP request failed with status %d", resp.StatusCode)
+		span.SetStatus(codes.Error, err.Error())
+		return "", err
 	}
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
 		return "", err
 	}
+	span.SetAttributes(attribute.Int("http.response.body.size", len(body)))
 
 	return string(body), nil
 }
@@ -0,0 +1,94 @@
+package scraper
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"gobcv/pkg/requestid"
+)
+
+func TestBCVScraperTracesAndPropagatesRequests(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	previousProvider, previousPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		provider.Shutdown(context.Background())
+		otel.SetTracerProvider(previousProvider)
+		otel.SetTextMapPropagator(previousPropagator)
+	})
+
+	var traceparent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		w.Write([]byte("<html><body></body></html>"))
+	}))
+	defer server.Close()
+
+	scraper := &BCVScraper{baseURL: server.URL, httpClient: server.Client()}
+
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "parent")
+	if _, err := scraper.getHTML(ctx, server.URL); err != nil {
+		t.Fatalf("getHTML: %v", err)
+	}
+	parent.End()
+
+	var span *tracetest.SpanStub
+	spans := exporter.GetSpans()
+	for i := range spans {
+		if spans[i].Name == "BCVScraper.getHTML" {
+			span = &spans[i]
+		}
+	}
+	if span == nil {
+		t.Fatalf("no scraper span in %d spans", len(spans))
+	}
+
+	if span.SpanKind != trace.SpanKindClient {
+		t.Errorf("span kind = %v, want client", span.SpanKind)
+	}
+	if span.Parent.SpanID() != parent.SpanContext().SpanID() {
+		t.Error("scraper span is not a child of the caller span")
+	}
+
+	// El servidor consultado continúa la traza desde el span del scraper
+	want := "00-" + span.SpanContext.TraceID().String() + "-" + span.SpanContext.SpanID().String() + "-01"
+	if traceparent != want {
+		t.Errorf("traceparent = %q, want %q", traceparent, want)
+	}
+}
+
+func TestBCVScraperDoesNotForwardRequestIDs(t *testing.T) {
+	var requestIDs []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestIDs = append(requestIDs, r.Header.Get(requestid.Header))
+		w.Write([]byte("<html><body></body></html>"))
+	}))
+	defer server.Close()
+
+	scraper := &BCVScraper{baseURL: server.URL, httpClient: server.Client()}
+
+	// El ID puede venir del cliente de la API: no se envía a un tercero
+	ctx := requestid.WithID(context.Background(), "client-supplied-id")
+	if _, err := scraper.getHTML(ctx, server.URL); err != nil {
+		t.Fatalf("getHTML: %v", err)
+	}
+	if err := scraper.IsHealthy(ctx); err != nil {
+		t.Fatalf("IsHealthy: %v", err)
+	}
+
+	for _, id := range requestIDs {
+		if id != "" {
+			t.Errorf("BCV received %s %q", requestid.Header, id)
+		}
+	}
+}
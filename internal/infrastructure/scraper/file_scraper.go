@@ -0,0 +1,62 @@
+// Package scraper implementa el scraper sobre una página del BCV guardada en disco.
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gobcv/internal/domain/entity"
+	"gobcv/internal/domain/service"
+)
+
+// FileScraper obtiene las monedas de una página del BCV guardada en disco, lo que
+// permite trabajar sin conexión o reproducir una página concreta.
+type FileScraper struct {
+	path   string
+	parser *BCVScraper
+}
+
+// NewFileScraper crea un scraper que lee la página HTML indicada en cada consulta.
+// El origen de las monedas es la URL file:// del archivo.
+func NewFileScraper(path string) (service.CurrencyScraper, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("error resolving %s: %w", path, err)
+	}
+
+	return &FileScraper{
+		path:   absPath,
+		parser: &BCVScraper{baseURL: "file://" + filepath.ToSlash(absPath)},
+	}, nil
+}
+
+// ScrapeCurrencies obtiene las monedas desde el archivo.
+func (s *FileScraper) ScrapeCurrencies(ctx context.Context) ([]*entity.Currency, error) {
+	content, err := os.ReadFile(s.path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading HTML: %w", err)
+	}
+
+	return s.parser.parseCurrencies(string(content))
+}
+
+// ScrapeCurrency obtiene una moneda específica desde el archivo.
+func (s *FileScraper) ScrapeCurrency(ctx context.Context, currencyID string) (*entity.Currency, error) {
+	currencies, err := s.ScrapeCurrencies(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return findCurrency(currencies, currencyID)
+}
+
+// IsHealthy verifica que el archivo exista y se pueda leer.
+func (s *FileScraper) IsHealthy(ctx context.Context) error {
+	file, err := os.Open(s.path)
+	if err != nil {
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,77 @@
+package scraper
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFileScraperParsesSavedPage(t *testing.T) {
+	ctx := context.Background()
+
+	scraper, err := NewFileScraper(filepath.Join("testdata", "bcv.html"))
+	if err != nil {
+		t.Fatalf("NewFileScraper: %v", err)
+	}
+	if err := scraper.IsHealthy(ctx); err != nil {
+		t.Errorf("IsHealthy: %v", err)
+	}
+
+	currencies, err := scraper.ScrapeCurrencies(ctx)
+	if err != nil {
+		t.Fatalf("ScrapeCurrencies: %v", err)
+	}
+
+	want := map[string]float64{"EUR": 40.12345678, "USD": 36.5}
+	if len(currencies) != len(want) {
+		t.Fatalf("got %d currencies, want %d", len(currencies), len(want))
+	}
+	for _, currency := range currencies {
+		if currency.Value != want[currency.ID] {
+			t.Errorf("%s = %v, want %v", currency.ID, currency.Value, want[currency.ID])
+		}
+		// El origen es la URL file:// absoluta del archivo
+		if !strings.HasPrefix(currency.Source, "file:///") || !strings.HasSuffix(currency.Source, "/testdata/bcv.html") {
+			t.Errorf("%s source = %q, want the file:// URL of the page", currency.ID, currency.Source)
+		}
+	}
+
+	usd, err := scraper.ScrapeCurrency(ctx, "USD")
+	if err != nil || usd.Value != 36.5 {
+		t.Errorf("ScrapeCurrency(USD) = %+v, %v", usd, err)
+	}
+	if _, err := scraper.ScrapeCurrency(ctx, "CNY"); err == nil {
+		t.Error("ScrapeCurrency(CNY) succeeded, want not found")
+	}
+}
+
+func TestFileScraperErrors(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	missing, err := NewFileScraper(filepath.Join(dir, "missing.html"))
+	if err != nil {
+		t.Fatalf("NewFileScraper: %v", err)
+	}
+	if err := missing.IsHealthy(ctx); err == nil {
+		t.Error("IsHealthy on a missing file succeeded")
+	}
+	if _, err := missing.ScrapeCurrencies(ctx); err == nil || !strings.Contains(err.Error(), "error reading HTML") {
+		t.Errorf("ScrapeCurrencies on a missing file = %v", err)
+	}
+
+	// Una página que no es la del BCV no tiene monedas
+	other := filepath.Join(dir, "other.html")
+	if err := os.WriteFile(other, []byte("<html><body><p>Mantenimiento</p></body></html>"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	scraper, err := NewFileScraper(other)
+	if err != nil {
+		t.Fatalf("NewFileScraper: %v", err)
+	}
+	if _, err := scraper.ScrapeCurrencies(ctx); err == nil || !strings.Contains(err.Error(), "no currencies found") {
+		t.Errorf("ScrapeCurrencies on another page = %v", err)
+	}
+}
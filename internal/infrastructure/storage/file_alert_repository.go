@@ -0,0 +1,73 @@
+// Package storage implementa el repositorio de reglas de alerta persistido en archivo.
+package storage
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"gobcv/internal/domain/entity"
+	"gobcv/internal/domain/repository"
+	"gobcv/internal/infrastructure/cache"
+)
+
+// FileAlertRepository persiste las reglas de alerta en un archivo JSON. Las alertas
+// disparadas solo se conservan en memoria.
+type FileAlertRepository struct {
+	repository.AlertRepository
+	path  string
+	mutex sync.Mutex
+}
+
+// NewFileAlertRepository crea el repositorio cargando las reglas del archivo indicado.
+// Si el archivo no existe se crea con la primera modificación.
+func NewFileAlertRepository(ctx context.Context, path string, historySize int) (repository.AlertRepository, error) {
+	r := &FileAlertRepository{
+		AlertRepository: cache.NewMemoryAlertRepository(historySize),
+		path:            path,
+	}
+
+	var rules []*entity.AlertRule
+	if err := readJSONFile(path, &rules); err != nil {
+		return nil, err
+	}
+
+	for _, rule := range rules {
+		if err := r.AlertRepository.SaveRule(ctx, rule); err != nil {
+			return nil, fmt.Errorf("error loading alert rule %q: %w", rule.ID, err)
+		}
+	}
+
+	return r, nil
+}
+
+// SaveRule guarda o actualiza una regla y persiste el archivo.
+func (r *FileAlertRepository) SaveRule(ctx context.Context, rule *entity.AlertRule) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if err := r.AlertRepository.SaveRule(ctx, rule); err != nil {
+		return err
+	}
+	return r.flush(ctx)
+}
+
+// DeleteRule elimina una regla y persiste el archivo.
+func (r *FileAlertRepository) DeleteRule(ctx context.Context, id string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if err := r.AlertRepository.DeleteRule(ctx, id); err != nil {
+		return err
+	}
+	return r.flush(ctx)
+}
+
+// flush reescribe el archivo con todas las reglas.
+func (r *FileAlertRepository) flush(ctx context.Context) error {
+	rules, err := r.AlertRepository.FindRules(ctx)
+	if err != nil {
+		return err
+	}
+	return writeJSONFile(r.path, rules)
+}
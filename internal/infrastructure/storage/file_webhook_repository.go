@@ -0,0 +1,83 @@
+// Package storage implementa repositorios persistidos en archivos locales.
+package storage
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"gobcv/internal/domain/entity"
+	"gobcv/internal/domain/repository"
+	"gobcv/internal/infrastructure/cache"
+)
+
+// FileWebhookRepository persiste los webhooks en un archivo JSON. Las lecturas se
+// sirven desde memoria y cada modificación reescribe el archivo de forma atómica.
+type FileWebhookRepository struct {
+	memory repository.WebhookRepository
+	path   string
+	mutex  sync.Mutex
+}
+
+// NewFileWebhookRepository crea el repositorio cargando los webhooks del archivo
+// indicado. Si el archivo no existe se crea con la primera modificación.
+func NewFileWebhookRepository(ctx context.Context, path string) (repository.WebhookRepository, error) {
+	r := &FileWebhookRepository{
+		memory: cache.NewMemoryWebhookRepository(),
+		path:   path,
+	}
+
+	var webhooks []*entity.Webhook
+	if err := readJSONFile(path, &webhooks); err != nil {
+		return nil, err
+	}
+
+	for _, webhook := range webhooks {
+		if err := r.memory.Save(ctx, webhook); err != nil {
+			return nil, fmt.Errorf("error loading webhook %q: %w", webhook.ID, err)
+		}
+	}
+
+	return r, nil
+}
+
+// Save guarda o actualiza un webhook y persiste el archivo.
+func (r *FileWebhookRepository) Save(ctx context.Context, webhook *entity.Webhook) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if err := r.memory.Save(ctx, webhook); err != nil {
+		return err
+	}
+	return r.flush(ctx)
+}
+
+// FindByID busca un webhook por su ID.
+func (r *FileWebhookRepository) FindByID(ctx context.Context, id string) (*entity.Webhook, error) {
+	return r.memory.FindByID(ctx, id)
+}
+
+// FindAll obtiene todos los webhooks registrados.
+func (r *FileWebhookRepository) FindAll(ctx context.Context) ([]*entity.Webhook, error) {
+	return r.memory.FindAll(ctx)
+}
+
+// Delete elimina un webhook y persiste el archivo.
+func (r *FileWebhookRepository) Delete(ctx context.Context, id string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if err := r.memory.Delete(ctx, id); err != nil {
+		return err
+	}
+	return r.flush(ctx)
+}
+
+// flush reescribe el archivo con todos los webhooks.
+func (r *FileWebhookRepository) flush(ctx context.Context) error {
+	webhooks, err := r.memory.FindAll(ctx)
+	if err != nil {
+		return err
+	}
+	return writeJSONFile(r.path, webhooks)
+}
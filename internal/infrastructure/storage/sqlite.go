@@ -0,0 +1,306 @@
+// Package storage implementa los repositorios de monedas e historial sobre SQLite.
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"math"
+	"strings"
+	"time"
+
+	// Driver database/sql de SQLite; requiere compilar con CGO_ENABLED=1
+	_ "github.com/mattn/go-sqlite3"
+
+	"gobcv/internal/domain/entity"
+	"gobcv/internal/domain/repository"
+)
+
+// sqliteSchema crea las tablas si no existen. Las fechas se guardan como nanosegundos
+// Unix para conservar la precisión y ordenarlas como enteros.
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS currencies (
+	id         TEXT PRIMARY KEY,
+	name       TEXT NOT NULL,
+	value      REAL NOT NULL,
+	source     TEXT NOT NULL,
+	updated_at INTEGER NOT NULL
+);
+CREATE TABLE IF NOT EXISTS rate_changes (
+	sequence       INTEGER PRIMARY KEY AUTOINCREMENT,
+	currency_id    TEXT NOT NULL,
+	name           TEXT NOT NULL,
+	value          REAL NOT NULL,
+	previous_value REAL NOT NULL,
+	source         TEXT NOT NULL,
+	changed_at     INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS rate_changes_currency ON rate_changes (currency_id, changed_at);
+`
+
+// OpenSQLite abre la base de datos SQLite del archivo indicado, creándolo junto con
+// las tablas si no existen. La misma conexión puede compartirse entre el repositorio
+// de monedas y el historial.
+func OpenSQLite(ctx context.Context, path string) (*sql.DB, error) {
+	db, err := sql.Open("sqlite3", "file:"+path+"?_busy_timeout=5000&_journal_mode=WAL")
+	if err != nil {
+		return nil, fmt.Errorf("error opening sqlite database %s: %w", path, err)
+	}
+
+	// SQLite admite un solo escritor; una conexión evita errores de base bloqueada
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.ExecContext(ctx, sqliteSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("error initializing sqlite database %s: %w", path, err)
+	}
+
+	return db, nil
+}
+
+// SQLiteRepository implementa el repositorio de monedas sobre SQLite.
+type SQLiteRepository struct {
+	db *sql.DB
+}
+
+// NewSQLiteRepository crea un repositorio de monedas sobre una base abierta con OpenSQLite.
+func NewSQLiteRepository(db *sql.DB) repository.CurrencyRepository {
+	return &SQLiteRepository{db: db}
+}
+
+// Save guarda o actualiza una moneda en el repositorio.
+func (r *SQLiteRepository) Save(ctx context.Context, currency *entity.Currency) error {
+	if currency == nil {
+		return fmt.Errorf("currency cannot be nil")
+	}
+
+	if !currency.IsValid() {
+		return fmt.Errorf("currency is not valid")
+	}
+
+	// Actualizar timestamp
+	currency.UpdatedAt = time.Now()
+
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO currencies (id, name, value, source, updated_at) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET
+			name = excluded.name, value = excluded.value,
+			source = excluded.source, updated_at = excluded.updated_at`,
+		currency.ID, currency.Name, currency.Value, currency.Source, currency.UpdatedAt.UnixNano())
+	if err != nil {
+		return fmt.Errorf("error saving currency %s: %w", currency.ID, err)
+	}
+
+	slog.DebugContext(ctx, "Moneda guardada en repositorio", "currency", currency.ID, "value", currency.Value)
+
+	return nil
+}
+
+// FindByID busca una moneda por su ID.
+func (r *SQLiteRepository) FindByID(ctx context.Context, id string) (*entity.Currency, error) {
+	if id == "" {
+		return nil, fmt.Errorf("id cannot be empty")
+	}
+
+	currencies, err := r.query(ctx, "WHERE id = ?", id)
+	if err != nil || len(currencies) == 0 {
+		return nil, err
+	}
+	return currencies[0], nil
+}
+
+// FindAll obtiene todas las monedas disponibles.
+func (r *SQLiteRepository) FindAll(ctx context.Context) ([]*entity.Currency, error) {
+	currencies, err := r.query(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	if currencies == nil {
+		currencies = []*entity.Currency{}
+	}
+	return currencies, nil
+}
+
+// Delete elimina una moneda del repositorio.
+func (r *SQLiteRepository) Delete(ctx context.Context, id string) error {
+	if id == "" {
+		return fmt.Errorf("id cannot be empty")
+	}
+
+	if _, err := r.db.ExecContext(ctx, "DELETE FROM currencies WHERE id = ?", id); err != nil {
+		return fmt.Errorf("error deleting currency %s: %w", id, err)
+	}
+	return nil
+}
+
+// FindByLastUpdate busca monedas actualizadas después de una fecha específica.
+func (r *SQLiteRepository) FindByLastUpdate(ctx context.Context, since time.Time) ([]*entity.Currency, error) {
+	return r.query(ctx, "WHERE updated_at > ?", unixNano(since))
+}
+
+// Count retorna la cantidad de monedas almacenadas.
+func (r *SQLiteRepository) Count(ctx context.Context) (int, error) {
+	var count int
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM currencies").Scan(&count); err != nil {
+		return 0, fmt.Errorf("error counting currencies: %w", err)
+	}
+	return count, nil
+}
+
+// query obtiene las monedas que cumplen la condición, ordenadas por ID.
+func (r *SQLiteRepository) query(ctx context.Context, where string, args ...interface{}) ([]*entity.Currency, error) {
+	rows, err := r.db.QueryContext(ctx,
+		"SELECT id, name, value, source, updated_at FROM currencies "+where+" ORDER BY id", args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying currencies: %w", err)
+	}
+	defer rows.Close()
+
+	var currencies []*entity.Currency
+	for rows.Next() {
+		var currency entity.Currency
+		var updatedAt int64
+		if err := rows.Scan(&currency.ID, &currency.Name, &currency.Value, &currency.Source, &updatedAt); err != nil {
+			return nil, fmt.Errorf("error reading currency: %w", err)
+		}
+		currency.UpdatedAt = time.Unix(0, updatedAt)
+		currencies = append(currencies, &currency)
+	}
+
+	return currencies, rows.Err()
+}
+
+// SQLiteRateHistory implementa el historial de cambios de tasas sobre SQLite.
+// Conserva como máximo capacity cambios descartando los más antiguos.
+type SQLiteRateHistory struct {
+	db       *sql.DB
+	capacity int
+}
+
+// NewSQLiteRateHistory crea un historial sobre una base abierta con OpenSQLite.
+// capacity <= 0 no limita la cantidad de cambios conservados.
+func NewSQLiteRateHistory(db *sql.DB, capacity int) repository.RateHistoryRepository {
+	return &SQLiteRateHistory{
+		db:       db,
+		capacity: capacity,
+	}
+}
+
+// Append agrega un cambio al historial y le asigna el siguiente número de secuencia.
+func (h *SQLiteRateHistory) Append(ctx context.Context, change *entity.RateChange) error {
+	if change == nil {
+		return fmt.Errorf("rate change cannot be nil")
+	}
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error appending rate change: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		INSERT INTO rate_changes (currency_id, name, value, previous_value, source, changed_at)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		change.CurrencyID, change.Name, change.Value, change.PreviousValue, change.Source, change.ChangedAt.UnixNano())
+	if err != nil {
+		return fmt.Errorf("error appending rate change: %w", err)
+	}
+
+	sequence, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("error appending rate change: %w", err)
+	}
+
+	if h.capacity > 0 {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM rate_changes WHERE sequence <= ?", sequence-int64(h.capacity)); err != nil {
+			return fmt.Errorf("error trimming rate history: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error appending rate change: %w", err)
+	}
+
+	change.Sequence = uint64(sequence)
+	return nil
+}
+
+// FindSince obtiene los cambios posteriores a la secuencia indicada.
+func (h *SQLiteRateHistory) FindSince(ctx context.Context, afterSequence uint64, currencyIDs []string, limit int) ([]*entity.RateChange, error) {
+	where := "WHERE sequence > ?"
+	args := []interface{}{int64(afterSequence)}
+
+	if len(currencyIDs) > 0 {
+		where += " AND currency_id IN (?" + strings.Repeat(", ?", len(currencyIDs)-1) + ")"
+		for _, id := range currencyIDs {
+			args = append(args, id)
+		}
+	}
+
+	where += " ORDER BY sequence"
+	if limit > 0 {
+		where += " LIMIT ?"
+		args = append(args, limit)
+	}
+
+	return h.query(ctx, where, args...)
+}
+
+// FindByCurrency obtiene los cambios de una moneda dentro del rango [from, to].
+func (h *SQLiteRateHistory) FindByCurrency(ctx context.Context, currencyID string, from, to time.Time) ([]*entity.RateChange, error) {
+	if currencyID == "" {
+		return nil, fmt.Errorf("currency id cannot be empty")
+	}
+
+	return h.query(ctx, "WHERE currency_id = ? AND changed_at BETWEEN ? AND ? ORDER BY sequence",
+		currencyID, unixNano(from), unixNano(to))
+}
+
+// LastSequence retorna la secuencia del último cambio registrado.
+func (h *SQLiteRateHistory) LastSequence(ctx context.Context) (uint64, error) {
+	var sequence sql.NullInt64
+	if err := h.db.QueryRowContext(ctx, "SELECT MAX(sequence) FROM rate_changes").Scan(&sequence); err != nil {
+		return 0, fmt.Errorf("error reading last sequence: %w", err)
+	}
+	return uint64(sequence.Int64), nil
+}
+
+// query obtiene los cambios que cumplen la condición.
+func (h *SQLiteRateHistory) query(ctx context.Context, where string, args ...interface{}) ([]*entity.RateChange, error) {
+	rows, err := h.db.QueryContext(ctx, `
+		SELECT sequence, currency_id, name, value, previous_value, source, changed_at
+		FROM rate_changes `+where, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying rate history: %w", err)
+	}
+	defer rows.Close()
+
+	var changes []*entity.RateChange
+	for rows.Next() {
+		var change entity.RateChange
+		var sequence, changedAt int64
+		if err := rows.Scan(&sequence, &change.CurrencyID, &change.Name, &change.Value,
+			&change.PreviousValue, &change.Source, &changedAt); err != nil {
+			return nil, fmt.Errorf("error reading rate change: %w", err)
+		}
+		change.Sequence = uint64(sequence)
+		change.ChangedAt = time.Unix(0, changedAt)
+		changes = append(changes, &change)
+	}
+
+	return changes, rows.Err()
+}
+
+// unixNano convierte una fecha a nanosegundos Unix limitándola al rango representable,
+// ya que time.Time{} y las fechas lejanas desbordan int64.
+func unixNano(t time.Time) int64 {
+	switch {
+	case t.Before(time.Unix(0, 0)):
+		return 0
+	case t.After(time.Unix(0, math.MaxInt64)):
+		return math.MaxInt64
+	default:
+		return t.UnixNano()
+	}
+}
@@ -0,0 +1,201 @@
+//go:build cgo
+
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gobcv/internal/domain/entity"
+)
+
+// openTestDB abre una base SQLite en un archivo temporal.
+func openTestDB(t *testing.T, path string) *sql.DB {
+	t.Helper()
+
+	db, err := OpenSQLite(context.Background(), path)
+	if err != nil {
+		t.Fatalf("OpenSQLite: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestSQLiteRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := NewSQLiteRepository(openTestDB(t, filepath.Join(t.TempDir(), "gobcv.db")))
+
+	if currencies, err := repo.FindAll(ctx); err != nil || currencies == nil || len(currencies) != 0 {
+		t.Fatalf("FindAll on an empty database = %v, %v; want an empty list", currencies, err)
+	}
+
+	before := time.Now()
+	for _, currency := range []*entity.Currency{
+		entity.NewCurrency("USD", "Dólar", 36.5, "test"),
+		entity.NewCurrency("EUR", "Euro", 40, "test"),
+	} {
+		if err := repo.Save(ctx, currency); err != nil {
+			t.Fatalf("Save(%s): %v", currency.ID, err)
+		}
+	}
+
+	// Guardar de nuevo actualiza la fila existente
+	if err := repo.Save(ctx, entity.NewCurrency("USD", "Dólar Americano", 36.6, "bcv")); err != nil {
+		t.Fatalf("Save(USD): %v", err)
+	}
+
+	currencies, err := repo.FindAll(ctx)
+	if err != nil {
+		t.Fatalf("FindAll: %v", err)
+	}
+	if len(currencies) != 2 || currencies[0].ID != "EUR" || currencies[1].ID != "USD" {
+		t.Fatalf("FindAll = %v, want EUR and USD ordered by id", currencies)
+	}
+	usd := currencies[1]
+	if usd.Name != "Dólar Americano" || usd.Value != 36.6 || usd.Source != "bcv" || usd.UpdatedAt.Before(before) {
+		t.Errorf("USD = %+v, want the second save", usd)
+	}
+
+	found, err := repo.FindByID(ctx, "EUR")
+	if err != nil || found == nil || found.Value != 40 {
+		t.Errorf("FindByID(EUR) = %+v, %v", found, err)
+	}
+	if found, err := repo.FindByID(ctx, "XYZ"); err != nil || found != nil {
+		t.Errorf("FindByID(XYZ) = %+v, %v; want nil", found, err)
+	}
+	if updated, err := repo.FindByLastUpdate(ctx, time.Now().Add(time.Hour)); err != nil || len(updated) != 0 {
+		t.Errorf("FindByLastUpdate(future) = %v, %v", updated, err)
+	}
+
+	if err := repo.Save(ctx, entity.NewCurrency("", "Sin código", 1, "test")); err == nil {
+		t.Error("Save accepted an invalid currency")
+	}
+
+	if err := repo.Delete(ctx, "EUR"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if currencies, err := repo.FindAll(ctx); err != nil || len(currencies) != 1 {
+		t.Errorf("FindAll after delete = %v, %v; want only USD", currencies, err)
+	}
+}
+
+func TestSQLiteRateHistory(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "gobcv.db")
+	history := NewSQLiteRateHistory(openTestDB(t, path), 0)
+
+	base := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	appended := []*entity.RateChange{
+		{CurrencyID: "USD", Name: "Dólar", Value: 36.4, Source: "test", ChangedAt: base},
+		{CurrencyID: "EUR", Name: "Euro", Value: 40, Source: "test", ChangedAt: base.Add(time.Hour)},
+		{CurrencyID: "USD", Name: "Dólar", Value: 36.5, PreviousValue: 36.4, Source: "test", ChangedAt: base.Add(24 * time.Hour)},
+		{CurrencyID: "USD", Name: "Dólar", Value: 36.6, PreviousValue: 36.5, Source: "test", ChangedAt: base.Add(48 * time.Hour)},
+	}
+	for i, change := range appended {
+		if err := history.Append(ctx, change); err != nil {
+			t.Fatalf("Append: %v", err)
+		}
+		if change.Sequence != uint64(i+1) {
+			t.Errorf("change %d got sequence %d", i, change.Sequence)
+		}
+	}
+
+	tests := []struct {
+		name      string
+		after     uint64
+		ids       []string
+		limit     int
+		sequences []uint64
+	}{
+		{"all", 0, nil, 0, []uint64{1, 2, 3, 4}},
+		{"after a sequence", 2, nil, 0, []uint64{3, 4}},
+		{"one currency", 0, []string{"USD"}, 0, []uint64{1, 3, 4}},
+		{"several currencies", 1, []string{"USD", "EUR"}, 0, []uint64{2, 3, 4}},
+		{"limit keeps the oldest", 0, nil, 2, []uint64{1, 2}},
+		{"after the last", 4, nil, 0, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes, err := history.FindSince(ctx, tt.after, tt.ids, tt.limit)
+			if err != nil {
+				t.Fatalf("FindSince: %v", err)
+			}
+			if got := sequences(changes); !equalSequences(got, tt.sequences) {
+				t.Errorf("sequences = %v, want %v", got, tt.sequences)
+			}
+		})
+	}
+
+	// El rango incluye ambos extremos
+	changes, err := history.FindByCurrency(ctx, "USD", base, base.Add(24*time.Hour))
+	if err != nil {
+		t.Fatalf("FindByCurrency: %v", err)
+	}
+	if got := sequences(changes); !equalSequences(got, []uint64{1, 3}) {
+		t.Errorf("FindByCurrency range = %v, want [1 3]", got)
+	}
+	if changes[1].PreviousValue != 36.4 || !changes[1].ChangedAt.Equal(base.Add(24*time.Hour)) {
+		t.Errorf("change 3 = %+v", changes[1])
+	}
+
+	// Fechas sin indicar o fuera del rango de int64 no desbordan
+	if changes, err := history.FindByCurrency(ctx, "USD", time.Time{}, base.AddDate(500, 0, 0)); err != nil || len(changes) != 3 {
+		t.Errorf("FindByCurrency unbounded = %d changes, %v; want 3", len(changes), err)
+	}
+
+	// Al reabrir el archivo se conservan los cambios y las secuencias continúan
+	reopened := NewSQLiteRateHistory(openTestDB(t, path), 0)
+	if last, err := reopened.LastSequence(ctx); err != nil || last != 4 {
+		t.Errorf("LastSequence after reopening = %d, %v; want 4", last, err)
+	}
+	next := &entity.RateChange{CurrencyID: "EUR", Name: "Euro", Value: 41, Source: "test", ChangedAt: base.Add(72 * time.Hour)}
+	if err := reopened.Append(ctx, next); err != nil || next.Sequence != 5 {
+		t.Errorf("Append after reopening = sequence %d, %v; want 5", next.Sequence, err)
+	}
+}
+
+func TestSQLiteRateHistoryCapacity(t *testing.T) {
+	ctx := context.Background()
+	history := NewSQLiteRateHistory(openTestDB(t, filepath.Join(t.TempDir(), "gobcv.db")), 2)
+
+	for i := 0; i < 5; i++ {
+		change := &entity.RateChange{CurrencyID: "USD", Name: "Dólar", Value: 36 + float64(i), Source: "test", ChangedAt: time.Now()}
+		if err := history.Append(ctx, change); err != nil {
+			t.Fatalf("Append: %v", err)
+		}
+	}
+
+	// Se descartan los más antiguos sin reutilizar sus secuencias
+	changes, err := history.FindSince(ctx, 0, nil, 0)
+	if err != nil {
+		t.Fatalf("FindSince: %v", err)
+	}
+	if got := sequences(changes); !equalSequences(got, []uint64{4, 5}) {
+		t.Errorf("sequences = %v, want [4 5]", got)
+	}
+}
+
+// sequences retorna las secuencias de los cambios.
+func sequences(changes []*entity.RateChange) []uint64 {
+	var result []uint64
+	for _, change := range changes {
+		result = append(result, change.Sequence)
+	}
+	return result
+}
+
+// equalSequences compara dos listas de secuencias.
+func equalSequences(a, b []uint64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,131 @@
+// Package tracing implementa el decorador que traza las operaciones del caché.
+package tracing
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"gobcv/internal/domain/service"
+	"gobcv/pkg/telemetry"
+)
+
+// TracedCache decora un CacheService creando un span por operación.
+type TracedCache struct {
+	cache  service.CacheService
+	tracer trace.Tracer
+}
+
+// NewTracedCache crea el decorador de trazas del caché.
+func NewTracedCache(cache service.CacheService) service.CacheService {
+	return &TracedCache{
+		cache:  cache,
+		tracer: telemetry.Tracer(),
+	}
+}
+
+// start inicia el span de una operación del caché.
+func (c *TracedCache) start(ctx context.Context, operation string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return c.tracer.Start(ctx, "cache."+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(append(attrs, attribute.String("cache.operation", operation))...),
+	)
+}
+
+// Set guarda un valor en el caché.
+func (c *TracedCache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration, tags ...string) error {
+	ctx, span := c.start(ctx, "set", attribute.String("cache.key", key))
+	defer span.End()
+
+	return recordError(span, c.cache.Set(ctx, key, value, ttl, tags...))
+}
+
+// SetWithStale guarda un valor con ventana de obsolescencia.
+func (c *TracedCache) SetWithStale(ctx context.Context, key string, value interface{}, softTTL, hardTTL time.Duration, tags ...string) error {
+	ctx, span := c.start(ctx, "set", attribute.String("cache.key", key))
+	defer span.End()
+
+	return recordError(span, c.cache.SetWithStale(ctx, key, value, softTTL, hardTTL, tags...))
+}
+
+// Get obtiene un valor del caché.
+func (c *TracedCache) Get(ctx context.Context, key string) (interface{}, error) {
+	ctx, span := c.start(ctx, "get", attribute.String("cache.key", key))
+	defer span.End()
+
+	value, err := c.cache.Get(ctx, key)
+	span.SetAttributes(attribute.Bool("cache.hit", err == nil && value != nil))
+	return value, recordError(span, err)
+}
+
+// GetEntry obtiene una entrada del caché con sus metadatos.
+func (c *TracedCache) GetEntry(ctx context.Context, key string) (*service.CacheEntry, error) {
+	ctx, span := c.start(ctx, "get", attribute.String("cache.key", key))
+	defer span.End()
+
+	entry, err := c.cache.GetEntry(ctx, key)
+	span.SetAttributes(attribute.Bool("cache.hit", err == nil && entry != nil))
+	if entry != nil {
+		span.SetAttributes(attribute.Bool("cache.stale", entry.Stale))
+	}
+	return entry, recordError(span, err)
+}
+
+// Delete elimina un valor del caché.
+func (c *TracedCache) Delete(ctx context.Context, key string) error {
+	ctx, span := c.start(ctx, "delete", attribute.String("cache.key", key))
+	defer span.End()
+
+	return recordError(span, c.cache.Delete(ctx, key))
+}
+
+// DeletePrefix elimina las claves con el prefijo indicado.
+func (c *TracedCache) DeletePrefix(ctx context.Context, prefix string) error {
+	ctx, span := c.start(ctx, "delete_prefix", attribute.String("cache.prefix", prefix))
+	defer span.End()
+
+	return recordError(span, c.cache.DeletePrefix(ctx, prefix))
+}
+
+// InvalidateTag elimina las claves asociadas a una etiqueta.
+func (c *TracedCache) InvalidateTag(ctx context.Context, tag string) error {
+	ctx, span := c.start(ctx, "invalidate_tag", attribute.String("cache.tag", tag))
+	defer span.End()
+
+	return recordError(span, c.cache.InvalidateTag(ctx, tag))
+}
+
+// Exists verifica si una clave existe.
+func (c *TracedCache) Exists(ctx context.Context, key string) (bool, error) {
+	ctx, span := c.start(ctx, "exists", attribute.String("cache.key", key))
+	defer span.End()
+
+	exists, err := c.cache.Exists(ctx, key)
+	return exists, recordError(span, err)
+}
+
+// Clear limpia todo el caché.
+func (c *TracedCache) Clear(ctx context.Context) error {
+	ctx, span := c.start(ctx, "clear")
+	defer span.End()
+
+	return recordError(span, c.cache.Clear(ctx))
+}
+
+// GetStats obtiene las estadísticas del caché sin crear spans, ya que se consulta
+// en cada scrape de métricas.
+func (c *TracedCache) GetStats(ctx context.Context) (service.CacheStats, error) {
+	return c.cache.GetStats(ctx)
+}
+
+// recordError marca el span como fallido si hubo error y retorna el mismo error.
+func recordError(span trace.Span, err error) error {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return err
+}
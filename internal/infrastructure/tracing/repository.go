@@ -0,0 +1,87 @@
+// Package tracing implementa el decorador que traza las operaciones del repositorio.
+package tracing
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"gobcv/internal/domain/entity"
+	"gobcv/internal/domain/repository"
+	"gobcv/pkg/telemetry"
+)
+
+// TracedRepository decora un CurrencyRepository creando un span por operación.
+type TracedRepository struct {
+	repo   repository.CurrencyRepository
+	tracer trace.Tracer
+}
+
+// NewTracedRepository crea el decorador de trazas del repositorio.
+func NewTracedRepository(repo repository.CurrencyRepository) repository.CurrencyRepository {
+	return &TracedRepository{
+		repo:   repo,
+		tracer: telemetry.Tracer(),
+	}
+}
+
+// start inicia el span de una operación del repositorio.
+func (r *TracedRepository) start(ctx context.Context, operation string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return r.tracer.Start(ctx, "repository."+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(append(attrs, attribute.String("db.operation.name", operation))...),
+	)
+}
+
+// Save guarda o actualiza una moneda.
+func (r *TracedRepository) Save(ctx context.Context, currency *entity.Currency) error {
+	var attrs []attribute.KeyValue
+	if currency != nil {
+		attrs = append(attrs, attribute.String("currency.id", currency.ID))
+	}
+
+	ctx, span := r.start(ctx, "Save", attrs...)
+	defer span.End()
+
+	return recordError(span, r.repo.Save(ctx, currency))
+}
+
+// FindByID busca una moneda por su ID.
+func (r *TracedRepository) FindByID(ctx context.Context, id string) (*entity.Currency, error) {
+	ctx, span := r.start(ctx, "FindByID", attribute.String("currency.id", id))
+	defer span.End()
+
+	currency, err := r.repo.FindByID(ctx, id)
+	span.SetAttributes(attribute.Bool("currency.found", currency != nil))
+	return currency, recordError(span, err)
+}
+
+// FindAll obtiene todas las monedas.
+func (r *TracedRepository) FindAll(ctx context.Context) ([]*entity.Currency, error) {
+	ctx, span := r.start(ctx, "FindAll")
+	defer span.End()
+
+	currencies, err := r.repo.FindAll(ctx)
+	span.SetAttributes(attribute.Int("currency.count", len(currencies)))
+	return currencies, recordError(span, err)
+}
+
+// Delete elimina una moneda.
+func (r *TracedRepository) Delete(ctx context.Context, id string) error {
+	ctx, span := r.start(ctx, "Delete", attribute.String("currency.id", id))
+	defer span.End()
+
+	return recordError(span, r.repo.Delete(ctx, id))
+}
+
+// FindByLastUpdate busca monedas actualizadas después de una fecha.
+func (r *TracedRepository) FindByLastUpdate(ctx context.Context, since time.Time) ([]*entity.Currency, error) {
+	ctx, span := r.start(ctx, "FindByLastUpdate")
+	defer span.End()
+
+	currencies, err := r.repo.FindByLastUpdate(ctx, since)
+	span.SetAttributes(attribute.Int("currency.count", len(currencies)))
+	return currencies, recordError(span, err)
+}
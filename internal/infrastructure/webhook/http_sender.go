@@ -0,0 +1,156 @@
+// Package webhook implementa el envío firmado de eventos a webhooks por HTTP.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+
+	"gobcv/internal/domain/entity"
+	"gobcv/internal/domain/service"
+)
+
+// Cabeceras enviadas en cada entrega.
+const (
+	SignatureHeader = "X-GoBCV-Signature"
+	EventHeader     = "X-GoBCV-Event"
+	DeliveryHeader  = "X-GoBCV-Delivery"
+	AttemptHeader   = "X-GoBCV-Attempt"
+)
+
+// HTTPSender implementa service.WebhookSender con peticiones POST firmadas.
+type HTTPSender struct {
+	client *http.Client
+}
+
+// NewHTTPSender crea un nuevo emisor de webhooks con el timeout indicado por entrega.
+// Salvo con allowPrivateNetworks, solo se conecta a IPs públicas: la IP se valida al
+// conectar, después de resolver el nombre, para que un DNS que cambie de respuesta tras
+// registrar el webhook no alcance servicios internos.
+func NewHTTPSender(timeout time.Duration, allowPrivateNetworks bool) *HTTPSender {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if !allowPrivateNetworks {
+		// Un proxy ocultaría la IP de destino, por lo que no se usa
+		transport.Proxy = nil
+		dialer := &net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+			Control:   publicAddressOnly,
+		}
+		transport.DialContext = dialer.DialContext
+	}
+
+	return &HTTPSender{
+		client: &http.Client{
+			Timeout:   timeout,
+			Transport: transport,
+			// Una redirección podría reenviar el evento firmado a otro destino
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+var _ service.WebhookSender = (*HTTPSender)(nil)
+
+// Send envía el evento firmado con el secreto del webhook.
+func (s *HTTPSender) Send(ctx context.Context, webhook *entity.Webhook, request service.WebhookRequest) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(request.Payload))
+	if err != nil {
+		return 0, fmt.Errorf("error creating webhook request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "gobcv-webhooks/1.0")
+	req.Header.Set(EventHeader, request.Event)
+	req.Header.Set(DeliveryHeader, request.DeliveryID)
+	req.Header.Set(AttemptHeader, strconv.Itoa(request.Attempt))
+	req.Header.Set(SignatureHeader, Sign(webhook.Secret, time.Now(), request.Payload))
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("error delivering webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("webhook receiver responded with status %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// publicAddressOnly rechaza las conexiones a IPs que no son públicas.
+func publicAddressOnly(_, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("webhook destination %q is not an IP address", address)
+	}
+	if !entity.IsPublicWebhookAddress(addrPort.Addr()) {
+		return fmt.Errorf("webhook destination %s is not a public address", addrPort.Addr())
+	}
+	return nil
+}
+
+// Sign calcula la cabecera de firma de un payload: t=<unix>,v1=<hex>, donde v1 es
+// HMAC-SHA256 con el secreto del webhook sobre "<unix>.<payload>". Incluir el instante
+// permite a los receptores rechazar entregas repetidas fuera de una ventana de tiempo.
+func Sign(secret string, timestamp time.Time, payload []byte) string {
+	unix := strconv.FormatInt(timestamp.Unix(), 10)
+	return "t=" + unix + ",v1=" + signature(secret, unix, payload)
+}
+
+// Verify valida la cabecera de firma de una entrega. tolerance limita la antigüedad
+// aceptada de la firma; 0 no la limita.
+func Verify(secret, header string, payload []byte, tolerance time.Duration) error {
+	var unix, expected string
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			unix = value
+		case "v1":
+			expected = value
+		}
+	}
+
+	seconds, err := strconv.ParseInt(unix, 10, 64)
+	if err != nil || expected == "" {
+		return fmt.Errorf("malformed webhook signature header")
+	}
+
+	if tolerance > 0 && time.Since(time.Unix(seconds, 0)).Abs() > tolerance {
+		return fmt.Errorf("webhook signature timestamp outside tolerance")
+	}
+
+	if !hmac.Equal([]byte(expected), []byte(signature(secret, unix, payload))) {
+		return fmt.Errorf("webhook signature mismatch")
+	}
+	return nil
+}
+
+// signature calcula el HMAC-SHA256 en hexadecimal de "<unix>.<payload>".
+func signature(secret, unix string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(unix))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
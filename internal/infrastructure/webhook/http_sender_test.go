@@ -0,0 +1,62 @@
+package webhook
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"gobcv/internal/domain/entity"
+	"gobcv/internal/domain/service"
+)
+
+func TestHTTPSenderRefusesPrivateAddresses(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer server.Close()
+
+	webhook := &entity.Webhook{ID: "wh_test", URL: server.URL, Secret: "whsec_0123456789abcdef"}
+	request := service.WebhookRequest{DeliveryID: "dlv_test", Event: entity.WebhookEventRateChanged, Attempt: 1, Payload: []byte(`{}`)}
+
+	_, err := NewHTTPSender(time.Second, false).Send(context.Background(), webhook, request)
+	if err == nil || !strings.Contains(err.Error(), "not a public address") {
+		t.Fatalf("Send to loopback error = %v, want a non-public address error", err)
+	}
+	if requests != 0 {
+		t.Fatalf("loopback receiver got %d requests", requests)
+	}
+
+	status, err := NewHTTPSender(time.Second, true).Send(context.Background(), webhook, request)
+	if err != nil || status != http.StatusOK || requests != 1 {
+		t.Errorf("Send with private networks allowed = %d, %v, %d requests", status, err, requests)
+	}
+}
+
+func TestSignAndVerify(t *testing.T) {
+	secret := "whsec_0123456789abcdef"
+	payload := []byte(`{"id":"dlv_1"}`)
+	header := Sign(secret, time.Now(), payload)
+
+	if err := Verify(secret, header, payload, time.Minute); err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+
+	tests := map[string]struct {
+		secret, header string
+		payload        []byte
+	}{
+		"other secret":    {"whsec_other", header, payload},
+		"altered payload": {secret, header, []byte(`{"id":"dlv_2"}`)},
+		"expired":         {secret, Sign(secret, time.Now().Add(-time.Hour), payload), payload},
+		"malformed":       {secret, "v1=abc", payload},
+	}
+	for name, tt := range tests {
+		if err := Verify(tt.secret, tt.header, tt.payload, time.Minute); err == nil {
+			t.Errorf("%s: Verify accepted the signature", name)
+		}
+	}
+}
@@ -0,0 +1,545 @@
+// Package server inicia la API REST, gRPC y GraphQL con todas sus dependencias.
+package server
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"gobcv/internal/application/service"
+	"gobcv/internal/domain/repository"
+	domainService "gobcv/internal/domain/service"
+	"gobcv/internal/infrastructure/alerting"
+	"gobcv/internal/infrastructure/auth"
+	"gobcv/internal/infrastructure/cache"
+	"gobcv/internal/infrastructure/events"
+	graphqlInfra "gobcv/internal/infrastructure/graphql"
+	grpcInfra "gobcv/internal/infrastructure/grpc"
+	httpInfra "gobcv/internal/infrastructure/http"
+	"gobcv/internal/infrastructure/invalidation"
+	"gobcv/internal/infrastructure/logging"
+	"gobcv/internal/infrastructure/metrics"
+	"gobcv/internal/infrastructure/ratelimit"
+	"gobcv/internal/infrastructure/scraper"
+	"gobcv/internal/infrastructure/storage"
+	"gobcv/internal/infrastructure/tracing"
+	"gobcv/internal/infrastructure/webhook"
+	"gobcv/pkg/config"
+)
+
+// grpcReadinessInterval es el intervalo con el que el health checking gRPC refleja la sonda de readiness.
+const grpcReadinessInterval = 10 * time.Second
+
+// Run inicia los servidores con la configuración indicada y bloquea hasta recibir
+// SIGINT o SIGTERM, tras lo cual los cierra ordenadamente. Los errores de
+// inicialización se registran y terminan el proceso.
+func Run(cfg *config.Config) {
+	logger, err := logging.New(os.Stdout, cfg.Log.Format, cfg.Log.Level)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error configurando logging: %v\n", err)
+		os.Exit(1)
+	}
+	slog.SetDefault(logger)
+
+	slog.Info("Iniciando BCV Currency API", "host", cfg.Server.Host, "port", cfg.Server.Port, "replica_id", cfg.Server.ReplicaID)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	shutdownTracing, err := tracing.Setup(ctx, cfg.Tracing.Exporter, cfg.Tracing.ServiceName, cfg.Tracing.SampleRatio)
+	if err != nil {
+		fatal("Error inicializando trazas", err)
+	}
+	defer func() {
+		flushCtx, flushCancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer flushCancel()
+		if err := shutdownTracing(flushCtx); err != nil {
+			slog.Error("Error exportando trazas pendientes", "error", err)
+		}
+	}()
+
+	// Inicializar dependencias
+	cacheService, closeCache, err := newCacheService(cfg)
+	if err != nil {
+		fatal("Error inicializando caché", err)
+	}
+	defer closeCache()
+	cacheService = tracing.NewTracedCache(cacheService)
+
+	invalidationBus, err := newInvalidationBus(cfg)
+	if err != nil {
+		fatal("Error inicializando bus de invalidación", err)
+	}
+	defer invalidationBus.Close()
+
+	registry := metrics.NewRegistry()
+
+	currencyRepository, rateHistory, closeDatabase, err := newRepositories(ctx, cfg)
+	if err != nil {
+		fatal("Error inicializando base de datos", err)
+	}
+	defer closeDatabase()
+	repository := tracing.NewTracedRepository(currencyRepository)
+	scraperService := metrics.NewInstrumentedScraper(scraper.NewBCVScraper(), registry)
+
+	registry.MustRegister(
+		metrics.NewCacheCollector(cacheService),
+		// Sin trazas: cada scrape de Prometheus generaría una traza nueva
+		metrics.NewCurrencyCollector(currencyRepository),
+	)
+
+	rateEvents := events.NewBroker()
+	corsPolicy := httpInfra.CORSPolicy{
+		AllowedOrigins:   cfg.CORS.AllowedOrigins,
+		AllowedMethods:   cfg.CORS.AllowedMethods,
+		AllowedHeaders:   cfg.CORS.AllowedHeaders,
+		ExposedHeaders:   cfg.CORS.ExposedHeaders,
+		AllowCredentials: cfg.CORS.AllowCredentials,
+		MaxAge:           int(cfg.CORS.MaxAge.Seconds()),
+	}
+	rateSocket := httpInfra.NewRateSocket(rateEvents, rateHistory, corsPolicy, cfg.Stream.HeartbeatInterval, cfg.Stream.BufferSize)
+
+	// Inicializar servicios de aplicación
+	currencyService := service.NewCurrencyService(
+		repository,
+		scraperService,
+		cacheService,
+		invalidationBus,
+		rateHistory,
+		rateEvents,
+		cfg.Server.ReplicaID,
+		cfg.Cache.MaxStale,
+	)
+
+	webhookRepository, err := newWebhookRepository(ctx, cfg)
+	if err != nil {
+		fatal("Error inicializando webhooks", err)
+	}
+	webhookService := service.NewWebhookService(
+		webhookRepository,
+		cache.NewMemoryWebhookDeliveryRepository(cfg.Webhooks.DeliveryLogSize),
+		webhook.NewHTTPSender(cfg.Webhooks.Timeout, cfg.Webhooks.AllowPrivateNetworks),
+		rateEvents,
+		rateHistory,
+		service.WebhookPolicy{
+			MaxAttempts:          cfg.Webhooks.MaxAttempts,
+			Backoff:              cfg.Webhooks.RetryBackoff,
+			MaxBackoff:           cfg.Webhooks.MaxBackoff,
+			DisableAfter:         cfg.Webhooks.DisableAfter,
+			AllowPrivateNetworks: cfg.Webhooks.AllowPrivateNetworks,
+		},
+	)
+
+	alertRepository, err := newAlertRepository(ctx, cfg)
+	if err != nil {
+		fatal("Error inicializando alertas", err)
+	}
+	alertService := service.NewAlertService(alertRepository, rateHistory, newAlertNotifiers(cfg), cfg.Alerts.DefaultCooldown)
+	currencyService.GetRefreshHandler().RegisterObserver(alertService)
+
+	healthService := service.NewHealthService(repository, cacheService, scraperService, cfg.Scraper.RefreshInterval)
+
+	// Las claves de API se comparten entre la API REST y gRPC
+	apiKeys, err := newAPIKeyRepository(ctx, cfg)
+	if err != nil {
+		fatal("Error inicializando autenticación", err)
+	}
+
+	// El esquema GraphQL delega en las mismas consultas que la API REST
+	graphqlExecutor, err := graphqlInfra.NewExecutor(
+		currencyService.GetCurrencyHandler(),
+		currencyService.GetAllCurrenciesHandler(),
+		currencyService.GetConvertHandler(),
+		currencyService.GetRateHistoryHandler(),
+		rateEvents,
+		rateHistory,
+		cfg.Stream.BufferSize,
+		graphqlInfra.Limits{MaxDepth: cfg.GraphQL.MaxDepth, MaxComplexity: cfg.GraphQL.MaxComplexity},
+	)
+	if err != nil {
+		fatal("Error inicializando GraphQL", err)
+	}
+	graphqlEndpoint := httpInfra.NewGraphQLEndpoint(graphqlExecutor, corsPolicy, cfg.Stream.HeartbeatInterval)
+
+	// Inicializar handlers HTTP
+	handlers := httpInfra.NewHandlers(
+		currencyService.GetRefreshHandler(),
+		currencyService.GetCurrencyHandler(),
+		currencyService.GetAllCurrenciesHandler(),
+		currencyService.GetConvertHandler(),
+		currencyService.GetRateHistoryHandler(),
+		currencyService,
+		currencyService,
+		healthService,
+		httpInfra.NewRateStream(rateEvents, rateHistory, cfg.Stream.HeartbeatInterval, cfg.Stream.BufferSize),
+		rateSocket,
+		graphqlEndpoint,
+		webhookService,
+		alertService,
+	)
+
+	// Configurar middlewares por ruta; CORS va primero para que los rechazos sean legibles
+	// y las métricas después, para contar también las respuestas 401 y 429
+	routeMiddlewares := []httpInfra.RouteMiddleware{
+		httpInfra.NewCORSMiddleware(corsPolicy),
+		httpInfra.NewTracingMiddleware(),
+		httpInfra.NewHTTPMetrics(registry),
+	}
+	var rateLimitMiddleware *httpInfra.RateLimitMiddleware
+	if cfg.RateLimit.Enabled {
+		var closeStore func()
+		rateLimitMiddleware, closeStore, err = newRateLimitMiddleware(cfg)
+		if err != nil {
+			fatal("Error inicializando límites de peticiones", err)
+		}
+		defer closeStore()
+	}
+	if cfg.Auth.Enabled {
+		// Las autenticaciones fallidas se cuentan por IP antes de validar la clave, para
+		// que los 401 también consuman el límite
+		if rateLimitMiddleware != nil {
+			routeMiddlewares = append(routeMiddlewares, rateLimitMiddleware.AuthFailures())
+		}
+		routeMiddlewares = append(routeMiddlewares, httpInfra.NewAuthMiddleware(apiKeys, cfg.Auth.AnonymousScopes))
+	} else {
+		slog.Warn("AUTH_ENABLED=false: cualquier cliente puede forzar actualizaciones desde el BCV " +
+			"(POST /api/v1/currencies/refresh) y consultar las rutas de administración, y las rutas de " +
+			"webhooks y alertas no se montan; habilítelo en producción")
+	}
+	// Después de la autenticación, para identificar al cliente por su clave
+	if rateLimitMiddleware != nil {
+		routeMiddlewares = append(routeMiddlewares, rateLimitMiddleware)
+	}
+
+	// Configurar router
+	router := httpInfra.SetupRouter(handlers, httpInfra.RouterOptions{
+		MetricsPath:     cfg.Metrics.Path,
+		MetricsHandler:  metrics.Handler(registry),
+		AuthEnabled:     cfg.Auth.Enabled,
+		AnonymousScopes: cfg.Auth.AnonymousScopes,
+	}, routeMiddlewares...)
+
+	// Configurar servidor HTTP
+	server := &http.Server{
+		Addr:         fmt.Sprintf("%s:%s", cfg.Server.Host, cfg.Server.Port),
+		Handler:      router,
+		ReadTimeout:  cfg.Server.ReadTimeout,
+		WriteTimeout: cfg.Server.WriteTimeout,
+		IdleTimeout:  cfg.Server.IdleTimeout,
+	}
+	// Configurar servidor gRPC con las mismas consultas que la API REST
+	var grpcServer *grpcInfra.Server
+	var grpcListener net.Listener
+	if cfg.GRPC.Enabled {
+		var grpcAuth *grpcInfra.Authenticator
+		if cfg.Auth.Enabled {
+			grpcAuth = grpcInfra.NewAuthenticator(apiKeys, cfg.Auth.AnonymousScopes)
+		}
+
+		grpcServer = grpcInfra.NewServer(
+			grpcInfra.NewCurrencyServer(
+				currencyService.GetCurrencyHandler(),
+				currencyService.GetAllCurrenciesHandler(),
+				currencyService.GetConvertHandler(),
+				currencyService.GetRateHistoryHandler(),
+				rateEvents,
+				rateHistory,
+				cfg.Stream.BufferSize,
+			),
+			grpcAuth,
+			cfg.Stream.HeartbeatInterval,
+		)
+
+		// Escuchar antes de iniciar para fallar al arrancar si el puerto está ocupado
+		grpcListener, err = net.Listen("tcp", net.JoinHostPort(cfg.Server.Host, cfg.GRPC.Port))
+		if err != nil {
+			fatal("Error iniciando servidor gRPC", err)
+		}
+	}
+
+	// Cerrar las suscripciones al apagar para que los streams abiertos no retrasen el cierre
+	server.RegisterOnShutdown(func() { rateEvents.Close() })
+
+	// Iniciar actualización periódica de monedas en background
+	if err := currencyService.StartInvalidationListener(ctx); err != nil {
+		fatal("Error suscribiendo invalidaciones de caché", err)
+	}
+
+	// La actualización periódica hace también la actualización inicial
+	go currencyService.StartPeriodicRefresh(ctx, cfg.Scraper.RefreshInterval)
+	go webhookService.Start(ctx)
+
+	// Canal para señales del sistema
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+
+	// Iniciar servidor en una goroutine
+	go func() {
+		slog.Info("Servidor iniciado", "url", fmt.Sprintf("http://%s:%s", cfg.Server.Host, cfg.Server.Port))
+		logEndpoints(handlers.OpenAPI())
+
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			fatal("Error iniciando servidor", err)
+		}
+	}()
+
+	if grpcServer != nil {
+		go func() {
+			slog.Info("Servidor gRPC iniciado", "addr", grpcListener.Addr().String())
+			if err := grpcServer.Serve(grpcListener); err != nil {
+				fatal("Error iniciando servidor gRPC", err)
+			}
+		}()
+		go grpcServer.WatchReadiness(ctx, healthService, grpcReadinessInterval)
+	}
+
+	// Esperar señal de terminación
+	<-sigChan
+	slog.Info("Recibida señal de terminación, cerrando servidor")
+
+	// Graceful shutdown
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer shutdownCancel()
+
+	// Las conexiones WebSocket no las cierra server.Shutdown: se cierran antes con going away
+	if err := rateSocket.Shutdown(shutdownCtx); err != nil {
+		slog.Error("Error cerrando conexiones WebSocket", "error", err)
+	}
+	if err := graphqlEndpoint.Shutdown(shutdownCtx); err != nil {
+		slog.Error("Error cerrando conexiones GraphQL WebSocket", "error", err)
+	}
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		slog.Error("Error durante el cierre del servidor", "error", err)
+	}
+
+	// Después del servidor HTTP: al cerrarse el broker terminan también los streams WatchRates
+	if grpcServer != nil {
+		if err := grpcServer.Shutdown(shutdownCtx); err != nil {
+			slog.Error("Error durante el cierre del servidor gRPC", "error", err)
+		}
+	}
+
+	// Cancelar contexto para detener actualización periódica
+	cancel()
+
+	// Las notificaciones de alertas en curso terminan o vencen por su propio timeout
+	alertService.Wait()
+
+	slog.Info("Servidor cerrado exitosamente")
+}
+
+// newRepositories crea el repositorio de monedas y el historial de cambios según el
+// tipo de base de datos configurado, junto con su función de cierre.
+func newRepositories(ctx context.Context, cfg *config.Config) (repository.CurrencyRepository, repository.RateHistoryRepository, func(), error) {
+	switch cfg.Database.Type {
+	case "memory":
+		return cache.NewMemoryRepository(), cache.NewMemoryRateHistory(cfg.Stream.HistorySize), func() {}, nil
+	case "sqlite":
+		db, err := storage.OpenSQLite(ctx, cfg.Database.Path)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		slog.Info("Usando base de datos SQLite", "path", cfg.Database.Path)
+		return storage.NewSQLiteRepository(db), storage.NewSQLiteRateHistory(db, cfg.Stream.HistorySize), func() { db.Close() }, nil
+	default:
+		return nil, nil, nil, fmt.Errorf("unknown database type: %s", cfg.Database.Type)
+	}
+}
+
+// newCacheService crea el servicio de caché indicado en la configuración junto con su función de cierre.
+func newCacheService(cfg *config.Config) (domainService.CacheService, func(), error) {
+	switch cfg.Cache.Type {
+	case "memory":
+		memoryCache := cache.NewMemoryCache()
+		return memoryCache, memoryCache.Close, nil
+	case "redis":
+		redisCache, err := newRedisCache(cfg)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		slog.Info("Usando caché Redis", "addr", cfg.Redis.Addr)
+		return redisCache, func() { redisCache.Close() }, nil
+	case "tiered":
+		redisCache, err := newRedisCache(cfg)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		memoryCache := cache.NewMemoryCache()
+		tieredCache := cache.NewTieredCache(memoryCache, redisCache, cfg.Cache.L1TTL, cfg.Cache.NegativeTTL)
+
+		slog.Info("Usando caché en dos niveles: memoria (L1) y Redis (L2)", "l1_ttl", cfg.Cache.L1TTL, "addr", cfg.Redis.Addr)
+		return tieredCache, func() {
+			memoryCache.Close()
+			redisCache.Close()
+		}, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown cache type: %s", cfg.Cache.Type)
+	}
+}
+
+// newRedisCache crea el caché sobre Redis verificando que el servidor esté disponible.
+func newRedisCache(cfg *config.Config) (*cache.RedisCache, error) {
+	redisCache := cache.NewRedisCache(cfg.Redis.Addr, cfg.Redis.Password, cfg.Redis.DB, cfg.Redis.KeyPrefix)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := redisCache.Ping(ctx); err != nil {
+		redisCache.Close()
+		return nil, fmt.Errorf("redis not available at %s: %w", cfg.Redis.Addr, err)
+	}
+
+	return redisCache, nil
+}
+
+// newInvalidationBus crea el bus de invalidación de caché indicado en la configuración.
+func newInvalidationBus(cfg *config.Config) (domainService.InvalidationBus, error) {
+	switch cfg.Cache.InvalidationBus {
+	case "memory":
+		return invalidation.NewMemoryBus(), nil
+	case "redis":
+		return invalidation.NewRedisBus(cfg.Redis.Addr, cfg.Redis.Password, cfg.Redis.DB, cfg.Cache.InvalidationChannel), nil
+	case "postgres":
+		return invalidation.NewPostgresBus(cfg.Database.PostgresDSN(), cfg.Cache.InvalidationChannel)
+	default:
+		return nil, fmt.Errorf("unknown invalidation bus: %s", cfg.Cache.InvalidationBus)
+	}
+}
+
+// newAPIKeyRepository crea el repositorio de claves de API cargando las claves del archivo
+// configurado. Retorna nil si la autenticación está deshabilitada.
+func newAPIKeyRepository(ctx context.Context, cfg *config.Config) (repository.APIKeyRepository, error) {
+	if !cfg.Auth.Enabled {
+		return nil, nil
+	}
+
+	keys := cache.NewMemoryAPIKeyRepository()
+
+	if cfg.Auth.KeysFile != "" {
+		count, err := auth.LoadKeyFile(ctx, cfg.Auth.KeysFile, keys)
+		if err != nil {
+			return nil, err
+		}
+		slog.Info("Autenticación por clave de API habilitada", "keys", count)
+	} else {
+		slog.Warn("Autenticación habilitada sin AUTH_KEYS_FILE, solo se permiten accesos anónimos")
+	}
+
+	return keys, nil
+}
+
+// newRateLimitMiddleware crea el middleware de límites de peticiones con el almacén configurado.
+// Se registra después de la autenticación para identificar a los clientes por su clave de API.
+func newRateLimitMiddleware(cfg *config.Config) (*httpInfra.RateLimitMiddleware, func(), error) {
+	trustedProxies, err := httpInfra.ParseTrustedProxies(cfg.RateLimit.TrustedProxies)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	limits := map[string]domainService.RateLimit{
+		httpInfra.RouteClassRead:    {Burst: cfg.RateLimit.ReadRequests, Period: cfg.RateLimit.ReadPeriod},
+		httpInfra.RouteClassRefresh: {Burst: cfg.RateLimit.RefreshRequests, Period: cfg.RateLimit.RefreshPeriod},
+		httpInfra.RouteClassAuth:    {Burst: cfg.RateLimit.AuthFailures, Period: cfg.RateLimit.AuthFailurePeriod},
+	}
+
+	switch cfg.RateLimit.Store {
+	case "memory":
+		slog.Info("Usando límites de peticiones en memoria")
+		return httpInfra.NewRateLimitMiddleware(ratelimit.NewMemoryStore(), limits, trustedProxies), func() {}, nil
+	case "redis":
+		store := ratelimit.NewRedisStore(cfg.Redis.Addr, cfg.Redis.Password, cfg.Redis.DB, cfg.Redis.KeyPrefix+"ratelimit:")
+
+		pingCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := store.Ping(pingCtx); err != nil {
+			store.Close()
+			return nil, nil, fmt.Errorf("error connecting to redis at %s: %w", cfg.Redis.Addr, err)
+		}
+
+		slog.Info("Usando límites de peticiones compartidos en Redis", "addr", cfg.Redis.Addr)
+		return httpInfra.NewRateLimitMiddleware(store, limits, trustedProxies), func() { store.Close() }, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown rate limit store %q", cfg.RateLimit.Store)
+	}
+}
+
+// fatal registra un error de inicialización y termina el proceso.
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
+}
+
+// logEndpoints registra los endpoints documentados en la especificación OpenAPI.
+func logEndpoints(doc *httpInfra.OpenAPIDocument) {
+	var endpoints []string
+	for path, operations := range doc.Paths {
+		for method, operation := range operations {
+			endpoints = append(endpoints, fmt.Sprintf("%-6s %s - %s", strings.ToUpper(method), path, operation.Summary))
+		}
+	}
+	sort.Strings(endpoints)
+
+	for _, endpoint := range endpoints {
+		slog.Info("Endpoint disponible", "endpoint", endpoint)
+	}
+}
+
+// newAlertRepository crea el repositorio de alertas; las reglas se persisten en archivo si se configuró uno.
+func newAlertRepository(ctx context.Context, cfg *config.Config) (repository.AlertRepository, error) {
+	if cfg.Alerts.StoreFile == "" {
+		return cache.NewMemoryAlertRepository(cfg.Alerts.HistorySize), nil
+	}
+	return storage.NewFileAlertRepository(ctx, cfg.Alerts.StoreFile, cfg.Alerts.HistorySize)
+}
+
+// newAlertNotifiers crea los canales de alerta configurados. El registro en el log
+// siempre está disponible.
+func newAlertNotifiers(cfg *config.Config) []domainService.AlertNotifier {
+	notifiers := []domainService.AlertNotifier{alerting.NewLogNotifier()}
+
+	if cfg.Alerts.WebhookURL != "" {
+		notifiers = append(notifiers, alerting.NewWebhookNotifier(
+			cfg.Alerts.WebhookURL,
+			cfg.Alerts.WebhookSecret,
+			// El destino lo define el operador, no un cliente de la API
+			webhook.NewHTTPSender(cfg.Webhooks.Timeout, true),
+		))
+	}
+
+	if cfg.Alerts.SMTPAddr != "" {
+		if len(cfg.Alerts.SMTPTo) == 0 {
+			slog.Warn("ALERT_SMTP_TO vacío: el canal smtp queda deshabilitado")
+		} else {
+			notifiers = append(notifiers, alerting.NewSMTPNotifier(alerting.SMTPConfig{
+				Addr:     cfg.Alerts.SMTPAddr,
+				Username: cfg.Alerts.SMTPUsername,
+				Password: cfg.Alerts.SMTPPassword,
+				From:     cfg.Alerts.SMTPFrom,
+				To:       cfg.Alerts.SMTPTo,
+			}))
+		}
+	}
+
+	return notifiers
+}
+
+// newWebhookRepository crea el repositorio de webhooks, persistido en archivo si se configuró uno.
+func newWebhookRepository(ctx context.Context, cfg *config.Config) (repository.WebhookRepository, error) {
+	if cfg.Webhooks.StoreFile == "" {
+		slog.Warn("WEBHOOKS_FILE no configurado: los webhooks se perderán al reiniciar")
+		return cache.NewMemoryWebhookRepository(), nil
+	}
+	return storage.NewFileWebhookRepository(ctx, cfg.Webhooks.StoreFile)
+}
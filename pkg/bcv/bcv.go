@@ -0,0 +1,421 @@
+// Package bcv permite usar el scraping, el caché y el repositorio de monedas desde otros
+// programas, como procesos batch, sin iniciar el servidor HTTP:
+//
+//	rates, err := bcv.New()
+//	defer rates.Close()
+//	usd, err := rates.Get(ctx, "USD")
+//
+// Los tipos del dominio se exponen como alias, por lo que también es posible
+// implementar un scraper, repositorio o caché propios.
+package bcv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"gobcv/internal/application/command"
+	"gobcv/internal/application/query"
+	appService "gobcv/internal/application/service"
+	"gobcv/internal/domain/entity"
+	"gobcv/internal/domain/repository"
+	"gobcv/internal/domain/service"
+	"gobcv/internal/infrastructure/cache"
+	"gobcv/internal/infrastructure/events"
+	"gobcv/internal/infrastructure/invalidation"
+	"gobcv/internal/infrastructure/scraper"
+)
+
+// Tipos del dominio usados por la API del paquete.
+type (
+	// Currency es una moneda con su valor en VES publicado por el BCV.
+	Currency = entity.Currency
+	// RateChange es un cambio de valor de una moneda.
+	RateChange = entity.RateChange
+	// RefreshResult es el resultado de una actualización desde el scraper.
+	RefreshResult = command.RefreshCurrenciesResult
+	// Scraper obtiene las monedas desde la fuente externa.
+	Scraper = service.CurrencyScraper
+	// Repository almacena las monedas.
+	Repository = repository.CurrencyRepository
+	// History almacena los cambios de tasas.
+	History = repository.RateHistoryRepository
+	// Cache almacena los resultados de las consultas.
+	Cache = service.CacheService
+	// CacheEntry es un valor del caché con sus metadatos de frescura.
+	CacheEntry = service.CacheEntry
+	// CacheStats son las estadísticas de un caché.
+	CacheStats = service.CacheStats
+)
+
+// Errores retornados por Service.
+var (
+	// ErrNotFound indica que la moneda solicitada no existe.
+	ErrNotFound = errors.New("currency not found")
+	// ErrInvalidQuery indica que los parámetros de la consulta no son válidos.
+	ErrInvalidQuery = query.ErrInvalidQuery
+	// ErrStale indica que las monedas guardadas son más antiguas que Freshness y no se
+	// pudieron actualizar desde el scraper.
+	ErrStale = errors.New("currency data is stale")
+)
+
+// Freshness es la antigüedad a partir de la cual las monedas guardadas se consideran
+// obsoletas: Latest las descarta y las consultas las actualizan antes desde el scraper.
+const Freshness = query.CurrencyFreshness
+
+const (
+	// defaultMaxStale es la ventana por defecto en la que se sirven datos obsoletos.
+	defaultMaxStale = 24 * time.Hour
+	// defaultHistorySize es la capacidad por defecto del historial de cambios.
+	defaultHistorySize = 1000
+	// defaultSubscriptionBuffer es la cantidad por defecto de cambios que puede acumular
+	// una suscripción antes de cerrarse.
+	defaultSubscriptionBuffer = 32
+)
+
+// Conversion es el resultado de convertir un monto entre dos monedas.
+type Conversion struct {
+	From    string    `json:"from"`
+	To      string    `json:"to"`
+	Amount  float64   `json:"amount"`
+	Result  float64   `json:"result"`
+	Rate    float64   `json:"rate"`     // Unidades de To por unidad de From
+	RatesAt time.Time `json:"rates_at"` // Fecha de la tasa más antigua usada
+	Stale   bool      `json:"stale"`
+}
+
+// options contiene la configuración de New.
+type options struct {
+	scraper            Scraper
+	repository         Repository
+	history            History
+	cache              Cache
+	refreshInterval    time.Duration
+	maxStale           time.Duration
+	historySize        int
+	subscriptionBuffer int
+}
+
+// Option configura un Service.
+type Option func(*options)
+
+// WithScraper usa el scraper indicado en lugar del scraper del sitio web del BCV.
+func WithScraper(s Scraper) Option {
+	return func(o *options) {
+		o.scraper = s
+	}
+}
+
+// WithRepository usa el repositorio indicado en lugar de uno en memoria.
+func WithRepository(r Repository) Option {
+	return func(o *options) {
+		o.repository = r
+	}
+}
+
+// WithHistory usa el historial de cambios indicado en lugar de uno en memoria de
+// WithHistorySize cambios.
+func WithHistory(h History) Option {
+	return func(o *options) {
+		o.history = h
+	}
+}
+
+// WithCache usa el caché indicado en lugar de uno en memoria. El caché no se cierra
+// con Service.Close.
+func WithCache(c Cache) Option {
+	return func(o *options) {
+		o.cache = c
+	}
+}
+
+// WithRefreshInterval actualiza las monedas al crear el servicio y luego con el
+// intervalo indicado, hasta Service.Close. Sin esta opción las monedas se obtienen en
+// la primera consulta y con Service.Refresh.
+func WithRefreshInterval(interval time.Duration) Option {
+	return func(o *options) {
+		o.refreshInterval = interval
+	}
+}
+
+// WithMaxStale define durante cuánto tiempo se sirven monedas desactualizadas.
+func WithMaxStale(maxStale time.Duration) Option {
+	return func(o *options) {
+		o.maxStale = maxStale
+	}
+}
+
+// WithHistorySize define cuántos cambios de tasas se conservan en memoria.
+func WithHistorySize(size int) Option {
+	return func(o *options) {
+		o.historySize = size
+	}
+}
+
+// WithSubscriptionBuffer define cuántos cambios puede acumular una suscripción sin
+// leer antes de cerrarse.
+func WithSubscriptionBuffer(size int) Option {
+	return func(o *options) {
+		o.subscriptionBuffer = size
+	}
+}
+
+// NewBCVScraper crea el scraper del sitio web del BCV.
+func NewBCVScraper() Scraper {
+	return scraper.NewBCVScraper()
+}
+
+// NewFileScraper crea un scraper que lee una página del BCV guardada en disco.
+func NewFileScraper(path string) (Scraper, error) {
+	return scraper.NewFileScraper(path)
+}
+
+// NewMemoryRepository crea un repositorio de monedas en memoria.
+func NewMemoryRepository() Repository {
+	return cache.NewMemoryRepository()
+}
+
+// Service ofrece las consultas de monedas sobre el servicio de la aplicación. Es seguro
+// para uso concurrente.
+type Service struct {
+	currencies *appService.CurrencyService
+	repository Repository
+	history    History
+	events     *events.Broker
+	bus        *invalidation.MemoryBus
+	buffer     int
+	closeCache func()
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	// freshUntil es el instante, en nanosegundos Unix, hasta el cual las monedas guardadas
+	// se consideran vigentes sin volver a consultar el repositorio.
+	freshUntil atomic.Int64
+	loadMutex  sync.Mutex
+}
+
+// New crea el servicio con el scraper del BCV y repositorio y caché en memoria, salvo
+// que las opciones indiquen otros.
+func New(opts ...Option) (*Service, error) {
+	o := options{
+		maxStale:           defaultMaxStale,
+		historySize:        defaultHistorySize,
+		subscriptionBuffer: defaultSubscriptionBuffer,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if o.refreshInterval < 0 || o.maxStale < 0 {
+		return nil, fmt.Errorf("refresh interval and max stale must not be negative")
+	}
+	if o.historySize <= 0 || o.subscriptionBuffer <= 0 {
+		return nil, fmt.Errorf("history size and subscription buffer must be positive")
+	}
+
+	s := &Service{
+		events:     events.NewBroker(),
+		bus:        invalidation.NewMemoryBus(),
+		buffer:     o.subscriptionBuffer,
+		closeCache: func() {},
+	}
+
+	if o.scraper == nil {
+		o.scraper = NewBCVScraper()
+	}
+	if o.repository == nil {
+		o.repository = NewMemoryRepository()
+	}
+	if o.history == nil {
+		o.history = cache.NewMemoryRateHistory(o.historySize)
+	}
+	if o.cache == nil {
+		memoryCache := cache.NewMemoryCache()
+		o.cache = memoryCache
+		s.closeCache = memoryCache.Close
+	}
+	s.repository = o.repository
+	s.history = o.history
+
+	s.currencies = appService.NewCurrencyService(
+		o.repository,
+		o.scraper,
+		o.cache,
+		s.bus,
+		o.history,
+		s.events,
+		"bcv",
+		o.maxStale,
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	if o.refreshInterval > 0 {
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.currencies.StartPeriodicRefresh(ctx, o.refreshInterval)
+		}()
+	}
+
+	return s, nil
+}
+
+// Close detiene la actualización periódica y cierra las suscripciones.
+func (s *Service) Close() error {
+	s.cancel()
+	s.wg.Wait()
+
+	s.events.Close()
+	s.closeCache()
+	return s.bus.Close()
+}
+
+// Latest obtiene las monedas vigentes. Si el repositorio está vacío o sus monedas son
+// más antiguas que Freshness, las obtiene antes del scraper.
+func (s *Service) Latest(ctx context.Context) ([]*Currency, error) {
+	if err := s.ensureLoaded(ctx); err != nil {
+		return nil, err
+	}
+
+	result, err := s.currencies.GetAllCurrenciesHandler().Handle(ctx, query.GetAllCurrenciesQuery{UseCache: true})
+	if err != nil {
+		return nil, err
+	}
+	return result.Currencies, nil
+}
+
+// Get obtiene una moneda por su código ISO 4217 (USD, EUR). Si el repositorio está vacío
+// o sus monedas son más antiguas que Freshness, las obtiene antes del scraper.
+func (s *Service) Get(ctx context.Context, id string) (*Currency, error) {
+	if err := s.ensureLoaded(ctx); err != nil {
+		return nil, err
+	}
+
+	id = strings.ToUpper(strings.TrimSpace(id))
+	result, err := s.currencies.GetCurrencyHandler().Handle(ctx, query.GetCurrencyQuery{CurrencyID: id, UseCache: true})
+	if err != nil {
+		return nil, err
+	}
+	if !result.Success {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	return result.Currency, nil
+}
+
+// Convert convierte amount de la moneda from a la moneda to; cualquiera de las dos puede
+// ser VES.
+func (s *Service) Convert(ctx context.Context, from, to string, amount float64) (*Conversion, error) {
+	if err := s.ensureLoaded(ctx); err != nil {
+		return nil, err
+	}
+
+	result, err := s.currencies.GetConvertHandler().Handle(ctx, query.ConvertCurrencyQuery{
+		From:     strings.ToUpper(strings.TrimSpace(from)),
+		To:       strings.ToUpper(strings.TrimSpace(to)),
+		Amount:   amount,
+		UseCache: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !result.Success {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, result.Message)
+	}
+
+	return &Conversion{
+		From:    result.From,
+		To:      result.To,
+		Amount:  result.Amount,
+		Result:  result.Result,
+		Rate:    result.Rate,
+		RatesAt: result.RatesAt,
+		Stale:   result.Stale,
+	}, nil
+}
+
+// History obtiene los cambios de una moneda en el rango [from, to]. from en cero no
+// limita el inicio y to en cero equivale al momento actual.
+func (s *Service) History(ctx context.Context, id string, from, to time.Time) ([]*RateChange, error) {
+	result, err := s.currencies.GetRateHistoryHandler().Handle(ctx, query.GetRateHistoryQuery{
+		CurrencyID: id,
+		From:       from,
+		To:         to,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.Changes, nil
+}
+
+// Refresh obtiene las monedas del scraper y las guarda. Con force se guardan aunque no
+// hayan cambiado.
+func (s *Service) Refresh(ctx context.Context, force bool) (*RefreshResult, error) {
+	result, err := s.currencies.GetRefreshHandler().Handle(ctx, command.RefreshCurrenciesCommand{ForceRefresh: force})
+	if err != nil {
+		return result, err
+	}
+	if !result.Success {
+		return result, errors.New(result.Message)
+	}
+
+	s.freshUntil.Store(time.Now().Add(Freshness).UnixNano())
+	return result, nil
+}
+
+// Subscribe retorna un canal con los cambios de las monedas indicadas (todas si no se
+// indica ninguna) guardados por Refresh o la actualización periódica. El canal se
+// cierra al cancelarse el contexto, al cerrarse el servicio o si el suscriptor acumula
+// más cambios sin leer que el buffer de suscripción.
+func (s *Service) Subscribe(ctx context.Context, currencies ...string) <-chan *RateChange {
+	ids := make([]string, 0, len(currencies))
+	for _, id := range currencies {
+		ids = append(ids, strings.ToUpper(strings.TrimSpace(id)))
+	}
+	return s.events.Subscribe(ctx, ids, s.buffer)
+}
+
+// ensureLoaded obtiene las monedas del scraper si el repositorio está vacío o si la
+// moneda más reciente es más antigua que Freshness. Si la actualización falla y hay
+// monedas guardadas, retorna ErrStale con la fecha de las más recientes.
+func (s *Service) ensureLoaded(ctx context.Context) error {
+	if time.Now().UnixNano() < s.freshUntil.Load() {
+		return nil
+	}
+
+	s.loadMutex.Lock()
+	defer s.loadMutex.Unlock()
+
+	if time.Now().UnixNano() < s.freshUntil.Load() {
+		return nil
+	}
+
+	currencies, err := s.repository.FindAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	var newest time.Time
+	for _, currency := range currencies {
+		if currency.UpdatedAt.After(newest) {
+			newest = currency.UpdatedAt
+		}
+	}
+	if freshUntil := newest.Add(Freshness); len(currencies) > 0 && time.Now().Before(freshUntil) {
+		s.freshUntil.Store(freshUntil.UnixNano())
+		return nil
+	}
+
+	_, err = s.Refresh(ctx, false)
+	if err != nil && len(currencies) > 0 {
+		return fmt.Errorf("%w: newest rates are from %s and refreshing them failed: %v",
+			ErrStale, newest.Format(time.RFC3339), err)
+	}
+	return err
+}
@@ -0,0 +1,152 @@
+package bcv
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// stubScraper retorna USD con el valor configurado, o err si no es nil, y cuenta las
+// llamadas a ScrapeCurrencies.
+type stubScraper struct {
+	value float64
+	err   error
+	calls int
+}
+
+func (s *stubScraper) ScrapeCurrencies(ctx context.Context) ([]*Currency, error) {
+	s.calls++
+	if s.err != nil {
+		return nil, s.err
+	}
+	return []*Currency{{ID: "USD", Name: "Dólar", Value: s.value, UpdatedAt: time.Now(), Source: "test"}}, nil
+}
+
+func (s *stubScraper) ScrapeCurrency(ctx context.Context, currencyID string) (*Currency, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (s *stubScraper) IsHealthy(ctx context.Context) error {
+	return nil
+}
+
+// agedRepository simula un repositorio cuyas monedas se guardaron hace age, como la base
+// local de una ejecución anterior. Guardar una moneda vuelve a dejarlas al día.
+type agedRepository struct {
+	Repository
+	age time.Duration
+}
+
+func (r *agedRepository) Save(ctx context.Context, currency *Currency) error {
+	r.age = 0
+	return r.Repository.Save(ctx, currency)
+}
+
+func (r *agedRepository) FindByID(ctx context.Context, id string) (*Currency, error) {
+	currency, err := r.Repository.FindByID(ctx, id)
+	if currency != nil {
+		currency = r.aged(currency)
+	}
+	return currency, err
+}
+
+func (r *agedRepository) FindAll(ctx context.Context) ([]*Currency, error) {
+	currencies, err := r.Repository.FindAll(ctx)
+	for i, currency := range currencies {
+		currencies[i] = r.aged(currency)
+	}
+	return currencies, err
+}
+
+// aged retorna una copia de la moneda con la antigüedad del repositorio.
+func (r *agedRepository) aged(currency *Currency) *Currency {
+	copied := *currency
+	copied.UpdatedAt = copied.UpdatedAt.Add(-r.age)
+	return &copied
+}
+
+// newTestService crea un servicio sobre un repositorio con USD a 36.5 guardado hace age.
+func newTestService(t *testing.T, scraper Scraper, age time.Duration) *Service {
+	t.Helper()
+
+	repo := &agedRepository{Repository: NewMemoryRepository()}
+	usd := &Currency{ID: "USD", Name: "Dólar", Value: 36.5, Source: "test"}
+	if err := repo.Save(context.Background(), usd); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	repo.age = age
+
+	s, err := New(WithScraper(scraper), WithRepository(repo))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func TestServiceUsesFreshStoredCurrencies(t *testing.T) {
+	scraper := &stubScraper{value: 37}
+	s := newTestService(t, scraper, time.Minute)
+
+	usd, err := s.Get(context.Background(), "usd")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if usd.Value != 36.5 || scraper.calls != 0 {
+		t.Errorf("Get = %v after %d scrapes, want the stored 36.5 without scraping", usd.Value, scraper.calls)
+	}
+}
+
+func TestServiceRefreshesStaleCurrencies(t *testing.T) {
+	ctx := context.Background()
+	scraper := &stubScraper{value: 37}
+	s := newTestService(t, scraper, 25*time.Hour)
+
+	currencies, err := s.Latest(ctx)
+	if err != nil {
+		t.Fatalf("Latest: %v", err)
+	}
+	if len(currencies) != 1 || currencies[0].Value != 37 {
+		t.Fatalf("Latest = %v, want USD refreshed to 37", currencies)
+	}
+
+	// Las monedas recién actualizadas se sirven sin volver al scraper
+	if _, err := s.Get(ctx, "USD"); err != nil || scraper.calls != 1 {
+		t.Errorf("Get = %v after %d scrapes, want 1 scrape", err, scraper.calls)
+	}
+}
+
+func TestServiceReportsStaleCurrencies(t *testing.T) {
+	ctx := context.Background()
+	scraper := &stubScraper{err: errors.New("connection refused")}
+	s := newTestService(t, scraper, 25*time.Hour)
+
+	if _, err := s.Get(ctx, "USD"); !errors.Is(err, ErrStale) {
+		t.Errorf("Get error = %v, want ErrStale", err)
+	}
+	if _, err := s.Convert(ctx, "USD", "VES", 1); !errors.Is(err, ErrStale) {
+		t.Errorf("Convert error = %v, want ErrStale", err)
+	}
+
+	// Cuando el scraper vuelve a responder, la siguiente consulta actualiza las monedas
+	scraper.err = nil
+	scraper.value = 37
+	if usd, err := s.Get(ctx, "USD"); err != nil || usd.Value != 37 {
+		t.Errorf("Get = %v, %v, want 37 once the scraper recovers", usd, err)
+	}
+}
+
+func TestServiceReportsScraperErrorWithoutCurrencies(t *testing.T) {
+	scrapeErr := errors.New("connection refused")
+	s, err := New(WithScraper(&stubScraper{err: scrapeErr}))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	defer s.Close()
+
+	_, err = s.Latest(context.Background())
+	if !errors.Is(err, scrapeErr) || errors.Is(err, ErrStale) {
+		t.Errorf("Latest error = %v, want the scraper error", err)
+	}
+}
@@ -0,0 +1,425 @@
+// Package client implementa un cliente Go tipado de la API de monedas del BCV, con
+// reintentos, caché por ETag y errores construidos a partir del envoltorio de la API.
+//
+//	c, err := client.New("http://localhost:8080", client.WithAPIKey(key))
+//	usd, err := c.Currency(ctx, "USD")
+//	if errors.Is(err, client.ErrNotFound) { ... }
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gobcv/pkg/requestid"
+)
+
+const (
+	// apiPrefix es el prefijo de las rutas de la API.
+	apiPrefix = "/api/v1"
+	// defaultMaxRetries es la cantidad de reintentos por defecto.
+	defaultMaxRetries = 3
+	// defaultRetryBackoff es la espera antes del primer reintento; se duplica en cada uno.
+	defaultRetryBackoff = 200 * time.Millisecond
+	// maxRetryWait es la espera máxima entre reintentos. Si el servidor pide esperar
+	// más con Retry-After, el error se retorna sin reintentar.
+	maxRetryWait = 10 * time.Second
+	// maxResponseBody limita el tamaño de las respuestas leídas.
+	maxResponseBody = 10 << 20
+	// maxCachedResponses limita las respuestas guardadas para peticiones condicionales.
+	maxCachedResponses = 256
+)
+
+// Client es un cliente de la API. Es seguro para uso concurrente.
+type Client struct {
+	baseURL      string
+	httpClient   *http.Client
+	apiKey       string
+	userAgent    string
+	maxRetries   int
+	retryBackoff time.Duration
+	cache        *responseCache
+}
+
+// Option configura un Client.
+type Option func(*Client)
+
+// WithHTTPClient usa el cliente HTTP indicado en lugar de uno con timeout de 30 segundos.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithAPIKey envía la clave de API en cada petición.
+func WithAPIKey(key string) Option {
+	return func(c *Client) {
+		c.apiKey = key
+	}
+}
+
+// WithUserAgent define la cabecera User-Agent de las peticiones.
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// WithRetries define cuántas veces se reintenta una petición fallida y la espera antes
+// del primer reintento. maxRetries 0 desactiva los reintentos.
+func WithRetries(maxRetries int, backoff time.Duration) Option {
+	return func(c *Client) {
+		c.maxRetries = maxRetries
+		c.retryBackoff = backoff
+	}
+}
+
+// WithoutCache desactiva las peticiones condicionales con If-None-Match.
+func WithoutCache() Option {
+	return func(c *Client) {
+		c.cache = nil
+	}
+}
+
+// RequestOption modifica los parámetros de una consulta.
+type RequestOption func(url.Values)
+
+// SkipCache pide al servidor que obtenga los datos sin usar su caché.
+func SkipCache() RequestOption {
+	return func(params url.Values) {
+		params.Set("cache", "false")
+	}
+}
+
+// IncludeStale pide al servidor incluir las monedas desactualizadas en el listado.
+func IncludeStale() RequestOption {
+	return func(params url.Values) {
+		params.Set("include_stale", "true")
+	}
+}
+
+// New crea un cliente para la API en baseURL, por ejemplo http://localhost:8080. El
+// prefijo /api/v1 se agrega si la URL no lo incluye.
+func New(baseURL string, opts ...Option) (*Client, error) {
+	parsed, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL: %w", err)
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid base URL %q: must be an absolute http or https URL", baseURL)
+	}
+
+	base := strings.TrimSuffix(parsed.String(), "/")
+	if !strings.HasSuffix(base, apiPrefix) {
+		base += apiPrefix
+	}
+
+	c := &Client{
+		baseURL:      base,
+		httpClient:   &http.Client{Timeout: 30 * time.Second},
+		userAgent:    "gobcv-client/1.0",
+		maxRetries:   defaultMaxRetries,
+		retryBackoff: defaultRetryBackoff,
+		cache:        &responseCache{entries: make(map[string]cachedResponse)},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// Currencies obtiene todas las monedas.
+func (c *Client) Currencies(ctx context.Context, opts ...RequestOption) (*CurrenciesResult, error) {
+	var result CurrenciesResult
+	notModified, err := c.do(ctx, http.MethodGet, "/currencies", requestParams(opts), true, &result)
+	if err != nil {
+		return nil, err
+	}
+	result.NotModified = notModified
+	return &result, nil
+}
+
+// Currency obtiene una moneda por su código ISO 4217 (USD, EUR).
+func (c *Client) Currency(ctx context.Context, id string, opts ...RequestOption) (*CurrencyResult, error) {
+	var result CurrencyResult
+	notModified, err := c.do(ctx, http.MethodGet, "/currencies/"+currencyPath(id), requestParams(opts), true, &result)
+	if err != nil {
+		return nil, err
+	}
+	result.NotModified = notModified
+	return &result, nil
+}
+
+// History obtiene los cambios de una moneda entre from y to. Un from cero no limita el
+// inicio y un to cero equivale a ahora.
+func (c *Client) History(ctx context.Context, id string, from, to time.Time) (*RateHistory, error) {
+	params := url.Values{}
+	if !from.IsZero() {
+		params.Set("from", from.Format(time.RFC3339Nano))
+	}
+	if !to.IsZero() {
+		params.Set("to", to.Format(time.RFC3339Nano))
+	}
+
+	var result RateHistory
+	if _, err := c.do(ctx, http.MethodGet, "/currencies/"+currencyPath(id)+"/history", params, false, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Convert convierte amount de la moneda from a la moneda to; cualquiera de las dos puede ser VES.
+func (c *Client) Convert(ctx context.Context, from, to string, amount float64, opts ...RequestOption) (*Conversion, error) {
+	params := requestParams(opts)
+	params.Set("from", strings.ToUpper(from))
+	params.Set("to", strings.ToUpper(to))
+	params.Set("amount", strconv.FormatFloat(amount, 'f', -1, 64))
+
+	var result Conversion
+	if _, err := c.do(ctx, http.MethodGet, "/currencies/convert", params, false, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Refresh actualiza las monedas desde el BCV. Con force se actualizan aunque no hayan cambiado.
+func (c *Client) Refresh(ctx context.Context, force bool) (*RefreshResult, error) {
+	params := url.Values{}
+	if force {
+		params.Set("force", "true")
+	}
+
+	var result RefreshResult
+	if _, err := c.do(ctx, http.MethodPost, "/currencies/refresh", params, false, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// CacheStats obtiene las estadísticas del caché del servidor.
+func (c *Client) CacheStats(ctx context.Context) (*CacheStats, error) {
+	var result CacheStats
+	if _, err := c.do(ctx, http.MethodGet, "/cache/stats", nil, false, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// envelope es el envoltorio de las respuestas de la API.
+type envelope struct {
+	Success bool            `json:"success"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data"`
+	Error   string          `json:"error"`
+}
+
+// do ejecuta una petición con reintentos y decodifica el campo data de la respuesta en
+// out. Con conditional envía If-None-Match con el ETag de la respuesta guardada y, si el
+// servidor responde 304, la reutiliza y retorna true.
+func (c *Client) do(ctx context.Context, method, path string, params url.Values, conditional bool, out interface{}) (bool, error) {
+	target := c.baseURL + path
+	if len(params) > 0 {
+		target += "?" + params.Encode()
+	}
+
+	cache := c.cache
+	if !conditional {
+		cache = nil
+	}
+
+	for attempt := 0; ; attempt++ {
+		status, header, body, err := c.send(ctx, method, target, cache)
+		if err != nil {
+			if ctx.Err() != nil {
+				return false, ctx.Err()
+			}
+			// Solo GET es seguro de repetir si no se sabe si la petición llegó
+			if method != http.MethodGet || !c.wait(ctx, attempt, 0) {
+				return false, err
+			}
+			continue
+		}
+
+		if retryableStatus(method, status) {
+			retryAfter, _ := strconv.Atoi(header.Get("Retry-After"))
+			if c.wait(ctx, attempt, time.Duration(retryAfter)*time.Second) {
+				continue
+			}
+		}
+
+		notModified := false
+		if status == http.StatusNotModified {
+			cached, ok := cache.get(target)
+			if !ok {
+				return false, fmt.Errorf("gobcv: unexpected 304 Not Modified for %s", path)
+			}
+			body, status, notModified = cached.body, http.StatusOK, true
+		}
+
+		var response envelope
+		if err := json.Unmarshal(body, &response); err != nil {
+			if status >= http.StatusBadRequest {
+				return false, &APIError{StatusCode: status, Message: http.StatusText(status), RequestID: header.Get(requestid.Header)}
+			}
+			return false, fmt.Errorf("gobcv: invalid response from %s: %w", path, err)
+		}
+
+		if status >= http.StatusBadRequest || !response.Success {
+			return false, &APIError{
+				StatusCode: status,
+				Message:    response.Message,
+				Detail:     response.Error,
+				RequestID:  header.Get(requestid.Header),
+			}
+		}
+
+		if etag := header.Get("ETag"); etag != "" && !notModified {
+			cache.put(target, cachedResponse{etag: etag, body: body})
+		}
+
+		if len(response.Data) > 0 && out != nil {
+			if err := json.Unmarshal(response.Data, out); err != nil {
+				return false, fmt.Errorf("gobcv: invalid response data from %s: %w", path, err)
+			}
+		}
+		return notModified, nil
+	}
+}
+
+// send envía una petición y lee la respuesta completa.
+func (c *Client) send(ctx context.Context, method, target string, cache *responseCache) (int, http.Header, []byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, target, nil)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", c.userAgent)
+	if c.apiKey != "" {
+		req.Header.Set("X-API-Key", c.apiKey)
+	}
+	if id := requestid.FromContext(ctx); id != "" {
+		req.Header.Set(requestid.Header, id)
+	}
+	if cached, ok := cache.get(target); ok {
+		req.Header.Set("If-None-Match", cached.etag)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	return resp.StatusCode, resp.Header, body, nil
+}
+
+// wait espera antes del reintento indicado. retryAfter, si no es cero, reemplaza la
+// espera exponencial. Retorna false si no quedan reintentos, si la espera supera
+// maxRetryWait o si se cancela el contexto.
+func (c *Client) wait(ctx context.Context, attempt int, retryAfter time.Duration) bool {
+	if attempt >= c.maxRetries {
+		return false
+	}
+
+	delay := retryAfter
+	if delay == 0 {
+		delay = c.retryBackoff << attempt
+		// Variación aleatoria para que los clientes no reintenten a la vez
+		delay += rand.N(delay/2 + 1)
+	}
+	if delay > maxRetryWait {
+		return false
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// retryableStatus indica si una respuesta puede reintentarse. Las peticiones que no son
+// GET solo se reintentan si el límite de peticiones impidió procesarlas.
+func retryableStatus(method string, status int) bool {
+	switch status {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return method == http.MethodGet
+	}
+	return false
+}
+
+// requestParams aplica las opciones de una consulta.
+func requestParams(opts []RequestOption) url.Values {
+	params := url.Values{}
+	for _, opt := range opts {
+		opt(params)
+	}
+	return params
+}
+
+// currencyPath normaliza un código de moneda para usarlo en una ruta.
+func currencyPath(id string) string {
+	return url.PathEscape(strings.ToUpper(strings.TrimSpace(id)))
+}
+
+// cachedResponse es una respuesta guardada para peticiones condicionales.
+type cachedResponse struct {
+	etag string
+	body []byte
+}
+
+// responseCache guarda la última respuesta con ETag de cada URL. Un caché nil no guarda nada.
+type responseCache struct {
+	entries map[string]cachedResponse
+	mutex   sync.Mutex
+}
+
+// get obtiene la respuesta guardada de una URL.
+func (r *responseCache) get(target string) (cachedResponse, bool) {
+	if r == nil {
+		return cachedResponse{}, false
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	cached, ok := r.entries[target]
+	return cached, ok
+}
+
+// put guarda la respuesta de una URL, descartando otra si el caché está lleno.
+func (r *responseCache) put(target string, cached cachedResponse) {
+	if r == nil {
+		return
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, exists := r.entries[target]; !exists && len(r.entries) >= maxCachedResponses {
+		for key := range r.entries {
+			delete(r.entries, key)
+			break
+		}
+	}
+	r.entries[target] = cached
+}
@@ -0,0 +1,434 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	appService "gobcv/internal/application/service"
+	"gobcv/internal/domain/entity"
+	"gobcv/internal/domain/service"
+	"gobcv/internal/infrastructure/cache"
+	"gobcv/internal/infrastructure/events"
+	httpInfra "gobcv/internal/infrastructure/http"
+	"gobcv/internal/infrastructure/invalidation"
+	"gobcv/internal/infrastructure/ratelimit"
+)
+
+// Claves de API registradas en el servidor de prueba.
+const (
+	readKey    = "test-read-key"
+	refreshKey = "test-refresh-key"
+	adminKey   = "test-admin-key"
+)
+
+// stubScraper retorna las monedas configuradas como si vinieran del BCV.
+type stubScraper struct {
+	currencies []*entity.Currency
+}
+
+func (s *stubScraper) ScrapeCurrencies(ctx context.Context) ([]*entity.Currency, error) {
+	currencies := make([]*entity.Currency, 0, len(s.currencies))
+	for _, currency := range s.currencies {
+		copied := *currency
+		copied.UpdatedAt = time.Now()
+		currencies = append(currencies, &copied)
+	}
+	return currencies, nil
+}
+
+func (s *stubScraper) ScrapeCurrency(ctx context.Context, currencyID string) (*entity.Currency, error) {
+	for _, currency := range s.currencies {
+		if currency.ID == currencyID {
+			return currency, nil
+		}
+	}
+	return nil, errors.New("currency not found")
+}
+
+func (s *stubScraper) IsHealthy(ctx context.Context) error {
+	return nil
+}
+
+// recordedRequest es una petición recibida por el servidor de prueba y su respuesta.
+type recordedRequest struct {
+	method      string
+	ifNoneMatch string
+	status      int
+}
+
+// testAPI es la API real servida por httptest, con las peticiones que recibió.
+type testAPI struct {
+	*httptest.Server
+
+	mutex    sync.Mutex
+	requests []recordedRequest
+	// failures son los códigos con los que se responden las próximas peticiones antes
+	// de llegar al router, como lo haría un proxy.
+	failures []int
+}
+
+// newTestAPI monta el router de la API con USD a 36.5 y EUR a 40 en memoria, lecturas
+// anónimas y los middlewares indicados después de la autenticación.
+func newTestAPI(t *testing.T, middlewares ...httpInfra.RouteMiddleware) *testAPI {
+	t.Helper()
+
+	ctx := context.Background()
+	repo := cache.NewMemoryRepository()
+	repo.Save(ctx, entity.NewCurrency("USD", "Dólar", 36.5, "test"))
+	repo.Save(ctx, entity.NewCurrency("EUR", "Euro", 40, "test"))
+	history := cache.NewMemoryRateHistory(10)
+	history.Append(ctx, &entity.RateChange{CurrencyID: "USD", Name: "Dólar", Value: 36.5, PreviousValue: 36.2, ChangedAt: time.Now().Add(-time.Hour)})
+
+	memoryCache := cache.NewMemoryCache()
+	t.Cleanup(func() { memoryCache.Close() })
+
+	scraper := &stubScraper{currencies: []*entity.Currency{
+		entity.NewCurrency("USD", "Dólar", 37, "test"),
+		entity.NewCurrency("EUR", "Euro", 40, "test"),
+	}}
+	currencyService := appService.NewCurrencyService(repo, scraper, memoryCache, invalidation.NewMemoryBus(),
+		history, events.NewBroker(), "test", time.Hour)
+
+	keys := cache.NewMemoryAPIKeyRepository()
+	keys.Save(ctx, &entity.APIKey{ID: "read", KeyHash: entity.HashAPIKey(readKey), Scopes: []string{entity.ScopeRead}})
+	keys.Save(ctx, &entity.APIKey{ID: "refresh", KeyHash: entity.HashAPIKey(refreshKey), Scopes: []string{entity.ScopeRead, entity.ScopeRefresh}})
+	keys.Save(ctx, &entity.APIKey{ID: "admin", KeyHash: entity.HashAPIKey(adminKey), Scopes: []string{entity.ScopeRead, entity.ScopeRefresh, entity.ScopeAdmin}})
+
+	handlers := httpInfra.NewHandlers(
+		currencyService.GetRefreshHandler(),
+		currencyService.GetCurrencyHandler(),
+		currencyService.GetAllCurrenciesHandler(),
+		currencyService.GetConvertHandler(),
+		currencyService.GetRateHistoryHandler(),
+		currencyService,
+		currencyService,
+		nil, nil, nil, nil, nil, nil,
+	)
+	anonymous := []string{entity.ScopeRead}
+	options := httpInfra.RouterOptions{AuthEnabled: true, AnonymousScopes: anonymous}
+	router := httpInfra.SetupRouter(handlers, options,
+		append([]httpInfra.RouteMiddleware{httpInfra.NewAuthMiddleware(keys, anonymous)}, middlewares...)...)
+
+	api := &testAPI{}
+	api.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		if status, ok := api.nextFailure(); ok {
+			http.Error(recorder, "<html>upstream unavailable</html>", status)
+		} else {
+			router.ServeHTTP(recorder, r)
+		}
+		api.record(recordedRequest{method: r.Method, ifNoneMatch: r.Header.Get("If-None-Match"), status: recorder.status})
+	}))
+	t.Cleanup(api.Close)
+
+	return api
+}
+
+// failNext hace que las próximas peticiones respondan con los códigos indicados.
+func (a *testAPI) failNext(statuses ...int) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.failures = append(a.failures, statuses...)
+}
+
+// nextFailure retorna el próximo código de falla, si queda alguno.
+func (a *testAPI) nextFailure() (int, bool) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	if len(a.failures) == 0 {
+		return 0, false
+	}
+	status := a.failures[0]
+	a.failures = a.failures[1:]
+	return status, true
+}
+
+func (a *testAPI) record(request recordedRequest) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.requests = append(a.requests, request)
+}
+
+// received retorna las peticiones recibidas y reinicia el registro.
+func (a *testAPI) received() []recordedRequest {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	requests := a.requests
+	a.requests = nil
+	return requests
+}
+
+// statusRecorder guarda el código de estado de la respuesta.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// newTestClient crea un cliente de la API de prueba con reintentos rápidos.
+func newTestClient(t *testing.T, api *testAPI, opts ...Option) *Client {
+	t.Helper()
+
+	c, err := New(api.URL, append([]Option{WithRetries(2, time.Millisecond)}, opts...)...)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return c
+}
+
+func TestClientMethods(t *testing.T) {
+	ctx := context.Background()
+	api := newTestAPI(t)
+	c := newTestClient(t, api, WithAPIKey(adminKey))
+
+	currencies, err := c.Currencies(ctx)
+	if err != nil {
+		t.Fatalf("Currencies: %v", err)
+	}
+	if currencies.Count != 2 || len(currencies.Currencies) != 2 {
+		t.Errorf("Currencies = %+v, want USD and EUR", currencies)
+	}
+
+	usd, err := c.Currency(ctx, "usd", SkipCache())
+	if err != nil {
+		t.Fatalf("Currency: %v", err)
+	}
+	if usd.Currency.ID != "USD" || usd.Currency.Value != 36.5 || usd.FromCache {
+		t.Errorf("Currency = %+v, want USD 36.5 from the repository", usd.Currency)
+	}
+
+	history, err := c.History(ctx, "USD", time.Now().Add(-24*time.Hour), time.Time{})
+	if err != nil {
+		t.Fatalf("History: %v", err)
+	}
+	if history.Count != 1 || history.CurrencyID != "USD" || history.Changes[0].PreviousValue != 36.2 {
+		t.Errorf("History = %+v, want one USD change", history)
+	}
+
+	conversion, err := c.Convert(ctx, "usd", "eur", 80)
+	if err != nil {
+		t.Fatalf("Convert: %v", err)
+	}
+	if conversion.From != "USD" || conversion.To != "EUR" || conversion.Result != 73 || conversion.RatesAt.IsZero() {
+		t.Errorf("Convert = %+v, want 73 EUR", conversion)
+	}
+
+	refreshed, err := c.Refresh(ctx, true)
+	if err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	if !refreshed.Success || refreshed.UpdatedCount != 2 || refreshed.ChangedCount != 1 {
+		t.Errorf("Refresh = %+v, want 2 updated and 1 changed", refreshed)
+	}
+
+	usd, err = c.Currency(ctx, "USD")
+	if err != nil || usd.Currency.Value != 37 {
+		t.Errorf("Currency after refresh = %+v, %v, want 37", usd, err)
+	}
+
+	if _, err := c.CacheStats(ctx); err != nil {
+		t.Errorf("CacheStats: %v", err)
+	}
+
+	for _, request := range api.received() {
+		if request.status >= http.StatusBadRequest {
+			t.Errorf("%s answered %d", request.method, request.status)
+		}
+	}
+}
+
+func TestClientMapsErrorEnvelope(t *testing.T) {
+	ctx := context.Background()
+	api := newTestAPI(t)
+
+	tests := []struct {
+		name    string
+		call    func(c *Client) error
+		key     string
+		target  error
+		status  int
+		message string
+	}{
+		{
+			name:    "not found",
+			call:    func(c *Client) error { _, err := c.Currency(ctx, "GBP"); return err },
+			target:  ErrNotFound,
+			status:  http.StatusNotFound,
+			message: "Moneda no encontrada",
+		},
+		{
+			name:   "bad request",
+			call:   func(c *Client) error { _, err := c.Convert(ctx, "US", "EUR", 1); return err },
+			target: ErrBadRequest,
+			status: http.StatusBadRequest,
+		},
+		{
+			name:    "missing key",
+			call:    func(c *Client) error { _, err := c.Refresh(ctx, false); return err },
+			target:  ErrUnauthorized,
+			status:  http.StatusUnauthorized,
+			message: "API key required",
+		},
+		{
+			name:   "invalid key",
+			call:   func(c *Client) error { _, err := c.Currencies(ctx); return err },
+			key:    "unknown-key",
+			target: ErrUnauthorized,
+			status: http.StatusUnauthorized,
+		},
+		{
+			name:   "missing scope",
+			call:   func(c *Client) error { _, err := c.Refresh(ctx, false); return err },
+			key:    readKey,
+			target: ErrForbidden,
+			status: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient(t, api, WithAPIKey(tt.key))
+			err := tt.call(c)
+
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("error = %v, want *APIError", err)
+			}
+			if !errors.Is(err, tt.target) {
+				t.Errorf("errors.Is(%v, %v) = false", err, tt.target)
+			}
+			if apiErr.StatusCode != tt.status || apiErr.Message == "" || apiErr.RequestID == "" {
+				t.Errorf("APIError = %+v, want status %d with message and request id", apiErr, tt.status)
+			}
+			if tt.message != "" && apiErr.Message != tt.message {
+				t.Errorf("Message = %q, want %q", apiErr.Message, tt.message)
+			}
+			if tt.status < http.StatusInternalServerError && errors.Is(err, ErrServer) {
+				t.Errorf("%v matches ErrServer", err)
+			}
+		})
+	}
+}
+
+func TestClientConditionalRequests(t *testing.T) {
+	ctx := context.Background()
+	api := newTestAPI(t)
+	c := newTestClient(t, api, WithAPIKey(refreshKey))
+
+	first, err := c.Currency(ctx, "USD")
+	if err != nil || first.NotModified {
+		t.Fatalf("first Currency = %+v, %v, want a full response", first, err)
+	}
+
+	second, err := c.Currency(ctx, "USD")
+	if err != nil {
+		t.Fatalf("second Currency: %v", err)
+	}
+	if !second.NotModified || second.Currency.Value != first.Currency.Value {
+		t.Errorf("second Currency = %+v, want the cached USD marked NotModified", second)
+	}
+
+	requests := api.received()
+	if len(requests) != 2 || requests[0].ifNoneMatch != "" || requests[1].ifNoneMatch == "" || requests[1].status != http.StatusNotModified {
+		t.Fatalf("requests = %+v, want a conditional request answered with 304", requests)
+	}
+
+	// Cuando la tasa cambia, el ETag ya no coincide y se recibe la respuesta completa
+	if _, err := c.Refresh(ctx, true); err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	third, err := c.Currency(ctx, "USD")
+	if err != nil || third.NotModified || third.Currency.Value != 37 {
+		t.Errorf("Currency after refresh = %+v, %v, want 37 not from the client cache", third, err)
+	}
+
+	uncached := newTestClient(t, api, WithoutCache())
+	for i := 0; i < 2; i++ {
+		if result, err := uncached.Currencies(ctx); err != nil || result.NotModified {
+			t.Errorf("Currencies without cache = %+v, %v", result, err)
+		}
+	}
+	for _, request := range api.received()[2:] {
+		if request.ifNoneMatch != "" {
+			t.Errorf("client without cache sent If-None-Match %q", request.ifNoneMatch)
+		}
+	}
+}
+
+func TestClientRetriesRateLimited(t *testing.T) {
+	ctx := context.Background()
+	limiter := httpInfra.NewRateLimitMiddleware(ratelimit.NewMemoryStore(), map[string]service.RateLimit{
+		httpInfra.RouteClassRead: {Burst: 1, Period: 500 * time.Millisecond},
+	}, nil)
+	api := newTestAPI(t, limiter)
+
+	// Sin reintentos el 429 se retorna como error
+	noRetries := newTestClient(t, api, WithRetries(0, time.Millisecond))
+	if _, err := noRetries.Currencies(ctx); err != nil {
+		t.Fatalf("first Currencies: %v", err)
+	}
+	_, err := noRetries.Currencies(ctx)
+	var apiErr *APIError
+	if !errors.Is(err, ErrRateLimited) || !errors.As(err, &apiErr) || apiErr.Message != "Rate limit exceeded" {
+		t.Fatalf("rate limited Currencies error = %v, want ErrRateLimited", err)
+	}
+	api.received()
+
+	// Con reintentos se espera lo indicado por Retry-After
+	c := newTestClient(t, api)
+	start := time.Now()
+	if _, err := c.Currencies(ctx); err != nil {
+		t.Fatalf("Currencies with retries: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 900*time.Millisecond {
+		t.Errorf("retried after %s, want the 1s of Retry-After", elapsed)
+	}
+
+	requests := api.received()
+	if len(requests) != 2 || requests[0].status != http.StatusTooManyRequests || requests[1].status != http.StatusOK {
+		t.Errorf("requests = %+v, want 429 then 200", requests)
+	}
+}
+
+func TestClientRetriesTransientErrors(t *testing.T) {
+	ctx := context.Background()
+	api := newTestAPI(t)
+	c := newTestClient(t, api, WithAPIKey(refreshKey))
+
+	// Las lecturas se reintentan ante fallas del proxy
+	api.failNext(http.StatusServiceUnavailable, http.StatusBadGateway)
+	if _, err := c.Currency(ctx, "USD"); err != nil {
+		t.Fatalf("Currency after transient failures: %v", err)
+	}
+	if requests := api.received(); len(requests) != 3 {
+		t.Errorf("Currency sent %d requests, want 3", len(requests))
+	}
+
+	// Agotados los reintentos, un cuerpo que no es el envoltorio se reporta con su código
+	api.failNext(http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusServiceUnavailable)
+	_, err := c.Currency(ctx, "USD")
+	var apiErr *APIError
+	if !errors.Is(err, ErrServer) || !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable ||
+		apiErr.Message != http.StatusText(http.StatusServiceUnavailable) {
+		t.Errorf("Currency after exhausting retries error = %v, want a 503 APIError", err)
+	}
+	api.received()
+
+	// Refresh no se reintenta: el servidor podría haberlo procesado
+	api.failNext(http.StatusServiceUnavailable)
+	if _, err := c.Refresh(ctx, false); !errors.Is(err, ErrServer) {
+		t.Errorf("Refresh error = %v, want ErrServer", err)
+	}
+	if requests := api.received(); len(requests) != 1 {
+		t.Errorf("Refresh sent %d requests, want 1", len(requests))
+	}
+}
@@ -0,0 +1,57 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Errores con los que se puede comparar un *APIError mediante errors.Is.
+var (
+	ErrBadRequest   = errors.New("bad request")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+	ErrNotFound     = errors.New("not found")
+	ErrRateLimited  = errors.New("rate limited")
+	ErrServer       = errors.New("server error")
+)
+
+// APIError es una respuesta de error de la API, construida a partir de su envoltorio.
+type APIError struct {
+	StatusCode int
+	// Message y Detail son los campos message y error del envoltorio.
+	Message   string
+	Detail    string
+	RequestID string
+}
+
+// Error implementa error.
+func (e *APIError) Error() string {
+	message := fmt.Sprintf("gobcv: %d %s", e.StatusCode, e.Message)
+	if e.Detail != "" {
+		message += ": " + e.Detail
+	}
+	if e.RequestID != "" {
+		message += " (request " + e.RequestID + ")"
+	}
+	return message
+}
+
+// Is permite comparar el error con los errores de la categoría de su código de estado.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrBadRequest:
+		return e.StatusCode == http.StatusBadRequest
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrServer:
+		return e.StatusCode >= http.StatusInternalServerError
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+package client
+
+import "time"
+
+// Currency es una moneda con su valor en VES publicado por el BCV.
+type Currency struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Value     float64   `json:"value"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Source    string    `json:"source"`
+}
+
+// CurrencyResult es la respuesta de una moneda.
+type CurrencyResult struct {
+	Currency   *Currency `json:"currency"`
+	FromCache  bool      `json:"from_cache"`
+	Stale      bool      `json:"stale"`
+	AgeSeconds int64     `json:"age_seconds"`
+	// NotModified indica que el servidor respondió 304 y el resultado proviene del
+	// caché del cliente; FromCache, Stale y AgeSeconds son los de la respuesta guardada.
+	NotModified bool `json:"-"`
+}
+
+// CurrenciesResult es la respuesta del listado de monedas.
+type CurrenciesResult struct {
+	Currencies []*Currency `json:"currencies"`
+	Count      int         `json:"count"`
+	FromCache  bool        `json:"from_cache"`
+	Stale      bool        `json:"stale"`
+	AgeSeconds int64       `json:"age_seconds"`
+	// NotModified indica que el servidor respondió 304 y el resultado proviene del
+	// caché del cliente; FromCache, Stale y AgeSeconds son los de la respuesta guardada.
+	NotModified bool `json:"-"`
+}
+
+// RefreshResult es el resultado de una actualización de monedas desde el BCV.
+type RefreshResult struct {
+	UpdatedCount int      `json:"updated_count"`
+	ChangedCount int      `json:"changed_count"`
+	Currencies   []string `json:"currencies"`
+	Success      bool     `json:"success"`
+	Message      string   `json:"message"`
+}
+
+// CacheStats son las estadísticas del caché del servidor.
+type CacheStats struct {
+	Hits      int64                 `json:"hits"`
+	Misses    int64                 `json:"misses"`
+	StaleHits int64                 `json:"stale_hits"`
+	Keys      int64                 `json:"keys"`
+	Tiers     map[string]CacheStats `json:"tiers,omitempty"`
+}
+
+// RateChange es un cambio de valor de una moneda.
+type RateChange struct {
+	Sequence      uint64    `json:"sequence"`
+	CurrencyID    string    `json:"currency_id"`
+	Name          string    `json:"name"`
+	Value         float64   `json:"value"`
+	PreviousValue float64   `json:"previous_value,omitempty"`
+	Source        string    `json:"source"`
+	ChangedAt     time.Time `json:"changed_at"`
+}
+
+// RateHistory son los cambios de una moneda en un rango de fechas.
+type RateHistory struct {
+	CurrencyID string        `json:"currency_id"`
+	From       time.Time     `json:"from"`
+	To         time.Time     `json:"to"`
+	Changes    []*RateChange `json:"changes"`
+	Count      int           `json:"count"`
+}
+
+// Conversion es el resultado de convertir un monto entre dos monedas.
+type Conversion struct {
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+	Amount    float64   `json:"amount"`
+	Result    float64   `json:"result"`
+	Rate      float64   `json:"rate"`
+	RatesAt   time.Time `json:"rates_at"`
+	FromCache bool      `json:"from_cache"`
+	Stale     bool      `json:"stale"`
+}
@@ -2,109 +2,309 @@
 package config
 
 import (
+	"fmt"
+	"net"
+	"net/url"
 	"os"
-	"strconv"
 	"time"
 )
 
 // Config contiene toda la configuración de la aplicación.
 type Config struct {
-	Server   ServerConfig   `json:"server"`
-	Cache    CacheConfig    `json:"cache"`
-	Scraper  ScraperConfig  `json:"scraper"`
-	Database DatabaseConfig `json:"database"`
+	Server    ServerConfig    `json:"server"`
+	GRPC      GRPCConfig      `json:"grpc"`
+	GraphQL   GraphQLConfig   `json:"graphql"`
+	Cache     CacheConfig     `json:"cache"`
+	Scraper   ScraperConfig   `json:"scraper"`
+	Database  DatabaseConfig  `json:"database"`
+	Redis     RedisConfig     `json:"redis"`
+	Auth      AuthConfig      `json:"auth"`
+	RateLimit RateLimitConfig `json:"rate_limit"`
+	CORS      CORSConfig      `json:"cors"`
+	Log       LogConfig       `json:"log"`
+	Metrics   MetricsConfig   `json:"metrics"`
+	Tracing   TracingConfig   `json:"tracing"`
+	Stream    StreamConfig    `json:"stream"`
+	Webhooks  WebhooksConfig  `json:"webhooks"`
+	Alerts    AlertsConfig    `json:"alerts"`
 }
 
 // ServerConfig contiene la configuración del servidor HTTP.
 type ServerConfig struct {
-	Port         string        `json:"port"`
-	Host         string        `json:"host"`
-	ReadTimeout  time.Duration `json:"read_timeout"`
-	WriteTimeout time.Duration `json:"write_timeout"`
-	IdleTimeout  time.Duration `json:"idle_timeout"`
+	ReplicaID    string        `json:"replica_id" env:"REPLICA_ID"`
+	Port         string        `json:"port" env:"SERVER_PORT"`
+	Host         string        `json:"host" env:"SERVER_HOST"`
+	ReadTimeout  time.Duration `json:"read_timeout" env:"SERVER_READ_TIMEOUT"`
+	WriteTimeout time.Duration `json:"write_timeout" env:"SERVER_WRITE_TIMEOUT"`
+	IdleTimeout  time.Duration `json:"idle_timeout" env:"SERVER_IDLE_TIMEOUT"`
+}
+
+// GraphQLConfig contiene los límites de las consultas del endpoint GraphQL.
+type GraphQLConfig struct {
+	MaxDepth      int `json:"max_depth" env:"GRAPHQL_MAX_DEPTH"`
+	MaxComplexity int `json:"max_complexity" env:"GRAPHQL_MAX_COMPLEXITY"`
+}
+
+// GRPCConfig contiene la configuración del servidor gRPC, que escucha en el mismo
+// host que el servidor HTTP.
+type GRPCConfig struct {
+	Enabled bool   `json:"enabled" env:"GRPC_ENABLED"`
+	Port    string `json:"port" env:"GRPC_PORT"`
 }
 
 // CacheConfig contiene la configuración del caché.
 type CacheConfig struct {
-	DefaultTTL    time.Duration `json:"default_ttl"`
-	CleanupPeriod time.Duration `json:"cleanup_period"`
-	MaxItems      int           `json:"max_items"`
+	Type                string        `json:"type" env:"CACHE_TYPE"`
+	InvalidationBus     string        `json:"invalidation_bus" env:"CACHE_INVALIDATION_BUS"`
+	InvalidationChannel string        `json:"invalidation_channel" env:"CACHE_INVALIDATION_CHANNEL"`
+	DefaultTTL          time.Duration `json:"default_ttl" env:"CACHE_DEFAULT_TTL"`
+	CleanupPeriod       time.Duration `json:"cleanup_period" env:"CACHE_CLEANUP_PERIOD"`
+	MaxItems            int           `json:"max_items" env:"CACHE_MAX_ITEMS"`
+	MaxStale            time.Duration `json:"max_stale" env:"CACHE_MAX_STALE"`
+	L1TTL               time.Duration `json:"l1_ttl" env:"CACHE_L1_TTL"`
+	NegativeTTL         time.Duration `json:"negative_ttl" env:"CACHE_NEGATIVE_TTL"`
 }
 
 // ScraperConfig contiene la configuración del scraper.
 type ScraperConfig struct {
-	BaseURL         string        `json:"base_url"`
-	Timeout         time.Duration `json:"timeout"`
-	RefreshInterval time.Duration `json:"refresh_interval"`
-	UserAgent       string        `json:"user_agent"`
+	BaseURL         string        `json:"base_url" env:"SCRAPER_BASE_URL"`
+	Timeout         time.Duration `json:"timeout" env:"SCRAPER_TIMEOUT"`
+	RefreshInterval time.Duration `json:"refresh_interval" env:"SCRAPER_REFRESH_INTERVAL"`
+	UserAgent       string        `json:"user_agent" env:"SCRAPER_USER_AGENT"`
+}
+
+// RedisConfig contiene la configuración de conexión a Redis.
+type RedisConfig struct {
+	Addr      string `json:"addr" env:"REDIS_ADDR"`
+	Password  string `json:"password" env:"REDIS_PASSWORD" secret:"true"`
+	DB        int    `json:"db" env:"REDIS_DB"`
+	KeyPrefix string `json:"key_prefix" env:"REDIS_KEY_PREFIX"`
+}
+
+// AuthConfig contiene la configuración de autenticación por claves de API.
+type AuthConfig struct {
+	Enabled         bool     `json:"enabled" env:"AUTH_ENABLED"`
+	KeysFile        string   `json:"keys_file" env:"AUTH_KEYS_FILE"`
+	AnonymousScopes []string `json:"anonymous_scopes" env:"AUTH_ANONYMOUS_SCOPES"`
+}
+
+// RateLimitConfig contiene la configuración de los límites de peticiones por cliente.
+type RateLimitConfig struct {
+	Enabled           bool          `json:"enabled" env:"RATE_LIMIT_ENABLED"`
+	Store             string        `json:"store" env:"RATE_LIMIT_STORE"`
+	ReadRequests      int           `json:"read_requests" env:"RATE_LIMIT_READ_REQUESTS"`
+	ReadPeriod        time.Duration `json:"read_period" env:"RATE_LIMIT_READ_PERIOD"`
+	RefreshRequests   int           `json:"refresh_requests" env:"RATE_LIMIT_REFRESH_REQUESTS"`
+	RefreshPeriod     time.Duration `json:"refresh_period" env:"RATE_LIMIT_REFRESH_PERIOD"`
+	AuthFailures      int           `json:"auth_failures" env:"RATE_LIMIT_AUTH_FAILURES"`
+	AuthFailurePeriod time.Duration `json:"auth_failure_period" env:"RATE_LIMIT_AUTH_FAILURE_PERIOD"`
+	TrustedProxies    []string      `json:"trusted_proxies" env:"RATE_LIMIT_TRUSTED_PROXIES"`
+}
+
+// CORSConfig contiene la política CORS de la API.
+type CORSConfig struct {
+	AllowedOrigins   []string      `json:"allowed_origins" env:"CORS_ALLOWED_ORIGINS"`
+	AllowedMethods   []string      `json:"allowed_methods" env:"CORS_ALLOWED_METHODS"`
+	AllowedHeaders   []string      `json:"allowed_headers" env:"CORS_ALLOWED_HEADERS"`
+	ExposedHeaders   []string      `json:"exposed_headers" env:"CORS_EXPOSED_HEADERS"`
+	AllowCredentials bool          `json:"allow_credentials" env:"CORS_ALLOW_CREDENTIALS"`
+	MaxAge           time.Duration `json:"max_age" env:"CORS_MAX_AGE"`
+}
+
+// LogConfig contiene la configuración del logging estructurado.
+type LogConfig struct {
+	Level  string `json:"level" env:"LOG_LEVEL"`
+	Format string `json:"format" env:"LOG_FORMAT"`
+}
+
+// MetricsConfig contiene la configuración del endpoint de métricas Prometheus.
+type MetricsConfig struct {
+	Path string `json:"path" env:"METRICS_PATH"`
+}
+
+// TracingConfig contiene la configuración de OpenTelemetry. El destino del
+// exportador OTLP se toma de las variables estándar OTEL_EXPORTER_OTLP_*.
+type TracingConfig struct {
+	Exporter    string  `json:"exporter" env:"OTEL_TRACES_EXPORTER"`
+	ServiceName string  `json:"service_name" env:"OTEL_SERVICE_NAME"`
+	SampleRatio float64 `json:"sample_ratio" env:"OTEL_TRACES_SAMPLER_ARG"`
+}
+
+// StreamConfig contiene la configuración del historial de cambios de tasas y del
+// stream de eventos que se reanuda desde él.
+type StreamConfig struct {
+	HistorySize       int           `json:"history_size" env:"RATE_HISTORY_SIZE"`
+	HeartbeatInterval time.Duration `json:"heartbeat_interval" env:"STREAM_HEARTBEAT_INTERVAL"`
+	BufferSize        int           `json:"buffer_size" env:"STREAM_BUFFER_SIZE"`
+}
+
+// WebhooksConfig contiene la configuración de los webhooks de cambios de tasas.
+// Sin StoreFile los webhooks se guardan solo en memoria.
+type WebhooksConfig struct {
+	StoreFile       string        `json:"store_file" env:"WEBHOOKS_FILE"`
+	Timeout         time.Duration `json:"timeout" env:"WEBHOOK_TIMEOUT"`
+	MaxAttempts     int           `json:"max_attempts" env:"WEBHOOK_MAX_ATTEMPTS"`
+	RetryBackoff    time.Duration `json:"retry_backoff" env:"WEBHOOK_RETRY_BACKOFF"`
+	MaxBackoff      time.Duration `json:"max_backoff" env:"WEBHOOK_MAX_BACKOFF"`
+	DisableAfter    int           `json:"disable_after" env:"WEBHOOK_DISABLE_AFTER"`
+	DeliveryLogSize int           `json:"delivery_log_size" env:"WEBHOOK_DELIVERY_LOG_SIZE"`
+	// AllowPrivateNetworks permite destinos en loopback y redes privadas o de enlace local
+	AllowPrivateNetworks bool `json:"allow_private_networks" env:"WEBHOOK_ALLOW_PRIVATE_NETWORKS"`
+}
+
+// AlertsConfig contiene la configuración de las reglas de alerta y sus canales.
+// Sin StoreFile las reglas se guardan solo en memoria; los canales webhook y smtp
+// se habilitan al configurar WebhookURL y SMTPAddr respectivamente.
+type AlertsConfig struct {
+	StoreFile       string        `json:"store_file" env:"ALERTS_FILE"`
+	DefaultCooldown time.Duration `json:"default_cooldown" env:"ALERT_DEFAULT_COOLDOWN"`
+	HistorySize     int           `json:"history_size" env:"ALERT_HISTORY_SIZE"`
+	WebhookURL      string        `json:"webhook_url" env:"ALERT_WEBHOOK_URL"`
+	WebhookSecret   string        `json:"webhook_secret" env:"ALERT_WEBHOOK_SECRET" secret:"true"`
+	SMTPAddr        string        `json:"smtp_addr" env:"ALERT_SMTP_ADDR"`
+	SMTPUsername    string        `json:"smtp_username" env:"ALERT_SMTP_USERNAME"`
+	SMTPPassword    string        `json:"smtp_password" env:"ALERT_SMTP_PASSWORD" secret:"true"`
+	SMTPFrom        string        `json:"smtp_from" env:"ALERT_SMTP_FROM"`
+	SMTPTo          []string      `json:"smtp_to" env:"ALERT_SMTP_TO"`
 }
 
-// DatabaseConfig contiene la configuración de la base de datos (para futuras extensiones).
+// DatabaseConfig contiene la configuración de la base de datos. Type elige dónde se
+// guardan las monedas y el historial (memory o sqlite, en el archivo Path); los datos
+// de conexión a Postgres los usa el bus de invalidación.
 type DatabaseConfig struct {
-	Type     string `json:"type"`
-	Host     string `json:"host"`
-	Port     string `json:"port"`
-	Database string `json:"database"`
-	Username string `json:"username"`
-	Password string `json:"password"`
+	Type     string `json:"type" env:"DB_TYPE"`
+	Path     string `json:"path" env:"DB_PATH"`
+	Host     string `json:"host" env:"DB_HOST"`
+	Port     string `json:"port" env:"DB_PORT"`
+	Database string `json:"database" env:"DB_NAME"`
+	Username string `json:"username" env:"DB_USER"`
+	Password string `json:"password" env:"DB_PASSWORD" secret:"true"`
+	SSLMode  string `json:"ssl_mode" env:"DB_SSLMODE"`
+}
+
+// PostgresDSN construye la cadena de conexión a Postgres a partir de la configuración.
+func (c DatabaseConfig) PostgresDSN() string {
+	dsn := url.URL{
+		Scheme:   "postgres",
+		Host:     net.JoinHostPort(c.Host, c.Port),
+		Path:     "/" + c.Database,
+		RawQuery: url.Values{"sslmode": {c.SSLMode}}.Encode(),
+	}
+	if c.Username != "" {
+		dsn.User = url.UserPassword(c.Username, c.Password)
+	}
+	return dsn.String()
 }
 
-// LoadConfig carga la configuración desde variables de entorno con valores por defecto.
-func LoadConfig() *Config {
+// Default retorna la configuración por defecto, sin considerar archivos ni variables de entorno.
+func Default() *Config {
 	return &Config{
 		Server: ServerConfig{
-			Port:         getEnvOrDefault("SERVER_PORT", "8080"),
-			Host:         getEnvOrDefault("SERVER_HOST", "0.0.0.0"),
-			ReadTimeout:  getDurationEnvOrDefault("SERVER_READ_TIMEOUT", 15*time.Second),
-			WriteTimeout: getDurationEnvOrDefault("SERVER_WRITE_TIMEOUT", 15*time.Second),
-			IdleTimeout:  getDurationEnvOrDefault("SERVER_IDLE_TIMEOUT", 60*time.Second),
+			ReplicaID:    defaultReplicaID(),
+			Port:         "8080",
+			Host:         "0.0.0.0",
+			ReadTimeout:  15 * time.Second,
+			WriteTimeout: 15 * time.Second,
+			IdleTimeout:  60 * time.Second,
+		},
+		GRPC: GRPCConfig{
+			Enabled: true,
+			Port:    "9090",
+		},
+		GraphQL: GraphQLConfig{
+			MaxDepth:      8,
+			MaxComplexity: 10000,
 		},
 		Cache: CacheConfig{
-			DefaultTTL:    getDurationEnvOrDefault("CACHE_DEFAULT_TTL", 5*time.Minute),
-			CleanupPeriod: getDurationEnvOrDefault("CACHE_CLEANUP_PERIOD", 5*time.Minute),
-			MaxItems:      getIntEnvOrDefault("CACHE_MAX_ITEMS", 1000),
+			Type:                "memory",
+			InvalidationBus:     "memory",
+			InvalidationChannel: "gobcv_invalidation",
+			DefaultTTL:          5 * time.Minute,
+			CleanupPeriod:       5 * time.Minute,
+			MaxItems:            1000,
+			MaxStale:            24 * time.Hour,
+			L1TTL:               10 * time.Second,
+			NegativeTTL:         5 * time.Second,
 		},
 		Scraper: ScraperConfig{
-			BaseURL:         getEnvOrDefault("SCRAPER_BASE_URL", "https://www.bcv.org.ve/"),
-			Timeout:         getDurationEnvOrDefault("SCRAPER_TIMEOUT", 30*time.Second),
-			RefreshInterval: getDurationEnvOrDefault("SCRAPER_REFRESH_INTERVAL", 15*time.Minute),
-			UserAgent:       getEnvOrDefault("SCRAPER_USER_AGENT", "BCV-Currency-API/1.0"),
+			BaseURL:         "https://www.bcv.org.ve/",
+			Timeout:         30 * time.Second,
+			RefreshInterval: 15 * time.Minute,
+			UserAgent:       "BCV-Currency-API/1.0",
 		},
 		Database: DatabaseConfig{
-			Type:     getEnvOrDefault("DB_TYPE", "memory"),
-			Host:     getEnvOrDefault("DB_HOST", "localhost"),
-			Port:     getEnvOrDefault("DB_PORT", "5432"),
-			Database: getEnvOrDefault("DB_NAME", "currencies"),
-			Username: getEnvOrDefault("DB_USER", ""),
-			Password: getEnvOrDefault("DB_PASSWORD", ""),
+			Type:     "memory",
+			Path:     "gobcv.db",
+			Host:     "localhost",
+			Port:     "5432",
+			Database: "currencies",
+			SSLMode:  "disable",
+		},
+		Redis: RedisConfig{
+			Addr:      "localhost:6379",
+			KeyPrefix: "gobcv:",
+		},
+		Auth: AuthConfig{
+			AnonymousScopes: []string{"read"},
+		},
+		RateLimit: RateLimitConfig{
+			Store:             "memory",
+			ReadRequests:      120,
+			ReadPeriod:        time.Minute,
+			RefreshRequests:   5,
+			RefreshPeriod:     time.Minute,
+			AuthFailures:      10,
+			AuthFailurePeriod: 15 * time.Minute,
+		},
+		CORS: CORSConfig{
+			AllowedOrigins: []string{"*"},
+			AllowedHeaders: []string{
+				"Content-Type", "Authorization", "Accept", "If-None-Match", "If-Modified-Since", "X-API-Key", "X-Request-ID",
+			},
+			ExposedHeaders: []string{
+				"ETag", "Last-Modified", "X-Request-ID", "RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "RateLimit-Policy", "Retry-After",
+			},
+			MaxAge: 10 * time.Minute,
+		},
+		Log: LogConfig{
+			Level:  "info",
+			Format: "text",
+		},
+		Metrics: MetricsConfig{
+			Path: "/metrics",
+		},
+		Tracing: TracingConfig{
+			Exporter:    "none",
+			ServiceName: "gobcv",
+			SampleRatio: 1.0,
+		},
+		Stream: StreamConfig{
+			HistorySize:       10000,
+			HeartbeatInterval: 15 * time.Second,
+			BufferSize:        32,
+		},
+		Webhooks: WebhooksConfig{
+			Timeout:         10 * time.Second,
+			MaxAttempts:     5,
+			RetryBackoff:    2 * time.Second,
+			MaxBackoff:      time.Minute,
+			DisableAfter:    10,
+			DeliveryLogSize: 100,
+		},
+		Alerts: AlertsConfig{
+			DefaultCooldown: time.Hour,
+			HistorySize:     100,
+			SMTPFrom:        "gobcv@localhost",
+			SMTPTo:          []string{},
 		},
 	}
 }
 
-// getEnvOrDefault obtiene una variable de entorno o retorna un valor por defecto.
-func getEnvOrDefault(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
-	}
-	return defaultValue
-}
-
-// getDurationEnvOrDefault obtiene una duración desde una variable de entorno o retorna un valor por defecto.
-func getDurationEnvOrDefault(key string, defaultValue time.Duration) time.Duration {
-	if value := os.Getenv(key); value != "" {
-		if duration, err := time.ParseDuration(value); err == nil {
-			return duration
-		}
-	}
-	return defaultValue
-}
-
-// getIntEnvOrDefault obtiene un entero desde una variable de entorno o retorna un valor por defecto.
-func getIntEnvOrDefault(key string, defaultValue int) int {
-	if value := os.Getenv(key); value != "" {
-		if intValue, err := strconv.Atoi(value); err == nil {
-			return intValue
-		}
+// defaultReplicaID genera un identificador de réplica a partir del hostname y el PID.
+func defaultReplicaID() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "localhost"
 	}
-	return defaultValue
+	return fmt.Sprintf("%s-%d", hostname, os.Getpid())
 }
@@ -0,0 +1,493 @@
+// Package config carga la configuración desde archivos, variables de entorno y opciones.
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Formatos de archivo de configuración soportados por Load y Marshal. FormatEnv solo
+// se admite en Marshal.
+const (
+	FormatYAML = "yaml"
+	FormatJSON = "json"
+	FormatTOML = "toml"
+	FormatEnv  = "env"
+)
+
+// redacted reemplaza los valores secretos en Marshal.
+const redacted = "********"
+
+// Options indica de dónde se carga la configuración además de los valores por defecto.
+type Options struct {
+	// File es un archivo YAML, JSON o TOML, según su extensión. Vacío: solo variables de entorno.
+	File string
+	// Overrides son asignaciones clave=valor (server.port=9000) con prioridad sobre el
+	// archivo y las variables de entorno.
+	Overrides []string
+}
+
+// RegisterFlags registra las opciones -config, que toma por defecto CONFIG_FILE, y -set,
+// que puede repetirse.
+func (o *Options) RegisterFlags(fs *flag.FlagSet) {
+	fs.StringVar(&o.File, "config", os.Getenv("CONFIG_FILE"), "archivo de configuración YAML, JSON o TOML")
+	fs.Func("set", "asigna una clave de configuración, por ejemplo server.port=9000 (puede repetirse)", func(value string) error {
+		o.Overrides = append(o.Overrides, value)
+		return nil
+	})
+}
+
+// Load carga la configuración en orden de prioridad creciente: valores por defecto,
+// archivo, variables de entorno y Overrides. Las variables vacías se ignoran. Si algún
+// valor no se puede interpretar o la configuración resultante no es válida, retorna un
+// *ValidationError con todos los problemas encontrados.
+func Load(opts Options) (*Config, error) {
+	cfg := Default()
+	fields := cfg.fields()
+
+	byKey := make(map[string]field, len(fields))
+	for _, f := range fields {
+		byKey[f.key] = f
+	}
+
+	var problems []string
+
+	if opts.File != "" {
+		values, err := readFile(opts.File)
+		if err != nil {
+			return nil, err
+		}
+		problems = append(problems, applyFile(byKey, opts.File, values)...)
+	}
+
+	for _, f := range fields {
+		if value := os.Getenv(f.env); value != "" {
+			if err := f.set(value); err != nil {
+				problems = append(problems, fmt.Sprintf("%s: %v", f.env, err))
+			}
+		}
+	}
+
+	for _, override := range opts.Overrides {
+		key, value, ok := strings.Cut(override, "=")
+		if !ok {
+			problems = append(problems, fmt.Sprintf("-set %s: expected key=value", override))
+			continue
+		}
+
+		f, known := byKey[strings.TrimSpace(key)]
+		if !known {
+			problems = append(problems, fmt.Sprintf("-set %s: unknown key", key))
+			continue
+		}
+		if err := f.set(value); err != nil {
+			problems = append(problems, fmt.Sprintf("-set %s: %v", key, err))
+		}
+	}
+
+	problems = append(problems, cfg.validate()...)
+	if len(problems) > 0 {
+		return nil, &ValidationError{Problems: problems}
+	}
+
+	return cfg, nil
+}
+
+// Marshal escribe la configuración en el formato indicado, con las secciones y claves
+// en el orden de Config. Los valores secretos no vacíos se reemplazan por asteriscos.
+func (c *Config) Marshal(format string) ([]byte, error) {
+	sections := c.sections()
+
+	switch format {
+	case FormatYAML:
+		return marshalYAML(sections)
+	case FormatJSON:
+		return marshalJSON(sections)
+	case FormatTOML:
+		return marshalTOML(sections)
+	case FormatEnv:
+		var buf bytes.Buffer
+		for _, s := range sections {
+			for _, f := range s.fields {
+				value := f.display()
+				if list, ok := value.([]string); ok {
+					value = strings.Join(list, ",")
+				}
+				fmt.Fprintf(&buf, "%s=%v\n", f.env, value)
+			}
+		}
+		return buf.Bytes(), nil
+	default:
+		return nil, fmt.Errorf("unknown config format %q: must be %s, %s, %s or %s", format, FormatYAML, FormatJSON, FormatTOML, FormatEnv)
+	}
+}
+
+// field es un valor configurable con su clave en los archivos (sección.clave) y su
+// variable de entorno.
+type field struct {
+	key    string
+	name   string
+	env    string
+	secret bool
+	value  reflect.Value
+}
+
+// section agrupa los campos de una sección del archivo.
+type section struct {
+	name   string
+	fields []field
+}
+
+// sections recorre Config por reflexión usando las etiquetas json y env.
+func (c *Config) sections() []section {
+	root := reflect.ValueOf(c).Elem()
+
+	var sections []section
+	for i := 0; i < root.NumField(); i++ {
+		s := section{name: root.Type().Field(i).Tag.Get("json")}
+
+		group := root.Field(i)
+		for j := 0; j < group.NumField(); j++ {
+			tag := group.Type().Field(j)
+			name := tag.Tag.Get("json")
+			s.fields = append(s.fields, field{
+				key:    s.name + "." + name,
+				name:   name,
+				env:    tag.Tag.Get("env"),
+				secret: tag.Tag.Get("secret") == "true",
+				value:  group.Field(j),
+			})
+		}
+		sections = append(sections, s)
+	}
+	return sections
+}
+
+// fields retorna todos los campos configurables.
+func (c *Config) fields() []field {
+	var fields []field
+	for _, s := range c.sections() {
+		fields = append(fields, s.fields...)
+	}
+	return fields
+}
+
+// set asigna al campo un valor de un archivo o, como texto, de una variable de entorno
+// o de -set. Las duraciones deben indicar su unidad (30s, 5m).
+func (f field) set(raw interface{}) error {
+	switch target := f.value.Addr().Interface().(type) {
+	case *string:
+		value, err := scalarString(raw)
+		if err != nil {
+			return err
+		}
+		*target = value
+	case *time.Duration:
+		text, ok := raw.(string)
+		if !ok {
+			return fmt.Errorf("expected a duration with unit such as 30s, got %v", raw)
+		}
+		value, err := time.ParseDuration(strings.TrimSpace(text))
+		if err != nil {
+			return fmt.Errorf("invalid duration %q: use a unit such as 30s or 5m", text)
+		}
+		*target = value
+	case *int:
+		value, err := parseInt(raw)
+		if err != nil {
+			return err
+		}
+		*target = value
+	case *float64:
+		value, err := parseFloat(raw)
+		if err != nil {
+			return err
+		}
+		*target = value
+	case *bool:
+		switch value := raw.(type) {
+		case bool:
+			*target = value
+		case string:
+			parsed, err := strconv.ParseBool(strings.TrimSpace(value))
+			if err != nil {
+				return fmt.Errorf("invalid boolean %q", value)
+			}
+			*target = parsed
+		default:
+			return fmt.Errorf("expected a boolean, got %v", raw)
+		}
+	case *[]string:
+		value, err := parseList(raw)
+		if err != nil {
+			return err
+		}
+		*target = value
+	default:
+		return fmt.Errorf("unsupported field type %s", f.value.Type())
+	}
+	return nil
+}
+
+// display retorna el valor del campo para Marshal.
+func (f field) display() interface{} {
+	switch value := f.value.Interface().(type) {
+	case time.Duration:
+		return value.String()
+	case []string:
+		if value == nil {
+			return []string{}
+		}
+		return value
+	case string:
+		if f.secret && value != "" {
+			return redacted
+		}
+		return value
+	default:
+		return value
+	}
+}
+
+// readFile decodifica el archivo de configuración según su extensión.
+func readFile(path string) (map[string]interface{}, error) {
+	ext := strings.ToLower(filepath.Ext(path))
+	switch ext {
+	case ".yaml", ".yml", ".json", ".toml":
+	default:
+		return nil, fmt.Errorf("unsupported config file extension %q: use .yaml, .yml, .json or .toml", ext)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading config file: %w", err)
+	}
+
+	values := map[string]interface{}{}
+	switch ext {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &values)
+	case ".json":
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+		err = decoder.Decode(&values)
+	case ".toml":
+		err = toml.Unmarshal(data, &values)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error parsing config file %s: %w", path, err)
+	}
+
+	return values, nil
+}
+
+// applyFile asigna los valores del archivo y retorna los problemas encontrados,
+// incluidas las claves desconocidas.
+func applyFile(byKey map[string]field, path string, values map[string]interface{}) []string {
+	var problems []string
+
+	for sectionName, sectionValue := range values {
+		entries, ok := sectionValue.(map[string]interface{})
+		if !ok {
+			problems = append(problems, fmt.Sprintf("%s: %s: expected a section with keys", path, sectionName))
+			continue
+		}
+
+		for name, raw := range entries {
+			key := sectionName + "." + name
+			f, known := byKey[key]
+			if !known {
+				problems = append(problems, fmt.Sprintf("%s: %s: unknown key", path, key))
+				continue
+			}
+			if err := f.set(raw); err != nil {
+				problems = append(problems, fmt.Sprintf("%s: %s: %v", path, key, err))
+			}
+		}
+	}
+
+	// Los mapas no tienen orden: ordenar para que los mensajes sean reproducibles
+	sort.Strings(problems)
+	return problems
+}
+
+// scalarString convierte a texto un valor escalar; los números se admiten para claves
+// como los puertos.
+func scalarString(raw interface{}) (string, error) {
+	switch value := raw.(type) {
+	case string:
+		return value, nil
+	case int, int64, uint64, float64, json.Number, bool:
+		return fmt.Sprint(value), nil
+	default:
+		return "", fmt.Errorf("expected a string, got %v", raw)
+	}
+}
+
+// parseInt interpreta un entero de un archivo o de texto.
+func parseInt(raw interface{}) (int, error) {
+	switch value := raw.(type) {
+	case int:
+		return value, nil
+	case int64:
+		return int(value), nil
+	case uint64:
+		return int(value), nil
+	case float64:
+		if value != math.Trunc(value) {
+			return 0, fmt.Errorf("expected an integer, got %v", value)
+		}
+		return int(value), nil
+	case json.Number:
+		return parseInt(value.String())
+	case string:
+		parsed, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil {
+			return 0, fmt.Errorf("invalid integer %q", value)
+		}
+		return parsed, nil
+	default:
+		return 0, fmt.Errorf("expected an integer, got %v", raw)
+	}
+}
+
+// parseFloat interpreta un número de un archivo o de texto.
+func parseFloat(raw interface{}) (float64, error) {
+	switch value := raw.(type) {
+	case int:
+		return float64(value), nil
+	case int64:
+		return float64(value), nil
+	case uint64:
+		return float64(value), nil
+	case float64:
+		return value, nil
+	case json.Number:
+		return parseFloat(value.String())
+	case string:
+		parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid number %q", value)
+		}
+		return parsed, nil
+	default:
+		return 0, fmt.Errorf("expected a number, got %v", raw)
+	}
+}
+
+// parseList interpreta una lista de un archivo o un texto separado por comas. El texto
+// "none" produce una lista vacía.
+func parseList(raw interface{}) ([]string, error) {
+	switch value := raw.(type) {
+	case []interface{}:
+		list := make([]string, 0, len(value))
+		for _, item := range value {
+			text, err := scalarString(item)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, text)
+		}
+		return list, nil
+	case string:
+		list := []string{}
+		if value == "none" {
+			return list, nil
+		}
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+		return list, nil
+	default:
+		return nil, fmt.Errorf("expected a list, got %v", raw)
+	}
+}
+
+// marshalYAML escribe las secciones como un documento YAML.
+func marshalYAML(sections []section) ([]byte, error) {
+	root := &yaml.Node{Kind: yaml.MappingNode}
+	for _, s := range sections {
+		entries := &yaml.Node{Kind: yaml.MappingNode}
+		for _, f := range s.fields {
+			var value yaml.Node
+			if err := value.Encode(f.display()); err != nil {
+				return nil, err
+			}
+			entries.Content = append(entries.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: f.name}, &value)
+		}
+		root.Content = append(root.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: s.name}, entries)
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(root); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// marshalJSON escribe las secciones como un objeto JSON indentado.
+func marshalJSON(sections []section) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("{\n")
+	for i, s := range sections {
+		fmt.Fprintf(&buf, "  %q: {\n", s.name)
+		for j, f := range s.fields {
+			value, err := json.Marshal(f.display())
+			if err != nil {
+				return nil, err
+			}
+			fmt.Fprintf(&buf, "    %q: %s%s\n", f.name, value, separator(j, len(s.fields)))
+		}
+		fmt.Fprintf(&buf, "  }%s\n", separator(i, len(sections)))
+	}
+	buf.WriteString("}\n")
+	return buf.Bytes(), nil
+}
+
+// marshalTOML escribe las secciones como tablas TOML. Los valores se codifican como
+// JSON, cuyas cadenas, números, booleanos y listas son también TOML válido.
+func marshalTOML(sections []section) ([]byte, error) {
+	var buf bytes.Buffer
+	for i, s := range sections {
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+		fmt.Fprintf(&buf, "[%s]\n", s.name)
+		for _, f := range s.fields {
+			value, err := json.Marshal(f.display())
+			if err != nil {
+				return nil, err
+			}
+			fmt.Fprintf(&buf, "%s = %s\n", f.name, value)
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// separator retorna la coma entre elementos JSON, salvo tras el último.
+func separator(i, n int) string {
+	if i < n-1 {
+		return ","
+	}
+	return ""
+}
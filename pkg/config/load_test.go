@@ -0,0 +1,299 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// clearEnv vacía las variables de entorno de la configuración durante el test; Load
+// ignora las variables vacías.
+func clearEnv(t *testing.T) {
+	t.Helper()
+
+	for _, f := range Default().fields() {
+		t.Setenv(f.env, "")
+	}
+}
+
+// writeConfig escribe un archivo de configuración en un directorio temporal.
+func writeConfig(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// loadProblems carga la configuración y retorna los problemas del *ValidationError.
+func loadProblems(t *testing.T, opts Options) []string {
+	t.Helper()
+
+	_, err := Load(opts)
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Load error = %v, want a *ValidationError", err)
+	}
+	return validationErr.Problems
+}
+
+// hasProblem verifica si algún problema contiene el texto indicado.
+func hasProblem(problems []string, text string) bool {
+	for _, problem := range problems {
+		if strings.Contains(problem, text) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestLoadFileFormats(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"config.yaml", `
+server:
+  port: 9000
+  read_timeout: 45s
+grpc:
+  enabled: true
+graphql:
+  max_depth: 5
+cors:
+  allowed_origins: [https://a.example, https://b.example]
+tracing:
+  sample_ratio: 0.5
+`},
+		{"config.json", `{
+  "server": {"port": 9000, "read_timeout": "45s"},
+  "grpc": {"enabled": true},
+  "graphql": {"max_depth": 5},
+  "cors": {"allowed_origins": ["https://a.example", "https://b.example"]},
+  "tracing": {"sample_ratio": 0.5}
+}`},
+		{"config.toml", `
+[server]
+port = 9000
+read_timeout = "45s"
+
+[grpc]
+enabled = true
+
+[graphql]
+max_depth = 5
+
+[cors]
+allowed_origins = ["https://a.example", "https://b.example"]
+
+[tracing]
+sample_ratio = 0.5
+`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv(t)
+
+			cfg, err := Load(Options{File: writeConfig(t, tt.name, tt.content)})
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+
+			if cfg.Server.Port != "9000" || cfg.Server.ReadTimeout != 45*time.Second {
+				t.Errorf("server = %+v", cfg.Server)
+			}
+			if !cfg.GRPC.Enabled || cfg.GraphQL.MaxDepth != 5 || cfg.Tracing.SampleRatio != 0.5 {
+				t.Errorf("grpc.enabled = %v, graphql.max_depth = %d, tracing.sample_ratio = %v",
+					cfg.GRPC.Enabled, cfg.GraphQL.MaxDepth, cfg.Tracing.SampleRatio)
+			}
+			if want := []string{"https://a.example", "https://b.example"}; !reflect.DeepEqual(cfg.CORS.AllowedOrigins, want) {
+				t.Errorf("cors.allowed_origins = %v, want %v", cfg.CORS.AllowedOrigins, want)
+			}
+
+			// Las claves ausentes conservan el valor por defecto
+			if cfg.Scraper.Timeout != Default().Scraper.Timeout {
+				t.Errorf("scraper.timeout = %s, want the default", cfg.Scraper.Timeout)
+			}
+		})
+	}
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	file := writeConfig(t, "config.yaml", "server:\n  port: 9000\nscraper:\n  timeout: 45s\n")
+
+	tests := []struct {
+		name      string
+		env       string
+		overrides []string
+		port      string
+	}{
+		{"file over defaults", "", nil, "9000"},
+		{"env over file", "9100", nil, "9100"},
+		{"set over env", "9100", []string{"server.port=9200"}, "9200"},
+		{"last set wins", "", []string{"server.port=9200", "server.port=9300"}, "9300"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv(t)
+			t.Setenv("SERVER_PORT", tt.env)
+
+			cfg, err := Load(Options{File: file, Overrides: tt.overrides})
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			if cfg.Server.Port != tt.port {
+				t.Errorf("server.port = %s, want %s", cfg.Server.Port, tt.port)
+			}
+			// Las claves que solo indica el archivo no se pierden
+			if cfg.Scraper.Timeout != 45*time.Second {
+				t.Errorf("scraper.timeout = %s, want 45s from the file", cfg.Scraper.Timeout)
+			}
+		})
+	}
+}
+
+func TestLoadRejectsInvalidValues(t *testing.T) {
+	tests := []struct {
+		name      string
+		file      string
+		content   string
+		env       map[string]string
+		overrides []string
+		problem   string
+	}{
+		{name: "unknown file key", file: "config.yaml", content: "server:\n  prot: 9000\n", problem: "server.prot: unknown key"},
+		{name: "unknown file section", file: "config.toml", content: "[servr]\nport = 9000\n", problem: "servr.port: unknown key"},
+		{name: "key outside a section", file: "config.json", content: `{"port": 9000}`, problem: "port: expected a section with keys"},
+		{name: "unitless env duration", env: map[string]string{"SCRAPER_TIMEOUT": "30"}, problem: `SCRAPER_TIMEOUT: invalid duration "30"`},
+		{name: "unitless file duration", file: "config.toml", content: "[scraper]\ntimeout = 30\n", problem: "scraper.timeout: expected a duration with unit"},
+		{name: "invalid env boolean", env: map[string]string{"GRPC_ENABLED": "si"}, problem: `GRPC_ENABLED: invalid boolean "si"`},
+		{name: "fractional integer", file: "config.yaml", content: "graphql:\n  max_depth: 2.5\n", problem: "expected an integer, got 2.5"},
+		{name: "unknown set key", overrides: []string{"server.prot=9000"}, problem: "-set server.prot: unknown key"},
+		{name: "set without value", overrides: []string{"server.port"}, problem: "-set server.port: expected key=value"},
+		{name: "invalid value", overrides: []string{"cache.type=disk"}, problem: `cache.type (CACHE_TYPE): must be one of memory, redis, tiered, got "disk"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv(t)
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			opts := Options{Overrides: tt.overrides}
+			if tt.file != "" {
+				opts.File = writeConfig(t, tt.file, tt.content)
+			}
+
+			if problems := loadProblems(t, opts); !hasProblem(problems, tt.problem) {
+				t.Errorf("problems = %q, want %q", problems, tt.problem)
+			}
+		})
+	}
+}
+
+func TestLoadReturnsAllProblemsTogether(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("SCRAPER_TIMEOUT", "30")
+
+	problems := loadProblems(t, Options{
+		File:      writeConfig(t, "config.yaml", "server:\n  prot: 9000\n"),
+		Overrides: []string{"server.port=0", "log.level=loud"},
+	})
+
+	for _, want := range []string{
+		"server.prot: unknown key",
+		"SCRAPER_TIMEOUT: invalid duration",
+		"server.port (SERVER_PORT): must be a port number",
+		"log.level (LOG_LEVEL): must be one of",
+	} {
+		if !hasProblem(problems, want) {
+			t.Errorf("missing %q in %q", want, problems)
+		}
+	}
+}
+
+func TestLoadRejectsUnsupportedFiles(t *testing.T) {
+	clearEnv(t)
+
+	if _, err := Load(Options{File: writeConfig(t, "config.ini", "port=9000")}); err == nil || !strings.Contains(err.Error(), "unsupported config file extension") {
+		t.Errorf("Load(.ini) = %v", err)
+	}
+	if _, err := Load(Options{File: writeConfig(t, "config.json", "{")}); err == nil || !strings.Contains(err.Error(), "error parsing config file") {
+		t.Errorf("Load(invalid json) = %v", err)
+	}
+	if _, err := Load(Options{File: filepath.Join(t.TempDir(), "missing.yaml")}); err == nil || !strings.Contains(err.Error(), "error reading config file") {
+		t.Errorf("Load(missing file) = %v", err)
+	}
+}
+
+func TestMarshalRedactsSecrets(t *testing.T) {
+	cfg := Default()
+	cfg.Redis.Password = "redis-secret"
+	cfg.Database.Password = "db-secret"
+	cfg.Alerts.SMTPPassword = "smtp-secret"
+
+	for _, format := range []string{FormatYAML, FormatJSON, FormatTOML, FormatEnv} {
+		t.Run(format, func(t *testing.T) {
+			output, err := cfg.Marshal(format)
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+
+			text := string(output)
+			for _, secret := range []string{"redis-secret", "db-secret", "smtp-secret"} {
+				if strings.Contains(text, secret) {
+					t.Errorf("output contains %q:\n%s", secret, text)
+				}
+			}
+			if strings.Count(text, redacted) != 3 {
+				t.Errorf("got %d redacted values, want 3; empty secrets stay empty", strings.Count(text, redacted))
+			}
+		})
+	}
+}
+
+func TestMarshalRoundTrip(t *testing.T) {
+	clearEnv(t)
+
+	cfg := Default()
+	cfg.Server.Port = "9000"
+	cfg.Scraper.Timeout = 45 * time.Second
+	cfg.CORS.AllowedOrigins = []string{"https://a.example"}
+	cfg.Tracing.SampleRatio = 0.25
+
+	want, err := cfg.Marshal(FormatYAML)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	// Lo que escribe Marshal se puede cargar de nuevo sin cambios
+	for _, format := range []string{FormatYAML, FormatJSON, FormatTOML} {
+		t.Run(format, func(t *testing.T) {
+			output, err := cfg.Marshal(format)
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+
+			loaded, err := Load(Options{File: writeConfig(t, "config."+format, string(output))})
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			got, err := loaded.Marshal(FormatYAML)
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+			if string(got) != string(want) {
+				t.Errorf("round trip through %s changed the configuration:\n%s\nwant:\n%s", format, got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,7 @@
+//go:build cgo
+
+// Package config indica si el binario admite SQLite.
+package config
+
+// sqliteSupported indica si el driver de SQLite, que usa cgo, está disponible.
+const sqliteSupported = true
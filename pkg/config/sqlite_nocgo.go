@@ -0,0 +1,8 @@
+//go:build !cgo
+
+// Package config indica si el binario admite SQLite.
+package config
+
+// sqliteSupported indica si el driver de SQLite, que usa cgo, está disponible. Los
+// binarios compilados con CGO_ENABLED=0 fallarían al abrir la base.
+const sqliteSupported = false
@@ -0,0 +1,214 @@
+// Package config valida la configuración de la aplicación.
+package config
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ValidationError agrupa todos los problemas encontrados al cargar o validar la configuración.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid configuration:\n  - " + strings.Join(e.Problems, "\n  - ")
+}
+
+// Validate verifica la configuración y retorna un *ValidationError con todos los
+// problemas encontrados, o nil si es válida.
+func (c *Config) Validate() error {
+	if problems := c.validate(); len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// validate retorna los problemas de la configuración.
+func (c *Config) validate() []string {
+	v := validator{envs: make(map[string]string)}
+	for _, f := range c.fields() {
+		v.envs[f.key] = f.env
+	}
+
+	v.required("server.replica_id", c.Server.ReplicaID)
+	v.port("server.port", c.Server.Port)
+	v.positive("server.read_timeout", c.Server.ReadTimeout)
+	v.positive("server.write_timeout", c.Server.WriteTimeout)
+	v.positive("server.idle_timeout", c.Server.IdleTimeout)
+
+	if c.GRPC.Enabled {
+		v.port("grpc.port", c.GRPC.Port)
+		if c.GRPC.Port == c.Server.Port {
+			v.addf("grpc.port", "must differ from server.port")
+		}
+	}
+
+	v.atLeast("graphql.max_depth", c.GraphQL.MaxDepth, 0)
+	v.atLeast("graphql.max_complexity", c.GraphQL.MaxComplexity, 0)
+
+	v.oneOf("cache.type", c.Cache.Type, "memory", "redis", "tiered")
+	v.oneOf("cache.invalidation_bus", c.Cache.InvalidationBus, "memory", "redis", "postgres")
+	v.required("cache.invalidation_channel", c.Cache.InvalidationChannel)
+	v.positive("cache.default_ttl", c.Cache.DefaultTTL)
+	v.positive("cache.cleanup_period", c.Cache.CleanupPeriod)
+	v.atLeast("cache.max_items", c.Cache.MaxItems, 0)
+	v.nonNegative("cache.max_stale", c.Cache.MaxStale)
+	v.positive("cache.l1_ttl", c.Cache.L1TTL)
+	v.nonNegative("cache.negative_ttl", c.Cache.NegativeTTL)
+
+	v.httpURL("scraper.base_url", c.Scraper.BaseURL, true)
+	v.positive("scraper.timeout", c.Scraper.Timeout)
+	v.positive("scraper.refresh_interval", c.Scraper.RefreshInterval)
+
+	v.oneOf("database.type", c.Database.Type, "memory", "sqlite")
+	if c.Database.Type == "sqlite" {
+		v.required("database.path", c.Database.Path)
+		if !sqliteSupported {
+			v.addf("database.type", "sqlite requires a binary built with CGO_ENABLED=1")
+		}
+	}
+	v.port("database.port", c.Database.Port)
+	v.oneOf("database.ssl_mode", c.Database.SSLMode, "disable", "allow", "prefer", "require", "verify-ca", "verify-full")
+
+	if c.Cache.Type != "memory" || c.Cache.InvalidationBus == "redis" || c.RateLimit.Store == "redis" {
+		v.hostPort("redis.addr", c.Redis.Addr)
+	}
+	v.atLeast("redis.db", c.Redis.DB, 0)
+
+	v.oneOf("rate_limit.store", c.RateLimit.Store, "memory", "redis")
+	v.atLeast("rate_limit.read_requests", c.RateLimit.ReadRequests, 1)
+	v.positive("rate_limit.read_period", c.RateLimit.ReadPeriod)
+	v.atLeast("rate_limit.refresh_requests", c.RateLimit.RefreshRequests, 1)
+	v.positive("rate_limit.refresh_period", c.RateLimit.RefreshPeriod)
+	v.atLeast("rate_limit.auth_failures", c.RateLimit.AuthFailures, 1)
+	v.positive("rate_limit.auth_failure_period", c.RateLimit.AuthFailurePeriod)
+
+	v.nonNegative("cors.max_age", c.CORS.MaxAge)
+	if c.CORS.AllowCredentials {
+		for _, origin := range c.CORS.AllowedOrigins {
+			if strings.TrimSpace(origin) == "*" {
+				v.addf("cors.allowed_origins", "must list explicit origins when cors.allow_credentials is enabled, got %q", origin)
+			}
+		}
+	}
+
+	v.oneOf("log.level", strings.ToLower(c.Log.Level), "debug", "info", "warn", "error")
+	v.oneOf("log.format", strings.ToLower(c.Log.Format), "text", "json")
+
+	if !strings.HasPrefix(c.Metrics.Path, "/") {
+		v.addf("metrics.path", "must start with /, got %q", c.Metrics.Path)
+	}
+
+	v.oneOf("tracing.exporter", c.Tracing.Exporter, "none", "otlp")
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		v.addf("tracing.sample_ratio", "must be between 0 and 1, got %v", c.Tracing.SampleRatio)
+	}
+
+	v.atLeast("stream.history_size", c.Stream.HistorySize, 0)
+	v.positive("stream.heartbeat_interval", c.Stream.HeartbeatInterval)
+	v.atLeast("stream.buffer_size", c.Stream.BufferSize, 1)
+
+	v.positive("webhooks.timeout", c.Webhooks.Timeout)
+	v.atLeast("webhooks.max_attempts", c.Webhooks.MaxAttempts, 1)
+	v.positive("webhooks.retry_backoff", c.Webhooks.RetryBackoff)
+	v.positive("webhooks.max_backoff", c.Webhooks.MaxBackoff)
+	if c.Webhooks.MaxBackoff < c.Webhooks.RetryBackoff {
+		v.addf("webhooks.max_backoff", "must not be less than webhooks.retry_backoff (%s)", c.Webhooks.RetryBackoff)
+	}
+	v.atLeast("webhooks.disable_after", c.Webhooks.DisableAfter, 0)
+	v.atLeast("webhooks.delivery_log_size", c.Webhooks.DeliveryLogSize, 1)
+
+	v.nonNegative("alerts.default_cooldown", c.Alerts.DefaultCooldown)
+	v.atLeast("alerts.history_size", c.Alerts.HistorySize, 1)
+	v.httpURL("alerts.webhook_url", c.Alerts.WebhookURL, false)
+	if c.Alerts.SMTPAddr != "" {
+		v.hostPort("alerts.smtp_addr", c.Alerts.SMTPAddr)
+	}
+
+	return v.problems
+}
+
+// validator acumula los problemas de la configuración identificando cada clave con su
+// variable de entorno.
+type validator struct {
+	envs     map[string]string
+	problems []string
+}
+
+// addf registra un problema de la clave indicada.
+func (v *validator) addf(key, format string, args ...interface{}) {
+	v.problems = append(v.problems, fmt.Sprintf("%s (%s): %s", key, v.envs[key], fmt.Sprintf(format, args...)))
+}
+
+// required verifica que el valor no esté vacío.
+func (v *validator) required(key, value string) {
+	if strings.TrimSpace(value) == "" {
+		v.addf(key, "must not be empty")
+	}
+}
+
+// port verifica que el valor sea un puerto TCP entre 1 y 65535.
+func (v *validator) port(key, value string) {
+	if port, err := strconv.Atoi(value); err != nil || port < 1 || port > 65535 {
+		v.addf(key, "must be a port number between 1 and 65535, got %q", value)
+	}
+}
+
+// hostPort verifica que el valor tenga la forma host:puerto.
+func (v *validator) hostPort(key, value string) {
+	_, port, err := net.SplitHostPort(value)
+	if err != nil {
+		v.addf(key, "must be host:port, got %q", value)
+		return
+	}
+	v.port(key, port)
+}
+
+// positive verifica que la duración sea mayor que cero.
+func (v *validator) positive(key string, value time.Duration) {
+	if value <= 0 {
+		v.addf(key, "must be a positive duration, got %s", value)
+	}
+}
+
+// nonNegative verifica que la duración no sea negativa.
+func (v *validator) nonNegative(key string, value time.Duration) {
+	if value < 0 {
+		v.addf(key, "must not be negative, got %s", value)
+	}
+}
+
+// atLeast verifica que el entero no sea menor que minimum.
+func (v *validator) atLeast(key string, value, minimum int) {
+	if value < minimum {
+		v.addf(key, "must be at least %d, got %d", minimum, value)
+	}
+}
+
+// oneOf verifica que el valor sea uno de los permitidos.
+func (v *validator) oneOf(key, value string, allowed ...string) {
+	for _, option := range allowed {
+		if value == option {
+			return
+		}
+	}
+	v.addf(key, "must be one of %s, got %q", strings.Join(allowed, ", "), value)
+}
+
+// httpURL verifica que el valor sea una URL http o https absoluta. Si no es required,
+// el valor vacío es válido.
+func (v *validator) httpURL(key, value string, required bool) {
+	if value == "" && !required {
+		return
+	}
+
+	parsed, err := url.Parse(value)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		v.addf(key, "must be an absolute http or https URL, got %q", value)
+	}
+}
@@ -0,0 +1,49 @@
+// Package requestid propaga el ID de petición a través de context.Context para
+// correlacionar los registros de todas las capas.
+package requestid
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// Header es la cabecera HTTP que transporta el ID de petición.
+const Header = "X-Request-ID"
+
+// contextKey es la clave del contexto con el ID de petición.
+type contextKey struct{}
+
+// WithID retorna un contexto que transporta el ID de petición.
+func WithID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext retorna el ID de petición del contexto, o "" si no tiene.
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
+
+// New genera un ID de petición aleatorio.
+func New() string {
+	var buf [16]byte
+	if _, err := rand.Read(buf[:]); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(buf[:])
+}
+
+// IsValid verifica que un ID recibido de un cliente sea seguro para registrar y reenviar.
+func IsValid(id string) bool {
+	if id == "" || len(id) > 128 {
+		return false
+	}
+	for _, char := range id {
+		if !(char >= 'a' && char <= 'z' || char >= 'A' && char <= 'Z' || char >= '0' && char <= '9' ||
+			char == '-' || char == '_' || char == '.' || char == ':') {
+			return false
+		}
+	}
+	return true
+}
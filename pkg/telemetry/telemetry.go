@@ -0,0 +1,16 @@
+// Package telemetry identifica el tracer OpenTelemetry compartido por todas las capas.
+package telemetry
+
+import (
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// InstrumentationName identifica a los tracers de la aplicación.
+const InstrumentationName = "gobcv"
+
+// Tracer retorna el tracer de la aplicación desde el TracerProvider global. Puede
+// obtenerse antes de configurar el proveedor: los spans usan el que esté instalado.
+func Tracer() trace.Tracer {
+	return otel.Tracer(InstrumentationName)
+}